@@ -0,0 +1,1065 @@
+// API gRPC del control plane. Cubre un subconjunto de application.Services:
+// lectura de applications, environments, application environments y
+// webhooks; Watch; y los comandos de teams, applications, repositorios,
+// environments, GitOps, secretos, secret bindings y webhooks. La
+// autorización, las transiciones de estado y los errores son los mismos que
+// en la API HTTP.
+//
+// Sólo por HTTP: organizaciones, cuotas, aprobaciones, consulta y
+// verificación de auditoría, decommissioning y archivado, revocación de
+// secret bindings, /commands:batch, ?dryRun=true,
+// ListApplicationEnvironments y ListSecretBindings.
+//
+// Los errores llevan un google.rpc.ErrorInfo con domain "nuevo-idp",
+// reason = Code estable y metadata["kind"] = Kind de plataforma (ver
+// platform/grpcx).
+//
+// Regenerar con `make proto` desde la raíz del repo.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: controlplane/v1/controlplane.proto
+
+package controlplanev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ControlPlane_GetApplication_FullMethodName                             = "/controlplane.v1.ControlPlane/GetApplication"
+	ControlPlane_GetEnvironment_FullMethodName                             = "/controlplane.v1.ControlPlane/GetEnvironment"
+	ControlPlane_GetApplicationEnvironment_FullMethodName                  = "/controlplane.v1.ControlPlane/GetApplicationEnvironment"
+	ControlPlane_GetWebhookSubscription_FullMethodName                     = "/controlplane.v1.ControlPlane/GetWebhookSubscription"
+	ControlPlane_ListWebhookDeliveries_FullMethodName                      = "/controlplane.v1.ControlPlane/ListWebhookDeliveries"
+	ControlPlane_Watch_FullMethodName                                      = "/controlplane.v1.ControlPlane/Watch"
+	ControlPlane_CreateTeam_FullMethodName                                 = "/controlplane.v1.ControlPlane/CreateTeam"
+	ControlPlane_CreateApplication_FullMethodName                          = "/controlplane.v1.ControlPlane/CreateApplication"
+	ControlPlane_ApproveApplication_FullMethodName                         = "/controlplane.v1.ControlPlane/ApproveApplication"
+	ControlPlane_StartApplicationOnboarding_FullMethodName                 = "/controlplane.v1.ControlPlane/StartApplicationOnboarding"
+	ControlPlane_ActivateApplication_FullMethodName                        = "/controlplane.v1.ControlPlane/ActivateApplication"
+	ControlPlane_DeprecateApplication_FullMethodName                       = "/controlplane.v1.ControlPlane/DeprecateApplication"
+	ControlPlane_DeclareCodeRepository_FullMethodName                      = "/controlplane.v1.ControlPlane/DeclareCodeRepository"
+	ControlPlane_CreateEnvironment_FullMethodName                          = "/controlplane.v1.ControlPlane/CreateEnvironment"
+	ControlPlane_DeclareDeploymentRepository_FullMethodName                = "/controlplane.v1.ControlPlane/DeclareDeploymentRepository"
+	ControlPlane_DeclareApplicationEnvironment_FullMethodName              = "/controlplane.v1.ControlPlane/DeclareApplicationEnvironment"
+	ControlPlane_CompleteApplicationEnvironmentProvisioning_FullMethodName = "/controlplane.v1.ControlPlane/CompleteApplicationEnvironmentProvisioning"
+	ControlPlane_DeclareGitOpsIntegration_FullMethodName                   = "/controlplane.v1.ControlPlane/DeclareGitOpsIntegration"
+	ControlPlane_CreateSecret_FullMethodName                               = "/controlplane.v1.ControlPlane/CreateSecret"
+	ControlPlane_StartSecretRotation_FullMethodName                        = "/controlplane.v1.ControlPlane/StartSecretRotation"
+	ControlPlane_CompleteSecretRotation_FullMethodName                     = "/controlplane.v1.ControlPlane/CompleteSecretRotation"
+	ControlPlane_DeclareSecretBinding_FullMethodName                       = "/controlplane.v1.ControlPlane/DeclareSecretBinding"
+	ControlPlane_CreateWebhookSubscription_FullMethodName                  = "/controlplane.v1.ControlPlane/CreateWebhookSubscription"
+	ControlPlane_DisableWebhookSubscription_FullMethodName                 = "/controlplane.v1.ControlPlane/DisableWebhookSubscription"
+	ControlPlane_RedeliverWebhookDelivery_FullMethodName                   = "/controlplane.v1.ControlPlane/RedeliverWebhookDelivery"
+)
+
+// ControlPlaneClient is the client API for ControlPlane service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlPlaneClient interface {
+	// Queries.
+	GetApplication(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Application, error)
+	GetEnvironment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Environment, error)
+	GetApplicationEnvironment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*ApplicationEnvironment, error)
+	GetWebhookSubscription(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*WebhookSubscription, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// Watch es el equivalente de GET /watch: reproduce los eventos posteriores
+	// a last_event_id y luego entrega los nuevos. Si el servidor corta el
+	// stream (UNAVAILABLE), el cliente reanuda con el último id recibido.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
+	// Comandos.
+	CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CreateApplication(ctx context.Context, in *CreateApplicationRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	ApproveApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	StartApplicationOnboarding(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	ActivateApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeprecateApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeclareCodeRepository(ctx context.Context, in *DeclareCodeRepositoryRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CreateEnvironment(ctx context.Context, in *CreateEnvironmentRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeclareDeploymentRepository(ctx context.Context, in *DeclareDeploymentRepositoryRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeclareApplicationEnvironment(ctx context.Context, in *DeclareApplicationEnvironmentRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CompleteApplicationEnvironmentProvisioning(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeclareGitOpsIntegration(ctx context.Context, in *DeclareGitOpsIntegrationRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CreateSecret(ctx context.Context, in *CreateSecretRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	StartSecretRotation(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CompleteSecretRotation(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DeclareSecretBinding(ctx context.Context, in *DeclareSecretBindingRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DisableWebhookSubscription(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+}
+
+type controlPlaneClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlPlaneClient(cc grpc.ClientConnInterface) ControlPlaneClient {
+	return &controlPlaneClient{cc}
+}
+
+func (c *controlPlaneClient) GetApplication(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Application, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Application)
+	err := c.cc.Invoke(ctx, ControlPlane_GetApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) GetEnvironment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Environment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Environment)
+	err := c.cc.Invoke(ctx, ControlPlane_GetEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) GetApplicationEnvironment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*ApplicationEnvironment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplicationEnvironment)
+	err := c.cc.Invoke(ctx, ControlPlane_GetApplicationEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) GetWebhookSubscription(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*WebhookSubscription, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookSubscription)
+	err := c.cc.Invoke(ctx, ControlPlane_GetWebhookSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ControlPlane_ServiceDesc.Streams[0], ControlPlane_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
+func (c *controlPlaneClient) CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CreateTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CreateApplication(ctx context.Context, in *CreateApplicationRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CreateApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) ApproveApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ApproveApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) StartApplicationOnboarding(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_StartApplicationOnboarding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) ActivateApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ActivateApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeprecateApplication(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeprecateApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeclareCodeRepository(ctx context.Context, in *DeclareCodeRepositoryRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeclareCodeRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CreateEnvironment(ctx context.Context, in *CreateEnvironmentRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CreateEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeclareDeploymentRepository(ctx context.Context, in *DeclareDeploymentRepositoryRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeclareDeploymentRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeclareApplicationEnvironment(ctx context.Context, in *DeclareApplicationEnvironmentRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeclareApplicationEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CompleteApplicationEnvironmentProvisioning(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CompleteApplicationEnvironmentProvisioning_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeclareGitOpsIntegration(ctx context.Context, in *DeclareGitOpsIntegrationRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeclareGitOpsIntegration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CreateSecret(ctx context.Context, in *CreateSecretRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CreateSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) StartSecretRotation(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_StartSecretRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CompleteSecretRotation(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CompleteSecretRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DeclareSecretBinding(ctx context.Context, in *DeclareSecretBindingRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DeclareSecretBinding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_CreateWebhookSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) DisableWebhookSubscription(ctx context.Context, in *TransitionRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_DisableWebhookSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_RedeliverWebhookDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ControlPlaneServer is the server API for ControlPlane service.
+// All implementations must embed UnimplementedControlPlaneServer
+// for forward compatibility.
+type ControlPlaneServer interface {
+	// Queries.
+	GetApplication(context.Context, *GetRequest) (*Application, error)
+	GetEnvironment(context.Context, *GetRequest) (*Environment, error)
+	GetApplicationEnvironment(context.Context, *GetRequest) (*ApplicationEnvironment, error)
+	GetWebhookSubscription(context.Context, *GetRequest) (*WebhookSubscription, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// Watch es el equivalente de GET /watch: reproduce los eventos posteriores
+	// a last_event_id y luego entrega los nuevos. Si el servidor corta el
+	// stream (UNAVAILABLE), el cliente reanuda con el último id recibido.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
+	// Comandos.
+	CreateTeam(context.Context, *CreateTeamRequest) (*CommandResponse, error)
+	CreateApplication(context.Context, *CreateApplicationRequest) (*CommandResponse, error)
+	ApproveApplication(context.Context, *TransitionRequest) (*CommandResponse, error)
+	StartApplicationOnboarding(context.Context, *TransitionRequest) (*CommandResponse, error)
+	ActivateApplication(context.Context, *TransitionRequest) (*CommandResponse, error)
+	DeprecateApplication(context.Context, *TransitionRequest) (*CommandResponse, error)
+	DeclareCodeRepository(context.Context, *DeclareCodeRepositoryRequest) (*CommandResponse, error)
+	CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CommandResponse, error)
+	DeclareDeploymentRepository(context.Context, *DeclareDeploymentRepositoryRequest) (*CommandResponse, error)
+	DeclareApplicationEnvironment(context.Context, *DeclareApplicationEnvironmentRequest) (*CommandResponse, error)
+	CompleteApplicationEnvironmentProvisioning(context.Context, *TransitionRequest) (*CommandResponse, error)
+	DeclareGitOpsIntegration(context.Context, *DeclareGitOpsIntegrationRequest) (*CommandResponse, error)
+	CreateSecret(context.Context, *CreateSecretRequest) (*CommandResponse, error)
+	StartSecretRotation(context.Context, *TransitionRequest) (*CommandResponse, error)
+	CompleteSecretRotation(context.Context, *TransitionRequest) (*CommandResponse, error)
+	DeclareSecretBinding(context.Context, *DeclareSecretBindingRequest) (*CommandResponse, error)
+	CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CommandResponse, error)
+	DisableWebhookSubscription(context.Context, *TransitionRequest) (*CommandResponse, error)
+	RedeliverWebhookDelivery(context.Context, *RedeliverWebhookDeliveryRequest) (*CommandResponse, error)
+	mustEmbedUnimplementedControlPlaneServer()
+}
+
+// UnimplementedControlPlaneServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedControlPlaneServer struct{}
+
+func (UnimplementedControlPlaneServer) GetApplication(context.Context, *GetRequest) (*Application, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApplication not implemented")
+}
+func (UnimplementedControlPlaneServer) GetEnvironment(context.Context, *GetRequest) (*Environment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnvironment not implemented")
+}
+func (UnimplementedControlPlaneServer) GetApplicationEnvironment(context.Context, *GetRequest) (*ApplicationEnvironment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApplicationEnvironment not implemented")
+}
+func (UnimplementedControlPlaneServer) GetWebhookSubscription(context.Context, *GetRequest) (*WebhookSubscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookSubscription not implemented")
+}
+func (UnimplementedControlPlaneServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedControlPlaneServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedControlPlaneServer) CreateTeam(context.Context, *CreateTeamRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTeam not implemented")
+}
+func (UnimplementedControlPlaneServer) CreateApplication(context.Context, *CreateApplicationRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApplication not implemented")
+}
+func (UnimplementedControlPlaneServer) ApproveApplication(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveApplication not implemented")
+}
+func (UnimplementedControlPlaneServer) StartApplicationOnboarding(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartApplicationOnboarding not implemented")
+}
+func (UnimplementedControlPlaneServer) ActivateApplication(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateApplication not implemented")
+}
+func (UnimplementedControlPlaneServer) DeprecateApplication(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeprecateApplication not implemented")
+}
+func (UnimplementedControlPlaneServer) DeclareCodeRepository(context.Context, *DeclareCodeRepositoryRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclareCodeRepository not implemented")
+}
+func (UnimplementedControlPlaneServer) CreateEnvironment(context.Context, *CreateEnvironmentRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEnvironment not implemented")
+}
+func (UnimplementedControlPlaneServer) DeclareDeploymentRepository(context.Context, *DeclareDeploymentRepositoryRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclareDeploymentRepository not implemented")
+}
+func (UnimplementedControlPlaneServer) DeclareApplicationEnvironment(context.Context, *DeclareApplicationEnvironmentRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclareApplicationEnvironment not implemented")
+}
+func (UnimplementedControlPlaneServer) CompleteApplicationEnvironmentProvisioning(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteApplicationEnvironmentProvisioning not implemented")
+}
+func (UnimplementedControlPlaneServer) DeclareGitOpsIntegration(context.Context, *DeclareGitOpsIntegrationRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclareGitOpsIntegration not implemented")
+}
+func (UnimplementedControlPlaneServer) CreateSecret(context.Context, *CreateSecretRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecret not implemented")
+}
+func (UnimplementedControlPlaneServer) StartSecretRotation(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartSecretRotation not implemented")
+}
+func (UnimplementedControlPlaneServer) CompleteSecretRotation(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteSecretRotation not implemented")
+}
+func (UnimplementedControlPlaneServer) DeclareSecretBinding(context.Context, *DeclareSecretBindingRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclareSecretBinding not implemented")
+}
+func (UnimplementedControlPlaneServer) CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhookSubscription not implemented")
+}
+func (UnimplementedControlPlaneServer) DisableWebhookSubscription(context.Context, *TransitionRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableWebhookSubscription not implemented")
+}
+func (UnimplementedControlPlaneServer) RedeliverWebhookDelivery(context.Context, *RedeliverWebhookDeliveryRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhookDelivery not implemented")
+}
+func (UnimplementedControlPlaneServer) mustEmbedUnimplementedControlPlaneServer() {}
+func (UnimplementedControlPlaneServer) testEmbeddedByValue()                      {}
+
+// UnsafeControlPlaneServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlPlaneServer will
+// result in compilation errors.
+type UnsafeControlPlaneServer interface {
+	mustEmbedUnimplementedControlPlaneServer()
+}
+
+func RegisterControlPlaneServer(s grpc.ServiceRegistrar, srv ControlPlaneServer) {
+	// If the following call pancis, it indicates UnimplementedControlPlaneServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ControlPlane_ServiceDesc, srv)
+}
+
+func _ControlPlane_GetApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetApplication(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_GetEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetEnvironment(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_GetApplicationEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetApplicationEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetApplicationEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetApplicationEnvironment(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_GetWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetWebhookSubscription(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlPlaneServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+func _ControlPlane_CreateTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CreateTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CreateTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CreateTeam(ctx, req.(*CreateTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CreateApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CreateApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CreateApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CreateApplication(ctx, req.(*CreateApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_ApproveApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ApproveApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ApproveApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ApproveApplication(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_StartApplicationOnboarding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).StartApplicationOnboarding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_StartApplicationOnboarding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).StartApplicationOnboarding(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_ActivateApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ActivateApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ActivateApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ActivateApplication(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeprecateApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeprecateApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeprecateApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeprecateApplication(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeclareCodeRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclareCodeRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeclareCodeRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeclareCodeRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeclareCodeRepository(ctx, req.(*DeclareCodeRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CreateEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CreateEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CreateEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CreateEnvironment(ctx, req.(*CreateEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeclareDeploymentRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclareDeploymentRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeclareDeploymentRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeclareDeploymentRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeclareDeploymentRepository(ctx, req.(*DeclareDeploymentRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeclareApplicationEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclareApplicationEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeclareApplicationEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeclareApplicationEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeclareApplicationEnvironment(ctx, req.(*DeclareApplicationEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CompleteApplicationEnvironmentProvisioning_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CompleteApplicationEnvironmentProvisioning(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CompleteApplicationEnvironmentProvisioning_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CompleteApplicationEnvironmentProvisioning(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeclareGitOpsIntegration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclareGitOpsIntegrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeclareGitOpsIntegration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeclareGitOpsIntegration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeclareGitOpsIntegration(ctx, req.(*DeclareGitOpsIntegrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CreateSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CreateSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CreateSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CreateSecret(ctx, req.(*CreateSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_StartSecretRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).StartSecretRotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_StartSecretRotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).StartSecretRotation(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CompleteSecretRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CompleteSecretRotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CompleteSecretRotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CompleteSecretRotation(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DeclareSecretBinding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclareSecretBindingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DeclareSecretBinding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DeclareSecretBinding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DeclareSecretBinding(ctx, req.(*DeclareSecretBindingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_CreateWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).CreateWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_CreateWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).CreateWebhookSubscription(ctx, req.(*CreateWebhookSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_DisableWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).DisableWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_DisableWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).DisableWebhookSubscription(ctx, req.(*TransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_RedeliverWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).RedeliverWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_RedeliverWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).RedeliverWebhookDelivery(ctx, req.(*RedeliverWebhookDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ControlPlane_ServiceDesc is the grpc.ServiceDesc for ControlPlane service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ControlPlane_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "controlplane.v1.ControlPlane",
+	HandlerType: (*ControlPlaneServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetApplication",
+			Handler:    _ControlPlane_GetApplication_Handler,
+		},
+		{
+			MethodName: "GetEnvironment",
+			Handler:    _ControlPlane_GetEnvironment_Handler,
+		},
+		{
+			MethodName: "GetApplicationEnvironment",
+			Handler:    _ControlPlane_GetApplicationEnvironment_Handler,
+		},
+		{
+			MethodName: "GetWebhookSubscription",
+			Handler:    _ControlPlane_GetWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _ControlPlane_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "CreateTeam",
+			Handler:    _ControlPlane_CreateTeam_Handler,
+		},
+		{
+			MethodName: "CreateApplication",
+			Handler:    _ControlPlane_CreateApplication_Handler,
+		},
+		{
+			MethodName: "ApproveApplication",
+			Handler:    _ControlPlane_ApproveApplication_Handler,
+		},
+		{
+			MethodName: "StartApplicationOnboarding",
+			Handler:    _ControlPlane_StartApplicationOnboarding_Handler,
+		},
+		{
+			MethodName: "ActivateApplication",
+			Handler:    _ControlPlane_ActivateApplication_Handler,
+		},
+		{
+			MethodName: "DeprecateApplication",
+			Handler:    _ControlPlane_DeprecateApplication_Handler,
+		},
+		{
+			MethodName: "DeclareCodeRepository",
+			Handler:    _ControlPlane_DeclareCodeRepository_Handler,
+		},
+		{
+			MethodName: "CreateEnvironment",
+			Handler:    _ControlPlane_CreateEnvironment_Handler,
+		},
+		{
+			MethodName: "DeclareDeploymentRepository",
+			Handler:    _ControlPlane_DeclareDeploymentRepository_Handler,
+		},
+		{
+			MethodName: "DeclareApplicationEnvironment",
+			Handler:    _ControlPlane_DeclareApplicationEnvironment_Handler,
+		},
+		{
+			MethodName: "CompleteApplicationEnvironmentProvisioning",
+			Handler:    _ControlPlane_CompleteApplicationEnvironmentProvisioning_Handler,
+		},
+		{
+			MethodName: "DeclareGitOpsIntegration",
+			Handler:    _ControlPlane_DeclareGitOpsIntegration_Handler,
+		},
+		{
+			MethodName: "CreateSecret",
+			Handler:    _ControlPlane_CreateSecret_Handler,
+		},
+		{
+			MethodName: "StartSecretRotation",
+			Handler:    _ControlPlane_StartSecretRotation_Handler,
+		},
+		{
+			MethodName: "CompleteSecretRotation",
+			Handler:    _ControlPlane_CompleteSecretRotation_Handler,
+		},
+		{
+			MethodName: "DeclareSecretBinding",
+			Handler:    _ControlPlane_DeclareSecretBinding_Handler,
+		},
+		{
+			MethodName: "CreateWebhookSubscription",
+			Handler:    _ControlPlane_CreateWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "DisableWebhookSubscription",
+			Handler:    _ControlPlane_DisableWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhookDelivery",
+			Handler:    _ControlPlane_RedeliverWebhookDelivery_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ControlPlane_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "controlplane/v1/controlplane.proto",
+}
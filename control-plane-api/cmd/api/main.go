@@ -2,19 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/grpcapi"
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/httpapi"
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/pgrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/webhookhttp"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/workflowenginehttp"
 	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/auth"
 	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/health"
 	"github.com/nuevo-idp/platform/observability"
 	"github.com/nuevo-idp/platform/tracing"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -26,40 +36,42 @@ func main() {
 		_ = logger.Sync()
 	}()
 
-	// Inicializar tracing global para el servicio.
+	// Inicializar tracing global para el servicio. El flush de spans es un
+	// paso del drenado, no un defer: tiene que correr antes de que main
+	// retorne.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	shutdownTracing, err := tracing.InitTracing(ctx, "control-plane-api")
 	if err != nil {
 		log.Printf("failed to initialize tracing: %v", err)
-	} else {
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = shutdownTracing(shutdownCtx)
-		}()
 	}
 
+	// Readiness: Postgres (si se usa) es obligatorio.
+	checker := health.NewChecker(0)
+
 	// Registrar métricas globales HTTP
 	observability.InitMetrics()
 
 	var teamRepo application.TeamRepository = memoryrepo.NewTeamRepository()
+	var pool *pgxpool.Pool
 
 	dsn := config.Get("DATABASE_URL", "")
 	if dsn != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		pool, err := pgxpool.New(ctx, dsn)
+		p, err := pgxpool.New(ctx, dsn)
 		if err != nil {
 			log.Printf("failed to create pgx pool, using in-memory TeamRepository: %v", err)
 		} else {
-			if err := pool.Ping(ctx); err != nil {
+			if err := p.Ping(ctx); err != nil {
 				log.Printf("failed to ping Postgres, using in-memory TeamRepository: %v", err)
-				pool.Close()
+				p.Close()
 			} else {
 				log.Printf("using Postgres-backed TeamRepository")
+				pool = p
 				teamRepo = pgrepo.NewTeamRepository(pool)
+				checker.Require("postgres", health.PingCheck(pool))
 			}
 		}
 	}
@@ -72,7 +84,15 @@ func main() {
 	depRepo := memoryrepo.NewDeploymentRepositoryRepository()
 	gitopsRepo := memoryrepo.NewGitOpsIntegrationRepository()
 
+	// Log de auditoría de comandos: archivo en AUDIT_LOG_PATH o memoria. Un
+	// log con la cadena rota no se sigue escribiendo.
+	auditStore, closeAudit, err := audit.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("failed to open audit log: %v", err)
+	}
+
 	services := &application.Services{
+		Organizations:           memoryrepo.NewOrganizationRepository(),
 		Teams:                   teamRepo,
 		Applications:            appRepo,
 		CodeRepositories:        codeRepo,
@@ -82,9 +102,61 @@ func main() {
 		SecretBindings:          secretBindingRepo,
 		DeploymentRepositories:  depRepo,
 		GitOpsIntegrations:      gitopsRepo,
+		WebhookSubscriptions:    memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:       memoryrepo.NewWebhookDeliveryRepository(),
+		Approvals:               memoryrepo.NewApprovalRepository(),
+		// Las decisiones de aprobación llegan a los workflows como señales a
+		// través de workflow-engine.
+		Signals: workflowenginehttp.NewSignaler(config.Get("WORKFLOW_ENGINE_URL", "http://workflow-engine:8081")),
+		// El change feed alimenta /watch; retiene los últimos eventos para
+		// que los clientes puedan reanudar con Last-Event-ID.
+		Changes: memoryrepo.NewChangeFeed(4096),
+		Audit:   auditStore,
+	}
+
+	serverOpts := []httpapi.Option{httpapi.WithHealth(checker)}
+	var grpcOpts []grpcapi.Option
+	var senderOpts []webhookhttp.Option
+	verifier, err := auth.NewVerifierFromEnv()
+	if err != nil {
+		log.Printf("JWT authentication disabled (dev mode): %v", err)
+		// En modo dev los receptores de webhooks suelen ser locales y http.
+		services.AllowInsecureWebhooks = true
+		senderOpts = append(senderOpts, webhookhttp.AllowPrivateTargets())
+	} else {
+		serverOpts = append(serverOpts, httpapi.WithVerifier(verifier))
+		grpcOpts = append(grpcOpts, grpcapi.WithVerifier(verifier))
 	}
 
-	server := httpapi.NewServer(services, logger)
+	// Los webhooks de los teams se alimentan del mismo change feed que /watch.
+	dispatcher := application.NewWebhookDispatcher(services, webhookhttp.NewSender(senderOpts...), application.WebhookDispatcherOptions{
+		OnError: func(err error) { logger.Warn("webhook dispatcher error", zap.Error(err)) },
+	})
+	dispatchCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		if err := dispatcher.Run(dispatchCtx); err != nil {
+			logger.Error("webhook dispatcher stopped", zap.Error(err))
+		}
+	}()
+
+	// La API gRPC se sirve en paralelo a HTTP, con la misma autenticación y
+	// política de autorización.
+	grpcAddr := config.Get("GRPC_ADDR", ":9090")
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", grpcAddr, err)
+	}
+	grpcServer := grpcapi.NewServer(services, logger, grpcOpts...).GRPCServer()
+	go func() {
+		log.Printf("control-plane-api gRPC listening on %s", grpcAddr)
+		if err := grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("gRPC server error: %v", err)
+		}
+	}()
+
+	server := httpapi.NewServer(services, logger, serverOpts...)
 	handler := server.Routes()
 
 	srv := &http.Server{
@@ -95,8 +167,40 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Println("control-plane-api listening on :8080")
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Printf("server error: %v", err)
+	sigCtx, stop := health.SignalContext(context.Background())
+	defer stop()
+	go func() {
+		log.Println("control-plane-api listening on :8080")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("server error: %v", err)
+			stop()
+		}
+	}()
+	<-sigCtx.Done()
+
+	// Drenado: primero se deja de aceptar tráfico (HTTP y gRPC terminan lo
+	// que tienen en curso), después se frena el dispatcher de webhooks y al
+	// final se hace flush de tracing y se cierra el pool.
+	drainer := health.NewDrainer(checker, logger, health.DrainOptionsFromEnv())
+	drainer.Add("http", health.ShutdownHTTP(srv))
+	drainer.Add("grpc", health.StopGracefully(grpcServer))
+	drainer.Add("webhook-dispatcher", func(ctx context.Context) error {
+		stopDispatcher()
+		select {
+		case <-dispatcherDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	drainer.Add("audit", func(context.Context) error { return closeAudit() })
+	if shutdownTracing != nil {
+		drainer.Add("tracing", shutdownTracing)
+	}
+	if pool != nil {
+		drainer.Add("postgres", health.Blocking(pool.Close))
+	}
+	if err := drainer.Drain(context.Background()); err != nil {
+		logger.Error("control-plane-api shutdown incomplete", zap.Error(err))
 	}
 }
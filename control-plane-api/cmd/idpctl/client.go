@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// apiClient habla HTTP con control-plane-api usando las credenciales del
+// perfil.
+type apiClient struct {
+	baseURL       string
+	token         string
+	internalToken string
+	organization  string
+	http          *http.Client
+	// timeout acota cada request salvo los streams de /watch.
+	timeout time.Duration
+}
+
+// apiError es un error devuelto por la API, con su problem+json.
+type apiError struct {
+	problem httpx.Problem
+}
+
+func (e *apiError) Error() string {
+	p := e.problem
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s", p.Status, p.Title)
+	if p.Code != "" {
+		fmt.Fprintf(&b, " (%s)", p.Code)
+	}
+	if p.Detail != "" {
+		fmt.Fprintf(&b, ": %s", p.Detail)
+	}
+	for _, f := range p.Errors {
+		fmt.Fprintf(&b, "\n  %s: %s", f.Field, f.Message)
+	}
+	if p.TraceID != "" {
+		fmt.Fprintf(&b, "\n  traceId: %s", p.TraceID)
+	}
+	return b.String()
+}
+
+// requestOptions son los headers opcionales de un comando.
+type requestOptions struct {
+	ifMatch        int64
+	idempotencyKey string
+}
+
+func (c *apiClient) newRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.internalToken != "" {
+		req.Header.Set(auth.InternalTokenHeader, c.internalToken)
+	}
+	if c.organization != "" {
+		req.Header.Set(client.OrganizationHeader, c.organization)
+	}
+	return req, nil
+}
+
+// call ejecuta la request y devuelve el status y el body. Un status >= 400
+// se devuelve como *apiError.
+func (c *apiClient) call(ctx context.Context, method, path string, query url.Values, body any, opts requestOptions) (int, []byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if opts.ifMatch > 0 {
+		req.Header.Set("If-Match", httpx.VersionETag(opts.ifMatch))
+	}
+	if opts.idempotencyKey != "" {
+		req.Header.Set(httpx.IdempotencyKeyHeader, opts.idempotencyKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, nil, &apiError{problem: httpx.ReadProblem(resp)}
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, raw, nil
+}
+
+// stream abre un GET de larga duración (SSE); el caller cierra el body.
+func (c *apiClient) stream(ctx context.Context, path string, query url.Values, lastEventID string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", httpx.EventStreamContentType)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &apiError{problem: httpx.ReadProblem(resp)}
+	}
+	return resp, nil
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type fieldKind int
+
+const (
+	fieldString fieldKind = iota
+	fieldInt
+	// fieldList acepta el flag repetido o valores separados por coma.
+	fieldList
+)
+
+// field mapea un flag a un campo del body JSON. json admite rutas con
+// punto para objetos anidados (filter.actions).
+type field struct {
+	flag     string
+	json     string
+	kind     fieldKind
+	required bool
+	usage    string
+}
+
+// commandSpec describe un comando de la API: "idpctl <verb> <resource> ID"
+// hace POST a path con {"id": ID} más los fields.
+type commandSpec struct {
+	verb     string
+	resource string
+	path     string
+	summary  string
+	// done es lo que se imprime al terminar ("created", "approved").
+	done   string
+	fields []field
+}
+
+func (s commandSpec) synopsis() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s ID", s.verb, s.resource)
+	for _, f := range s.fields {
+		if f.required {
+			fmt.Fprintf(&b, " --%s", f.flag)
+		}
+	}
+	return b.String()
+}
+
+func str(flagName, jsonName, usage string) field {
+	return field{flag: flagName, json: jsonName, usage: usage}
+}
+
+func required(f field) field {
+	f.required = true
+	return f
+}
+
+// commandTable cubre los comandos de control-plane-api (ver
+// internal/adapters/httpapi/routes.go).
+var commandTable = []commandSpec{
+	{verb: "create", resource: "organization", path: "/commands/organizations", summary: "Crear una Organization", done: "created",
+		fields: []field{required(str("name", "name", "nombre de la organización"))}},
+	{verb: "create", resource: "team", path: "/commands/teams", summary: "Crear un Team", done: "created",
+		fields: []field{required(str("name", "name", "nombre del team"))}},
+	{verb: "set-quota", resource: "team", path: "/commands/teams/quota", summary: "Fijar la cuota de un Team (0 usa la política por defecto)", done: "quota set",
+		fields: []field{
+			{flag: "max-applications", json: "maxApplications", kind: fieldInt, usage: "máximo de Applications"},
+			{flag: "max-secrets", json: "maxSecrets", kind: fieldInt, usage: "máximo de Secrets"},
+			{flag: "max-bindings-per-secret", json: "maxBindingsPerSecret", kind: fieldInt, usage: "máximo de bindings por Secret"},
+			{flag: "max-environments-per-application", json: "maxEnvironmentsPerApplication", kind: fieldInt, usage: "máximo de ApplicationEnvironments por Application"},
+		}},
+	{verb: "create", resource: "application", path: "/commands/applications", summary: "Crear una Application", done: "created",
+		fields: []field{required(str("name", "name", "nombre")), required(str("team", "teamId", "team dueño"))}},
+	{verb: "approve", resource: "application", path: "/commands/applications/approve", summary: "Aprobar una Application", done: "approved"},
+	{verb: "start-onboarding", resource: "application", path: "/commands/applications/start-onboarding", summary: "Iniciar onboarding (uso interno)", done: "onboarding started"},
+	{verb: "activate", resource: "application", path: "/commands/applications/activate", summary: "Activar una Application (uso interno)", done: "activated"},
+	{verb: "deprecate", resource: "application", path: "/commands/applications/deprecate", summary: "Deprecar una Application", done: "deprecated"},
+	{verb: "start-decommissioning", resource: "application", path: "/commands/applications/start-decommissioning", summary: "Iniciar el decommissioning (uso interno)", done: "decommissioning started"},
+	{verb: "archive", resource: "application", path: "/commands/applications/archive", summary: "Archivar una Application (uso interno)", done: "archived"},
+	{verb: "create", resource: "environment", path: "/commands/environments", summary: "Crear un Environment", done: "created",
+		fields: []field{required(str("name", "name", "nombre"))}},
+	{verb: "declare", resource: "application-environment", path: "/commands/application-environments", summary: "Declarar un ApplicationEnvironment", done: "declared",
+		fields: []field{required(str("application", "applicationId", "application")), required(str("environment", "environmentId", "environment"))}},
+	{verb: "complete-provisioning", resource: "application-environment", path: "/commands/application-environments/complete-provisioning", summary: "Completar el provisioning (uso interno)", done: "provisioning completed"},
+	{verb: "decommission", resource: "application-environment", path: "/commands/application-environments/decommission", summary: "Decomisionar un ApplicationEnvironment (uso interno)", done: "decommissioned"},
+	{verb: "create", resource: "secret", path: "/commands/secrets", summary: "Crear un Secret", done: "created",
+		fields: []field{required(str("owner-team", "ownerTeamId", "team dueño")), str("purpose", "purpose", "propósito"), str("sensitivity", "sensitivity", "sensibilidad")}},
+	{verb: "start-rotation", resource: "secret", path: "/commands/secrets/start-rotation", summary: "Iniciar la rotación de un Secret", done: "rotation started"},
+	{verb: "complete-rotation", resource: "secret", path: "/commands/secrets/complete-rotation", summary: "Completar la rotación (uso interno)", done: "rotation completed"},
+	{verb: "declare", resource: "secret-binding", path: "/commands/secret-bindings", summary: "Declarar un SecretBinding", done: "declared",
+		fields: []field{required(str("secret", "secretId", "secret")), required(str("target", "targetId", "ID del destino")), required(str("target-type", "targetType", "tipo del destino"))}},
+	{verb: "revoke", resource: "secret-binding", path: "/commands/secret-bindings/revoke", summary: "Revocar un SecretBinding", done: "revoked"},
+	{verb: "declare", resource: "code-repository", path: "/commands/code-repositories", summary: "Declarar un CodeRepository", done: "declared",
+		fields: []field{required(str("application", "applicationId", "application"))}},
+	{verb: "declare", resource: "deployment-repository", path: "/commands/deployment-repositories", summary: "Declarar un DeploymentRepository", done: "declared",
+		fields: []field{required(str("application", "applicationId", "application")), str("deployment-model", "deploymentModel", "modelo de despliegue")}},
+	{verb: "declare", resource: "gitops-integration", path: "/commands/gitops-integrations", summary: "Declarar una GitOpsIntegration", done: "declared",
+		fields: []field{required(str("application", "applicationId", "application")), required(str("deployment-repository", "deploymentRepositoryId", "DeploymentRepository"))}},
+	{verb: "create", resource: "webhook-subscription", path: "/commands/webhook-subscriptions", summary: "Crear una WebhookSubscription", done: "created",
+		fields: []field{
+			required(str("team", "teamId", "team dueño")), required(str("url", "url", "URL destino")), required(str("secret", "secret", "secreto de firma")),
+			{flag: "resource-type", json: "filter.resourceTypes", kind: fieldList, usage: "filtrar por tipo de recurso (repetible)"},
+			{flag: "action", json: "filter.actions", kind: fieldList, usage: "filtrar por acción (repetible)"},
+			str("filter-application", "filter.applicationId", "filtrar por application"),
+		}},
+	{verb: "disable", resource: "webhook-subscription", path: "/commands/webhook-subscriptions/disable", summary: "Deshabilitar una WebhookSubscription", done: "disabled"},
+	{verb: "redeliver", resource: "webhook-delivery", path: "/commands/webhook-deliveries/redeliver", summary: "Re-encolar una entrega DeadLettered", done: "redelivered"},
+	{verb: "create", resource: "approval", path: "/commands/approvals", summary: "Crear un pedido de aprobación", done: "created",
+		fields: []field{
+			required(str("type", "type", "tipo de aprobación")), required(str("resource-type", "resourceType", "Application o Secret")), required(str("resource-id", "resourceId", "ID del recurso")),
+			{flag: "role", json: "rolesAllowed", kind: fieldList, usage: "rol aprobador (repetible)"},
+			str("reason", "reason", "motivo"), str("workflow", "workflowId", "workflow a señalizar"), str("signal", "signalName", "nombre de la señal"),
+			{flag: "timeout-seconds", json: "timeoutSeconds", kind: fieldInt, usage: "vencimiento en segundos"},
+		}},
+	{verb: "approve", resource: "approval", path: "/commands/approvals/approve", summary: "Aprobar un pedido", done: "approved",
+		fields: []field{str("comment", "comment", "comentario")}},
+	{verb: "reject", resource: "approval", path: "/commands/approvals/reject", summary: "Rechazar un pedido", done: "rejected",
+		fields: []field{str("comment", "comment", "comentario")}},
+}
+
+// resourceAliases son abreviaturas aceptadas en lugar del nombre completo.
+var resourceAliases = map[string]string{
+	"app":     "application",
+	"env":     "environment",
+	"appenv":  "application-environment",
+	"binding": "secret-binding",
+	"repo":    "code-repository",
+	"webhook": "webhook-subscription",
+}
+
+func canonicalResource(name string) string {
+	if full, ok := resourceAliases[name]; ok {
+		return full
+	}
+	return name
+}
+
+func findCommand(verb, resource string) (commandSpec, bool) {
+	resource = canonicalResource(resource)
+	for _, spec := range commandTable {
+		if spec.verb == verb && spec.resource == resource {
+			return spec, true
+		}
+	}
+	return commandSpec{}, false
+}
+
+// listFlag acumula valores de un flag repetible o separado por comas.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// commandResult es la salida de un comando en JSON/YAML.
+type commandResult struct {
+	Resource string `json:"resource" yaml:"resource"`
+	ID       string `json:"id" yaml:"id"`
+	Status   int    `json:"status" yaml:"status"`
+	Result   string `json:"result" yaml:"result"`
+}
+
+func (c *cli) runCommand(ctx context.Context, spec commandSpec, args []string) error {
+	var g globalOptions
+	fs := newFlagSet(spec.verb + " " + spec.resource)
+	g.register(fs)
+	ifMatch := fs.Int64("if-match", 0, "versión esperada del recurso (If-Match)")
+	idempotencyKey := fs.String("idempotency-key", "", "Idempotency-Key de la request")
+	dryRun := fs.Bool("dry-run", false, "validar el comando sin aplicarlo")
+	values := make(map[string]any, len(spec.fields))
+	for _, f := range spec.fields {
+		switch f.kind {
+		case fieldList:
+			l := &listFlag{}
+			fs.Var(l, f.flag, f.usage)
+			values[f.flag] = l
+		case fieldInt:
+			values[f.flag] = fs.Int64(f.flag, 0, f.usage)
+		default:
+			values[f.flag] = fs.String(f.flag, "", f.usage)
+		}
+	}
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError("usage: idpctl " + spec.synopsis())
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	body := map[string]any{"id": pos[0]}
+	for _, f := range spec.fields {
+		if f.required && !set[f.flag] {
+			return usageError(fmt.Sprintf("--%s is required\nusage: idpctl %s", f.flag, spec.synopsis()))
+		}
+		if !set[f.flag] {
+			continue
+		}
+		var v any
+		switch p := values[f.flag].(type) {
+		case *listFlag:
+			v = []string(*p)
+		case *int64:
+			v = *p
+		case *string:
+			v = *p
+		}
+		setPath(body, f.json, v)
+	}
+
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+	status, resp, err := client.call(ctx, http.MethodPost, spec.path, dryRunQuery(*dryRun), body, requestOptions{ifMatch: *ifMatch, idempotencyKey: *idempotencyKey})
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		// La salida es la del servidor: el recurso como habría quedado.
+		if g.output == "table" {
+			fmt.Fprintf(c.stdout, "%s/%s %s (dry run)\n", spec.resource, pos[0], spec.done)
+			return nil
+		}
+		return c.printResponse(g.output, resp, nil, "")
+	}
+	res := commandResult{Resource: spec.resource, ID: pos[0], Status: status, Result: spec.done}
+	if g.output == "table" {
+		fmt.Fprintf(c.stdout, "%s/%s %s\n", res.Resource, res.ID, res.Result)
+		return nil
+	}
+	return printValue(c.stdout, g.output, res)
+}
+
+// dryRunQuery devuelve la query de ?dryRun=true, o nil sin --dry-run.
+func dryRunQuery(dryRun bool) url.Values {
+	if !dryRun {
+		return nil
+	}
+	return url.Values{"dryRun": {"true"}}
+}
+
+// setPath asigna v en la ruta con puntos de m, creando los objetos
+// intermedios.
+func setPath(m map[string]any, path string, v any) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = v
+}
+
+// runBatch implementa "idpctl batch -f FILE": el archivo (JSON o YAML) es
+// el body de POST /commands:batch.
+func (c *cli) runBatch(ctx context.Context, args []string) error {
+	var g globalOptions
+	fs := newFlagSet("batch")
+	g.register(fs)
+	file := fs.String("f", "", "archivo JSON o YAML con {mode, commands}; - lee de stdin")
+	mode := fs.String("mode", "", "atomic o bestEffort; pisa el mode del archivo")
+	idempotencyKey := fs.String("idempotency-key", "", "Idempotency-Key de la request")
+	dryRun := fs.Bool("dry-run", false, "validar el batch sin aplicar nada")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if *file == "" || len(pos) > 0 {
+		return usageError("usage: idpctl batch -f FILE [--mode atomic|bestEffort] [--dry-run]")
+	}
+
+	var raw []byte
+	if *file == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("read batch file: %w", err)
+	}
+	// YAML es superconjunto de JSON: un solo decoder sirve para ambos.
+	var body map[string]any
+	if err := yaml.Unmarshal(raw, &body); err != nil {
+		return fmt.Errorf("parse batch file: %w", err)
+	}
+	if *mode != "" {
+		body["mode"] = *mode
+	}
+
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+	_, resp, err := client.call(ctx, http.MethodPost, "/commands:batch", dryRunQuery(*dryRun), body, requestOptions{idempotencyKey: *idempotencyKey})
+	if err != nil {
+		return err
+	}
+	if err := c.printResponse(g.output, resp, batchColumns, "results"); err != nil {
+		return err
+	}
+
+	var result struct {
+		Results []struct {
+			Status int `json:"status"`
+		} `json:"results"`
+	}
+	_ = json.Unmarshal(resp, &result)
+	for _, r := range result.Results {
+		if r.Status >= http.StatusBadRequest {
+			return errors.New("some batch commands failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile es una instalación de la plataforma: a qué control-plane-api
+// hablar y con qué credenciales.
+type Profile struct {
+	Server string `yaml:"server"`
+	// Token es el bearer JWT del usuario. TokenFile lo lee de un archivo en
+	// cada invocación, para tokens que otro proceso renueva.
+	Token     string `yaml:"token,omitempty"`
+	TokenFile string `yaml:"tokenFile,omitempty"`
+	// InternalToken autentica como workflow-engine (X-Internal-Token); sólo
+	// para los comandos de uso interno.
+	InternalToken string `yaml:"internalToken,omitempty"`
+	// Organization es la organización en la que se opera; vacío usa la del
+	// token o la default.
+	Organization string `yaml:"organization,omitempty"`
+}
+
+// Config es el archivo de perfiles de idpctl.
+type Config struct {
+	CurrentContext string             `yaml:"currentContext,omitempty"`
+	Contexts       map[string]Profile `yaml:"contexts,omitempty"`
+}
+
+// configPath devuelve IDPCTL_CONFIG o ~/.config/idpctl/config.yaml.
+func (c *cli) configPath() (string, error) {
+	if p := c.getenv("IDPCTL_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config dir (set IDPCTL_CONFIG): %w", err)
+	}
+	return filepath.Join(dir, "idpctl", "config.yaml"), nil
+}
+
+// loadConfig lee el archivo de perfiles; si no existe devuelve uno vacío.
+func (c *cli) loadConfig() (*Config, string, error) {
+	path, err := c.configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := &Config{}
+	raw, err := os.ReadFile(path) //nolint:gosec // ruta elegida por el usuario
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, path, nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("read config %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, "", fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, path, nil
+}
+
+// saveConfig escribe cfg con permisos 0600: puede llevar tokens.
+func saveConfig(path string, cfg *Config) error {
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("write config %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveProfile combina el perfil elegido con los flags y variables de
+// entorno. Precedencia: flag > variable de entorno > perfil.
+func (c *cli) resolveProfile(g *globalOptions) (Profile, error) {
+	cfg, path, err := c.loadConfig()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	name := firstNonEmpty(g.context, c.getenv("IDPCTL_CONTEXT"), cfg.CurrentContext)
+	var p Profile
+	if name != "" {
+		var ok bool
+		if p, ok = cfg.Contexts[name]; !ok {
+			return Profile{}, fmt.Errorf("context %q not found in %s", name, path)
+		}
+	}
+
+	p.Server = firstNonEmpty(g.server, c.getenv("IDPCTL_SERVER"), p.Server, "http://localhost:8080")
+	p.Token = firstNonEmpty(g.token, c.getenv("IDPCTL_TOKEN"), p.Token)
+	p.InternalToken = firstNonEmpty(g.internalToken, c.getenv("IDPCTL_INTERNAL_TOKEN"), p.InternalToken)
+	p.Organization = firstNonEmpty(g.organization, c.getenv("IDPCTL_ORGANIZATION"), p.Organization)
+	if p.Token == "" && p.TokenFile != "" {
+		raw, err := os.ReadFile(p.TokenFile)
+		if err != nil {
+			return Profile{}, fmt.Errorf("read token file: %w", err)
+		}
+		p.Token = strings.TrimSpace(string(raw))
+	}
+	p.Server = strings.TrimRight(p.Server, "/")
+	return p, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// runConfig implementa "idpctl config ...".
+func (c *cli) runConfig(args []string) error {
+	if len(args) == 0 {
+		return usageError("config requires a subcommand: set-context, use-context, get-contexts, current-context, delete-context")
+	}
+	cfg, path, err := c.loadConfig()
+	if err != nil {
+		return err
+	}
+	sub, args := args[0], args[1:]
+
+	switch sub {
+	case "set-context":
+		fs := newFlagSet("config set-context")
+		server := fs.String("server", "", "URL base de control-plane-api")
+		token := fs.String("token", "", "bearer token del usuario")
+		tokenFile := fs.String("token-file", "", "archivo del que leer el bearer token en cada invocación")
+		internalToken := fs.String("internal-token", "", "token interno (X-Internal-Token)")
+		organization := fs.String("organization", "", "organización en la que operar")
+		use := fs.Bool("use", false, "además, usar este contexto por defecto")
+		pos, err := parseInterspersed(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(pos) != 1 {
+			return usageError("usage: idpctl config set-context NAME [--server URL] [--token T | --token-file F] [--internal-token T] [--organization ORG] [--use]")
+		}
+		if cfg.Contexts == nil {
+			cfg.Contexts = map[string]Profile{}
+		}
+		// Sólo se pisan los campos indicados, para poder rotar el token sin
+		// repetir el server.
+		p := cfg.Contexts[pos[0]]
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "server":
+				p.Server = *server
+			case "token":
+				p.Token, p.TokenFile = *token, ""
+			case "token-file":
+				p.TokenFile, p.Token = *tokenFile, ""
+			case "internal-token":
+				p.InternalToken = *internalToken
+			case "organization":
+				p.Organization = *organization
+			}
+		})
+		cfg.Contexts[pos[0]] = p
+		if *use || cfg.CurrentContext == "" {
+			cfg.CurrentContext = pos[0]
+		}
+		if err := saveConfig(path, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "context %q saved\n", pos[0])
+		return nil
+
+	case "use-context":
+		if len(args) != 1 {
+			return usageError("usage: idpctl config use-context NAME")
+		}
+		if _, ok := cfg.Contexts[args[0]]; !ok {
+			return fmt.Errorf("context %q not found in %s", args[0], path)
+		}
+		cfg.CurrentContext = args[0]
+		if err := saveConfig(path, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "switched to context %q\n", args[0])
+		return nil
+
+	case "delete-context":
+		if len(args) != 1 {
+			return usageError("usage: idpctl config delete-context NAME")
+		}
+		if _, ok := cfg.Contexts[args[0]]; !ok {
+			return fmt.Errorf("context %q not found in %s", args[0], path)
+		}
+		delete(cfg.Contexts, args[0])
+		if cfg.CurrentContext == args[0] {
+			cfg.CurrentContext = ""
+		}
+		if err := saveConfig(path, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "context %q deleted\n", args[0])
+		return nil
+
+	case "current-context":
+		if cfg.CurrentContext == "" {
+			return errors.New("no current context")
+		}
+		fmt.Fprintln(c.stdout, cfg.CurrentContext)
+		return nil
+
+	case "get-contexts":
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			current := ""
+			if name == cfg.CurrentContext {
+				current = "*"
+			}
+			p := cfg.Contexts[name]
+			auth := "none"
+			switch {
+			case p.Token != "":
+				auth = "token"
+			case p.TokenFile != "":
+				auth = "token-file"
+			}
+			rows = append(rows, []string{current, name, p.Server, p.Organization, auth})
+		}
+		writeTable(c.stdout, []string{"CURRENT", "NAME", "SERVER", "ORGANIZATION", "AUTH"}, rows)
+		return nil
+	}
+	return usageError(fmt.Sprintf("unknown config subcommand %q", sub))
+}
@@ -0,0 +1,196 @@
+// Command idpctl es el cliente de línea de comandos de control-plane-api:
+// cubre todos los comandos y queries de la API HTTP, con salida en tabla,
+// JSON o YAML, perfiles para varias instalaciones y espera de estados.
+//
+//	idpctl create team team-1 --name "Platform Team"
+//	idpctl get application app-1 -o yaml
+//	idpctl wait application app-1 --for Active --timeout 10m
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// cli agrupa las dependencias del proceso, para poder ejecutarlo en tests.
+type cli struct {
+	stdout, stderr io.Writer
+	getenv         func(string) string
+	httpClient     *http.Client
+}
+
+// globalOptions son los flags que acepta cualquier subcomando.
+type globalOptions struct {
+	context       string
+	server        string
+	token         string
+	internalToken string
+	organization  string
+	output        string
+	timeout       time.Duration
+}
+
+func (g *globalOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.context, "context", "", "perfil a usar (por defecto, el actual; ver idpctl config)")
+	fs.StringVar(&g.server, "server", "", "URL base de control-plane-api")
+	fs.StringVar(&g.token, "token", "", "bearer token")
+	fs.StringVar(&g.internalToken, "internal-token", "", "token interno (X-Internal-Token), para comandos de uso interno")
+	fs.StringVar(&g.organization, "organization", "", "organización en la que operar (X-Organization-ID)")
+	fs.StringVar(&g.output, "o", "table", "formato de salida: table, json o yaml")
+	fs.StringVar(&g.output, "output", "table", "formato de salida: table, json o yaml")
+	fs.DurationVar(&g.timeout, "request-timeout", 30*time.Second, "timeout de cada request HTTP")
+}
+
+// usageError es un error de invocación: se informa con el uso y código 2.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+const usage = `idpctl - cliente de control-plane-api
+
+Uso:
+  idpctl <verbo> <recurso> [ID] [flags]
+
+Comandos:
+%s
+  batch -f FILE [--mode atomic|bestEffort] [--dry-run]
+
+Queries:
+  get <organization|team-quota|application|environment|application-environment|webhook-subscription|approval> ID
+  list webhook-deliveries --subscription ID [--state S]
+  list approvals [--role R]... [--state S]
+  list application-environments --application ID
+  list secret-bindings --target ID [--target-type T]
+  audit [--actor A] [--resource-type T] [--resource-id ID] [--command C] [--from T] [--to T] [--after-seq N] [--limit N]
+  audit verify
+  watch [--type T] [--id ID] [--team ID] [--application ID] [--since EVENT_ID]
+  wait <recurso> ID --for STATE[,STATE...] [--timeout 5m]
+
+Perfiles:
+  config set-context NAME --server URL [--token T | --token-file F] [--internal-token T] [--organization ORG] [--use]
+  config use-context NAME | delete-context NAME | current-context | get-contexts
+
+Flags globales:
+  --context NAME, --server URL, --token T, --internal-token T, --organization ORG, -o table|json|yaml, --request-timeout D
+
+Variables de entorno: IDPCTL_CONFIG, IDPCTL_CONTEXT, IDPCTL_SERVER, IDPCTL_TOKEN, IDPCTL_INTERNAL_TOKEN,
+IDPCTL_ORGANIZATION.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv, httpClient: http.DefaultClient}
+	os.Exit(c.run(ctx, os.Args[1:]))
+}
+
+// run ejecuta idpctl con args y devuelve el código de salida: 0 si todo
+// salió bien, 1 ante un error y 2 ante una invocación inválida.
+func (c *cli) run(ctx context.Context, args []string) int {
+	err := c.dispatch(ctx, args)
+	var uerr usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &uerr):
+		fmt.Fprintf(c.stderr, "error: %s\n\n", err)
+		c.printUsage(c.stderr)
+		return 2
+	default:
+		fmt.Fprintf(c.stderr, "error: %s\n", err)
+		return 1
+	}
+}
+
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("missing command")
+	}
+	verb, rest := args[0], args[1:]
+	switch verb {
+	case "help", "-h", "--help":
+		c.printUsage(c.stdout)
+		return nil
+	case "config":
+		return c.runConfig(rest)
+	case "get":
+		return c.runGet(ctx, rest)
+	case "list":
+		return c.runList(ctx, rest)
+	case "audit":
+		return c.runAudit(ctx, rest)
+	case "watch":
+		return c.runWatch(ctx, rest)
+	case "wait":
+		return c.runWait(ctx, rest)
+	case "batch":
+		return c.runBatch(ctx, rest)
+	}
+	if len(rest) > 0 {
+		if spec, ok := findCommand(verb, rest[0]); ok {
+			return c.runCommand(ctx, spec, rest[1:])
+		}
+	}
+	return usageError(fmt.Sprintf("unknown command %q", strings.Join(args[:min(2, len(args))], " ")))
+}
+
+func (c *cli) printUsage(w io.Writer) {
+	var b strings.Builder
+	for _, spec := range commandTable {
+		fmt.Fprintf(&b, "  %-52s %s\n", spec.synopsis(), spec.summary)
+	}
+	fmt.Fprintf(w, usage, strings.TrimRight(b.String(), "\n"))
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseInterspersed parsea fs permitiendo flags después de los argumentos
+// posicionales (idpctl get application app-1 -o json), que flag no admite.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err.Error())
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// connect resuelve el perfil y devuelve el cliente HTTP de la invocación.
+func (c *cli) connect(g *globalOptions) (*apiClient, error) {
+	switch g.output {
+	case "table", "json", "yaml":
+	default:
+		return nil, usageError(fmt.Sprintf("unknown output format %q (use table, json or yaml)", g.output))
+	}
+	p, err := c.resolveProfile(g)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{
+		baseURL:       p.Server,
+		token:         p.Token,
+		internalToken: p.InternalToken,
+		organization:  p.Organization,
+		http:          c.httpClient,
+		timeout:       g.timeout,
+	}, nil
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/httpapi"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/httpx"
+	"go.uber.org/zap"
+)
+
+// runCLI ejecuta idpctl con env como entorno y devuelve código, stdout y
+// stderr.
+func runCLI(t *testing.T, env map[string]string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if env == nil {
+		env = map[string]string{}
+	}
+	if _, ok := env["IDPCTL_CONFIG"]; !ok {
+		env["IDPCTL_CONFIG"] = filepath.Join(t.TempDir(), "config.yaml")
+	}
+	c := &cli{
+		stdout:     &stdout,
+		stderr:     &stderr,
+		getenv:     func(k string) string { return env[k] },
+		httpClient: http.DefaultClient,
+	}
+	code := c.run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+const appJSON = `{"id":"app-1","name":"billing","teamId":"team-1","state":"%s","metadata":{"version":3,"createdBy":"alice","createdAt":"2026-01-01T00:00:00Z"}}`
+
+func TestCommand_SendsBodyAndHeaders(t *testing.T) {
+	var got struct {
+		path, authz, internal, ifMatch string
+		body                           map[string]any
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.authz = r.Header.Get("Authorization")
+		got.internal = r.Header.Get(auth.InternalTokenHeader)
+		got.ifMatch = r.Header.Get("If-Match")
+		_ = json.NewDecoder(r.Body).Decode(&got.body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	code, stdout, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL, "IDPCTL_TOKEN": "jwt"},
+		"create", "app", "app-1", "--name", "billing", "--team", "team-1", "--if-match", "2")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if got.path != "/commands/applications" || got.authz != "Bearer jwt" || got.internal != "" {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	if got.ifMatch != httpx.VersionETag(2) {
+		t.Fatalf("If-Match = %q", got.ifMatch)
+	}
+	want := map[string]any{"id": "app-1", "name": "billing", "teamId": "team-1"}
+	if fmt.Sprint(got.body) != fmt.Sprint(want) {
+		t.Fatalf("body = %v, want %v", got.body, want)
+	}
+	if stdout != "application/app-1 created\n" {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
+
+func TestCommand_NestedAndListFields(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	code, _, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL},
+		"create", "webhook-subscription", "sub-1", "--team", "team-1", "--url", "https://hooks.example.com",
+		"--secret", "s3cr3t", "--action", "created,stateChanged", "--resource-type", "Application")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	filter, _ := body["filter"].(map[string]any)
+	if fmt.Sprint(filter["actions"]) != "[created stateChanged]" || fmt.Sprint(filter["resourceTypes"]) != "[Application]" {
+		t.Fatalf("filter = %v", filter)
+	}
+}
+
+func TestCommand_DryRunPrintsWouldBeResource(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"command":"createApplication","status":201,"resource":`+appJSON+`}`, "Proposed")
+	}))
+	defer srv.Close()
+
+	code, stdout, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL},
+		"create", "app", "app-1", "--name", "billing", "--team", "team-1", "--dry-run", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if query != "dryRun=true" {
+		t.Fatalf("query = %q", query)
+	}
+	if !strings.Contains(stdout, `"state": "Proposed"`) {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
+
+func TestCommand_MissingRequiredFlag(t *testing.T) {
+	code, _, stderr := runCLI(t, nil, "create", "application", "app-1", "--name", "billing")
+	if code != 2 {
+		t.Fatalf("exit = %d, want 2", code)
+	}
+	if !strings.Contains(stderr, "--team is required") {
+		t.Fatalf("stderr = %q", stderr)
+	}
+}
+
+func TestCommand_APIErrorShowsProblem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"status":409,"title":"Conflict","code":"invalid_transition","detail":"application is not Proposed"}`))
+	}))
+	defer srv.Close()
+
+	code, _, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL}, "approve", "application", "app-1")
+	if code != 1 {
+		t.Fatalf("exit = %d, want 1", code)
+	}
+	if !strings.Contains(stderr, "409 Conflict (invalid_transition): application is not Proposed") {
+		t.Fatalf("stderr = %q", stderr)
+	}
+}
+
+func TestGet_OutputFormats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/queries/applications" || r.URL.Query().Get("id") != "app-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, appJSON, "Active")
+	}))
+	defer srv.Close()
+	env := map[string]string{"IDPCTL_SERVER": srv.URL}
+
+	_, stdout, _ := runCLI(t, env, "get", "application", "app-1")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || strings.Fields(lines[0])[0] != "ID" || strings.Join(strings.Fields(lines[1]), " ") != "app-1 billing team-1 Active 3" {
+		t.Fatalf("table output = %q", stdout)
+	}
+
+	_, stdout, _ = runCLI(t, env, "get", "application", "app-1", "-o", "yaml")
+	if !strings.Contains(stdout, "version: 3\n") || !strings.Contains(stdout, "state: Active\n") {
+		t.Fatalf("yaml output = %q", stdout)
+	}
+
+	_, stdout, _ = runCLI(t, env, "get", "application", "app-1", "-o", "json")
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(stdout), &decoded); err != nil || decoded["id"] != "app-1" {
+		t.Fatalf("json output = %q (%v)", stdout, err)
+	}
+
+	if code, _, _ := runCLI(t, env, "get", "application", "app-1", "-o", "xml"); code != 2 {
+		t.Fatalf("unknown format: exit = %d, want 2", code)
+	}
+}
+
+func TestConfig_ContextsSelectServerAndToken(t *testing.T) {
+	var authz atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz.Store(r.Header.Get("Authorization"))
+		_, _ = fmt.Fprintf(w, appJSON, "Active")
+	}))
+	defer srv.Close()
+	env := map[string]string{"IDPCTL_CONFIG": filepath.Join(t.TempDir(), "idpctl", "config.yaml")}
+
+	if code, _, stderr := runCLI(t, env, "config", "set-context", "prod", "--server", srv.URL, "--token", "prod-jwt"); code != 0 {
+		t.Fatalf("set-context prod: %s", stderr)
+	}
+	if code, _, stderr := runCLI(t, env, "config", "set-context", "dev", "--server", "http://127.0.0.1:1"); code != 0 {
+		t.Fatalf("set-context dev: %s", stderr)
+	}
+	_, stdout, _ := runCLI(t, env, "config", "current-context")
+	if stdout != "prod\n" {
+		t.Fatalf("current-context = %q, want the first context", stdout)
+	}
+
+	if code, _, stderr := runCLI(t, env, "get", "application", "app-1"); code != 0 || authz.Load() != "Bearer prod-jwt" {
+		t.Fatalf("get with prod: exit %d, authz %v: %s", code, authz.Load(), stderr)
+	}
+
+	// --context pisa el contexto actual.
+	if code, _, _ := runCLI(t, env, "get", "application", "app-1", "--context", "dev"); code != 1 {
+		t.Fatalf("get with dev context should fail to connect, exit = %d", code)
+	}
+	if code, _, _ := runCLI(t, env, "get", "application", "app-1", "--context", "missing"); code != 1 {
+		t.Fatalf("unknown context: exit = %d, want 1", code)
+	}
+}
+
+func TestConfig_OrganizationHeader(t *testing.T) {
+	var org atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org.Store(r.Header.Get("X-Organization-ID"))
+		_, _ = fmt.Fprintf(w, appJSON, "Active")
+	}))
+	defer srv.Close()
+	env := map[string]string{"IDPCTL_CONFIG": filepath.Join(t.TempDir(), "config.yaml")}
+
+	if code, _, stderr := runCLI(t, env, "config", "set-context", "bu", "--server", srv.URL, "--organization", "payments"); code != 0 {
+		t.Fatalf("set-context: %s", stderr)
+	}
+	if code, _, stderr := runCLI(t, env, "get", "application", "app-1"); code != 0 || org.Load() != "payments" {
+		t.Fatalf("get with profile organization: exit %d, header %v: %s", code, org.Load(), stderr)
+	}
+
+	// Flag > variable de entorno > perfil.
+	env["IDPCTL_ORGANIZATION"] = "retail"
+	if code, _, _ := runCLI(t, env, "get", "application", "app-1"); code != 0 || org.Load() != "retail" {
+		t.Fatalf("env organization not used, header %v", org.Load())
+	}
+	if code, _, _ := runCLI(t, env, "get", "application", "app-1", "--organization", "lending"); code != 0 || org.Load() != "lending" {
+		t.Fatalf("flag organization not used, header %v", org.Load())
+	}
+}
+
+func TestBatch_ReportsFailures(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"mode":"bestEffort","committed":true,"results":[` +
+			`{"index":0,"command":"createTeam","status":201},` +
+			`{"index":1,"command":"createApplication","status":404,"code":"team_not_found","message":"team not found"}]}`))
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "batch.yaml")
+	writeFile(t, file, "mode: atomic\ncommands:\n  - command: createTeam\n    body: {id: team-1, name: Platform}\n")
+
+	code, stdout, _ := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL}, "batch", "-f", file, "--mode", "bestEffort")
+	if code != 1 {
+		t.Fatalf("exit = %d, want 1 when a command fails", code)
+	}
+	if body["mode"] != "bestEffort" {
+		t.Fatalf("mode = %v, want the flag to override the file", body["mode"])
+	}
+	if !strings.Contains(stdout, "team_not_found") {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
+
+func TestWait_ReturnsWhenStateIsReached(t *testing.T) {
+	var state atomic.Value
+	state.Store("Onboarding")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/queries/applications":
+			_, _ = fmt.Fprintf(w, appJSON, state.Load())
+		case "/watch":
+			if r.URL.Query().Get("type") != "Application" || r.URL.Query().Get("id") != "app-1" {
+				http.Error(w, "bad filter", http.StatusBadRequest)
+				return
+			}
+			stream, err := httpx.NewEventStream(w)
+			if err != nil {
+				return
+			}
+			_ = stream.Comment("heartbeat")
+			time.Sleep(20 * time.Millisecond)
+			state.Store("Active")
+			_ = stream.Send("7", "change", map[string]any{"id": 7, "resourceType": "Application", "resourceId": "app-1", "action": "stateChanged", "state": "Active"})
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	code, stdout, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL},
+		"wait", "application", "app-1", "--for", "Active,Deprecated", "--timeout", "5s", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"state": "Active"`) {
+		t.Fatalf("stdout = %q", stdout)
+	}
+}
+
+func TestWait_TimesOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/queries/applications":
+			_, _ = fmt.Fprintf(w, appJSON, "Onboarding")
+		case "/watch":
+			if _, err := httpx.NewEventStream(w); err != nil {
+				return
+			}
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	code, _, stderr := runCLI(t, map[string]string{"IDPCTL_SERVER": srv.URL},
+		"wait", "application", "app-1", "--for", "Active", "--timeout", "100ms")
+	if code != 1 || !strings.Contains(stderr, `current state "Onboarding"`) {
+		t.Fatalf("exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	stream := ": heartbeat\n\nid: 3\nevent: change\ndata: {\"id\":3}\n\nevent: reset\ndata: {}\n\n"
+	var got []string
+	err := readEvents(strings.NewReader(stream), func(ev sseEvent) error {
+		got = append(got, ev.id+"|"+ev.event+"|"+string(ev.data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readEvents: %v", err)
+	}
+	want := []string{`3|change|{"id":3}`, `|reset|{}`}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// TestCoversEveryRoute falla si se agrega un comando o una query a
+// control-plane-api sin su equivalente en idpctl.
+func TestCoversEveryRoute(t *testing.T) {
+	covered := map[string]bool{
+		"/commands:batch":                        true,
+		"/queries/webhook-deliveries":            true,
+		"/queries/approval-requests":             true,
+		"/queries/application-environments:list": true,
+		"/queries/secret-bindings":               true,
+		"/queries/audit":                         true,
+		"/queries/audit/verify":                  true,
+		"/watch":                                 true,
+	}
+	for _, spec := range commandTable {
+		covered[spec.path] = true
+	}
+	for _, spec := range queryTable {
+		covered[spec.path] = true
+	}
+
+	doc := httpapi.NewServer(&application.Services{}, zap.NewNop()).OpenAPI()
+	for _, path := range doc.SortedPaths() {
+		if !strings.HasPrefix(path, "/commands") && !strings.HasPrefix(path, "/queries") && path != "/watch" {
+			continue
+		}
+		if !covered[path] {
+			t.Errorf("%s has no idpctl command", path)
+		}
+	}
+}
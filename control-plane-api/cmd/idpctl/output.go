@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// column es una columna de la salida en tabla: path es la ruta con puntos
+// del campo en el JSON de la respuesta (metadata.version).
+type column struct {
+	header string
+	path   string
+}
+
+var (
+	organizationColumns = []column{
+		{"ID", "id"}, {"NAME", "name"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	teamQuotaColumns = []column{
+		{"TEAM", "teamId"}, {"APPLICATIONS", "applications.used"}, {"MAX-APPLICATIONS", "applications.limit"}, {"SECRETS", "secrets.used"}, {"MAX-SECRETS", "secrets.limit"},
+		{"MAX-BINDINGS/SECRET", "quota.maxBindingsPerSecret"}, {"MAX-ENVS/APP", "quota.maxEnvironmentsPerApplication"},
+	}
+	applicationColumns = []column{
+		{"ID", "id"}, {"NAME", "name"}, {"TEAM", "teamId"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	environmentColumns = []column{
+		{"ID", "id"}, {"NAME", "name"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	applicationEnvironmentColumns = []column{
+		{"ID", "id"}, {"APPLICATION", "applicationId"}, {"ENVIRONMENT", "environmentId"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	webhookSubscriptionColumns = []column{
+		{"ID", "id"}, {"TEAM", "teamId"}, {"URL", "url"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	approvalColumns = []column{
+		{"ID", "id"}, {"TYPE", "type"}, {"RESOURCE-TYPE", "resourceType"}, {"RESOURCE", "resourceId"}, {"ROLES", "rolesAllowed"}, {"STATE", "state"}, {"EXPIRES", "expiresAt"},
+	}
+	secretBindingColumns = []column{
+		{"ID", "id"}, {"SECRET", "secretId"}, {"TARGET-TYPE", "targetType"}, {"TARGET", "targetId"}, {"STATE", "state"}, {"VERSION", "metadata.version"},
+	}
+	webhookDeliveryColumns = []column{
+		{"ID", "id"}, {"EVENT", "event.id"}, {"RESOURCE", "event.resourceId"}, {"ACTION", "event.action"}, {"STATE", "state"}, {"RETRIES", "retryCount"},
+	}
+	auditColumns = []column{
+		{"SEQ", "seq"}, {"AT", "at"}, {"ACTOR", "actor"}, {"COMMAND", "command"}, {"RESOURCE", "resourceId"}, {"OUTCOME", "outcome"}, {"CODE", "errorCode"},
+	}
+	auditVerifyColumns = []column{
+		{"VALID", "valid"}, {"RECORDS", "records"}, {"HEAD", "head"}, {"BROKEN-AT", "brokenAt"},
+	}
+	batchColumns = []column{
+		{"INDEX", "index"}, {"COMMAND", "command"}, {"STATUS", "status"}, {"CODE", "code"}, {"MESSAGE", "message"},
+	}
+)
+
+// writeTable escribe una tabla alineada con tabwriter.
+func writeTable(w io.Writer, headers []string, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+// printValue escribe v como JSON indentado o YAML.
+func printValue(w io.Writer, format string, v any) error {
+	switch format {
+	case "yaml":
+		// Se pasa por JSON para respetar los tags json de v.
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		generic, err := decodeJSON(raw)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		_, err = w.Write(out)
+		return err //nolint:wrapcheck // escritura a stdout
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+}
+
+// printResponse imprime el body JSON de una query en el formato pedido. En
+// tabla, cada objeto es una fila: el body si es un objeto, sus elementos si
+// es un array o, con rowsKey, los elementos de ese campo.
+func (c *cli) printResponse(format string, raw []byte, cols []column, rowsKey string) error {
+	switch format {
+	case "json":
+		var b bytes.Buffer
+		if err := json.Indent(&b, raw, "", "  "); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		b.WriteByte('\n')
+		_, err := c.stdout.Write(b.Bytes())
+		return err //nolint:wrapcheck // escritura a stdout
+	case "yaml":
+		v, err := decodeJSON(raw)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		_, err = c.stdout.Write(out)
+		return err //nolint:wrapcheck // escritura a stdout
+	}
+
+	v, err := decodeJSON(raw)
+	if err != nil {
+		return err
+	}
+	if obj, ok := v.(map[string]any); ok && rowsKey != "" {
+		v = obj[rowsKey]
+	}
+	var items []any
+	switch t := v.(type) {
+	case []any:
+		items = t
+	case nil:
+	default:
+		items = []any{t}
+	}
+	c.printRows(items, cols)
+	return nil
+}
+
+func (c *cli) printRows(items []any, cols []column) {
+	headers := make([]string, len(cols))
+	for i, col := range cols {
+		headers[i] = col.header
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = cell(lookup(item, col.path))
+		}
+		rows = append(rows, row)
+	}
+	writeTable(c.stdout, headers, rows)
+}
+
+// decodeJSON decodifica raw en valores genéricos; los números quedan como
+// int64 o float64 para que YAML no los imprima entre comillas.
+func decodeJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return normalizeNumbers(v), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = normalizeNumbers(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = normalizeNumbers(e)
+		}
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	}
+	return v
+}
+
+func lookup(v any, path string) any {
+	for _, p := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
+
+func cell(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		// Los time.Time cero que serializa la API no aportan nada.
+		if t == "0001-01-01T00:00:00Z" {
+			return ""
+		}
+		return t
+	case []any:
+		parts := make([]string, len(t))
+		for i, e := range t {
+			parts[i] = cell(e)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(t)
+	}
+}
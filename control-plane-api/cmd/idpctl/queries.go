@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// querySpec describe un recurso que se puede leer por ID.
+type querySpec struct {
+	path    string
+	columns []column
+	// resourceType es el tipo en los ChangeEvent de /watch.
+	resourceType string
+}
+
+var queryTable = map[string]querySpec{
+	"organization":            {path: "/queries/organizations", columns: organizationColumns, resourceType: "Organization"},
+	"team-quota":              {path: "/queries/team-quotas", columns: teamQuotaColumns, resourceType: "Team"},
+	"application":             {path: "/queries/applications", columns: applicationColumns, resourceType: "Application"},
+	"environment":             {path: "/queries/environments", columns: environmentColumns, resourceType: "Environment"},
+	"application-environment": {path: "/queries/application-environments", columns: applicationEnvironmentColumns, resourceType: "ApplicationEnvironment"},
+	"webhook-subscription":    {path: "/queries/webhook-subscriptions", columns: webhookSubscriptionColumns, resourceType: "WebhookSubscription"},
+	"approval":                {path: "/queries/approvals", columns: approvalColumns, resourceType: "Approval"},
+}
+
+func findQuery(resource string) (querySpec, error) {
+	spec, ok := queryTable[canonicalResource(resource)]
+	if !ok {
+		names := make([]string, 0, len(queryTable))
+		for name := range queryTable {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return querySpec{}, usageError(fmt.Sprintf("unknown resource %q (use %s)", resource, strings.Join(names, ", ")))
+	}
+	return spec, nil
+}
+
+// runGet implementa "idpctl get <recurso> ID".
+func (c *cli) runGet(ctx context.Context, args []string) error {
+	var g globalOptions
+	fs := newFlagSet("get")
+	g.register(fs)
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		return usageError("usage: idpctl get RESOURCE ID")
+	}
+	spec, err := findQuery(pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+	_, raw, err := client.call(ctx, http.MethodGet, spec.path, url.Values{"id": {pos[1]}}, nil, requestOptions{})
+	if err != nil {
+		return err
+	}
+	return c.printResponse(g.output, raw, spec.columns, "")
+}
+
+// listOptions son los flags de "idpctl list"; cada lista usa los suyos.
+type listOptions struct {
+	subscription string
+	state        string
+	roles        listFlag
+	application  string
+	targetType   string
+	target       string
+}
+
+// listQuery resuelve la query de la lista kind con los flags de opts.
+func listQuery(kind string, opts *listOptions) (string, []column, url.Values, error) {
+	q := url.Values{}
+	if opts.state != "" {
+		q.Set("state", opts.state)
+	}
+	switch kind {
+	case "webhook-deliveries", "deliveries":
+		if opts.subscription == "" {
+			return "", nil, nil, usageError("--subscription is required\nusage: idpctl list webhook-deliveries --subscription ID [--state S]")
+		}
+		q.Set("subscriptionId", opts.subscription)
+		return "/queries/webhook-deliveries", webhookDeliveryColumns, q, nil
+	case "approvals":
+		for _, r := range opts.roles {
+			q.Add("role", r)
+		}
+		return "/queries/approval-requests", approvalColumns, q, nil
+	case "application-environments":
+		if opts.application == "" {
+			return "", nil, nil, usageError("--application is required\nusage: idpctl list application-environments --application ID")
+		}
+		return "/queries/application-environments:list", applicationEnvironmentColumns, url.Values{"applicationId": {opts.application}}, nil
+	case "secret-bindings":
+		if opts.target == "" {
+			return "", nil, nil, usageError("--target is required\nusage: idpctl list secret-bindings --target ID [--target-type T]")
+		}
+		return "/queries/secret-bindings", secretBindingColumns, url.Values{"targetType": {opts.targetType}, "targetId": {opts.target}}, nil
+	default:
+		return "", nil, nil, usageError(fmt.Sprintf("unknown list %q (use webhook-deliveries, approvals, application-environments or secret-bindings)", kind))
+	}
+}
+
+// runList implementa "idpctl list webhook-deliveries|approvals|application-environments|secret-bindings".
+func (c *cli) runList(ctx context.Context, args []string) error {
+	var (
+		g    globalOptions
+		opts listOptions
+	)
+	fs := newFlagSet("list")
+	g.register(fs)
+	fs.StringVar(&opts.subscription, "subscription", "", "WebhookSubscription de las entregas")
+	fs.StringVar(&opts.state, "state", "", "filtrar por estado")
+	fs.Var(&opts.roles, "role", "rol aprobador (repetible); por defecto, los del principal")
+	fs.StringVar(&opts.application, "application", "", "Application de los environments")
+	fs.StringVar(&opts.targetType, "target-type", "ApplicationEnvironment", "tipo del destino de los bindings")
+	fs.StringVar(&opts.target, "target", "", "ID del destino de los bindings")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError("usage: idpctl list webhook-deliveries|approvals|application-environments|secret-bindings [flags]")
+	}
+	path, cols, q, err := listQuery(pos[0], &opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+	_, raw, err := client.call(ctx, http.MethodGet, path, q, nil, requestOptions{})
+	if err != nil {
+		return err
+	}
+	return c.printResponse(g.output, raw, cols, "")
+}
+
+// runAudit implementa "idpctl audit [verify]".
+func (c *cli) runAudit(ctx context.Context, args []string) error {
+	var g globalOptions
+	fs := newFlagSet("audit")
+	g.register(fs)
+	filters := map[string]*string{
+		"actor":        fs.String("actor", "", "sujeto que ejecutó el comando"),
+		"resourceType": fs.String("resource-type", "", "tipo de recurso"),
+		"resourceId":   fs.String("resource-id", "", "ID del recurso"),
+		"command":      fs.String("command", "", "operationId del comando"),
+		"from":         fs.String("from", "", "desde (RFC 3339)"),
+		"to":           fs.String("to", "", "hasta (RFC 3339)"),
+	}
+	afterSeq := fs.Int64("after-seq", 0, "sólo registros posteriores a esta secuencia")
+	limit := fs.Int("limit", 0, "máximo de registros")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	path, cols := "/queries/audit", auditColumns
+	q := url.Values{}
+	switch {
+	case len(pos) == 1 && pos[0] == "verify":
+		path, cols = "/queries/audit/verify", auditVerifyColumns
+	case len(pos) > 0:
+		return usageError("usage: idpctl audit [verify] [flags]")
+	default:
+		for name, v := range filters {
+			if *v != "" {
+				q.Set(name, *v)
+			}
+		}
+		if *afterSeq > 0 {
+			q.Set("afterSeq", strconv.FormatInt(*afterSeq, 10))
+		}
+		if *limit > 0 {
+			q.Set("limit", strconv.Itoa(*limit))
+		}
+	}
+
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+	_, raw, err := client.call(ctx, http.MethodGet, path, q, nil, requestOptions{})
+	if err != nil {
+		return err
+	}
+	return c.printResponse(g.output, raw, cols, "")
+}
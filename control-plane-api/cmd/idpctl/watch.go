@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// reconnectDelay es la espera antes de reabrir un stream de /watch cortado.
+var reconnectDelay = time.Second
+
+// sseEvent es un evento del stream de /watch.
+type sseEvent struct {
+	id    string
+	event string
+	data  []byte
+}
+
+// changeEvent son los campos de domain.ChangeEvent que usa idpctl.
+type changeEvent struct {
+	ID           int64     `json:"id"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	Action       string    `json:"action"`
+	State        string    `json:"state"`
+	Version      int64     `json:"version"`
+	By           string    `json:"by"`
+	At           time.Time `json:"at"`
+}
+
+// readEvents parsea el stream SSE de r y llama a fn por cada evento. Los
+// comentarios (heartbeats) se descartan. Corta con el primer error de fn.
+func readEvents(r io.Reader, fn func(sseEvent) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	var (
+		ev   sseEvent
+		data [][]byte
+	)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			if len(data) > 0 {
+				ev.data = bytes.Join(data, []byte("\n"))
+				if err := fn(ev); err != nil {
+					return err
+				}
+			}
+			ev, data = sseEvent{}, nil
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
+		name, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(name) {
+		case "id":
+			ev.id = string(value)
+		case "event":
+			ev.event = string(value)
+		case "data":
+			data = append(data, bytes.Clone(value))
+		}
+	}
+	return sc.Err() //nolint:wrapcheck // el caller decide si reconectar
+}
+
+// runWatch implementa "idpctl watch": imprime los cambios a medida que
+// llegan y reconecta con Last-Event-ID si el stream se corta.
+func (c *cli) runWatch(ctx context.Context, args []string) error {
+	var g globalOptions
+	fs := newFlagSet("watch")
+	g.register(fs)
+	resourceType := fs.String("type", "", "tipo de recurso (p.ej. ApplicationEnvironment)")
+	id := fs.String("id", "", "ID del recurso")
+	team := fs.String("team", "", "team dueño del recurso")
+	app := fs.String("application", "", "application a la que pertenece el recurso")
+	since := fs.String("since", "", "reanudar después de este id de evento")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageError("usage: idpctl watch [--type T] [--id ID] [--team ID] [--application ID] [--since EVENT_ID]")
+	}
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	for name, v := range map[string]string{"type": *resourceType, "id": *id, "teamId": *team, "applicationId": *app} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	lastID := *since
+	return c.follow(ctx, client, q, &lastID, nil, func(ev sseEvent) error {
+		switch ev.event {
+		case "reset":
+			fmt.Fprintln(c.stderr, "stream reset: events expired, re-read the current state with idpctl get")
+			return nil
+		case "change":
+			return c.printChange(g.output, ev.data)
+		}
+		return nil
+	})
+}
+
+// follow consume /watch hasta que fn devuelva un error o se cancele ctx,
+// reabriendo el stream desde *lastID cada vez que se corta. onOpen, si no es
+// nil, se llama cada vez que el stream queda abierto. Un error de la API
+// (401, 403, 400) no se reintenta.
+func (c *cli) follow(ctx context.Context, client *apiClient, q url.Values, lastID *string, onOpen func() error, fn func(sseEvent) error) error {
+	for {
+		resp, err := client.stream(ctx, "/watch", q, *lastID)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		if err == nil && onOpen != nil {
+			if err = onOpen(); err != nil {
+				_ = resp.Body.Close()
+				return err
+			}
+		}
+		if err == nil {
+			err = readEvents(resp.Body, func(ev sseEvent) error {
+				if ev.id != "" {
+					*lastID = ev.id
+				}
+				return fn(ev)
+			})
+			_ = resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck // lo interpreta el caller
+		}
+		if err != nil && !isStreamError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // lo interpreta el caller
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// isStreamError distingue los cortes de la conexión (se reconecta) de los
+// errores de fn (se devuelven).
+func isStreamError(err error) bool {
+	var fnErr *callbackError
+	return !errors.As(err, &fnErr)
+}
+
+// callbackError envuelve los errores de los callbacks de follow.
+type callbackError struct{ err error }
+
+func (e *callbackError) Error() string { return e.err.Error() }
+func (e *callbackError) Unwrap() error { return e.err }
+
+func (c *cli) printChange(format string, data []byte) error {
+	switch format {
+	case "json":
+		// Un evento por línea, para poder procesar el stream con jq.
+		var b bytes.Buffer
+		if err := json.Compact(&b, data); err != nil {
+			return &callbackError{fmt.Errorf("decode event: %w", err)}
+		}
+		fmt.Fprintln(c.stdout, b.String())
+		return nil
+	case "yaml":
+		fmt.Fprintln(c.stdout, "---")
+		if err := c.printResponse(format, data, nil, ""); err != nil {
+			return &callbackError{err}
+		}
+		return nil
+	}
+	var ev changeEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return &callbackError{fmt.Errorf("decode event: %w", err)}
+	}
+	fmt.Fprintf(c.stdout, "%s  #%d  %s/%s %s state=%s version=%d by=%s\n",
+		ev.At.Format(time.RFC3339), ev.ID, ev.ResourceType, ev.ResourceID, ev.Action, ev.State, ev.Version, ev.By)
+	return nil
+}
+
+// errReached corta el stream cuando el recurso llegó al estado esperado.
+var errReached = &callbackError{errors.New("state reached")}
+
+// runWait implementa "idpctl wait <recurso> ID --for STATE": bloquea hasta
+// que el recurso llegue a alguno de los estados y lo imprime. Se suscribe a
+// /watch antes de leer el estado actual para no perder transiciones.
+func (c *cli) runWait(ctx context.Context, args []string) error {
+	var g globalOptions
+	fs := newFlagSet("wait")
+	g.register(fs)
+	states := &listFlag{}
+	fs.Var(states, "for", "estado esperado (repetible o separado por comas)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "tiempo máximo de espera")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || len(*states) == 0 {
+		return usageError("usage: idpctl wait RESOURCE ID --for STATE[,STATE...] [--timeout 5m]")
+	}
+	spec, err := findQuery(pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := c.connect(&g)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	var (
+		current string
+		raw     []byte
+	)
+	check := func() (bool, error) {
+		_, body, err := client.call(ctx, http.MethodGet, spec.path, url.Values{"id": {pos[1]}}, nil, requestOptions{})
+		if err != nil {
+			return false, err
+		}
+		var res struct {
+			State string `json:"state"`
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			return false, fmt.Errorf("decode response: %w", err)
+		}
+		current, raw = res.State, body
+		return slices.Contains(*states, current), nil
+	}
+
+	q := url.Values{"type": {spec.resourceType}, "id": {pos[1]}}
+	lastID := ""
+	// El estado se relee al abrir cada stream y ante un reset: son los
+	// momentos en que pudo haber transiciones sin evento.
+	onOpen := func() error { return reachedOrErr(check()) }
+	err = c.follow(ctx, client, q, &lastID, onOpen, func(ev sseEvent) error {
+		switch ev.event {
+		case "reset":
+			return reachedOrErr(check())
+		case "change":
+		default:
+			return nil
+		}
+		var change changeEvent
+		if err := json.Unmarshal(ev.data, &change); err != nil {
+			return &callbackError{fmt.Errorf("decode event: %w", err)}
+		}
+		if !slices.Contains(*states, change.State) {
+			current = change.State
+			return nil
+		}
+		return reachedOrErr(check())
+	})
+	switch {
+	case errors.Is(err, errReached):
+		return c.printResponse(g.output, raw, spec.columns, "")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s waiting for %s/%s to reach %s (current state %q)",
+			*timeout, pos[0], pos[1], strings.Join(*states, ","), current)
+	}
+	return err
+}
+
+func reachedOrErr(ok bool, err error) error {
+	switch {
+	case err != nil:
+		return &callbackError{err}
+	case ok:
+		return errReached
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+// Package client es el SDK Go de control-plane-api. Cubre todos los
+// comandos y queries de la API HTTP con tipos propios (requests.go,
+// types.go), devuelve errores *Error que conservan el Code y el Kind del
+// problem+json, reintenta las fallas transitorias, inyecta las credenciales
+// y propaga la traza.
+//
+//	c := client.New(client.Options{BaseURL: "http://control-plane-api:8080", Token: jwt})
+//	err := c.CreateApplication(ctx, client.CreateApplicationRequest{ID: "app-1", Name: "billing", TeamID: "team-1"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrganizationHeader indica la organización en la que opera una request.
+const OrganizationHeader = httpx.OrganizationHeader
+
+const (
+	defaultTimeout        = 10 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// Options configura un Client.
+type Options struct {
+	// BaseURL es la URL de control-plane-api (p.ej. http://control-plane-api:8080).
+	BaseURL string
+	// HTTPClient por defecto usa un transport instrumentado con otelhttp, que
+	// propaga el traceparent.
+	HTTPClient *http.Client
+	// Timeout acota cada intento de un comando o query (por defecto 10s).
+	// No aplica a Watch.
+	Timeout time.Duration
+
+	// Token es el bearer JWT con el que se llama a la API. TokenSource, si
+	// no es nil, tiene prioridad y se consulta en cada request, para tokens
+	// que se renuevan.
+	Token       string
+	TokenSource func(ctx context.Context) (string, error)
+	// InternalToken autentica como servicio interno (X-Internal-Token).
+	InternalToken string
+	// Organization es la organización en la que operan las llamadas
+	// (header X-Organization-ID). La del contexto (httpx.WithOrganization)
+	// tiene prioridad; sin ninguna, se usa la del token o la default.
+	Organization string
+
+	Retry RetryPolicy
+}
+
+// RetryPolicy controla los reintentos ante fallas transitorias: errores de
+// red y respuestas 429, 502, 503, 504, idempotency_key_in_flight o
+// concurrent_modification. Las queries se reintentan siempre; los comandos,
+// sólo si llevan Idempotency-Key, para que el servidor no los aplique dos
+// veces, o si el servidor respondió concurrent_modification, que no aplica
+// nada.
+type RetryPolicy struct {
+	// MaxAttempts cuenta el primer intento: 0 usa 3 y 1 desactiva los
+	// reintentos.
+	MaxAttempts int
+	// InitialBackoff se duplica en cada intento hasta MaxBackoff. Un
+	// Retry-After del servidor tiene prioridad.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Client llama a control-plane-api. Es seguro para uso concurrente.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	opts    Options
+}
+
+// New crea un Client con opts, completando los valores por defecto.
+func New(opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.Retry.MaxAttempts <= 0 {
+		opts.Retry.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Retry.InitialBackoff <= 0 {
+		opts.Retry.InitialBackoff = defaultInitialBackoff
+	}
+	if opts.Retry.MaxBackoff <= 0 {
+		opts.Retry.MaxBackoff = defaultMaxBackoff
+	}
+	return &Client{baseURL: strings.TrimRight(opts.BaseURL, "/"), http: opts.HTTPClient, opts: opts}
+}
+
+// CallOption ajusta una llamada puntual.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	ifMatch        int64
+	idempotencyKey string
+	dryRun         bool
+	dryRunResult   *DryRunResult
+}
+
+// WithIfMatch condiciona el comando a la versión actual del recurso
+// (If-Match); si no coincide, la API responde precondition_failed.
+func WithIfMatch(version int64) CallOption {
+	return func(o *callOptions) { o.ifMatch = version }
+}
+
+// WithIdempotencyKey fija el Idempotency-Key del comando. Sin esta opción se
+// usa la key del contexto (httpx.WithIdempotencyKey), si la hay.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) { o.idempotencyKey = key }
+}
+
+// WithDryRun valida el comando sin aplicarlo (?dryRun=true). Si out no es
+// nil recibe el recurso como habría quedado; en RunBatch el resultado de
+// cada comando va en BatchResponse. Un comando que fallaría devuelve el
+// mismo error que sin dry-run.
+func WithDryRun(out *DryRunResult) CallOption {
+	return func(o *callOptions) { o.dryRun, o.dryRunResult = true, out }
+}
+
+// command hace POST de body a path. operation es el operationId y nombra
+// el span.
+func (c *Client) command(ctx context.Context, operation, path string, body any, opts []CallOption) error {
+	return c.post(ctx, operation, path, body, opts, nil)
+}
+
+// post hace POST de body a path y, si out no es nil, decodifica la
+// respuesta en out.
+func (c *Client) post(ctx context.Context, operation, path string, body any, opts []CallOption, out any) error {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal %s request: %w", operation, err)
+	}
+	var q url.Values
+	if o.dryRun {
+		q = url.Values{"dryRun": {"true"}}
+		if out == nil && o.dryRunResult != nil {
+			out = o.dryRunResult
+		}
+	}
+	return c.do(ctx, operation, http.MethodPost, path, q, raw, o, out)
+}
+
+// query hace GET de path y decodifica la respuesta en out.
+func (c *Client) query(ctx context.Context, operation, path string, q url.Values, out any) error {
+	return c.do(ctx, operation, http.MethodGet, path, q, nil, callOptions{}, out)
+}
+
+func (c *Client) do(ctx context.Context, operation, method, path string, q url.Values, body []byte, o callOptions, out any) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "controlplane.client."+operation)
+	span.SetAttributes(attribute.String("http.method", method), attribute.String("http.route", path))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if o.idempotencyKey == "" && method == http.MethodPost {
+		o.idempotencyKey, _ = httpx.IdempotencyKeyFromContext(ctx)
+	}
+	// Un dry-run no aplica nada, así que reintentarlo es seguro.
+	retryable := method == http.MethodGet || o.idempotencyKey != "" || o.dryRun
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, method, path, q, body, o, out)
+		if err == nil {
+			return nil
+		}
+		if !(retryable || concurrentModification(err)) || attempt >= c.opts.Retry.MaxAttempts || !transient(err) || ctx.Err() != nil {
+			return err
+		}
+		span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.backoff(attempt, resp)):
+		}
+	}
+}
+
+// attempt hace un intento de la request. Devuelve la respuesta (ya
+// cerrada) para que el caller lea Retry-After.
+func (c *Client) attempt(ctx context.Context, method, path string, q url.Values, body []byte, o callOptions, out any) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, method, path, q, body)
+	if err != nil {
+		return nil, err
+	}
+	if o.ifMatch > 0 {
+		req.Header.Set("If-Match", httpx.VersionETag(o.ifMatch))
+	}
+	if o.idempotencyKey != "" {
+		req.Header.Set(httpx.IdempotencyKeyHeader, o.idempotencyKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, newError(resp)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, q url.Values, body []byte) (*http.Request, error) {
+	u := c.baseURL + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("create %s %s request: %w", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	token := c.opts.Token
+	if c.opts.TokenSource != nil {
+		if token, err = c.opts.TokenSource(ctx); err != nil {
+			return nil, fmt.Errorf("get control-plane-api token: %w", err)
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.opts.InternalToken != "" {
+		req.Header.Set(auth.InternalTokenHeader, c.opts.InternalToken)
+	}
+	if org, ok := httpx.OrganizationFromContext(ctx); ok {
+		req.Header.Set(OrganizationHeader, org)
+	} else if c.opts.Organization != "" {
+		req.Header.Set(OrganizationHeader, c.opts.Organization)
+	}
+	return req, nil
+}
+
+// transient indica si err puede resolverse reintentando.
+func transient(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		// Error de red o timeout del intento (http.Client.Do devuelve
+		// *url.Error); no los de armado de la request o decodificación.
+		var netErr *url.Error
+		return errors.As(err, &netErr)
+	}
+	switch apiErr.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return apiErr.Code == "idempotency_key_in_flight" || apiErr.Code == "concurrent_modification"
+}
+
+// concurrentModification indica si el servidor rechazó el comando porque
+// otro escritor guardó el recurso en el medio; el comando no se aplicó.
+func concurrentModification(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == "concurrent_modification"
+}
+
+// backoff devuelve la espera antes del intento attempt+1: el Retry-After de
+// resp si lo trae, o un backoff exponencial.
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, c.opts.Retry.MaxBackoff)
+		}
+	}
+	d := c.opts.Retry.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > c.opts.Retry.MaxBackoff {
+		return c.opts.Retry.MaxBackoff
+	}
+	return d
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// fastRetry evita esperar el backoff real en los tests.
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestCommand_SendsAuthAndCallHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := New(Options{
+		BaseURL:       srv.URL,
+		Token:         "static",
+		TokenSource:   func(context.Context) (string, error) { return "fresh", nil },
+		InternalToken: "internal",
+		Organization:  "acme",
+	})
+	ctx := httpx.WithIdempotencyKey(context.Background(), "wf-1/3")
+	if err := c.ApproveApplication(ctx, ApproveApplicationRequest{ID: "app-1"}, WithIfMatch(4)); err != nil {
+		t.Fatalf("ApproveApplication: %v", err)
+	}
+	if got.Get("Authorization") != "Bearer fresh" || got.Get(auth.InternalTokenHeader) != "internal" || got.Get(OrganizationHeader) != "acme" {
+		t.Fatalf("unexpected auth headers: %v", got)
+	}
+	if got.Get("If-Match") != httpx.VersionETag(4) || got.Get(httpx.IdempotencyKeyHeader) != "wf-1/3" {
+		t.Fatalf("unexpected call headers: %v", got)
+	}
+
+	if err := c.ApproveApplication(ctx, ApproveApplicationRequest{ID: "app-1"}, WithIdempotencyKey("explicit")); err != nil {
+		t.Fatalf("ApproveApplication: %v", err)
+	}
+	if got.Get(httpx.IdempotencyKeyHeader) != "explicit" || got.Get("If-Match") != "" {
+		t.Fatalf("unexpected call headers: %v", got)
+	}
+
+	// La organización del contexto pisa la de Options.
+	if err := c.ApproveApplication(httpx.WithOrganization(ctx, "globex"), ApproveApplicationRequest{ID: "app-1"}); err != nil {
+		t.Fatalf("ApproveApplication: %v", err)
+	}
+	if got.Get(OrganizationHeader) != "globex" {
+		t.Fatalf("expected the context organization, got %v", got)
+	}
+}
+
+func TestError_PreservesProblemCodeAndKind(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = io.WriteString(w, `{"status":409,"title":"Conflict","detail":"application already exists","code":"application_already_exists","kind":"conflict","traceId":"t-1"}`)
+	}))
+	defer srv.Close()
+
+	err := New(Options{BaseURL: srv.URL}).CreateApplication(context.Background(), CreateApplicationRequest{ID: "app-1"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %T: %v", err, err)
+	}
+	if apiErr.Status != http.StatusConflict || apiErr.Kind != perrors.KindConflict || apiErr.TraceID != "t-1" {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+	if perrors.Code(err) != "application_already_exists" || !perrors.IsKind(err, perrors.KindConflict) {
+		t.Fatalf("perrors helpers do not see the API error: code=%q kind=%q", perrors.Code(err), perrors.KindOf(err))
+	}
+}
+
+func TestRetry_QueriesAndIdempotentCommands(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, `{"id":"app-1","state":"Active"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	c := New(Options{BaseURL: srv.URL, Retry: fastRetry})
+	ctx := context.Background()
+
+	app, err := c.GetApplication(ctx, "app-1")
+	if err != nil || app.State != "Active" || calls.Load() != 2 {
+		t.Fatalf("GetApplication = %+v, %v after %d calls", app, err, calls.Load())
+	}
+
+	calls.Store(0)
+	if err := c.ActivateApplication(ctx, ActivateApplicationRequest{ID: "app-1"}); err == nil || calls.Load() != 1 {
+		t.Fatalf("command without Idempotency-Key: %v after %d calls; want no retry", err, calls.Load())
+	}
+
+	calls.Store(0)
+	if err := c.ActivateApplication(ctx, ActivateApplicationRequest{ID: "app-1"}, WithIdempotencyKey("k")); err != nil || calls.Load() != 2 {
+		t.Fatalf("command with key: %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestRetry_CommandsOnConcurrentModification(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `{"status":409,"code":"concurrent_modification","kind":"conflict"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	err := New(Options{BaseURL: srv.URL, Retry: fastRetry}).ActivateApplication(context.Background(), ActivateApplicationRequest{ID: "app-1"})
+	if err != nil || calls.Load() != 2 {
+		t.Fatalf("ActivateApplication = %v after %d calls; want a retry", err, calls.Load())
+	}
+}
+
+func TestRetry_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := New(Options{BaseURL: srv.URL, Retry: fastRetry}).GetApplication(context.Background(), "app-1")
+	if err == nil || calls.Load() != 1 {
+		t.Fatalf("GetApplication = %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestWatchChanges_ParsesEventsAndResets(t *testing.T) {
+	var lastEventID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventID = r.Header.Get("Last-Event-ID")
+		if r.URL.Query().Get("applicationId") != "app-1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		stream, err := httpx.NewEventStream(w)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = stream.Send("", "reset", map[string]string{"reason": "events_expired"})
+		_ = stream.Comment("heartbeat")
+		_ = stream.Send("8", "change", ChangeEvent{ID: 8, ResourceType: "Application", ResourceID: "app-1", Action: "updated", State: "Active"})
+	}))
+	defer srv.Close()
+
+	s, err := New(Options{BaseURL: srv.URL}).WatchChanges(context.Background(), WatchFilter{ApplicationID: "app-1", LastEventID: "3"})
+	if err != nil {
+		t.Fatalf("WatchChanges: %v", err)
+	}
+	defer s.Close()
+	if lastEventID != "3" {
+		t.Fatalf("Last-Event-ID = %q", lastEventID)
+	}
+
+	ev, err := s.Next()
+	if err != nil || !ev.Reset {
+		t.Fatalf("first event = %+v, %v; want reset", ev, err)
+	}
+	ev, err = s.Next()
+	if err != nil || ev.ID != "8" || ev.Change.State != "Active" || s.LastEventID() != "8" {
+		t.Fatalf("second event = %+v, %v", ev, err)
+	}
+	if _, err := s.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF at end of stream, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import "context"
+
+// Comandos. Cada método hace POST de req al endpoint del operationId del
+// mismo nombre y devuelve nil si la API respondió 2xx.
+
+func (c *Client) CreateOrganization(ctx context.Context, req CreateOrganizationRequest, opts ...CallOption) error {
+	return c.command(ctx, "createOrganization", "/commands/organizations", req, opts)
+}
+
+func (c *Client) CreateTeam(ctx context.Context, req CreateTeamRequest, opts ...CallOption) error {
+	return c.command(ctx, "createTeam", "/commands/teams", req, opts)
+}
+
+func (c *Client) SetTeamQuota(ctx context.Context, req SetTeamQuotaRequest, opts ...CallOption) error {
+	return c.command(ctx, "setTeamQuota", "/commands/teams/quota", req, opts)
+}
+
+func (c *Client) CreateApplication(ctx context.Context, req CreateApplicationRequest, opts ...CallOption) error {
+	return c.command(ctx, "createApplication", "/commands/applications", req, opts)
+}
+
+func (c *Client) ApproveApplication(ctx context.Context, req ApproveApplicationRequest, opts ...CallOption) error {
+	return c.command(ctx, "approveApplication", "/commands/applications/approve", req, opts)
+}
+
+// StartApplicationOnboarding es de uso interno (workflow-engine).
+func (c *Client) StartApplicationOnboarding(ctx context.Context, req StartApplicationOnboardingRequest, opts ...CallOption) error {
+	return c.command(ctx, "startApplicationOnboarding", "/commands/applications/start-onboarding", req, opts)
+}
+
+// ActivateApplication es de uso interno (workflow-engine).
+func (c *Client) ActivateApplication(ctx context.Context, req ActivateApplicationRequest, opts ...CallOption) error {
+	return c.command(ctx, "activateApplication", "/commands/applications/activate", req, opts)
+}
+
+func (c *Client) DeprecateApplication(ctx context.Context, req DeprecateApplicationRequest, opts ...CallOption) error {
+	return c.command(ctx, "deprecateApplication", "/commands/applications/deprecate", req, opts)
+}
+
+// StartApplicationDecommissioning es de uso interno (workflow-engine).
+func (c *Client) StartApplicationDecommissioning(ctx context.Context, req StartApplicationDecommissioningRequest, opts ...CallOption) error {
+	return c.command(ctx, "startApplicationDecommissioning", "/commands/applications/start-decommissioning", req, opts)
+}
+
+// ArchiveApplication es de uso interno (workflow-engine).
+func (c *Client) ArchiveApplication(ctx context.Context, req ArchiveApplicationRequest, opts ...CallOption) error {
+	return c.command(ctx, "archiveApplication", "/commands/applications/archive", req, opts)
+}
+
+func (c *Client) CreateEnvironment(ctx context.Context, req CreateEnvironmentRequest, opts ...CallOption) error {
+	return c.command(ctx, "createEnvironment", "/commands/environments", req, opts)
+}
+
+func (c *Client) DeclareApplicationEnvironment(ctx context.Context, req DeclareApplicationEnvironmentRequest, opts ...CallOption) error {
+	return c.command(ctx, "declareApplicationEnvironment", "/commands/application-environments", req, opts)
+}
+
+// CompleteApplicationEnvironmentProvisioning es de uso interno (workflow-engine).
+func (c *Client) CompleteApplicationEnvironmentProvisioning(ctx context.Context, req CompleteApplicationEnvironmentProvisioningRequest, opts ...CallOption) error {
+	return c.command(ctx, "completeApplicationEnvironmentProvisioning", "/commands/application-environments/complete-provisioning", req, opts)
+}
+
+// DecommissionApplicationEnvironment es de uso interno (workflow-engine).
+func (c *Client) DecommissionApplicationEnvironment(ctx context.Context, req DecommissionApplicationEnvironmentRequest, opts ...CallOption) error {
+	return c.command(ctx, "decommissionApplicationEnvironment", "/commands/application-environments/decommission", req, opts)
+}
+
+func (c *Client) CreateSecret(ctx context.Context, req CreateSecretRequest, opts ...CallOption) error {
+	return c.command(ctx, "createSecret", "/commands/secrets", req, opts)
+}
+
+func (c *Client) RevokeSecretBinding(ctx context.Context, req RevokeSecretBindingRequest, opts ...CallOption) error {
+	return c.command(ctx, "revokeSecretBinding", "/commands/secret-bindings/revoke", req, opts)
+}
+
+func (c *Client) StartSecretRotation(ctx context.Context, req StartSecretRotationRequest, opts ...CallOption) error {
+	return c.command(ctx, "startSecretRotation", "/commands/secrets/start-rotation", req, opts)
+}
+
+// CompleteSecretRotation es de uso interno (workflow-engine).
+func (c *Client) CompleteSecretRotation(ctx context.Context, req CompleteSecretRotationRequest, opts ...CallOption) error {
+	return c.command(ctx, "completeSecretRotation", "/commands/secrets/complete-rotation", req, opts)
+}
+
+func (c *Client) DeclareSecretBinding(ctx context.Context, req DeclareSecretBindingRequest, opts ...CallOption) error {
+	return c.command(ctx, "declareSecretBinding", "/commands/secret-bindings", req, opts)
+}
+
+func (c *Client) DeclareCodeRepository(ctx context.Context, req DeclareCodeRepositoryRequest, opts ...CallOption) error {
+	return c.command(ctx, "declareCodeRepository", "/commands/code-repositories", req, opts)
+}
+
+func (c *Client) DeclareDeploymentRepository(ctx context.Context, req DeclareDeploymentRepositoryRequest, opts ...CallOption) error {
+	return c.command(ctx, "declareDeploymentRepository", "/commands/deployment-repositories", req, opts)
+}
+
+func (c *Client) DeclareGitOpsIntegration(ctx context.Context, req DeclareGitOpsIntegrationRequest, opts ...CallOption) error {
+	return c.command(ctx, "declareGitOpsIntegration", "/commands/gitops-integrations", req, opts)
+}
+
+func (c *Client) CreateWebhookSubscription(ctx context.Context, req CreateWebhookSubscriptionRequest, opts ...CallOption) error {
+	return c.command(ctx, "createWebhookSubscription", "/commands/webhook-subscriptions", req, opts)
+}
+
+func (c *Client) DisableWebhookSubscription(ctx context.Context, req DisableWebhookSubscriptionRequest, opts ...CallOption) error {
+	return c.command(ctx, "disableWebhookSubscription", "/commands/webhook-subscriptions/disable", req, opts)
+}
+
+func (c *Client) RedeliverWebhookDelivery(ctx context.Context, req RedeliverWebhookDeliveryRequest, opts ...CallOption) error {
+	return c.command(ctx, "redeliverWebhookDelivery", "/commands/webhook-deliveries/redeliver", req, opts)
+}
+
+func (c *Client) CreateApprovalRequest(ctx context.Context, req CreateApprovalRequestRequest, opts ...CallOption) error {
+	return c.command(ctx, "createApprovalRequest", "/commands/approvals", req, opts)
+}
+
+func (c *Client) ApproveApproval(ctx context.Context, req DecideApprovalRequest, opts ...CallOption) error {
+	return c.command(ctx, "approveApproval", "/commands/approvals/approve", req, opts)
+}
+
+func (c *Client) RejectApproval(ctx context.Context, req DecideApprovalRequest, opts ...CallOption) error {
+	return c.command(ctx, "rejectApproval", "/commands/approvals/reject", req, opts)
+}
+
+// RunBatch ejecuta req con POST /commands:batch. La API responde 200 aunque
+// fallen comandos: el resultado de cada uno está en BatchResponse.Results.
+func (c *Client) RunBatch(ctx context.Context, req BatchRequest, opts ...CallOption) (*BatchResponse, error) {
+	var out BatchResponse
+	if err := c.post(ctx, "runBatch", "/commands:batch", req, opts, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,208 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/httpapi"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/audit"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"go.uber.org/zap"
+)
+
+func newAPI(t *testing.T) *client.Client {
+	t.Helper()
+	services := &application.Services{
+		Organizations:           memoryrepo.NewOrganizationRepository(),
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		Environments:            memoryrepo.NewEnvironmentRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		Secrets:                 memoryrepo.NewSecretRepository(),
+		SecretBindings:          memoryrepo.NewSecretBindingRepository(),
+		CodeRepositories:        memoryrepo.NewCodeRepositoryRepository(),
+		DeploymentRepositories:  memoryrepo.NewDeploymentRepositoryRepository(),
+		GitOpsIntegrations:      memoryrepo.NewGitOpsIntegrationRepository(),
+		WebhookSubscriptions:    memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:       memoryrepo.NewWebhookDeliveryRepository(),
+		Approvals:               memoryrepo.NewApprovalRepository(),
+		Changes:                 memoryrepo.NewChangeFeed(64),
+		Audit:                   audit.NewMemoryStore(),
+	}
+	ts := httptest.NewServer(httpapi.NewServer(services, zap.NewNop()).Routes())
+	t.Cleanup(ts.Close)
+	return client.New(client.Options{BaseURL: ts.URL, Retry: client.RetryPolicy{MaxAttempts: 1}})
+}
+
+func TestClient_AgainstServer(t *testing.T) {
+	c := newAPI(t)
+	ctx := context.Background()
+
+	if err := c.CreateTeam(ctx, client.CreateTeamRequest{ID: "team-1", Name: "payments"}); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	if err := c.CreateApplication(ctx, client.CreateApplicationRequest{ID: "app-1", Name: "billing", TeamID: "team-1"}); err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	app, err := c.GetApplication(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("GetApplication: %v", err)
+	}
+	if app.TeamID != "team-1" || app.State != string(domain.ApplicationStateProposed) || app.Metadata.Version == 0 {
+		t.Fatalf("unexpected application: %+v", app)
+	}
+
+	err = c.ApproveApplication(ctx, client.ApproveApplicationRequest{ID: "app-1"}, client.WithIfMatch(app.Metadata.Version+1))
+	if !perrors.IsKind(err, perrors.KindPreconditionFailed) {
+		t.Fatalf("ApproveApplication with stale If-Match: %v", err)
+	}
+	if err := c.ApproveApplication(ctx, client.ApproveApplicationRequest{ID: "app-1"}, client.WithIfMatch(app.Metadata.Version)); err != nil {
+		t.Fatalf("ApproveApplication: %v", err)
+	}
+
+	var dry client.DryRunResult
+	if err := c.DeprecateApplication(ctx, client.DeprecateApplicationRequest{ID: "app-1"}, client.WithDryRun(&dry)); !perrors.IsKind(err, perrors.KindDomain) {
+		t.Fatalf("DeprecateApplication dry-run of an invalid transition: %v", err)
+	}
+	if err := c.CreateApplication(ctx, client.CreateApplicationRequest{ID: "app-2", Name: "ledger", TeamID: "team-1"}, client.WithDryRun(&dry)); err != nil {
+		t.Fatalf("CreateApplication dry-run: %v", err)
+	}
+	if dry.Command != "createApplication" || dry.Status != 201 || !strings.Contains(string(dry.Resource), `"app-2"`) {
+		t.Fatalf("unexpected dry-run result: %+v", dry)
+	}
+	if _, err := c.GetApplication(ctx, "app-2"); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("dry-run persisted app-2: %v", err)
+	}
+
+	_, err = c.GetApplication(ctx, "missing")
+	var apiErr *client.Error
+	if !perrors.IsKind(err, perrors.KindNotFound) || !errors.As(err, &apiErr) || apiErr.Status != 404 {
+		t.Fatalf("GetApplication(missing) = %v", err)
+	}
+
+	env, err := client.NewBatchCommand("createEnvironment", client.CreateEnvironmentRequest{ID: "env-dev", Name: "dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.RunBatch(ctx, client.BatchRequest{Mode: client.BatchModeBestEffort, Commands: []client.BatchCommand{env, env}})
+	if err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].Status != 201 || res.Results[1].Status != 409 {
+		t.Fatalf("unexpected batch results: %+v", res.Results)
+	}
+}
+
+// TestClient_CoversEveryOperation exige un método del Client por cada
+// operationId de la API.
+func TestClient_CoversEveryOperation(t *testing.T) {
+	doc := httpapi.NewServer(&application.Services{}, zap.NewNop()).OpenAPI()
+	methods := reflect.TypeOf(&client.Client{})
+	aliases := map[string]string{"watchChanges": "WatchChanges"}
+	for path, item := range doc.Paths {
+		if !strings.HasPrefix(path, "/commands") && !strings.HasPrefix(path, "/queries") && path != "/watch" {
+			continue
+		}
+		for _, op := range item {
+			name := aliases[op.OperationID]
+			if name == "" {
+				r := []rune(op.OperationID)
+				r[0] = unicode.ToUpper(r[0])
+				name = string(r)
+			}
+			if _, ok := methods.MethodByName(name); !ok {
+				t.Errorf("client.Client has no method %s for %s", name, path)
+			}
+		}
+	}
+}
+
+// TestTypes_MatchDomainJSON serializa cada tipo de dominio con todos los
+// campos poblados y exige que el tipo del SDK lo lea y lo reescriba igual.
+func TestTypes_MatchDomainJSON(t *testing.T) {
+	cases := []struct {
+		domain, client any
+	}{
+		{&domain.Application{}, &client.Application{}},
+		{&domain.Organization{}, &client.Organization{}},
+		{&domain.TeamQuotaUsage{}, &client.TeamQuotaUsage{}},
+		{&domain.Environment{}, &client.Environment{}},
+		{&domain.ApplicationEnvironment{}, &client.ApplicationEnvironment{}},
+		{&domain.SecretBinding{}, &client.SecretBinding{}},
+		{&domain.WebhookSubscription{}, &client.WebhookSubscription{}},
+		{&domain.WebhookDelivery{}, &client.WebhookDelivery{}},
+		{&domain.Approval{}, &client.Approval{}},
+		{&domain.ChangeEvent{}, &client.ChangeEvent{}},
+	}
+	for _, tc := range cases {
+		name := reflect.TypeOf(tc.domain).Elem().Name()
+		fill(reflect.ValueOf(tc.domain).Elem())
+		want, err := json.Marshal(tc.domain)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(want))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(tc.client); err != nil {
+			t.Errorf("%s: client type rejects domain JSON: %v", name, err)
+			continue
+		}
+		got, err := json.Marshal(tc.client)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: client type loses fields\n got: %s\nwant: %s", name, got, want)
+		}
+	}
+}
+
+// fill puebla v con valores no nulos, para que omitempty no oculte campos.
+func fill(v reflect.Value) {
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fill(key)
+		fill(elem)
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// Error es un error devuelto por control-plane-api. Captura el status HTTP
+// junto con el problem+json del cuerpo: código y kind estables, mensaje,
+// trace ID del servidor y, para validaciones, el detalle por campo.
+//
+// errors.As(err, **perrors.Error) también funciona, así que perrors.Code,
+// perrors.KindOf y perrors.IsKind sirven sobre los errores del cliente.
+type Error struct {
+	Status  int
+	Code    string
+	Kind    perrors.Kind
+	Message string
+	TraceID string
+	Fields  []perrors.FieldError
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+
+	code := e.Code
+	if code == "" {
+		code = "unknown_error"
+	}
+
+	msg := e.Message
+	if strings.TrimSpace(msg) == "" {
+		msg = fmt.Sprintf("control-plane-api returned status %d", e.Status)
+	}
+
+	if e.TraceID != "" {
+		return fmt.Sprintf("control-plane-api error: status=%d code=%s message=%s trace_id=%s", e.Status, code, msg, e.TraceID)
+	}
+	return fmt.Sprintf("control-plane-api error: status=%d code=%s message=%s", e.Status, code, msg)
+}
+
+// As expone e como *perrors.Error, con el mismo Kind, Code y Fields.
+func (e *Error) As(target any) bool {
+	t, ok := target.(**perrors.Error)
+	if !ok {
+		return false
+	}
+	*t = &perrors.Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Fields: e.Fields}
+	return true
+}
+
+// newError lee el problem+json de resp (y cierra su body).
+func newError(resp *http.Response) *Error {
+	p := httpx.ReadProblem(resp)
+	return &Error{
+		Status:  resp.StatusCode,
+		Code:    p.Code,
+		Kind:    p.Kind,
+		Message: p.Detail,
+		TraceID: p.TraceID,
+		Fields:  p.Errors,
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/nuevo-idp/platform/audit"
+)
+
+// Queries. Un recurso inexistente devuelve un *Error con Kind not_found.
+
+func (c *Client) GetOrganization(ctx context.Context, id string) (*Organization, error) {
+	return getByID[Organization](ctx, c, "getOrganization", "/queries/organizations", id)
+}
+
+// GetTeamQuota devuelve la cuota efectiva del team y cuánto usa de ella.
+func (c *Client) GetTeamQuota(ctx context.Context, teamID string) (*TeamQuotaUsage, error) {
+	return getByID[TeamQuotaUsage](ctx, c, "getTeamQuota", "/queries/team-quotas", teamID)
+}
+
+func (c *Client) GetApplication(ctx context.Context, id string) (*Application, error) {
+	return getByID[Application](ctx, c, "getApplication", "/queries/applications", id)
+}
+
+func (c *Client) GetEnvironment(ctx context.Context, id string) (*Environment, error) {
+	return getByID[Environment](ctx, c, "getEnvironment", "/queries/environments", id)
+}
+
+func (c *Client) GetApplicationEnvironment(ctx context.Context, id string) (*ApplicationEnvironment, error) {
+	return getByID[ApplicationEnvironment](ctx, c, "getApplicationEnvironment", "/queries/application-environments", id)
+}
+
+func (c *Client) GetWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error) {
+	return getByID[WebhookSubscription](ctx, c, "getWebhookSubscription", "/queries/webhook-subscriptions", id)
+}
+
+func (c *Client) GetApproval(ctx context.Context, id string) (*Approval, error) {
+	return getByID[Approval](ctx, c, "getApproval", "/queries/approvals", id)
+}
+
+// ListApplicationEnvironments devuelve los environments de una Application,
+// ordenados por ID.
+func (c *Client) ListApplicationEnvironments(ctx context.Context, applicationID string) ([]ApplicationEnvironment, error) {
+	var out []ApplicationEnvironment
+	q := url.Values{"applicationId": {applicationID}}
+	if err := c.query(ctx, "listApplicationEnvironments", "/queries/application-environments:list", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSecretBindings devuelve los bindings que apuntan a un recurso (p.ej.
+// targetType "ApplicationEnvironment"), ordenados por ID.
+func (c *Client) ListSecretBindings(ctx context.Context, targetType, targetID string) ([]SecretBinding, error) {
+	var out []SecretBinding
+	q := url.Values{"targetType": {targetType}, "targetId": {targetID}}
+	if err := c.query(ctx, "listSecretBindings", "/queries/secret-bindings", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhookDeliveries devuelve las entregas de una suscripción. state
+// vacío no filtra; "DeadLettered" es la dead-letter list.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, subscriptionID, state string) ([]WebhookDelivery, error) {
+	q := url.Values{"subscriptionId": {subscriptionID}}
+	if state != "" {
+		q.Set("state", state)
+	}
+	var out []WebhookDelivery
+	if err := c.query(ctx, "listWebhookDeliveries", "/queries/webhook-deliveries", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApprovalFilter selecciona la bandeja de ListApprovals. Roles vacío usa
+// los roles del principal y State vacío, Pending.
+type ApprovalFilter struct {
+	Roles []string
+	State string
+}
+
+func (c *Client) ListApprovals(ctx context.Context, f ApprovalFilter) ([]Approval, error) {
+	q := url.Values{}
+	for _, r := range f.Roles {
+		q.Add("role", r)
+	}
+	if f.State != "" {
+		q.Set("state", f.State)
+	}
+	var out []Approval
+	if err := c.query(ctx, "listApprovals", "/queries/approval-requests", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QueryAudit devuelve los registros del log de auditoría que cumplen f.
+func (c *Client) QueryAudit(ctx context.Context, f audit.Filter) ([]audit.Record, error) {
+	var out []audit.Record
+	if err := c.query(ctx, "queryAudit", "/queries/audit", audit.FilterQuery(f), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerifyAudit recorre la cadena de hashes del log de auditoría.
+func (c *Client) VerifyAudit(ctx context.Context) (*audit.Verification, error) {
+	var out audit.Verification
+	if err := c.query(ctx, "verifyAudit", "/queries/audit/verify", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func getByID[T any](ctx context.Context, c *Client, operation, path, id string) (*T, error) {
+	var out T
+	if err := c.query(ctx, operation, path, url.Values{"id": {id}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// Bodies de los comandos. Son la definición del contrato: los handlers de
+// control-plane-api decodifican en estos mismos tipos, y los tags validate
+// alimentan la validación y el documento OpenAPI del servidor.
+
+type CreateOrganizationRequest struct {
+	ID   string `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+type CreateTeamRequest struct {
+	ID   string `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+// SetTeamQuotaRequest fija la cuota propia del team ID. Un límite en 0
+// vuelve a la política por defecto.
+type SetTeamQuotaRequest struct {
+	ID                            string `json:"id" validate:"required"`
+	MaxApplications               int    `json:"maxApplications"`
+	MaxSecrets                    int    `json:"maxSecrets"`
+	MaxBindingsPerSecret          int    `json:"maxBindingsPerSecret"`
+	MaxEnvironmentsPerApplication int    `json:"maxEnvironmentsPerApplication"`
+}
+
+type CreateApplicationRequest struct {
+	ID     string `json:"id" validate:"required"`
+	Name   string `json:"name" validate:"required"`
+	TeamID string `json:"teamId" validate:"required"`
+}
+
+type ApproveApplicationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type StartApplicationOnboardingRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type ActivateApplicationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type DeprecateApplicationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type StartApplicationDecommissioningRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type ArchiveApplicationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type CreateEnvironmentRequest struct {
+	ID   string `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+type DeclareApplicationEnvironmentRequest struct {
+	ID            string `json:"id" validate:"required"`
+	ApplicationID string `json:"applicationId" validate:"required"`
+	EnvironmentID string `json:"environmentId" validate:"required"`
+}
+
+type CompleteApplicationEnvironmentProvisioningRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type DecommissionApplicationEnvironmentRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type CreateSecretRequest struct {
+	ID          string `json:"id" validate:"required"`
+	OwnerTeamID string `json:"ownerTeamId" validate:"required"`
+	Purpose     string `json:"purpose"`
+	Sensitivity string `json:"sensitivity"`
+}
+
+type DeclareSecretBindingRequest struct {
+	ID         string `json:"id" validate:"required"`
+	SecretID   string `json:"secretId" validate:"required"`
+	TargetID   string `json:"targetId" validate:"required"`
+	TargetType string `json:"targetType" validate:"required"`
+}
+
+type RevokeSecretBindingRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type StartSecretRotationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type CompleteSecretRotationRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type DeclareCodeRepositoryRequest struct {
+	ID            string `json:"id" validate:"required"`
+	ApplicationID string `json:"applicationId" validate:"required"`
+}
+
+type DeclareDeploymentRepositoryRequest struct {
+	ID              string `json:"id" validate:"required"`
+	ApplicationID   string `json:"applicationId" validate:"required"`
+	DeploymentModel string `json:"deploymentModel"`
+}
+
+type DeclareGitOpsIntegrationRequest struct {
+	ID               string `json:"id" validate:"required"`
+	ApplicationID    string `json:"applicationId" validate:"required"`
+	DeploymentRepoID string `json:"deploymentRepositoryId" validate:"required"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	ID     string        `json:"id" validate:"required"`
+	TeamID string        `json:"teamId" validate:"required"`
+	URL    string        `json:"url" validate:"required"`
+	Secret string        `json:"secret" validate:"required"`
+	Filter WebhookFilter `json:"filter"`
+}
+
+type DisableWebhookSubscriptionRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type RedeliverWebhookDeliveryRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type CreateApprovalRequestRequest struct {
+	ID           string `json:"id" validate:"required"`
+	Type         string `json:"type" validate:"required"`
+	ResourceType string `json:"resourceType" validate:"required"`
+	ResourceID   string `json:"resourceId" validate:"required"`
+	// RolesAllowed vacío permite platformAdmin y securityAdmin.
+	RolesAllowed []string `json:"rolesAllowed"`
+	Reason       string   `json:"reason"`
+	// WorkflowID y SignalName identifican la señal que recibe la decisión.
+	WorkflowID string `json:"workflowId"`
+	SignalName string `json:"signalName"`
+	// TimeoutSeconds <= 0 usa el vencimiento por defecto (48h).
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+}
+
+// DecideApprovalRequest es el body de approveApproval y rejectApproval.
+type DecideApprovalRequest struct {
+	ID      string `json:"id" validate:"required"`
+	Comment string `json:"comment"`
+}
+
+// Modos de POST /commands:batch.
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "bestEffort"
+)
+
+type BatchRequest struct {
+	// Mode es "atomic" (todo o nada) o "bestEffort".
+	Mode     string         `json:"mode" validate:"required"`
+	Commands []BatchCommand `json:"commands" validate:"required"`
+}
+
+type BatchCommand struct {
+	// Command es el operationId del comando (p.ej. createApplication).
+	Command string `json:"command" validate:"required"`
+	// ExpectedVersion reemplaza a If-Match para este comando; 0 no condiciona.
+	ExpectedVersion int64 `json:"expectedVersion"`
+	// Body es el mismo body que acepta el endpoint del comando.
+	Body json.RawMessage `json:"body" validate:"required"`
+}
+
+// NewBatchCommand arma un BatchCommand con body como JSON (p.ej.
+// NewBatchCommand("createTeam", CreateTeamRequest{...})).
+func NewBatchCommand(command string, body any) (BatchCommand, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return BatchCommand{}, fmt.Errorf("marshal %s batch body: %w", command, err)
+	}
+	return BatchCommand{Command: command, Body: raw}, nil
+}
+
+type BatchResponse struct {
+	Mode string `json:"mode"`
+	// Committed indica si se aplicó al menos un comando del batch.
+	Committed bool `json:"committed"`
+	// DryRun indica que el batch se validó sin aplicar nada (?dryRun=true).
+	DryRun  bool          `json:"dryRun,omitempty"`
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult es el resultado de un comando: status es el que hubiera
+// devuelto su endpoint; code y message sólo se informan si falló.
+type BatchResult struct {
+	Index   int                  `json:"index"`
+	Command string               `json:"command"`
+	Status  int                  `json:"status"`
+	Code    string               `json:"code,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Errors  []perrors.FieldError `json:"errors,omitempty"`
+	// Resource es el recurso como habría quedado; sólo en dry-run.
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// DryRunResult es la respuesta de un comando con ?dryRun=true: el status
+// que habría devuelto su endpoint y el recurso como habría quedado. Si el
+// comando hubiera fallado, la API responde el mismo error que sin dry-run.
+type DryRunResult struct {
+	Command  string          `json:"command"`
+	Status   int             `json:"status"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
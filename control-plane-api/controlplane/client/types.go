@@ -0,0 +1,163 @@
+package client
+
+import "time"
+
+// Recursos que devuelven las queries. Replican el JSON de los tipos de
+// internal/domain; contract_test.go verifica que no se desalineen.
+
+type Metadata struct {
+	// Version es la base del ETag de las queries y de If-Match.
+	Version   int64        `json:"version"`
+	CreatedBy string       `json:"createdBy"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Tags      []string     `json:"tags,omitempty"`
+	History   []Transition `json:"history,omitempty"`
+}
+
+type Transition struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	By   string    `json:"by"`
+	At   time.Time `json:"at"`
+}
+
+type Organization struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	State    string   `json:"state"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type TeamQuota struct {
+	MaxApplications               int `json:"maxApplications,omitempty"`
+	MaxSecrets                    int `json:"maxSecrets,omitempty"`
+	MaxBindingsPerSecret          int `json:"maxBindingsPerSecret,omitempty"`
+	MaxEnvironmentsPerApplication int `json:"maxEnvironmentsPerApplication,omitempty"`
+}
+
+type QuotaUsage struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// TeamQuotaUsage es la cuota efectiva de un team y su uso. Los límites por
+// Secret y por Application van indexados por ID.
+type TeamQuotaUsage struct {
+	TeamID                     string                `json:"teamId"`
+	Quota                      TeamQuota             `json:"quota"`
+	Applications               QuotaUsage            `json:"applications"`
+	Secrets                    QuotaUsage            `json:"secrets"`
+	BindingsPerSecret          map[string]QuotaUsage `json:"bindingsPerSecret"`
+	EnvironmentsPerApplication map[string]QuotaUsage `json:"environmentsPerApplication"`
+}
+
+type Application struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	TeamID   string   `json:"teamId"`
+	State    string   `json:"state"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type Environment struct {
+	ID             string   `json:"id"`
+	OrganizationID string   `json:"organizationId"`
+	Name           string   `json:"name"`
+	State          string   `json:"state"`
+	Metadata       Metadata `json:"metadata"`
+}
+
+type ApplicationEnvironment struct {
+	ID            string   `json:"id"`
+	ApplicationID string   `json:"applicationId"`
+	EnvironmentID string   `json:"environmentId"`
+	State         string   `json:"state"`
+	Metadata      Metadata `json:"metadata"`
+}
+
+type SecretBinding struct {
+	ID         string   `json:"id"`
+	SecretID   string   `json:"secretId"`
+	TargetID   string   `json:"targetId"`
+	TargetType string   `json:"targetType"`
+	State      string   `json:"state"`
+	Metadata   Metadata `json:"metadata"`
+}
+
+// WebhookFilter selecciona los ChangeEvent que recibe una suscripción. Las
+// listas vacías no filtran.
+type WebhookFilter struct {
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	Actions       []string `json:"actions,omitempty"`
+	ApplicationID string   `json:"applicationId,omitempty"`
+}
+
+type WebhookSubscription struct {
+	ID       string        `json:"id"`
+	TeamID   string        `json:"teamId"`
+	URL      string        `json:"url"`
+	Filter   WebhookFilter `json:"filter"`
+	State    string        `json:"state"`
+	Metadata Metadata      `json:"metadata"`
+}
+
+type WebhookAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscriptionId"`
+	TeamID         string           `json:"teamId"`
+	Event          ChangeEvent      `json:"event"`
+	State          string           `json:"state"`
+	Attempts       []WebhookAttempt `json:"attempts,omitempty"`
+	RetryCount     int              `json:"retryCount"`
+	NextAttemptAt  time.Time        `json:"nextAttemptAt,omitempty"`
+	RedeliveredBy  string           `json:"redeliveredBy,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
+
+type ApprovalDecision struct {
+	Approved bool      `json:"approved"`
+	By       string    `json:"by"`
+	Role     string    `json:"role,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+type Approval struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	ResourceType string   `json:"resourceType"`
+	ResourceID   string   `json:"resourceId"`
+	TeamID       string   `json:"teamId,omitempty"`
+	RolesAllowed []string `json:"rolesAllowed"`
+	Reason       string   `json:"reason,omitempty"`
+	WorkflowID   string   `json:"workflowId,omitempty"`
+	SignalName   string   `json:"signalName,omitempty"`
+	// ExpiresAt es el vencimiento del pedido; cero no vence.
+	ExpiresAt time.Time         `json:"expiresAt,omitempty"`
+	State     string            `json:"state"`
+	Decision  *ApprovalDecision `json:"decision,omitempty"`
+	Metadata  Metadata          `json:"metadata"`
+}
+
+// ChangeEvent es una entrada del change feed (GET /watch). ID es la
+// posición en el feed y sirve como Last-Event-ID para reanudar.
+type ChangeEvent struct {
+	ID             int64     `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	ResourceType   string    `json:"resourceType"`
+	ResourceID     string    `json:"resourceId"`
+	TeamID         string    `json:"teamId,omitempty"`
+	ApplicationID  string    `json:"applicationId,omitempty"`
+	Action         string    `json:"action"`
+	State          string    `json:"state,omitempty"`
+	Version        int64     `json:"version"`
+	By             string    `json:"by"`
+	At             time.Time `json:"at"`
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/tracing"
+)
+
+// WatchFilter selecciona los eventos de WatchChanges. Los campos vacíos no
+// filtran. LastEventID reanuda un stream después de ese evento.
+type WatchFilter struct {
+	ResourceType  string
+	ResourceID    string
+	TeamID        string
+	ApplicationID string
+	LastEventID   string
+}
+
+// Event es un evento del stream de cambios.
+type Event struct {
+	// ID es la posición en el feed; vacío en los reset.
+	ID string
+	// Reset indica que se perdieron eventos: hay que releer el estado con
+	// las queries.
+	Reset  bool
+	Change ChangeEvent
+}
+
+// Stream es un stream abierto de GET /watch.
+type Stream struct {
+	body   io.ReadCloser
+	sc     *bufio.Scanner
+	lastID string
+}
+
+// WatchChanges abre el stream SSE de cambios. El stream vive hasta que se
+// cancela ctx o se llama a Close; no tiene Timeout ni reintentos. Si el
+// servidor lo corta (p.ej. por un suscriptor lento), Next devuelve io.EOF y
+// el caller reabre con LastEventID.
+func (c *Client) WatchChanges(ctx context.Context, f WatchFilter) (*Stream, error) {
+	ctx, span := tracing.StartSpan(ctx, "controlplane.client.watchChanges")
+	defer span.End()
+
+	q := url.Values{}
+	for name, v := range map[string]string{"type": f.ResourceType, "id": f.ResourceID, "teamId": f.TeamID, "applicationId": f.ApplicationID} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "/watch", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", httpx.EventStreamContentType)
+	if f.LastEventID != "" {
+		req.Header.Set("Last-Event-ID", f.LastEventID)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("GET /watch: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newError(resp)
+		span.RecordError(apiErr)
+		return nil, apiErr
+	}
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	return &Stream{body: resp.Body, sc: sc, lastID: f.LastEventID}, nil
+}
+
+// Next bloquea hasta el próximo evento. Los heartbeats se descartan.
+func (s *Stream) Next() (Event, error) {
+	var (
+		id, name string
+		data     [][]byte
+	)
+	for s.sc.Scan() {
+		line := s.sc.Bytes()
+		if len(line) == 0 {
+			if len(data) == 0 {
+				id, name = "", ""
+				continue
+			}
+			return s.event(id, name, bytes.Join(data, []byte("\n")))
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "id":
+			id = string(value)
+		case "event":
+			name = string(value)
+		case "data":
+			data = append(data, bytes.Clone(value))
+		}
+	}
+	if err := s.sc.Err(); err != nil {
+		return Event{}, fmt.Errorf("read /watch stream: %w", err)
+	}
+	return Event{}, io.EOF
+}
+
+func (s *Stream) event(id, name string, data []byte) (Event, error) {
+	if id != "" {
+		s.lastID = id
+	}
+	if name == "reset" {
+		return Event{Reset: true}, nil
+	}
+	ev := Event{ID: id}
+	if err := json.Unmarshal(data, &ev.Change); err != nil {
+		return Event{}, fmt.Errorf("decode /watch event %s: %w", id, err)
+	}
+	return ev, nil
+}
+
+// LastEventID devuelve el id del último evento recibido, para reanudar.
+func (s *Stream) LastEventID() string { return s.lastID }
+
+// Close cierra el stream.
+func (s *Stream) Close() error {
+	return s.body.Close() //nolint:wrapcheck // cierre del body HTTP
+}
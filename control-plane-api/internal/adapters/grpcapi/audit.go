@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/audit"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// unaryAudit registra los comandos en el log de auditoría, como el
+// middleware de httpapi. Las queries (Get*, List*) no se auditan. Va después
+// de grpcx.UnaryAuth, así que ve el principal y el error sin convertir;
+// Status es el status HTTP equivalente.
+func (s *Server) unaryAudit() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+		if strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "List") {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		command := application.AuditCommandName(method)
+		entry := audit.Record{
+			Command:      command,
+			ResourceType: application.AuditResourceTypes[command],
+			Status:       http.StatusOK,
+		}
+		if err != nil {
+			entry.Status, entry.ErrorCode = httpx.StatusFor(err), perrors.Code(err)
+		}
+		if m, ok := req.(proto.Message); ok {
+			if raw, merr := (proto.MarshalOptions{Deterministic: true}).Marshal(m); merr == nil {
+				entry.PayloadHash = audit.PayloadHash(raw)
+			}
+		}
+		if r, ok := req.(interface{ GetId() string }); ok {
+			entry.ResourceID = r.GetId()
+		}
+		s.audit.Record(ctx, entry)
+		return resp, err
+	}
+}
@@ -0,0 +1,40 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	controlplanev1 "github.com/nuevo-idp/control-plane-api/api/controlplane/v1"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/audit"
+)
+
+func TestGRPC_AuditsCommandsOnly(t *testing.T) {
+	store := audit.NewMemoryStore()
+	client := newTestClientFor(t, &application.Services{
+		Teams:        memoryrepo.NewTeamRepository(),
+		Applications: memoryrepo.NewApplicationRepository(),
+		Audit:        store,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _ = client.CreateTeam(ctx, &controlplanev1.CreateTeamRequest{Id: "team-1", Name: "Platform"})
+	_, _ = client.CreateTeam(ctx, &controlplanev1.CreateTeamRequest{Id: "team-1", Name: "Platform"})
+	_, _ = client.GetApplication(ctx, &controlplanev1.GetRequest{Id: "app-1"})
+
+	records, _ := store.Query(ctx, audit.Filter{})
+	if len(records) != 2 {
+		t.Fatalf("expected the two CreateTeam calls only, got %+v", records)
+	}
+	if r := records[0]; r.Command != "CreateTeam" || r.ResourceType != "Team" || r.ResourceID != "team-1" ||
+		r.Outcome != audit.OutcomeSuccess || r.PayloadHash == "" {
+		t.Fatalf("unexpected record %+v", r)
+	}
+	if r := records[1]; r.Outcome != audit.OutcomeFailure || r.Status != http.StatusConflict || r.ErrorCode != "team_already_exists" {
+		t.Fatalf("unexpected failure record %+v", r)
+	}
+}
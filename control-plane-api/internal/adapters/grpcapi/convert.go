@@ -0,0 +1,126 @@
+package grpcapi
+
+import (
+	"time"
+
+	controlplanev1 "github.com/nuevo-idp/control-plane-api/api/controlplane/v1"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Conversión domain -> protobuf. Los estados viajan como string, igual que
+// en JSON, para no duplicar los enums del dominio en el .proto.
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toMetadata(m domain.Metadata) *controlplanev1.Metadata {
+	out := &controlplanev1.Metadata{
+		Version:   m.Version,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: timestamp(m.CreatedAt),
+		Tags:      m.Tags,
+	}
+	for _, t := range m.History {
+		out.History = append(out.History, &controlplanev1.Transition{From: t.From, To: t.To, By: t.By, At: timestamp(t.At)})
+	}
+	return out
+}
+
+func toApplication(a *domain.Application) *controlplanev1.Application {
+	return &controlplanev1.Application{
+		Id:       a.ID,
+		Name:     a.Name,
+		TeamId:   a.TeamID,
+		State:    string(a.State),
+		Metadata: toMetadata(a.Metadata),
+	}
+}
+
+func toEnvironment(e *domain.Environment) *controlplanev1.Environment {
+	return &controlplanev1.Environment{
+		Id:       e.ID,
+		Name:     e.Name,
+		State:    string(e.State),
+		Metadata: toMetadata(e.Metadata),
+	}
+}
+
+func toApplicationEnvironment(ae *domain.ApplicationEnvironment) *controlplanev1.ApplicationEnvironment {
+	return &controlplanev1.ApplicationEnvironment{
+		Id:            ae.ID,
+		ApplicationId: ae.ApplicationID,
+		EnvironmentId: ae.EnvironmentID,
+		State:         string(ae.State),
+		Metadata:      toMetadata(ae.Metadata),
+	}
+}
+
+func toWebhookFilter(f domain.WebhookFilter) *controlplanev1.WebhookFilter {
+	out := &controlplanev1.WebhookFilter{ResourceTypes: f.ResourceTypes, ApplicationId: f.ApplicationID}
+	for _, a := range f.Actions {
+		out.Actions = append(out.Actions, string(a))
+	}
+	return out
+}
+
+func fromWebhookFilter(f *controlplanev1.WebhookFilter) domain.WebhookFilter {
+	out := domain.WebhookFilter{ResourceTypes: f.GetResourceTypes(), ApplicationID: f.GetApplicationId()}
+	for _, a := range f.GetActions() {
+		out.Actions = append(out.Actions, domain.ChangeAction(a))
+	}
+	return out
+}
+
+func toWebhookSubscription(s *domain.WebhookSubscription) *controlplanev1.WebhookSubscription {
+	return &controlplanev1.WebhookSubscription{
+		Id:       s.ID,
+		TeamId:   s.TeamID,
+		Url:      s.URL,
+		Filter:   toWebhookFilter(s.Filter),
+		State:    string(s.State),
+		Metadata: toMetadata(s.Metadata),
+	}
+}
+
+func toWebhookDelivery(d *domain.WebhookDelivery) *controlplanev1.WebhookDelivery {
+	out := &controlplanev1.WebhookDelivery{
+		Id:             d.ID,
+		SubscriptionId: d.SubscriptionID,
+		TeamId:         d.TeamID,
+		Event:          toChangeEvent(d.Event),
+		State:          string(d.State),
+		RetryCount:     int32(d.RetryCount), //nolint:gosec // acotado por MaxAttempts
+		NextAttemptAt:  timestamp(d.NextAttemptAt),
+		RedeliveredBy:  d.RedeliveredBy,
+		CreatedAt:      timestamp(d.CreatedAt),
+	}
+	for _, a := range d.Attempts {
+		out.Attempts = append(out.Attempts, &controlplanev1.WebhookAttempt{
+			At:         timestamp(a.At),
+			StatusCode: int32(a.StatusCode), //nolint:gosec // status HTTP
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+		})
+	}
+	return out
+}
+
+func toChangeEvent(ev domain.ChangeEvent) *controlplanev1.ChangeEvent {
+	return &controlplanev1.ChangeEvent{
+		Id:            ev.ID,
+		ResourceType:  ev.ResourceType,
+		ResourceId:    ev.ResourceID,
+		TeamId:        ev.TeamID,
+		ApplicationId: ev.ApplicationID,
+		Action:        string(ev.Action),
+		State:         ev.State,
+		Version:       ev.Version,
+		By:            ev.By,
+		At:            timestamp(ev.At),
+	}
+}
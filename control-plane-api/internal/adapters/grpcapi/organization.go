@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// organizationMetadataKey es el equivalente gRPC del header
+// X-Organization-ID.
+const organizationMetadataKey = "x-organization-id"
+
+// unaryOrganization acota cada llamada a la organización de la metadata (o
+// a la del principal). Va después de grpcx.UnaryAuth.
+func unaryOrganization() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := organizationScope(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamOrganization es unaryOrganization para Watch.
+func streamOrganization() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := organizationScope(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &organizationStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func organizationScope(ctx context.Context) (context.Context, error) {
+	var requested string
+	if v := metadata.ValueFromIncomingContext(ctx, organizationMetadataKey); len(v) > 0 {
+		requested = v[0]
+	}
+	return application.OrganizationScope(ctx, requested) //nolint:wrapcheck // error de validación de plataforma; grpcx.UnaryErrors lo convierte
+}
+
+type organizationStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *organizationStream) Context() context.Context { return s.ctx }
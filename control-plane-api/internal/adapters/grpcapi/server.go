@@ -0,0 +1,292 @@
+// Package grpcapi expone application.API por gRPC (ver
+// api/controlplane/v1). Comparte con httpapi la autenticación, la política
+// de autorización y el mapeo de errores de plataforma.
+package grpcapi
+
+import (
+	"context"
+
+	controlplanev1 "github.com/nuevo-idp/control-plane-api/api/controlplane/v1"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/grpcx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// internalActor coincide con el de httpapi: es el subject del token interno
+// de workflow-engine.
+const internalActor = "workflow-engine"
+
+// watchBuffer es el buffer por suscriptor de Watch, como en GET /watch.
+const watchBuffer = 256
+
+type Server struct {
+	controlplanev1.UnimplementedControlPlaneServer
+
+	api      application.API
+	logger   *zap.Logger
+	verifier *auth.Verifier
+	authn    *auth.Authenticator
+	audit    *audit.Recorder
+}
+
+// Option configura dependencias opcionales del Server.
+type Option func(*Server)
+
+// WithVerifier habilita autenticación bearer JWT (ver httpapi.WithVerifier).
+func WithVerifier(v *auth.Verifier) Option {
+	return func(s *Server) { s.verifier = v }
+}
+
+func NewServer(services *application.Services, logger *zap.Logger, opts ...Option) *Server {
+	s := &Server{logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.authn = auth.NewAuthenticator(auth.Options{
+		Verifier:        s.verifier,
+		InternalToken:   config.Get("INTERNAL_AUTH_TOKEN", ""),
+		InternalSubject: internalActor,
+	})
+	if services.Audit != nil {
+		s.audit = audit.NewRecorder(services.Audit, audit.RecorderOptions{
+			Service: "control-plane-api",
+			OnError: func(ctx context.Context, r audit.Record, err error) {
+				observability.LoggerWithTrace(ctx, logger).Error("audit append failed",
+					zap.String("transport", "grpc"), zap.String("command", r.Command), zap.Error(err))
+			},
+		})
+	}
+	s.api = application.NewAuthorizer(services, application.AuthorizerOptions{
+		Auditor:        denialLogger{logger: logger},
+		AllowAnonymous: !s.authn.Enabled(),
+	})
+	return s
+}
+
+// GRPCServer devuelve un *grpc.Server con el servicio registrado, listo
+// para Serve.
+func (s *Server) GRPCServer() *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryOrganization()),
+		grpc.ChainStreamInterceptor(streamOrganization()),
+	}
+	if s.audit != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.unaryAudit()))
+	}
+	gs := grpcx.NewServer(s.authn, opts...)
+	controlplanev1.RegisterControlPlaneServer(gs, s)
+	return gs
+}
+
+// denialLogger audita las llamadas denegadas, igual que en httpapi.
+type denialLogger struct {
+	logger *zap.Logger
+}
+
+func (l denialLogger) AuditDenied(ctx context.Context, d application.Denial) {
+	observability.LoggerWithTrace(ctx, l.logger).Warn("authorization denied",
+		zap.Bool("audit", true),
+		zap.String("transport", "grpc"),
+		zap.String("command", d.Command),
+		zap.String("subject", d.Subject),
+		zap.String("principal_kind", string(d.Kind)),
+		zap.String("organization_id", d.Organization),
+		zap.String("team_id", d.TeamID),
+		zap.Time("at", d.At),
+	)
+}
+
+// actor devuelve el subject autenticado de la llamada.
+func actor(ctx context.Context) string {
+	return auth.Actor(ctx, "grpc")
+}
+
+// transition aplica expected_version (el If-Match de gRPC) antes de ejecutar
+// un comando de transición.
+func transition(ctx context.Context, req *controlplanev1.TransitionRequest, cmd func(ctx context.Context, id, by string) error) (*controlplanev1.CommandResponse, error) {
+	if v := req.GetExpectedVersion(); v > 0 {
+		ctx = application.WithExpectedVersion(ctx, v)
+	}
+	if err := cmd(ctx, req.GetId(), actor(ctx)); err != nil {
+		return nil, err
+	}
+	return &controlplanev1.CommandResponse{}, nil
+}
+
+func done(err error) (*controlplanev1.CommandResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &controlplanev1.CommandResponse{}, nil
+}
+
+func (s *Server) GetApplication(ctx context.Context, req *controlplanev1.GetRequest) (*controlplanev1.Application, error) {
+	app, err := s.api.GetApplication(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toApplication(app), nil
+}
+
+func (s *Server) GetEnvironment(ctx context.Context, req *controlplanev1.GetRequest) (*controlplanev1.Environment, error) {
+	env, err := s.api.GetEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toEnvironment(env), nil
+}
+
+func (s *Server) GetApplicationEnvironment(ctx context.Context, req *controlplanev1.GetRequest) (*controlplanev1.ApplicationEnvironment, error) {
+	ae, err := s.api.GetApplicationEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toApplicationEnvironment(ae), nil
+}
+
+func (s *Server) GetWebhookSubscription(ctx context.Context, req *controlplanev1.GetRequest) (*controlplanev1.WebhookSubscription, error) {
+	sub, err := s.api.GetWebhookSubscription(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookSubscription(sub), nil
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, req *controlplanev1.ListWebhookDeliveriesRequest) (*controlplanev1.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := s.api.ListWebhookDeliveries(ctx, req.GetSubscriptionId(), domain.WebhookDeliveryState(req.GetState()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &controlplanev1.ListWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, toWebhookDelivery(d))
+	}
+	return resp, nil
+}
+
+func (s *Server) Watch(req *controlplanev1.WatchRequest, stream grpc.ServerStreamingServer[controlplanev1.WatchResponse]) error {
+	ctx := stream.Context()
+	sub, err := s.api.WatchChanges(ctx, req.GetLastEventId(), watchBuffer)
+	if err != nil {
+		return err
+	}
+	filter := domain.ChangeFilter{
+		ResourceType:  req.GetResourceType(),
+		ResourceID:    req.GetResourceId(),
+		TeamID:        req.GetTeamId(),
+		ApplicationID: req.GetApplicationId(),
+	}
+
+	if sub.Gap {
+		reset := &controlplanev1.WatchResponse{Event: &controlplanev1.WatchResponse_Reset_{Reset_: &controlplanev1.Reset{Reason: "events_expired"}}}
+		if err := stream.Send(reset); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.Events:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				observability.LoggerWithTrace(ctx, s.logger).Warn("watch: subscriber too slow, closing stream")
+				return status.Error(codes.Unavailable, "subscriber buffer overflow; resume with last_event_id")
+			}
+			if !filter.Matches(ev) {
+				continue
+			}
+			resp := &controlplanev1.WatchResponse{Event: &controlplanev1.WatchResponse_Change{Change: toChangeEvent(ev)}}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) CreateTeam(ctx context.Context, req *controlplanev1.CreateTeamRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.CreateTeam(ctx, req.GetId(), req.GetName(), actor(ctx)))
+}
+
+func (s *Server) CreateApplication(ctx context.Context, req *controlplanev1.CreateApplicationRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.CreateApplication(ctx, req.GetId(), req.GetName(), req.GetTeamId(), actor(ctx)))
+}
+
+func (s *Server) ApproveApplication(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.ApproveApplication)
+}
+
+func (s *Server) StartApplicationOnboarding(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.StartApplicationOnboarding)
+}
+
+func (s *Server) ActivateApplication(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.ActivateApplication)
+}
+
+func (s *Server) DeprecateApplication(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.DeprecateApplication)
+}
+
+func (s *Server) DeclareCodeRepository(ctx context.Context, req *controlplanev1.DeclareCodeRepositoryRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.DeclareCodeRepository(ctx, req.GetId(), req.GetApplicationId(), actor(ctx)))
+}
+
+func (s *Server) CreateEnvironment(ctx context.Context, req *controlplanev1.CreateEnvironmentRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.CreateEnvironment(ctx, req.GetId(), req.GetName(), actor(ctx)))
+}
+
+func (s *Server) DeclareDeploymentRepository(ctx context.Context, req *controlplanev1.DeclareDeploymentRepositoryRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.DeclareDeploymentRepository(ctx, req.GetId(), req.GetApplicationId(), req.GetDeploymentModel(), actor(ctx)))
+}
+
+func (s *Server) DeclareApplicationEnvironment(ctx context.Context, req *controlplanev1.DeclareApplicationEnvironmentRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.DeclareApplicationEnvironment(ctx, req.GetId(), req.GetApplicationId(), req.GetEnvironmentId(), actor(ctx)))
+}
+
+func (s *Server) CompleteApplicationEnvironmentProvisioning(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.CompleteApplicationEnvironmentProvisioning)
+}
+
+func (s *Server) DeclareGitOpsIntegration(ctx context.Context, req *controlplanev1.DeclareGitOpsIntegrationRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.DeclareGitOpsIntegration(ctx, req.GetId(), req.GetApplicationId(), req.GetDeploymentRepositoryId(), actor(ctx)))
+}
+
+func (s *Server) CreateSecret(ctx context.Context, req *controlplanev1.CreateSecretRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.CreateSecret(ctx, req.GetId(), req.GetOwnerTeamId(), req.GetPurpose(), req.GetSensitivity(), actor(ctx)))
+}
+
+func (s *Server) StartSecretRotation(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.StartSecretRotation)
+}
+
+func (s *Server) CompleteSecretRotation(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.CompleteSecretRotation)
+}
+
+func (s *Server) DeclareSecretBinding(ctx context.Context, req *controlplanev1.DeclareSecretBindingRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.DeclareSecretBinding(ctx, req.GetId(), req.GetSecretId(), req.GetTargetId(), req.GetTargetType(), actor(ctx)))
+}
+
+func (s *Server) CreateWebhookSubscription(ctx context.Context, req *controlplanev1.CreateWebhookSubscriptionRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.CreateWebhookSubscription(ctx, req.GetId(), req.GetTeamId(), req.GetUrl(), req.GetSecret(), fromWebhookFilter(req.GetFilter()), actor(ctx)))
+}
+
+func (s *Server) DisableWebhookSubscription(ctx context.Context, req *controlplanev1.TransitionRequest) (*controlplanev1.CommandResponse, error) {
+	return transition(ctx, req, s.api.DisableWebhookSubscription)
+}
+
+func (s *Server) RedeliverWebhookDelivery(ctx context.Context, req *controlplanev1.RedeliverWebhookDeliveryRequest) (*controlplanev1.CommandResponse, error) {
+	return done(s.api.RedeliverWebhookDelivery(ctx, req.GetId(), actor(ctx)))
+}
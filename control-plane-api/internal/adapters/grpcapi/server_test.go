@@ -0,0 +1,149 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	controlplanev1 "github.com/nuevo-idp/control-plane-api/api/controlplane/v1"
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/grpcx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) controlplanev1.ControlPlaneClient {
+	t.Helper()
+	services := &application.Services{
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		Environments:            memoryrepo.NewEnvironmentRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		Changes:                 memoryrepo.NewChangeFeed(64),
+	}
+	return newTestClientFor(t, services)
+}
+
+func newTestClientFor(t *testing.T, services *application.Services) controlplanev1.ControlPlaneClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := NewServer(services, zap.NewNop()).GRPCServer()
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	opts := append(grpcx.ClientOptions(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return controlplanev1.NewControlPlaneClient(conn)
+}
+
+func TestGRPC_CommandsQueriesAndErrors(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.CreateTeam(ctx, &controlplanev1.CreateTeamRequest{Id: "team-1", Name: "Platform"}); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if _, err := client.CreateApplication(ctx, &controlplanev1.CreateApplicationRequest{Id: "app-1", Name: "App", TeamId: "team-1"}); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	app, err := client.GetApplication(ctx, &controlplanev1.GetRequest{Id: "app-1"})
+	if err != nil {
+		t.Fatalf("GetApplication failed: %v", err)
+	}
+	if app.GetState() != "Proposed" || app.GetTeamId() != "team-1" || app.GetMetadata().GetVersion() != 1 {
+		t.Fatalf("unexpected application %+v", app)
+	}
+
+	// Los errores llegan como errores de plataforma (vía grpcx.ClientOptions).
+	_, err = client.GetApplication(ctx, &controlplanev1.GetRequest{Id: "missing"})
+	if !perrors.IsKind(err, perrors.KindNotFound) || perrors.Code(err) != "application_not_found" {
+		t.Fatalf("expected application_not_found, got %v", err)
+	}
+
+	_, err = client.ApproveApplication(ctx, &controlplanev1.TransitionRequest{Id: "app-1", ExpectedVersion: 7})
+	if !perrors.IsKind(err, perrors.KindPreconditionFailed) || perrors.Code(err) != "version_mismatch" {
+		t.Fatalf("expected version_mismatch, got %v", err)
+	}
+	if _, err := client.ApproveApplication(ctx, &controlplanev1.TransitionRequest{Id: "app-1", ExpectedVersion: 1}); err != nil {
+		t.Fatalf("ApproveApplication failed: %v", err)
+	}
+}
+
+func TestGRPC_WatchStreamsFilteredChanges(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _ = client.CreateTeam(ctx, &controlplanev1.CreateTeamRequest{Id: "team-1", Name: "Platform"})
+	_, _ = client.CreateApplication(ctx, &controlplanev1.CreateApplicationRequest{Id: "app-1", Name: "App", TeamId: "team-1"})
+
+	// Reanudar desde el evento 1 reproduce la creación de la application.
+	stream, err := client.Watch(ctx, &controlplanev1.WatchRequest{LastEventId: 1, ResourceType: "Application"})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	change := resp.GetChange()
+	if change.GetId() != 2 || change.GetResourceId() != "app-1" || change.GetAction() != "created" {
+		t.Fatalf("unexpected change %+v", resp)
+	}
+
+	// Un id desconocido emite reset.
+	stream, _ = client.Watch(ctx, &controlplanev1.WatchRequest{LastEventId: 99})
+	if resp, err := stream.Recv(); err != nil || resp.GetReset_() == nil {
+		t.Fatalf("expected reset, got %+v (%v)", resp, err)
+	}
+}
+
+func TestGRPC_OrganizationFromMetadata(t *testing.T) {
+	services := &application.Services{
+		Organizations: memoryrepo.NewOrganizationRepository(),
+		Teams:         memoryrepo.NewTeamRepository(),
+		Applications:  memoryrepo.NewApplicationRepository(),
+	}
+	client := newTestClientFor(t, services)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.CreateOrganization(ctx, "acme", "Acme", "alice"); err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+
+	acme := metadata.AppendToOutgoingContext(ctx, organizationMetadataKey, "acme")
+	if _, err := client.CreateTeam(acme, &controlplanev1.CreateTeamRequest{Id: "team-1", Name: "Payments"}); err != nil {
+		t.Fatalf("CreateTeam in acme failed: %v", err)
+	}
+	if _, err := client.CreateApplication(acme, &controlplanev1.CreateApplicationRequest{Id: "app-1", Name: "App", TeamId: "team-1"}); err != nil {
+		t.Fatalf("CreateApplication in acme failed: %v", err)
+	}
+
+	// Sin metadata se opera en la organización default, que no ve app-1.
+	if _, err := client.GetApplication(ctx, &controlplanev1.GetRequest{Id: "app-1"}); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("expected not_found outside acme, got %v", err)
+	}
+	if _, err := client.GetApplication(acme, &controlplanev1.GetRequest{Id: "app-1"}); err != nil {
+		t.Fatalf("GetApplication in acme failed: %v", err)
+	}
+
+	bad := metadata.AppendToOutgoingContext(ctx, organizationMetadataKey, "Not Valid!")
+	if _, err := client.GetApplication(bad, &controlplanev1.GetRequest{Id: "app-1"}); !perrors.IsKind(err, perrors.KindValidation) {
+		t.Fatalf("expected validation error for invalid organization, got %v", err)
+	}
+}
@@ -1,11 +1,17 @@
 package httpapi
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/auth"
 	"github.com/nuevo-idp/platform/config"
 	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/health"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,65 +20,170 @@ import (
 )
 
 type Server struct {
-	services *application.Services
-	logger   *zap.Logger
+	services    *application.Services
+	api         application.API // services detrás de la política de autorización
+	logger      *zap.Logger
+	idempotency httpx.IdempotencyStore
+	limiter     *httpx.RateLimiter
+	verifier    *auth.Verifier
+	authn       *auth.Authenticator
+	health      *health.Checker
+	audit       *audit.Recorder
 }
 
-func NewServer(services *application.Services, logger *zap.Logger) *Server {
-	return &Server{services: services, logger: logger}
+// Option configura dependencias opcionales del Server.
+type Option func(*Server)
+
+// WithVerifier habilita autenticación bearer JWT. Sin verifier el Server
+// corre en modo dev: las requests sin credenciales se aceptan como
+// auth.Anonymous.
+func WithVerifier(v *auth.Verifier) Option {
+	return func(s *Server) { s.verifier = v }
+}
+
+// WithHealth usa checker para /healthz y /readyz. Sin checker, /readyz
+// responde listo mientras el proceso esté vivo.
+func WithHealth(checker *health.Checker) Option {
+	return func(s *Server) { s.health = checker }
+}
+
+// idempotencyTTL es cuánto recordamos una Idempotency-Key; cubre de sobra la
+// ventana de reintentos de las actividades de Temporal.
+const idempotencyTTL = 24 * time.Hour
+
+func NewServer(services *application.Services, logger *zap.Logger, opts ...Option) *Server {
+	s := &Server{
+		services:    services,
+		logger:      logger,
+		idempotency: organizationIdempotencyStore{next: auth.IdempotencyStore{Next: httpx.NewMemoryIdempotencyStore(idempotencyTTL)}},
+		limiter:     httpx.NewRateLimiter(auth.RateLimitKeys),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.health == nil {
+		s.health = health.NewChecker(0)
+	}
+	// workflow-engine se autentica con el token interno compartido; los
+	// humanos, con bearer JWT.
+	s.authn = auth.NewAuthenticator(auth.Options{
+		Verifier:        s.verifier,
+		InternalToken:   config.Get("INTERNAL_AUTH_TOKEN", ""),
+		InternalSubject: internalActor,
+	})
+	// Los comandos se auditan en el log de services.Audit, si lo hay.
+	if services.Audit != nil {
+		s.audit = audit.NewRecorder(services.Audit, audit.RecorderOptions{
+			Service: "control-plane-api",
+			OnError: func(ctx context.Context, r audit.Record, err error) {
+				observability.LoggerWithTrace(ctx, logger).Error("audit append failed",
+					zap.String("command", r.Command), zap.String("resource_id", r.ResourceID), zap.Error(err))
+			},
+		})
+	}
+	// Toda llamada a los casos de uso pasa por la política de autorización.
+	// En modo dev el principal anónimo no se restringe.
+	s.api = application.NewAuthorizer(services, application.AuthorizerOptions{
+		Auditor:        denialLogger{logger: logger},
+		AllowAnonymous: !s.authn.Enabled(),
+	})
+	return s
+}
+
+// denialLogger audita las llamadas denegadas en el log estructurado.
+type denialLogger struct {
+	logger *zap.Logger
+}
+
+func (l denialLogger) AuditDenied(ctx context.Context, d application.Denial) {
+	observability.LoggerWithTrace(ctx, l.logger).Warn("authorization denied",
+		zap.Bool("audit", true),
+		zap.String("command", d.Command),
+		zap.String("subject", d.Subject),
+		zap.String("principal_kind", string(d.Kind)),
+		zap.String("organization_id", d.Organization),
+		zap.String("team_id", d.TeamID),
+		zap.Time("at", d.At),
+	)
 }
 
+// Límites por defecto de cada grupo de rutas; se ajustan con
+// RATE_LIMIT_<GROUP>_<SCOPE> (ver httpx.RateLimitPolicyFromEnv). El bucket
+// de servicio es holgado porque workflow-engine concentra el tráfico de
+// todos los workflows.
+var (
+	defaultCommandRateLimits = map[string]httpx.RateLimit{
+		httpx.RateLimitScopePrincipal: {Rate: 5, Burst: 20},
+		httpx.RateLimitScopeTeam:      {Rate: 20, Burst: 50},
+		httpx.RateLimitScopeService:   {Rate: 50, Burst: 100},
+		httpx.RateLimitScopeClient:    {Rate: 5, Burst: 20},
+	}
+	defaultQueryRateLimits = map[string]httpx.RateLimit{
+		httpx.RateLimitScopePrincipal: {Rate: 20, Burst: 50},
+		httpx.RateLimitScopeTeam:      {Rate: 50, Burst: 100},
+		httpx.RateLimitScopeService:   {Rate: 100, Burst: 200},
+		httpx.RateLimitScopeClient:    {Rate: 20, Burst: 50},
+	}
+)
+
 func (s *Server) Routes() http.Handler {
+	// Un RATE_LIMIT_* inválido conserva el default y queda en el log.
+	commands, cmdErr := httpx.RateLimitPolicyFromEnv("commands", defaultCommandRateLimits)
+	queries, queryErr := httpx.RateLimitPolicyFromEnv("queries", defaultQueryRateLimits)
+	if err := errors.Join(cmdErr, queryErr); err != nil {
+		s.logger.Warn("ignoring invalid rate limits", zap.Error(err))
+	}
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", s.health)
-	mux.HandleFunc("/commands/teams", s.createTeam)
-	mux.HandleFunc("/commands/applications", s.createApplication)
-	mux.HandleFunc("/commands/applications/approve", s.approveApplication)
-	mux.HandleFunc("/commands/applications/start-onboarding", s.startApplicationOnboarding)
-	mux.HandleFunc("/commands/applications/activate", s.activateApplication)
-	mux.HandleFunc("/commands/applications/deprecate", s.deprecateApplication)
-	mux.HandleFunc("/commands/environments", s.createEnvironment)
-	mux.HandleFunc("/commands/application-environments", s.declareApplicationEnvironment)
-	mux.HandleFunc("/commands/application-environments/complete-provisioning", s.completeApplicationEnvironmentProvisioning)
-	mux.HandleFunc("/commands/secrets", s.createSecret)
-	mux.HandleFunc("/commands/secrets/start-rotation", s.startSecretRotation)
-	mux.HandleFunc("/commands/secrets/complete-rotation", s.completeSecretRotation)
-	mux.HandleFunc("/commands/secret-bindings", s.declareSecretBinding)
-	mux.HandleFunc("/commands/code-repositories", s.declareCodeRepository)
-	mux.HandleFunc("/commands/deployment-repositories", s.declareDeploymentRepository)
-	mux.HandleFunc("/commands/gitops-integrations", s.declareGitOpsIntegration)
-	mux.HandleFunc("/queries/applications", s.getApplication)
-	mux.HandleFunc("/queries/environments", s.getEnvironment)
-	mux.HandleFunc("/queries/application-environments", s.getApplicationEnvironment)
+	s.health.Register(mux)
+	for _, rt := range s.routeTable() {
+		// Orden: autenticación -> organización -> auditoría -> rate limit
+		// (por principal) -> idempotencia -> If-Match. Las requests
+		// rechazadas con 401 no se auditan: no hay principal al que
+		// atribuirlas.
+		var h http.Handler = rt.handler
+		policy := queries
+		if rt.op.Method == http.MethodPost {
+			h = httpx.Idempotent(s.idempotency, withIfMatch(h))
+			policy = commands
+		}
+		h = s.limiter.Middleware(policy, h)
+		if rt.op.Method == http.MethodPost {
+			// ?dryRun=true toma otra cadena: rate limit -> If-Match ->
+			// dry-run, sin idempotencia ni auditoría.
+			dryRun := s.limiter.Middleware(policy, withIfMatch(s.dryRunHandler(rt)))
+			h = withDryRun(s.withAudit(rt, h), dryRun)
+		}
+		mux.Handle(rt.op.Path, s.authn.Middleware(withOrganization(h)))
+	}
+	mux.Handle("/openapi.json", s.OpenAPI().Handler())
 	mux.Handle("/metrics", promhttp.Handler())
 
 	instrumented := observability.InstrumentHTTP(mux)
 	return otelhttp.NewHandler(instrumented, "control-plane-api")
 }
 
-func (s *Server) health(w http.ResponseWriter, _ *http.Request) {
-	httpx.WriteText(w, http.StatusOK, "ok")
-}
-
 func (s *Server) getApplication(w http.ResponseWriter, r *http.Request) {
 	if !httpx.RequireMethod(w, r, http.MethodGet) {
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
 		return
 	}
 
-	app, err := s.services.GetApplication(r.Context(), id)
+	app, err := s.api.GetApplication(r.Context(), id)
 	if err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("getApplication error", zap.Error(err))
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
+	if httpx.NotModified(w, r, httpx.VersionETag(app.Metadata.Version)) {
+		return
+	}
 	httpx.WriteJSON(w, http.StatusOK, app)
 }
 
@@ -81,20 +192,22 @@ func (s *Server) getEnvironment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
 		return
 	}
 
-	env, err := s.services.GetEnvironment(r.Context(), id)
+	env, err := s.api.GetEnvironment(r.Context(), id)
 	if err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("getEnvironment error", zap.Error(err))
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
+	if httpx.NotModified(w, r, httpx.VersionETag(env.Metadata.Version)) {
+		return
+	}
 	httpx.WriteJSON(w, http.StatusOK, env)
 }
 
@@ -103,24 +216,53 @@ func (s *Server) getApplicationEnvironment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	id := r.URL.Query().Get("id")
-	if id == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
 		return
 	}
 
-	ae, err := s.services.GetApplicationEnvironment(r.Context(), id)
+	ae, err := s.api.GetApplicationEnvironment(r.Context(), id)
 	if err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("getApplicationEnvironment error", zap.Error(err))
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
+	if httpx.NotModified(w, r, httpx.VersionETag(ae.Metadata.Version)) {
+		return
+	}
 	httpx.WriteJSON(w, http.StatusOK, ae)
 }
 
-const internalAuthHeader = "X-Internal-Token"
+// withIfMatch traslada el header If-Match al contexto como versión
+// esperada: el comando falla con 412 si el recurso cambió desde que el
+// cliente lo leyó.
+func withIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, ok, err := httpx.IfMatchVersion(r)
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		if ok {
+			r = r.WithContext(application.WithExpectedVersion(r.Context(), version))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+const internalAuthHeader = auth.InternalTokenHeader
+
+// internalActor es el actor registrado para los comandos que sólo dispara
+// workflow-engine.
+const internalActor = "workflow-engine"
+
+// actor devuelve el subject autenticado de la request, que es lo que se
+// registra como autor en Metadata.
+func actor(r *http.Request) string {
+	return auth.Actor(r.Context(), "api")
+}
 
 // requireInternalAuth aplica autenticación interna para llamadas servicio-a-servicio.
 // Si INTERNAL_AUTH_TOKEN no está configurado, no se aplica enforcement (modo dev).
@@ -130,40 +272,14 @@ func requireInternalAuth(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	}
 	if r.Header.Get(internalAuthHeader) != token {
-		httpx.WriteText(w, http.StatusUnauthorized, "missing or invalid internal auth token")
+		httpx.WriteError(w, r, perrors.Unauthorized("invalid_internal_token", "missing or invalid internal auth token", nil))
 		return false
 	}
 	return true
 }
 
-type errorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
-
-func writeDomainError(w http.ResponseWriter, err error) {
-	status := http.StatusBadRequest
-
-	switch {
-	case perrors.IsKind(err, perrors.KindNotFound):
-		status = http.StatusNotFound
-	case perrors.IsKind(err, perrors.KindConflict):
-		status = http.StatusConflict
-	case perrors.IsKind(err, perrors.KindInternal):
-		status = http.StatusInternalServerError
-	case perrors.IsKind(err, perrors.KindDomain), perrors.IsKind(err, perrors.KindValidation):
-		status = http.StatusBadRequest
-	}
-
-	code := perrors.Code(err)
-	if code == "" {
-		code = "unknown_error"
-	}
-
-	resp := errorResponse{
-		Code:    code,
-		Message: err.Error(),
-	}
-
-	httpx.WriteJSON(w, status, resp)
+// writeDomainError traduce errores de dominio/plataforma al problem+json
+// estándar de la API.
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	httpx.WriteError(w, r, err)
 }
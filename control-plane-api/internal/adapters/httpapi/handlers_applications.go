@@ -3,32 +3,19 @@ package httpapi
 import (
 	"net/http"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"go.uber.org/zap"
 )
 
-type createApplicationRequest struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	TeamID string `json:"teamId"`
-}
-
-type approveApplicationRequest struct {
-	ID string `json:"id"`
-}
-
-type deprecateApplicationRequest struct {
-	ID string `json:"id"`
-}
-
-type startApplicationOnboardingRequest struct {
-	ID string `json:"id"`
-}
-
-type activateApplicationRequest struct {
-	ID string `json:"id"`
-}
+type (
+	createApplicationRequest          = client.CreateApplicationRequest
+	approveApplicationRequest         = client.ApproveApplicationRequest
+	deprecateApplicationRequest       = client.DeprecateApplicationRequest
+	startApplicationOnboardingRequest = client.StartApplicationOnboardingRequest
+	activateApplicationRequest        = client.ActivateApplicationRequest
+)
 
 //nolint:dupl
 func (s *Server) createApplication(w http.ResponseWriter, r *http.Request) {
@@ -37,20 +24,15 @@ func (s *Server) createApplication(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req createApplicationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.Name == "" || req.TeamID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id, name and teamId are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.CreateApplication(r.Context(), req.ID, req.Name, req.TeamID, "api"); err != nil {
+	if err := s.api.CreateApplication(r.Context(), req.ID, req.Name, req.TeamID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("createApplication error", zap.Error(err))
 		observability.ObserveDomainEvent("application_created", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -64,20 +46,15 @@ func (s *Server) approveApplication(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req approveApplicationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
-		return
-	}
-
-	if err := s.services.ApproveApplication(r.Context(), req.ID, "api"); err != nil {
+	if err := s.api.ApproveApplication(r.Context(), req.ID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("approveApplication error", zap.Error(err))
 		observability.ObserveDomainEvent("application_approved", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -91,20 +68,15 @@ func (s *Server) deprecateApplication(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req deprecateApplicationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.DeprecateApplication(r.Context(), req.ID, "api"); err != nil {
+	if err := s.api.DeprecateApplication(r.Context(), req.ID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("deprecateApplication error", zap.Error(err))
 		observability.ObserveDomainEvent("application_deprecated", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -123,20 +95,15 @@ func (s *Server) startApplicationOnboarding(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req startApplicationOnboardingRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
-		return
-	}
-
-	if err := s.services.StartApplicationOnboarding(r.Context(), req.ID, "workflow-engine"); err != nil {
+	if err := s.api.StartApplicationOnboarding(r.Context(), req.ID, internalActor); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("startApplicationOnboarding error", zap.Error(err))
 		observability.ObserveDomainEvent("application_onboarding_started", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -155,20 +122,15 @@ func (s *Server) activateApplication(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req activateApplicationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.ActivateApplication(r.Context(), req.ID, "workflow-engine"); err != nil {
+	if err := s.api.ActivateApplication(r.Context(), req.ID, internalActor); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("activateApplication error", zap.Error(err))
 		observability.ObserveDomainEvent("application_activated", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
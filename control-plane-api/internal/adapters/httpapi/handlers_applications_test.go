@@ -63,7 +63,7 @@ func TestCreateApplicationEndpoint_FailsWhenTeamNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when team is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -102,7 +102,7 @@ func TestCreateApplicationEndpoint_FailsWhenDuplicateID(t *testing.T) {
 	if rec.Code != http.StatusConflict {
 		t.Fatalf("expected %d when creating duplicate application, got %d", http.StatusConflict, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -159,7 +159,7 @@ func TestApproveApplicationEndpoint_FailsWhenNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -184,6 +184,7 @@ func TestApproveApplicationEndpoint_FailsWhenInvalidState(t *testing.T) {
 		t.Fatalf("expected application, got err=%v app=%v", err, app)
 	}
 	app.State = domain.ApplicationStateApproved
+	app.Metadata.Version++
 	if err := appRepo.Save(ctx, app); err != nil {
 		t.Fatalf("saving app failed: %v", err)
 	}
@@ -199,7 +200,7 @@ func TestApproveApplicationEndpoint_FailsWhenInvalidState(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when approving from invalid state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -224,6 +225,7 @@ func TestDeprecateApplicationEndpoint_TransitionsToDeprecated(t *testing.T) {
 		t.Fatalf("expected application, got err=%v app=%v", err, app)
 	}
 	app.State = domain.ApplicationStateActive
+	app.Metadata.Version++
 	if err := appRepo.Save(ctx, app); err != nil {
 		t.Fatalf("saving app failed: %v", err)
 	}
@@ -264,7 +266,7 @@ func TestDeprecateApplicationEndpoint_FailsWhenNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -305,7 +307,7 @@ func TestDeprecateApplicationEndpoint_FailsWhenInvalidState(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when deprecating from non-Active state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -365,7 +367,7 @@ func TestStartApplicationOnboardingEndpoint_FailsWhenNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -406,7 +408,7 @@ func TestStartApplicationOnboardingEndpoint_FailsWhenInvalidState(t *testing.T)
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when starting onboarding from non-Approved state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -469,7 +471,7 @@ func TestActivateApplicationEndpoint_FailsWhenNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -510,7 +512,7 @@ func TestActivateApplicationEndpoint_FailsWhenInvalidState(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when activating from non-Onboarding state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -538,3 +540,55 @@ func TestStartApplicationOnboardingEndpoint_RequiresInternalAuth(t *testing.T) {
 		t.Fatalf("expected %d when missing internal auth token, got %d", http.StatusUnauthorized, rec.Code)
 	}
 }
+
+func TestApplicationQuery_ConditionalRequests(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	if err := server.services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := server.services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/queries/applications?id=app-1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	approve := func(ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"id": "app-1"})
+		req := httptest.NewRequest(http.MethodPost, "/commands/applications/approve", bytes.NewReader(body))
+		req.Header.Set("If-Match", ifMatch)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag != `"1"` {
+		t.Fatalf("expected 200 with ETag \"1\", got %d %q", first.Code, etag)
+	}
+	if rec := get(etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec := approve(`"7"`); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected %d for stale If-Match, got %d (%s)", http.StatusPreconditionFailed, rec.Code, rec.Body.String())
+	}
+	if rec := approve(etag); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected %d with current If-Match, got %d (%s)", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	// El cambio de estado avanzó la versión: el ETag viejo ya no vale.
+	if rec := get(etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("expected 200 with ETag \"2\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
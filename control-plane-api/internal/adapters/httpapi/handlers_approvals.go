@@ -0,0 +1,157 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+)
+
+type (
+	createApprovalRequestRequest = client.CreateApprovalRequestRequest
+	decideApprovalRequest        = client.DecideApprovalRequest
+)
+
+func approvalRequestFrom(req createApprovalRequestRequest) application.ApprovalRequest {
+	return application.ApprovalRequest{
+		ID:           req.ID,
+		Type:         req.Type,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		RolesAllowed: req.RolesAllowed,
+		Reason:       req.Reason,
+		WorkflowID:   req.WorkflowID,
+		SignalName:   req.SignalName,
+		Timeout:      time.Duration(req.TimeoutSeconds) * time.Second,
+	}
+}
+
+func (s *Server) createApprovalRequest(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req createApprovalRequestRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.CreateApprovalRequest(r.Context(), approvalRequestFrom(req), actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("createApprovalRequest error", zap.Error(err))
+		observability.ObserveDomainEvent("approval_requested", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("approval_requested", "success")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) approveApproval(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, true)
+}
+
+func (s *Server) rejectApproval(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, false)
+}
+
+func (s *Server) decideApproval(w http.ResponseWriter, r *http.Request, approved bool) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req decideApprovalRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	op, event, decide := "approveApproval", "approval_approved", s.api.ApproveApproval
+	if !approved {
+		op, event, decide = "rejectApproval", "approval_rejected", s.api.RejectApproval
+	}
+	if err := decide(r.Context(), req.ID, req.Comment, actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error(op+" error", zap.Error(err))
+		observability.ObserveDomainEvent(event, "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	// La decisión queda en el log de auditoría además de en el pedido.
+	observability.LoggerWithTrace(r.Context(), s.logger).Info("approval decided",
+		zap.Bool("audit", true),
+		zap.String("approval_id", req.ID),
+		zap.Bool("approved", approved),
+		zap.String("by", actor(r)),
+	)
+	observability.ObserveDomainEvent(event, "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) getApproval(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
+		return
+	}
+
+	a, err := s.api.GetApproval(r.Context(), id)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("getApproval error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	if httpx.NotModified(w, r, httpx.VersionETag(a.Metadata.Version)) {
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, a)
+}
+
+// listApprovals lista los pedidos por rol. Sin ?role= se usan los roles de
+// aprobación del principal: cada aprobador ve su bandeja.
+func (s *Server) listApprovals(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q := r.URL.Query()
+	filter := application.ApprovalFilter{Roles: q["role"], State: domain.ApprovalState(q.Get("state"))}
+	if q.Get("state") == "" {
+		filter.State = domain.ApprovalStatePending
+	}
+	if len(filter.Roles) == 0 {
+		if p, ok := auth.PrincipalFromContext(r.Context()); ok && p.Kind != auth.PrincipalAnonymous {
+			for _, role := range application.ManualApprovalRoles {
+				if p.HasRole(role) {
+					filter.Roles = append(filter.Roles, role)
+				}
+			}
+			if len(filter.Roles) == 0 {
+				httpx.WriteJSON(w, http.StatusOK, []*domain.Approval{})
+				return
+			}
+		}
+	}
+
+	approvals, err := s.api.ListApprovals(r.Context(), filter)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("listApprovals error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, approvals)
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+type recordingSignaler struct {
+	workflows []string
+}
+
+func (r *recordingSignaler) Signal(_ context.Context, workflowID, signalName string, _ any) error {
+	r.workflows = append(r.workflows, workflowID+" "+signalName)
+	return nil
+}
+
+func TestApprovals_CreateListAndDecide(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	signaler := &recordingSignaler{}
+	server.services.Signals = signaler
+	mux := server.Routes()
+	ctx := context.Background()
+	_ = server.services.CreateTeam(ctx, "team-1", "Platform", "alice")
+	_ = server.services.CreateApplication(ctx, "app-1", "App", "team-1", "alice")
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw)))
+		return rec
+	}
+
+	rec := post("/commands/approvals", map[string]any{
+		"id":             "decommission-app-1",
+		"type":           "ApplicationDecommissioning",
+		"resourceType":   "Application",
+		"resourceId":     "app-1",
+		"rolesAllowed":   []string{application.RolePlatformAdmin},
+		"workflowId":     "application-decommissioning-app-1",
+		"signalName":     "DecommissioningApproval",
+		"timeoutSeconds": 172800,
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/approval-requests?role=platformAdmin", nil))
+	var pending []domain.Approval
+	_ = json.Unmarshal(rec.Body.Bytes(), &pending)
+	if rec.Code != http.StatusOK || len(pending) != 2 {
+		t.Fatalf("expected application and decommissioning approvals, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post("/commands/approvals/approve", map[string]any{"id": "decommission-app-1", "comment": "retire it"})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(signaler.workflows) != 1 || signaler.workflows[0] != "application-decommissioning-app-1 DecommissioningApproval" {
+		t.Fatalf("unexpected signals %+v", signaler.workflows)
+	}
+
+	rec = post("/commands/approvals/reject", map[string]any{"id": application.ApplicationApprovalID("app-1"), "comment": "not yet"})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/approvals?id=decommission-app-1", nil))
+	var got domain.Approval
+	_ = json.Unmarshal(rec.Body.Bytes(), &got)
+	if rec.Code != http.StatusOK || got.State != domain.ApprovalStateApproved || got.Decision == nil || got.Decision.Comment != "retire it" {
+		t.Fatalf("unexpected approval %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/approval-requests", nil))
+	_ = json.Unmarshal(rec.Body.Bytes(), &pending)
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending approvals, got %s", rec.Body.String())
+	}
+
+	rec = post("/commands/approvals/approve", map[string]any{"id": "decommission-app-1"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an already decided approval, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,242 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/health"
+)
+
+// newTestVerifier genera una clave RSA, publica su JWKS en un fichero
+// temporal y devuelve el Verifier junto con una función para firmar tokens
+// (extra agrega claims, por ejemplo roles o groups).
+func newTestVerifier(t *testing.T) (*auth.Verifier, func(sub string, extra map[string]any) string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "k1", "use": "sig", "alg": "RS256",
+		"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	sign := func(sub string, extra map[string]any) string {
+		header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+		payload := map[string]any{"sub": sub, "exp": time.Now().Add(time.Hour).Unix()}
+		for k, v := range extra {
+			payload[k] = v
+		}
+		claims, _ := json.Marshal(payload)
+		input := b64(header) + "." + b64(claims)
+		digest := sha256.Sum256([]byte(input))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return input + "." + b64(sig)
+	}
+
+	return auth.NewVerifier(auth.NewFileKeySet(path), auth.VerifierConfig{}), sign
+}
+
+func postJSON(mux http.Handler, path string, body map[string]string, headers map[string]string) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth_RejectsRequestsWithoutBearerToken(t *testing.T) {
+	verifier, _ := newTestVerifier(t)
+	server, _, _, _, _, _, _, _, _, _ := newTestServer(WithVerifier(verifier))
+	mux := server.Routes()
+
+	rec := postJSON(mux, "/commands/teams", map[string]string{"id": "team-1", "name": "Platform"}, nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected WWW-Authenticate challenge")
+	}
+
+	rec = postJSON(mux, "/commands/teams", map[string]string{"id": "team-1", "name": "Platform"},
+		map[string]string{"Authorization": "Bearer not-a-token"})
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d for invalid token, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	hrec := httptest.NewRecorder()
+	mux.ServeHTTP(hrec, req)
+	if hrec.Code != http.StatusOK {
+		t.Fatalf("expected /healthz to stay unauthenticated, got %d", hrec.Code)
+	}
+}
+
+func TestHealth_ReadinessReflectsChecksAndStaysUnauthenticated(t *testing.T) {
+	verifier, _ := newTestVerifier(t)
+	checker := health.NewChecker(time.Second)
+	var dbErr error
+	checker.Require("postgres", func(context.Context) error { return dbErr })
+	server, _, _, _, _, _, _, _, _, _ := newTestServer(WithVerifier(verifier), WithHealth(checker))
+	mux := server.Routes()
+
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+	if code := readyz(); code != http.StatusOK {
+		t.Fatalf("expected /readyz 200, got %d", code)
+	}
+	dbErr = errors.New("connection refused")
+	if code := readyz(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected /readyz 503 with postgres down, got %d", code)
+	}
+	dbErr = nil
+	checker.SetDraining()
+	if code := readyz(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected /readyz 503 while draining, got %d", code)
+	}
+}
+
+func TestAuth_RecordsAuthenticatedActor(t *testing.T) {
+	verifier, sign := newTestVerifier(t)
+	server, teamRepo, appRepo, _, _, _, _, _, _, _ := newTestServer(WithVerifier(verifier))
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	alice := map[string]string{"Authorization": "Bearer " + sign("alice", map[string]any{"roles": []string{"platformAdmin"}})}
+	bob := map[string]string{"Authorization": "Bearer " + sign("bob", map[string]any{"roles": []string{"securityAdmin"}})}
+
+	if rec := postJSON(mux, "/commands/teams", map[string]string{"id": "team-1", "name": "Platform"}, alice); rec.Code != http.StatusCreated {
+		t.Fatalf("create team: expected %d, got %d (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	if rec := postJSON(mux, "/commands/applications", map[string]string{"id": "app-1", "name": "App", "teamId": "team-1"}, alice); rec.Code != http.StatusCreated {
+		t.Fatalf("create application: expected %d, got %d (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	if rec := postJSON(mux, "/commands/applications/approve", map[string]string{"id": "app-1"}, bob); rec.Code != http.StatusAccepted {
+		t.Fatalf("approve application: expected %d, got %d (%s)", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	team, _ := teamRepo.GetByID(ctx, "team-1")
+	if team.Metadata.CreatedBy != "alice" {
+		t.Fatalf("expected team createdBy 'alice', got %q", team.Metadata.CreatedBy)
+	}
+
+	app, _ := appRepo.GetByID(ctx, "app-1")
+	if app.Metadata.CreatedBy != "alice" {
+		t.Fatalf("expected application createdBy 'alice', got %q", app.Metadata.CreatedBy)
+	}
+	if len(app.Metadata.History) != 1 {
+		t.Fatalf("expected one transition in history, got %+v", app.Metadata.History)
+	}
+	if tr := app.Metadata.History[0]; tr.By != "bob" || tr.From != "Proposed" || tr.To != "Approved" {
+		t.Fatalf("unexpected transition %+v", tr)
+	}
+}
+
+func TestAuth_AcceptsInternalTokenForWorkflowEngine(t *testing.T) {
+	t.Setenv("INTERNAL_AUTH_TOKEN", "s3cr3t")
+	verifier, sign := newTestVerifier(t)
+	server, _, _, _, _, _, _, codeRepo, _, _ := newTestServer(WithVerifier(verifier))
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	alice := map[string]string{"Authorization": "Bearer " + sign("alice", map[string]any{"roles": []string{"platformAdmin"}})}
+	postJSON(mux, "/commands/teams", map[string]string{"id": "team-1", "name": "Platform"}, alice)
+	postJSON(mux, "/commands/applications", map[string]string{"id": "app-1", "name": "App", "teamId": "team-1"}, alice)
+
+	rec := postJSON(mux, "/commands/code-repositories", map[string]string{"id": "repo-1", "applicationId": "app-1"},
+		map[string]string{internalAuthHeader: "s3cr3t"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	repo, _ := codeRepo.GetByID(ctx, "repo-1")
+	if repo.Metadata.CreatedBy != internalActor {
+		t.Fatalf("expected createdBy %q, got %q", internalActor, repo.Metadata.CreatedBy)
+	}
+}
+
+func TestAuthz_ReturnsForbiddenProblemOutsideTeam(t *testing.T) {
+	verifier, sign := newTestVerifier(t)
+	server, _, _, _, _, secretRepo, _, _, _, _ := newTestServer(WithVerifier(verifier))
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	if err := server.services.CreateTeam(ctx, "team-a", "A", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	outsider := map[string]string{"Authorization": "Bearer " + sign("ben", map[string]any{"groups": []string{"team-b"}})}
+	rec := postJSON(mux, "/commands/secrets", map[string]string{"id": "sec-1", "ownerTeamId": "team-a", "purpose": "runtime", "sensitivity": "high"}, outsider)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+	var problem map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+	if problem["code"] != "forbidden" {
+		t.Fatalf("expected code 'forbidden', got %v", problem["code"])
+	}
+	if sec, _ := secretRepo.GetByID(ctx, "sec-1"); sec != nil {
+		t.Fatalf("expected secret not to be created")
+	}
+
+	member := map[string]string{"Authorization": "Bearer " + sign("ana", map[string]any{"groups": []string{"team-a"}})}
+	rec = postJSON(mux, "/commands/secrets", map[string]string{"id": "sec-1", "ownerTeamId": "team-a", "purpose": "runtime", "sensitivity": "high"}, member)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d for team member, got %d (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+}
+
+func TestRateLimit_ThrottlesPerPrincipal(t *testing.T) {
+	t.Setenv("RATE_LIMIT_COMMANDS_PRINCIPAL", "1/1")
+	verifier, sign := newTestVerifier(t)
+	server, _, _, _, _, _, _, _, _, _ := newTestServer(WithVerifier(verifier))
+	mux := server.Routes()
+
+	alice := map[string]string{"Authorization": "Bearer " + sign("alice", map[string]any{"roles": []string{"platformAdmin"}})}
+	bob := map[string]string{"Authorization": "Bearer " + sign("bob", map[string]any{"roles": []string{"platformAdmin"}})}
+
+	if rec := postJSON(mux, "/commands/teams", map[string]string{"id": "team-1", "name": "One"}, alice); rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	rec := postJSON(mux, "/commands/teams", map[string]string{"id": "team-2", "name": "Two"}, alice)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusTooManyRequests, rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+	if rec := postJSON(mux, "/commands/teams", map[string]string{"id": "team-2", "name": "Two"}, bob); rec.Code != http.StatusCreated {
+		t.Fatalf("expected a separate bucket for bob, got %d (%s)", rec.Code, rec.Body.String())
+	}
+}
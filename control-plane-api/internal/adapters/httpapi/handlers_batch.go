@@ -0,0 +1,240 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/openapi"
+	"go.uber.org/zap"
+)
+
+type (
+	batchRequest        = client.BatchRequest
+	batchCommandRequest = client.BatchCommand
+	batchResponse       = client.BatchResponse
+	batchItemResult     = client.BatchResult
+)
+
+// batchCommand ejecuta un comando del batch a partir de su body crudo.
+type batchCommand func(ctx context.Context, api application.API, body json.RawMessage, by string) error
+
+// batchCmd adapta un caso de uso a batchCommand: el body se valida contra el
+// mismo schema que en el endpoint individual.
+func batchCmd[T any](run func(ctx context.Context, api application.API, req T, by string) error) batchCommand {
+	return func(ctx context.Context, api application.API, body json.RawMessage, by string) error {
+		var req T
+		if err := openapi.DecodeJSONBytes(body, &req); err != nil {
+			return err
+		}
+		return run(ctx, api, req, by)
+	}
+}
+
+// batchCommands son los comandos que se pueden incluir en un batch, por
+// operationId.
+var batchCommands = map[string]batchCommand{
+	"createOrganization": batchCmd(func(ctx context.Context, api application.API, req createOrganizationRequest, by string) error {
+		return api.CreateOrganization(ctx, req.ID, req.Name, by)
+	}),
+	"createTeam": batchCmd(func(ctx context.Context, api application.API, req createTeamRequest, by string) error {
+		return api.CreateTeam(ctx, req.ID, req.Name, by)
+	}),
+	"setTeamQuota": batchCmd(func(ctx context.Context, api application.API, req setTeamQuotaRequest, by string) error {
+		return api.SetTeamQuota(ctx, req.ID, teamQuota(req), by)
+	}),
+	"createApplication": batchCmd(func(ctx context.Context, api application.API, req createApplicationRequest, by string) error {
+		return api.CreateApplication(ctx, req.ID, req.Name, req.TeamID, by)
+	}),
+	"approveApplication": batchCmd(func(ctx context.Context, api application.API, req approveApplicationRequest, by string) error {
+		return api.ApproveApplication(ctx, req.ID, by)
+	}),
+	"startApplicationOnboarding": batchCmd(func(ctx context.Context, api application.API, req startApplicationOnboardingRequest, by string) error {
+		return api.StartApplicationOnboarding(ctx, req.ID, by)
+	}),
+	"activateApplication": batchCmd(func(ctx context.Context, api application.API, req activateApplicationRequest, by string) error {
+		return api.ActivateApplication(ctx, req.ID, by)
+	}),
+	"deprecateApplication": batchCmd(func(ctx context.Context, api application.API, req deprecateApplicationRequest, by string) error {
+		return api.DeprecateApplication(ctx, req.ID, by)
+	}),
+	"startApplicationDecommissioning": batchCmd(func(ctx context.Context, api application.API, req startApplicationDecommissioningRequest, by string) error {
+		return api.StartApplicationDecommissioning(ctx, req.ID, by)
+	}),
+	"archiveApplication": batchCmd(func(ctx context.Context, api application.API, req archiveApplicationRequest, by string) error {
+		return api.ArchiveApplication(ctx, req.ID, by)
+	}),
+	"createEnvironment": batchCmd(func(ctx context.Context, api application.API, req createEnvironmentRequest, by string) error {
+		return api.CreateEnvironment(ctx, req.ID, req.Name, by)
+	}),
+	"declareApplicationEnvironment": batchCmd(func(ctx context.Context, api application.API, req declareApplicationEnvironmentRequest, by string) error {
+		return api.DeclareApplicationEnvironment(ctx, req.ID, req.ApplicationID, req.EnvironmentID, by)
+	}),
+	"completeApplicationEnvironmentProvisioning": batchCmd(func(ctx context.Context, api application.API, req completeApplicationEnvironmentProvisioningRequest, by string) error {
+		return api.CompleteApplicationEnvironmentProvisioning(ctx, req.ID, by)
+	}),
+	"decommissionApplicationEnvironment": batchCmd(func(ctx context.Context, api application.API, req decommissionApplicationEnvironmentRequest, by string) error {
+		return api.DecommissionApplicationEnvironment(ctx, req.ID, by)
+	}),
+	"createSecret": batchCmd(func(ctx context.Context, api application.API, req createSecretRequest, by string) error {
+		return api.CreateSecret(ctx, req.ID, req.OwnerTeamID, req.Purpose, req.Sensitivity, by)
+	}),
+	"startSecretRotation": batchCmd(func(ctx context.Context, api application.API, req startSecretRotationRequest, by string) error {
+		return api.StartSecretRotation(ctx, req.ID, by)
+	}),
+	"completeSecretRotation": batchCmd(func(ctx context.Context, api application.API, req completeSecretRotationRequest, by string) error {
+		return api.CompleteSecretRotation(ctx, req.ID, by)
+	}),
+	"declareSecretBinding": batchCmd(func(ctx context.Context, api application.API, req declareSecretBindingRequest, by string) error {
+		return api.DeclareSecretBinding(ctx, req.ID, req.SecretID, req.TargetID, req.TargetType, by)
+	}),
+	"revokeSecretBinding": batchCmd(func(ctx context.Context, api application.API, req revokeSecretBindingRequest, by string) error {
+		return api.RevokeSecretBinding(ctx, req.ID, by)
+	}),
+	"declareCodeRepository": batchCmd(func(ctx context.Context, api application.API, req declareCodeRepositoryRequest, by string) error {
+		return api.DeclareCodeRepository(ctx, req.ID, req.ApplicationID, by)
+	}),
+	"declareDeploymentRepository": batchCmd(func(ctx context.Context, api application.API, req declareDeploymentRepositoryRequest, by string) error {
+		return api.DeclareDeploymentRepository(ctx, req.ID, req.ApplicationID, req.DeploymentModel, by)
+	}),
+	"declareGitOpsIntegration": batchCmd(func(ctx context.Context, api application.API, req declareGitOpsIntegrationRequest, by string) error {
+		return api.DeclareGitOpsIntegration(ctx, req.ID, req.ApplicationID, req.DeploymentRepoID, by)
+	}),
+	"createWebhookSubscription": batchCmd(func(ctx context.Context, api application.API, req createWebhookSubscriptionRequest, by string) error {
+		return api.CreateWebhookSubscription(ctx, req.ID, req.TeamID, req.URL, req.Secret, webhookFilter(req.Filter), by)
+	}),
+	"disableWebhookSubscription": batchCmd(func(ctx context.Context, api application.API, req disableWebhookSubscriptionRequest, by string) error {
+		return api.DisableWebhookSubscription(ctx, req.ID, by)
+	}),
+	"redeliverWebhookDelivery": batchCmd(func(ctx context.Context, api application.API, req redeliverWebhookDeliveryRequest, by string) error {
+		return api.RedeliverWebhookDelivery(ctx, req.ID, by)
+	}),
+	"createApprovalRequest": batchCmd(func(ctx context.Context, api application.API, req createApprovalRequestRequest, by string) error {
+		return api.CreateApprovalRequest(ctx, approvalRequestFrom(req), by)
+	}),
+	"approveApproval": batchCmd(func(ctx context.Context, api application.API, req decideApprovalRequest, by string) error {
+		return api.ApproveApproval(ctx, req.ID, req.Comment, by)
+	}),
+	"rejectApproval": batchCmd(func(ctx context.Context, api application.API, req decideApprovalRequest, by string) error {
+		return api.RejectApproval(ctx, req.ID, req.Comment, by)
+	}),
+}
+
+func (s *Server) runBatch(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	// Cada comando lleva su propio expectedVersion: un If-Match para todo el
+	// batch sería ambiguo.
+	if r.Header.Get("If-Match") != "" {
+		writeDomainError(w, r, perrors.Validation("if_match_not_supported", "use expectedVersion on each command instead of If-Match", nil))
+		return
+	}
+
+	var req batchRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	// Los comandos desconocidos invalidan el batch entero antes de ejecutar
+	// nada, igual que un body mal formado.
+	var fields []perrors.FieldError
+	for i, c := range req.Commands {
+		if _, ok := batchCommands[c.Command]; !ok {
+			fields = append(fields, perrors.FieldError{Field: "commands[" + strconv.Itoa(i) + "].command", Message: "unknown command " + c.Command})
+		}
+	}
+	if len(fields) > 0 {
+		writeDomainError(w, r, perrors.Validation("invalid_request_body", "invalid request body: unknown commands", nil).WithFields(fields...))
+		return
+	}
+
+	dryRun, ok := dryRunRequested(w, r)
+	if !ok {
+		return
+	}
+	steps := batchSteps(req.Commands, actor(r))
+	if dryRun {
+		s.dryRunBatch(w, r, req, steps)
+		return
+	}
+
+	errs, err := s.api.RunBatch(r.Context(), application.BatchMode(req.Mode), steps)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("runBatch error", zap.Error(err))
+		observability.ObserveDomainEvent("batch_executed", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	statuses := s.commandStatuses()
+	resp := batchResponse{Mode: req.Mode, Results: make([]batchItemResult, len(req.Commands))}
+	for i, c := range req.Commands {
+		resp.Results[i] = batchResult(i, c.Command, statuses[c.Command], errs[i])
+		if errs[i] == nil {
+			resp.Committed = true
+		}
+	}
+
+	observability.ObserveDomainEvent("batch_executed", "success")
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
+
+// batchSteps arma un BatchStep por comando; expectedVersion hace las veces
+// del If-Match de cada uno.
+func batchSteps(commands []batchCommandRequest, by string) []application.BatchStep {
+	steps := make([]application.BatchStep, len(commands))
+	for i, c := range commands {
+		run, body, expected := batchCommands[c.Command], c.Body, c.ExpectedVersion
+		steps[i] = func(ctx context.Context, api application.API) error {
+			if expected > 0 {
+				ctx = application.WithExpectedVersion(ctx, expected)
+			}
+			return run(ctx, api, body, by)
+		}
+	}
+	return steps
+}
+
+// batchResult es el resultado del comando i: status es el de éxito de su
+// endpoint salvo que haya fallado con err.
+func batchResult(i int, command string, status int, err error) batchItemResult {
+	res := batchItemResult{Index: i, Command: command, Status: status}
+	if err != nil {
+		res.Status = httpx.StatusFor(err)
+		res.Code = perrors.Code(err)
+		res.Message = batchErrorMessage(err)
+		res.Errors = perrors.Fields(err)
+	}
+	return res
+}
+
+// commandStatuses mapea cada operationId de comando al status de éxito de
+// su endpoint.
+func (s *Server) commandStatuses() map[string]int {
+	out := make(map[string]int)
+	for _, rt := range s.routeTable() {
+		if rt.op.Method == http.MethodPost {
+			out[rt.op.ID] = rt.op.Status
+		}
+	}
+	return out
+}
+
+// batchErrorMessage devuelve el mensaje público del error, sin la causa
+// interna que pudiera envolver.
+func batchErrorMessage(err error) string {
+	var pe *perrors.Error
+	if errors.As(err, &pe) {
+		return pe.Message
+	}
+	return "internal error"
+}
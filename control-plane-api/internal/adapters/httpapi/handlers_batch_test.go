@@ -0,0 +1,164 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func postBatch(t *testing.T, mux http.Handler, payload any) (*httptest.ResponseRecorder, batchResponse) {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/commands:batch", bytes.NewReader(body)))
+
+	var resp batchResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid batch response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func bootstrapCommands(teamID string) []map[string]any {
+	return []map[string]any{
+		{"command": "createTeam", "body": map[string]any{"id": "team-1", "name": "Platform"}},
+		{"command": "createEnvironment", "body": map[string]any{"id": "dev", "name": "Dev"}},
+		{"command": "createApplication", "body": map[string]any{"id": "app-1", "name": "App", "teamId": teamID}},
+		{"command": "approveApplication", "expectedVersion": 1, "body": map[string]any{"id": "app-1"}},
+	}
+}
+
+func TestBatchEndpoint_AtomicAppliesAllCommands(t *testing.T) {
+	server, teamRepo, appRepo, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	rec, resp := postBatch(t, mux, map[string]any{"mode": "atomic", "commands": bootstrapCommands("team-1")})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !resp.Committed || len(resp.Results) != 4 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	wantStatus := []int{http.StatusCreated, http.StatusCreated, http.StatusCreated, http.StatusAccepted}
+	for i, res := range resp.Results {
+		if res.Index != i || res.Status != wantStatus[i] || res.Code != "" {
+			t.Errorf("result %d: unexpected %+v", i, res)
+		}
+	}
+
+	ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+	if team, _ := teamRepo.GetByID(ctx, "team-1"); team == nil {
+		t.Fatalf("expected team to be created")
+	}
+	if app, _ := appRepo.GetByID(ctx, "app-1"); app == nil || app.State != "Approved" {
+		t.Fatalf("expected approved application, got %+v", app)
+	}
+}
+
+func TestBatchEndpoint_AtomicReportsFailureAndRollsBack(t *testing.T) {
+	server, teamRepo, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	rec, resp := postBatch(t, mux, map[string]any{"mode": "atomic", "commands": bootstrapCommands("missing")})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if resp.Committed {
+		t.Fatalf("expected nothing to be committed")
+	}
+	want := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusConflict, "batch_rolled_back"},
+		{http.StatusConflict, "batch_rolled_back"},
+		{http.StatusNotFound, "team_not_found"},
+		{http.StatusConflict, "batch_aborted"},
+	}
+	for i, w := range want {
+		if got := resp.Results[i]; got.Status != w.status || got.Code != w.code || got.Message == "" {
+			t.Errorf("result %d: expected %d %s, got %+v", i, w.status, w.code, got)
+		}
+	}
+	if team, _ := teamRepo.GetByID(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "team-1"); team != nil {
+		t.Fatalf("expected team creation to be rolled back")
+	}
+}
+
+func TestBatchEndpoint_BestEffortReportsPerItem(t *testing.T) {
+	server, teamRepo, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	commands := bootstrapCommands("team-1")
+	commands[1]["body"] = map[string]any{"id": "dev"} // falta name
+	commands[3]["expectedVersion"] = 9
+
+	rec, resp := postBatch(t, mux, map[string]any{"mode": "bestEffort", "commands": commands})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !resp.Committed {
+		t.Fatalf("expected partial commit")
+	}
+	if r := resp.Results[1]; r.Status != http.StatusBadRequest || r.Code != "invalid_request_body" || len(r.Errors) != 1 || r.Errors[0].Field != "name" {
+		t.Fatalf("expected field error for name, got %+v", r)
+	}
+	if r := resp.Results[2]; r.Status != http.StatusCreated {
+		t.Fatalf("expected application to be created, got %+v", r)
+	}
+	if r := resp.Results[3]; r.Status != http.StatusPreconditionFailed || r.Code != "version_mismatch" {
+		t.Fatalf("expected version_mismatch, got %+v", r)
+	}
+	if team, _ := teamRepo.GetByID(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "team-1"); team == nil {
+		t.Fatalf("expected team to be created")
+	}
+}
+
+func TestBatchEndpoint_RejectsInvalidBatches(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	cases := []struct {
+		name    string
+		payload any
+		code    string
+	}{
+		{"unknown command", map[string]any{"mode": "atomic", "commands": []map[string]any{{"command": "dropTeam", "body": map[string]any{}}}}, "invalid_request_body"},
+		{"invalid mode", map[string]any{"mode": "sometimes", "commands": bootstrapCommands("team-1")}, "invalid_batch_mode"},
+		{"empty", map[string]any{"mode": "atomic", "commands": []any{}}, "empty_batch"},
+		{"missing body", map[string]any{"mode": "atomic", "commands": []map[string]any{{"command": "createTeam"}}}, "invalid_request_body"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, _ := postBatch(t, mux, tc.payload)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var problem struct {
+				Code string `json:"code"`
+			}
+			_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+			if problem.Code != tc.code {
+				t.Fatalf("expected %s, got %s", tc.code, problem.Code)
+			}
+		})
+	}
+}
+
+// Sin batchCommand, un comando tampoco admite ?dryRun=true (responde 400
+// dry_run_not_supported).
+func TestBatchCommands_CoverEveryCommandRoute(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	for _, rt := range server.routeTable() {
+		if rt.op.Method != http.MethodPost || rt.op.ID == batchRouteID {
+			continue
+		}
+		if _, ok := batchCommands[rt.op.ID]; !ok {
+			t.Errorf("command %s is not available in /commands:batch nor with ?dryRun=true", rt.op.ID)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+)
+
+type (
+	startApplicationDecommissioningRequest    = client.StartApplicationDecommissioningRequest
+	decommissionApplicationEnvironmentRequest = client.DecommissionApplicationEnvironmentRequest
+	revokeSecretBindingRequest                = client.RevokeSecretBindingRequest
+	archiveApplicationRequest                 = client.ArchiveApplicationRequest
+)
+
+// Los pasos del decommissioning los ejecuta el workflow
+// ApplicationDecommissioning después de la aprobación; por eso son de uso
+// interno, salvo revokeSecretBinding, que securityAdmin también puede usar
+// para cortar un binding a mano.
+
+//nolint:dupl
+func (s *Server) startApplicationDecommissioning(w http.ResponseWriter, r *http.Request) {
+	if !requireInternalAuth(w, r) {
+		return
+	}
+
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req startApplicationDecommissioningRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.StartApplicationDecommissioning(r.Context(), req.ID, internalActor); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("startApplicationDecommissioning error", zap.Error(err))
+		observability.ObserveDomainEvent("application_decommissioning_started", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("application_decommissioning_started", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+//nolint:dupl
+func (s *Server) decommissionApplicationEnvironment(w http.ResponseWriter, r *http.Request) {
+	if !requireInternalAuth(w, r) {
+		return
+	}
+
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req decommissionApplicationEnvironmentRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.DecommissionApplicationEnvironment(r.Context(), req.ID, internalActor); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("decommissionApplicationEnvironment error", zap.Error(err))
+		observability.ObserveDomainEvent("application_environment_decommissioned", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("application_environment_decommissioned", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) revokeSecretBinding(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req revokeSecretBindingRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.RevokeSecretBinding(r.Context(), req.ID, actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("revokeSecretBinding error", zap.Error(err))
+		observability.ObserveDomainEvent("secret_binding_revoked", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("secret_binding_revoked", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+//nolint:dupl
+func (s *Server) archiveApplication(w http.ResponseWriter, r *http.Request) {
+	if !requireInternalAuth(w, r) {
+		return
+	}
+
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req archiveApplicationRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.ArchiveApplication(r.Context(), req.ID, internalActor); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("archiveApplication error", zap.Error(err))
+		observability.ObserveDomainEvent("application_archived", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("application_archived", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) listApplicationEnvironments(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	applicationID, ok := httpx.RequireQuery(w, r, "applicationId")
+	if !ok {
+		return
+	}
+
+	appEnvs, err := s.api.ListApplicationEnvironments(r.Context(), applicationID)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("listApplicationEnvironments error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, appEnvs)
+}
+
+func (s *Server) listSecretBindings(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	targetType, ok := httpx.RequireQuery(w, r, "targetType")
+	if !ok {
+		return
+	}
+	targetID, ok := httpx.RequireQuery(w, r, "targetId")
+	if !ok {
+		return
+	}
+
+	bindings, err := s.api.ListSecretBindings(r.Context(), targetType, targetID)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("listSecretBindings error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, bindings)
+}
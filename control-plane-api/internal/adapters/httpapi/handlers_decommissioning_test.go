@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+func TestDecommissioningEndpoints_ArchiveApplication(t *testing.T) {
+	server, _, appRepo, _, appEnvRepo, _, bindingRepo, _, _, _ := newTestServer()
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	if err := server.services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := server.services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	app, _ := appRepo.GetByID(ctx, "app-1")
+	app.State = domain.ApplicationStateDeprecated
+	app.Metadata.Version++
+	_ = appRepo.Save(ctx, app)
+	_ = appEnvRepo.Save(ctx, &domain.ApplicationEnvironment{ID: "appenv-1", ApplicationID: "app-1", EnvironmentID: "env-1", State: domain.ApplicationEnvironmentStateActive})
+	_ = bindingRepo.Save(ctx, &domain.SecretBinding{ID: "bind-1", SecretID: "sec-1", TargetID: "appenv-1", TargetType: domain.SecretBindingTargetApplicationEnvironment, State: domain.SecretBindingStateActive})
+
+	if rec := postJSON(mux, "/commands/applications/start-decommissioning", map[string]string{"id": "app-1"}, nil); rec.Code != http.StatusAccepted {
+		t.Fatalf("start-decommissioning: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/application-environments:list?applicationId=app-1", nil))
+	var appEnvs []domain.ApplicationEnvironment
+	if err := json.Unmarshal(rec.Body.Bytes(), &appEnvs); err != nil || rec.Code != http.StatusOK || len(appEnvs) != 1 {
+		t.Fatalf("expected one application environment, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := postJSON(mux, "/commands/application-environments/decommission", map[string]string{"id": "appenv-1"}, nil); rec.Code != http.StatusAccepted {
+		t.Fatalf("decommission: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := postJSON(mux, "/commands/applications/archive", map[string]string{"id": "app-1"}, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("archive with an active binding: expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/secret-bindings?targetType=ApplicationEnvironment&targetId=appenv-1", nil))
+	var bindings []domain.SecretBinding
+	if err := json.Unmarshal(rec.Body.Bytes(), &bindings); err != nil || rec.Code != http.StatusOK || len(bindings) != 1 {
+		t.Fatalf("expected one secret binding, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := postJSON(mux, "/commands/secret-bindings/revoke", map[string]string{"id": "bind-1"}, nil); rec.Code != http.StatusAccepted {
+		t.Fatalf("revoke: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := postJSON(mux, "/commands/applications/archive", map[string]string{"id": "app-1"}, nil); rec.Code != http.StatusAccepted {
+		t.Fatalf("archive: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if app, _ := appRepo.GetByID(ctx, "app-1"); app.State != domain.ApplicationStateArchived {
+		t.Fatalf("expected Archived, got %q", app.State)
+	}
+}
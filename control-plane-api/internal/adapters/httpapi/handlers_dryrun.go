@@ -0,0 +1,155 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/openapi"
+	"go.uber.org/zap"
+)
+
+type dryRunResponse = client.DryRunResult
+
+// dryRunQuery es el parámetro que pide validar un comando sin aplicarlo.
+const dryRunQuery = "dryRun"
+
+var dryRunParam = openapi.Param{
+	Name:        dryRunQuery,
+	In:          "query",
+	Description: "true valida el comando (incluida la autorización) sin aplicarlo y responde 200 con el recurso como habría quedado",
+}
+
+// dryRunRequested lee ?dryRun. Un valor que no es booleano escribe un 400
+// y devuelve ok=false.
+func dryRunRequested(w http.ResponseWriter, r *http.Request) (dryRun, ok bool) {
+	v := r.URL.Query().Get(dryRunQuery)
+	if v == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		writeDomainError(w, r, perrors.Validation("invalid_query", dryRunQuery+" must be true or false", err).
+			WithFields(perrors.FieldError{Field: dryRunQuery, Message: "must be true or false"}))
+		return false, false
+	}
+	return dryRun, true
+}
+
+// withDryRun deriva a dryRun las requests con ?dryRun=true y el resto a
+// next. La rama de dry-run no pasa por idempotencia ni por auditoría: no
+// cambia nada que haya que deduplicar o registrar.
+func withDryRun(next, dryRun http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested, ok := dryRunRequested(w, r)
+		if !ok {
+			return
+		}
+		if requested {
+			dryRun.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// dryRunHandler es el handler de rt con ?dryRun=true. Los comandos corren
+// el mismo batchCommand que en /commands:batch; el batch decide él mismo
+// según el parámetro. Un comando sin batchCommand responde 400
+// dry_run_not_supported: nunca se aplica en lugar de simularse.
+func (s *Server) dryRunHandler(rt route) http.Handler {
+	if rt.op.ID == batchRouteID {
+		return rt.handler
+	}
+	run, ok := batchCommands[rt.op.ID]
+	if !ok {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeDomainError(w, r, perrors.Validation("dry_run_not_supported", rt.op.ID+" does not support "+dryRunQuery, nil))
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !httpx.RequireMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, openapi.MaxBodyBytes+1))
+		if err != nil {
+			writeDomainError(w, r, perrors.Validation("invalid_request_body", "could not read request body", err))
+			return
+		}
+		if len(body) > openapi.MaxBodyBytes {
+			writeDomainError(w, r, perrors.Validation("request_body_too_large", "request body too large", nil))
+			return
+		}
+
+		by := actor(r)
+		results, err := s.api.DryRun(r.Context(), application.BatchAtomic, []application.BatchStep{
+			func(ctx context.Context, api application.API) error { return run(ctx, api, body, by) },
+		})
+		if err == nil {
+			err = results[0].Err
+		}
+		if err != nil {
+			logger := observability.LoggerWithTrace(r.Context(), s.logger)
+			logger.Info("dry-run rejected", zap.String("command", rt.op.ID), zap.Error(err))
+			writeDomainError(w, r, err)
+			return
+		}
+
+		resource, err := marshalResource(results[0].Resource)
+		if err != nil {
+			writeDomainError(w, r, perrors.Internal("dry_run_encode_failed", "error encoding dry-run resource", err))
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, dryRunResponse{Command: rt.op.ID, Status: rt.op.Status, Resource: resource})
+	})
+}
+
+// dryRunBatch responde un batch con ?dryRun=true: los mismos results que
+// el batch real, más el recurso de cada comando que se habría aplicado.
+func (s *Server) dryRunBatch(w http.ResponseWriter, r *http.Request, req batchRequest, steps []application.BatchStep) {
+	results, err := s.api.DryRun(r.Context(), application.BatchMode(req.Mode), steps)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("runBatch dry-run error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	statuses := s.commandStatuses()
+	resp := batchResponse{Mode: req.Mode, DryRun: true, Results: make([]batchItemResult, len(req.Commands))}
+	for i, c := range req.Commands {
+		res := batchResult(i, c.Command, statuses[c.Command], results[i].Err)
+		if results[i].Err == nil {
+			resource, err := marshalResource(results[i].Resource)
+			if err != nil {
+				writeDomainError(w, r, perrors.Internal("dry_run_encode_failed", "error encoding dry-run resource", err))
+				return
+			}
+			res.Resource = resource
+		}
+		resp.Results[i] = res
+	}
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
+
+// marshalResource serializa el recurso de un dry-run; nil si el comando no
+// habría guardado nada.
+func marshalResource(resource any) (json.RawMessage, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("marshal dry-run resource: %w", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/openapi"
+)
+
+func TestDryRun_ReturnsResourceWithoutApplying(t *testing.T) {
+	server, teamRepo, appRepo, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+	ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+	_ = server.services.CreateTeam(ctx, "team-1", "Platform", "test")
+
+	app := map[string]string{"id": "app-1", "name": "billing", "teamId": "team-1"}
+	rec := postJSON(mux, "/commands/applications?dryRun=true", app, map[string]string{httpx.IdempotencyKeyHeader: "k-1"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp dryRunResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid dry-run response: %v", err)
+	}
+	var resource struct {
+		ID    string `json:"id"`
+		State string `json:"state"`
+	}
+	_ = json.Unmarshal(resp.Resource, &resource)
+	if resp.Command != "createApplication" || resp.Status != http.StatusCreated || resource.ID != "app-1" || resource.State != "Proposed" {
+		t.Fatalf("unexpected response %+v (%s)", resp, resp.Resource)
+	}
+	if got, _ := appRepo.GetByID(ctx, "app-1"); got != nil {
+		t.Fatalf("expected app-1 not to be persisted, got %+v", got)
+	}
+
+	// El dry-run no ocupa la Idempotency-Key ni queda en el log de auditoría.
+	rec = postJSON(mux, "/commands/applications", app, map[string]string{httpx.IdempotencyKeyHeader: "k-1"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for the real command, got %d: %s", rec.Code, rec.Body.String())
+	}
+	auditRec := httptest.NewRecorder()
+	mux.ServeHTTP(auditRec, httptest.NewRequest(http.MethodGet, "/queries/audit?resourceId=app-1", nil))
+	var records []audit.Record
+	_ = json.Unmarshal(auditRec.Body.Bytes(), &records)
+	if len(records) != 1 {
+		t.Fatalf("expected only the real command to be audited, got %s", auditRec.Body.String())
+	}
+
+	// El dry-run de un batch informa el recurso de cada comando.
+	batchRec := httptest.NewRecorder()
+	body, _ := json.Marshal(map[string]any{"mode": "atomic", "commands": []map[string]any{
+		{"command": "createTeam", "body": map[string]any{"id": "team-2", "name": "Payments"}},
+		{"command": "createApplication", "body": map[string]any{"id": "app-2", "name": "ledger", "teamId": "team-2"}},
+	}})
+	mux.ServeHTTP(batchRec, httptest.NewRequest(http.MethodPost, "/commands:batch?dryRun=true", bytes.NewReader(body)))
+	var batch batchResponse
+	_ = json.Unmarshal(batchRec.Body.Bytes(), &batch)
+	if batchRec.Code != http.StatusOK || !batch.DryRun || batch.Committed || len(batch.Results) != 2 || batch.Results[1].Resource == nil {
+		t.Fatalf("unexpected batch dry-run %d %s", batchRec.Code, batchRec.Body.String())
+	}
+	if team, _ := teamRepo.GetByID(ctx, "team-2"); team != nil {
+		t.Fatalf("expected team-2 not to be persisted, got %+v", team)
+	}
+}
+
+func TestDryRun_ReportsDomainErrors(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	app := map[string]string{"id": "app-1", "name": "billing", "teamId": "missing"}
+	rec := postJSON(mux, "/commands/applications?dryRun=true", app, nil)
+	var problem httpx.Problem
+	_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+	if rec.Code != http.StatusNotFound || problem.Code != "team_not_found" {
+		t.Fatalf("expected team_not_found, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := postJSON(mux, "/commands/applications?dryRun=maybe", app, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid dryRun, got %d", rec.Code)
+	}
+}
+
+func TestDryRun_UnknownCommandFailsClosed(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	called := false
+	rt := route{
+		op:      openapi.Operation{Method: http.MethodPost, Path: "/commands/unknown", ID: "unknownCommand"},
+		handler: func(w http.ResponseWriter, r *http.Request) { called = true },
+	}
+
+	rec := httptest.NewRecorder()
+	server.dryRunHandler(rt).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/commands/unknown?dryRun=true", bytes.NewReader([]byte("{}"))))
+	var problem httpx.Problem
+	_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+	if rec.Code != http.StatusBadRequest || problem.Code != "dry_run_not_supported" {
+		t.Fatalf("expected 400 dry_run_not_supported, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if called {
+		t.Fatal("the real handler must not run for a dry-run")
+	}
+}
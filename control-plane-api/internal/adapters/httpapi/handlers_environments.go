@@ -3,25 +3,17 @@ package httpapi
 import (
 	"net/http"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"go.uber.org/zap"
 )
 
-type createEnvironmentRequest struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
-type declareApplicationEnvironmentRequest struct {
-	ID            string `json:"id"`
-	ApplicationID string `json:"applicationId"`
-	EnvironmentID string `json:"environmentId"`
-}
-
-type completeApplicationEnvironmentProvisioningRequest struct {
-	ID string `json:"id"`
-}
+type (
+	createEnvironmentRequest                          = client.CreateEnvironmentRequest
+	declareApplicationEnvironmentRequest              = client.DeclareApplicationEnvironmentRequest
+	completeApplicationEnvironmentProvisioningRequest = client.CompleteApplicationEnvironmentProvisioningRequest
+)
 
 //nolint:misspell
 func (s *Server) createEnvironment(w http.ResponseWriter, r *http.Request) { //nolint:dupl // handler HTTP pequeño y simétrico con otros; duplicación es intencional por claridad
@@ -30,20 +22,15 @@ func (s *Server) createEnvironment(w http.ResponseWriter, r *http.Request) { //n
 	}
 
 	var req createEnvironmentRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if req.ID == "" || req.Name == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id and name are required")
-		return
-	}
-
-	if err := s.services.CreateEnvironment(r.Context(), req.ID, req.Name, "api"); err != nil {
+	if err := s.api.CreateEnvironment(r.Context(), req.ID, req.Name, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("createEnvironment error", zap.Error(err))
 		observability.ObserveDomainEvent("environment_created", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -58,20 +45,15 @@ func (s *Server) declareApplicationEnvironment(w http.ResponseWriter, r *http.Re
 	}
 
 	var req declareApplicationEnvironmentRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.ApplicationID == "" || req.EnvironmentID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id, applicationId and environmentId are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.DeclareApplicationEnvironment(r.Context(), req.ID, req.ApplicationID, req.EnvironmentID, "api"); err != nil {
+	if err := s.api.DeclareApplicationEnvironment(r.Context(), req.ID, req.ApplicationID, req.EnvironmentID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("declareApplicationEnvironment error", zap.Error(err))
 		observability.ObserveDomainEvent("application_environment_declared", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -90,20 +72,15 @@ func (s *Server) completeApplicationEnvironmentProvisioning(w http.ResponseWrite
 	}
 
 	var req completeApplicationEnvironmentProvisioningRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.CompleteApplicationEnvironmentProvisioning(r.Context(), req.ID, "workflow-engine"); err != nil {
+	if err := s.api.CompleteApplicationEnvironmentProvisioning(r.Context(), req.ID, internalActor); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("completeApplicationEnvironmentProvisioning error", zap.Error(err))
 		observability.ObserveDomainEvent("application_environment_provisioning_completed", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
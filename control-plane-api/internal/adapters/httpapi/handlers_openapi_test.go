@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+func TestOpenAPIEndpoint_DocumentsEveryRoute(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("expected JSON document, got %v", err)
+	}
+	if doc.OpenAPI == "" {
+		t.Fatalf("expected openapi version to be set")
+	}
+
+	for _, rt := range server.routeTable() {
+		item, ok := doc.Paths[rt.op.Path]
+		if !ok {
+			t.Errorf("expected path %s to be documented", rt.op.Path)
+			continue
+		}
+		if _, ok := item["post"]; !ok && rt.op.Method == http.MethodPost {
+			t.Errorf("expected POST operation for %s", rt.op.Path)
+		}
+	}
+}
+
+func TestCommandValidation_ReturnsFieldErrors(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	cases := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"missing required", `{"id":"team-1"}`, "name"},
+		{"empty required", `{"id":"team-1","name":""}`, "name"},
+		{"wrong type", `{"id":42,"name":"Platform"}`, "id"},
+		{"unknown field", `{"id":"team-1","name":"Platform","owner":"me"}`, "owner"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/commands/teams", bytes.NewBufferString(tc.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+			}
+
+			var payload httpx.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("expected JSON error payload, got %v", err)
+			}
+			if payload.Code != "invalid_request_body" {
+				t.Fatalf("expected code 'invalid_request_body', got %q", payload.Code)
+			}
+			if len(payload.Errors) != 1 || payload.Errors[0].Field != tc.field {
+				t.Fatalf("expected a single error for field %q, got %+v", tc.field, payload.Errors)
+			}
+		})
+	}
+}
+
+func TestQueryValidation_RequiresID(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/queries/applications", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	var payload httpx.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error payload, got %v", err)
+	}
+	if len(payload.Errors) != 1 || payload.Errors[0].Field != "id" {
+		t.Fatalf("expected error for field 'id', got %+v", payload.Errors)
+	}
+}
@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+)
+
+type (
+	createOrganizationRequest = client.CreateOrganizationRequest
+)
+
+const organizationHeader = client.OrganizationHeader
+
+//nolint:misspell
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request) { //nolint:dupl // handler HTTP pequeño y simétrico con otros; duplicación es intencional por claridad
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req createOrganizationRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.CreateOrganization(r.Context(), req.ID, req.Name, actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("createOrganization error", zap.Error(err))
+		observability.ObserveDomainEvent("organization_created", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("organization_created", "success")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
+		return
+	}
+
+	org, err := s.api.GetOrganization(r.Context(), id)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("getOrganization error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	if httpx.NotModified(w, r, httpx.VersionETag(org.Metadata.Version)) {
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, org)
+}
+
+// withOrganization acota la request a la organización del header
+// X-Organization-ID (o a la del principal). Va después de la autenticación.
+func withOrganization(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := application.OrganizationScope(r.Context(), r.Header.Get(organizationHeader))
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// organizationIdempotencyStore antepone la organización a cada key, para que
+// dos organizaciones que usan la misma Idempotency-Key no compartan
+// respuesta.
+type organizationIdempotencyStore struct {
+	next httpx.IdempotencyStore
+}
+
+func (s organizationIdempotencyStore) Begin(ctx context.Context, key, requestHash string) (*httpx.IdempotencyRecord, error) {
+	return s.next.Begin(ctx, domain.ScopedID(ctx, key), requestHash) //nolint:wrapcheck // decorador transparente del store
+}
+
+func (s organizationIdempotencyStore) Complete(ctx context.Context, key string, rec httpx.IdempotencyRecord) error {
+	return s.next.Complete(ctx, domain.ScopedID(ctx, key), rec) //nolint:wrapcheck // decorador transparente del store
+}
+
+func (s organizationIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.next.Release(ctx, domain.ScopedID(ctx, key)) //nolint:wrapcheck // decorador transparente del store
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+func postAs(mux http.Handler, org, path string, body any, headers map[string]string) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+	if org != "" {
+		req.Header.Set(organizationHeader, org)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOrganizations_HeaderScopesCommandsAndQueries(t *testing.T) {
+	server, teamRepo, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	if rec := postAs(mux, "", "/commands/organizations", createOrganizationRequest{ID: "acme", Name: "Acme"}, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("createOrganization: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// El mismo ID de team en dos organizaciones no colisiona.
+	for _, org := range []string{"", "acme"} {
+		if rec := postAs(mux, org, "/commands/teams", createTeamRequest{ID: "team-1", Name: "Payments"}, nil); rec.Code != http.StatusCreated {
+			t.Fatalf("createTeam in %q: expected 201, got %d: %s", org, rec.Code, rec.Body.String())
+		}
+	}
+	team, _ := teamRepo.GetByID(domain.WithOrganization(context.Background(), "acme"), "team-1")
+	if team == nil || team.OrganizationID != "acme" {
+		t.Fatalf("expected team-1 in acme, got %+v", team)
+	}
+
+	if rec := postAs(mux, "acme", "/commands/applications", createApplicationRequest{ID: "app-1", Name: "billing", TeamID: "team-1"}, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("createApplication in acme: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for org, want := range map[string]int{"acme": http.StatusOK, "": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodGet, "/queries/applications?id=app-1", nil)
+		req.Header.Set(organizationHeader, org)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("getApplication in %q: expected %d, got %d", org, want, rec.Code)
+		}
+	}
+
+	// Una organización inexistente no admite recursos raíz.
+	if rec := postAs(mux, "missing", "/commands/teams", createTeamRequest{ID: "team-2", Name: "Ops"}, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("createTeam in unknown organization: expected 404, got %d", rec.Code)
+	}
+	if rec := postAs(mux, "Not Valid!", "/commands/teams", createTeamRequest{ID: "team-2", Name: "Ops"}, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid organization header: expected 400, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/queries/organizations?id=acme", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var org domain.Organization
+	_ = json.Unmarshal(rec.Body.Bytes(), &org)
+	if rec.Code != http.StatusOK || org.State != domain.OrganizationStateActive {
+		t.Fatalf("getOrganization: expected active acme, got %d %+v", rec.Code, org)
+	}
+}
+
+func TestOrganizations_IdempotencyKeysAreScoped(t *testing.T) {
+	server, teamRepo, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+	_ = postAs(mux, "", "/commands/organizations", createOrganizationRequest{ID: "acme", Name: "Acme"}, nil)
+
+	key := map[string]string{httpx.IdempotencyKeyHeader: "create-team-1"}
+	for _, org := range []string{"", "acme"} {
+		rec := postAs(mux, org, "/commands/teams", createTeamRequest{ID: "team-1", Name: "Payments"}, key)
+		if rec.Code != http.StatusCreated || rec.Header().Get(httpx.IdempotentReplayedHeader) != "" {
+			t.Fatalf("createTeam in %q: expected a fresh 201, got %d (replayed=%q)", org, rec.Code, rec.Header().Get(httpx.IdempotentReplayedHeader))
+		}
+	}
+	if team, _ := teamRepo.GetByID(domain.WithOrganization(context.Background(), "acme"), "team-1"); team == nil {
+		t.Fatalf("expected the acme request to run instead of replaying the default one")
+	}
+}
@@ -3,27 +3,17 @@ package httpapi
 import (
 	"net/http"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"go.uber.org/zap"
 )
 
-type declareCodeRepositoryRequest struct {
-	ID            string `json:"id"`
-	ApplicationID string `json:"applicationId"`
-}
-
-type declareDeploymentRepositoryRequest struct {
-	ID              string `json:"id"`
-	ApplicationID   string `json:"applicationId"`
-	DeploymentModel string `json:"deploymentModel"`
-}
-
-type declareGitOpsIntegrationRequest struct {
-	ID               string `json:"id"`
-	ApplicationID    string `json:"applicationId"`
-	DeploymentRepoID string `json:"deploymentRepositoryId"`
-}
+type (
+	declareCodeRepositoryRequest       = client.DeclareCodeRepositoryRequest
+	declareDeploymentRepositoryRequest = client.DeclareDeploymentRepositoryRequest
+	declareGitOpsIntegrationRequest    = client.DeclareGitOpsIntegrationRequest
+)
 
 //nolint:dupl
 func (s *Server) declareCodeRepository(w http.ResponseWriter, r *http.Request) {
@@ -32,20 +22,15 @@ func (s *Server) declareCodeRepository(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req declareCodeRepositoryRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if req.ID == "" || req.ApplicationID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id and applicationId are required")
-		return
-	}
-
-	if err := s.services.DeclareCodeRepository(r.Context(), req.ID, req.ApplicationID, "api"); err != nil {
+	if err := s.api.DeclareCodeRepository(r.Context(), req.ID, req.ApplicationID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("declareCodeRepository error", zap.Error(err))
 		observability.ObserveDomainEvent("code_repository_declared", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -59,20 +44,15 @@ func (s *Server) declareDeploymentRepository(w http.ResponseWriter, r *http.Requ
 	}
 
 	var req declareDeploymentRepositoryRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.ApplicationID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id and applicationId are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.DeclareDeploymentRepository(r.Context(), req.ID, req.ApplicationID, req.DeploymentModel, "api"); err != nil {
+	if err := s.api.DeclareDeploymentRepository(r.Context(), req.ID, req.ApplicationID, req.DeploymentModel, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("declareDeploymentRepository error", zap.Error(err))
 		observability.ObserveDomainEvent("deployment_repository_declared", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -87,20 +67,15 @@ func (s *Server) declareGitOpsIntegration(w http.ResponseWriter, r *http.Request
 	}
 
 	var req declareGitOpsIntegrationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.ApplicationID == "" || req.DeploymentRepoID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id, applicationId and deploymentRepositoryId are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.DeclareGitOpsIntegration(r.Context(), req.ID, req.ApplicationID, req.DeploymentRepoID, "api"); err != nil {
+	if err := s.api.DeclareGitOpsIntegration(r.Context(), req.ID, req.ApplicationID, req.DeploymentRepoID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("declareGitOpsIntegration error", zap.Error(err))
 		observability.ObserveDomainEvent("gitops_integration_declared", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/nuevo-idp/platform/httpx"
 )
 
 func TestDeclareCodeRepositoryEndpoint_CreatesRepo(t *testing.T) {
@@ -140,7 +142,7 @@ func TestDeclareCodeRepositoryEndpoint_FailsWhenApplicationNotFound(t *testing.T
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -184,7 +186,7 @@ func TestDeclareCodeRepositoryEndpoint_FailsWhenDuplicateID(t *testing.T) {
 	if rec.Code != http.StatusConflict {
 		t.Fatalf("expected %d when declaring duplicate code repo, got %d", http.StatusConflict, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -211,7 +213,7 @@ func TestDeclareDeploymentRepositoryEndpoint_FailsWhenApplicationNotFound(t *tes
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -256,7 +258,7 @@ func TestDeclareDeploymentRepositoryEndpoint_FailsWhenDuplicateID(t *testing.T)
 	if rec.Code != http.StatusConflict {
 		t.Fatalf("expected %d when declaring duplicate deployment repo, got %d", http.StatusConflict, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -283,7 +285,7 @@ func TestDeclareGitOpsIntegrationEndpoint_FailsWhenApplicationNotFound(t *testin
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when application is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -322,7 +324,7 @@ func TestDeclareGitOpsIntegrationEndpoint_FailsWhenDeploymentRepositoryNotFound(
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when deployment repo is missing, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -375,7 +377,7 @@ func TestDeclareGitOpsIntegrationEndpoint_FailsWhenDeploymentRepositoryBelongsTo
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when deployment repo belongs to different application, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -428,7 +430,7 @@ func TestDeclareGitOpsIntegrationEndpoint_FailsWhenDuplicateID(t *testing.T) {
 	if rec.Code != http.StatusConflict {
 		t.Fatalf("expected %d when declaring duplicate gitops integration, got %d", http.StatusConflict, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -436,3 +438,40 @@ func TestDeclareGitOpsIntegrationEndpoint_FailsWhenDuplicateID(t *testing.T) {
 		t.Fatalf("expected error code 'gitops_integration_already_exists', got %q", errPayload["code"])
 	}
 }
+
+func TestDeclareCodeRepositoryEndpoint_RetryWithIdempotencyKeyReplaysResponse(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+	ctx := httptest.NewRequest("", "/", nil).Context()
+
+	if err := server.services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := server.services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	send := func(body map[string]string) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/commands/code-repositories", bytes.NewReader(raw))
+		req.Header.Set(httpx.IdempotencyKeyHeader, "wf-1/5")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	payload := map[string]string{"id": "repo-1", "applicationId": "app-1"}
+
+	// Un reintento de la actividad reenvía la misma key: en lugar de un 409
+	// code_repository_already_exists debe recibir el 201 original.
+	for i := 0; i < 2; i++ {
+		if rec := send(payload); rec.Code != http.StatusCreated {
+			t.Fatalf("attempt %d: expected %d, got %d (%s)", i+1, http.StatusCreated, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := send(map[string]string{"id": "repo-2", "applicationId": "app-1"})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected %d when reusing key with another payload, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+}
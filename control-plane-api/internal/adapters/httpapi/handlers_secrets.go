@@ -3,32 +3,18 @@ package httpapi
 import (
 	"net/http"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"go.uber.org/zap"
 )
 
-type createSecretRequest struct {
-	ID          string `json:"id"`
-	OwnerTeamID string `json:"ownerTeamId"`
-	Purpose     string `json:"purpose"`
-	Sensitivity string `json:"sensitivity"`
-}
-
-type declareSecretBindingRequest struct {
-	ID         string `json:"id"`
-	SecretID   string `json:"secretId"`
-	TargetID   string `json:"targetId"`
-	TargetType string `json:"targetType"`
-}
-
-type startSecretRotationRequest struct {
-	ID string `json:"id"`
-}
-
-type completeSecretRotationRequest struct {
-	ID string `json:"id"`
-}
+type (
+	createSecretRequest           = client.CreateSecretRequest
+	declareSecretBindingRequest   = client.DeclareSecretBindingRequest
+	startSecretRotationRequest    = client.StartSecretRotationRequest
+	completeSecretRotationRequest = client.CompleteSecretRotationRequest
+)
 
 func (s *Server) createSecret(w http.ResponseWriter, r *http.Request) {
 	if !httpx.RequireMethod(w, r, http.MethodPost) {
@@ -36,20 +22,15 @@ func (s *Server) createSecret(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req createSecretRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.OwnerTeamID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id and ownerTeamId are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.CreateSecret(r.Context(), req.ID, req.OwnerTeamID, req.Purpose, req.Sensitivity, "api"); err != nil {
+	if err := s.api.CreateSecret(r.Context(), req.ID, req.OwnerTeamID, req.Purpose, req.Sensitivity, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("createSecret error", zap.Error(err))
 		observability.ObserveDomainEvent("secret_created", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -63,20 +44,15 @@ func (s *Server) declareSecretBinding(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req declareSecretBindingRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if req.ID == "" || req.SecretID == "" || req.TargetID == "" || req.TargetType == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id, secretId, targetId and targetType are required")
-		return
-	}
-
-	if err := s.services.DeclareSecretBinding(r.Context(), req.ID, req.SecretID, req.TargetID, req.TargetType, "api"); err != nil {
+	if err := s.api.DeclareSecretBinding(r.Context(), req.ID, req.SecretID, req.TargetID, req.TargetType, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("declareSecretBinding error", zap.Error(err))
 		observability.ObserveDomainEvent("secret_binding_declared", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -90,20 +66,15 @@ func (s *Server) startSecretRotation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req startSecretRotationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.StartSecretRotation(r.Context(), req.ID, "api"); err != nil {
+	if err := s.api.StartSecretRotation(r.Context(), req.ID, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("startSecretRotation error", zap.Error(err))
 		observability.ObserveDomainEvent("secret_rotation_started", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -122,20 +93,15 @@ func (s *Server) completeSecretRotation(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req completeSecretRotationRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.CompleteSecretRotation(r.Context(), req.ID, "workflow-engine"); err != nil {
+	if err := s.api.CompleteSecretRotation(r.Context(), req.ID, internalActor); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("completeSecretRotation error", zap.Error(err))
 		observability.ObserveDomainEvent("secret_rotation_completed", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
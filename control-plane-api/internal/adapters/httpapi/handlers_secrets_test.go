@@ -72,7 +72,7 @@ func TestDeclareSecretBindingEndpoint_RequiresActiveSecret(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d because secret is not Active, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -86,6 +86,7 @@ func TestDeclareSecretBindingEndpoint_RequiresActiveSecret(t *testing.T) {
 		t.Fatalf("expected secret to exist, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving updated secret failed: %v", err)
 	}
@@ -120,6 +121,7 @@ func TestStartSecretRotationEndpoint_TransitionsToRotating(t *testing.T) { //nol
 		t.Fatalf("expected secret, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving secret failed: %v", err)
 	}
@@ -176,7 +178,7 @@ func TestStartSecretRotationEndpoint_FailsIfNotActive(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when starting rotation from non-Active state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -203,6 +205,7 @@ func TestCompleteSecretRotationEndpoint_TransitionsToActive(t *testing.T) { //no
 		t.Fatalf("expected secret, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateRotating
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving secret failed: %v", err)
 	}
@@ -259,7 +262,7 @@ func TestCompleteSecretRotationEndpoint_FailsIfNotRotating(t *testing.T) {
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected %d when completing rotation from non-Rotating state, got %d", http.StatusBadRequest, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
@@ -283,7 +286,7 @@ func TestCompleteSecretRotationEndpoint_FailsIfNotFound(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("expected %d when completing rotation for missing secret, got %d", http.StatusNotFound, rec.Code)
 	}
-	var errPayload map[string]string
+	var errPayload map[string]any
 	if err := json.Unmarshal(rec.Body.Bytes(), &errPayload); err != nil {
 		t.Fatalf("expected JSON error payload, got %v", err)
 	}
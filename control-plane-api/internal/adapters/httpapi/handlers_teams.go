@@ -3,15 +3,17 @@ package httpapi
 import (
 	"net/http"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"go.uber.org/zap"
 )
 
-type createTeamRequest struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
+type (
+	createTeamRequest   = client.CreateTeamRequest
+	setTeamQuotaRequest = client.SetTeamQuotaRequest
+)
 
 //nolint:misspell
 func (s *Server) createTeam(w http.ResponseWriter, r *http.Request) { //nolint:dupl // handler HTTP pequeño y simétrico con otros; duplicación es intencional por claridad
@@ -20,23 +22,73 @@ func (s *Server) createTeam(w http.ResponseWriter, r *http.Request) { //nolint:d
 	}
 
 	var req createTeamRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json") {
-		return
-	}
-
-	if req.ID == "" || req.Name == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "id and name are required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := s.services.CreateTeam(r.Context(), req.ID, req.Name, "api"); err != nil {
+	if err := s.api.CreateTeam(r.Context(), req.ID, req.Name, actor(r)); err != nil {
 		logger := observability.LoggerWithTrace(r.Context(), s.logger)
 		logger.Error("createTeam error", zap.Error(err))
 		observability.ObserveDomainEvent("team_created", "error")
-		writeDomainError(w, err)
+		writeDomainError(w, r, err)
 		return
 	}
 
 	observability.ObserveDomainEvent("team_created", "success")
 	w.WriteHeader(http.StatusCreated)
 }
+
+//nolint:misspell
+func (s *Server) setTeamQuota(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req setTeamQuotaRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.SetTeamQuota(r.Context(), req.ID, teamQuota(req), actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("setTeamQuota error", zap.Error(err))
+		observability.ObserveDomainEvent("team_quota_set", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("team_quota_set", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func teamQuota(req setTeamQuotaRequest) domain.TeamQuota {
+	return domain.TeamQuota{
+		MaxApplications:               req.MaxApplications,
+		MaxSecrets:                    req.MaxSecrets,
+		MaxBindingsPerSecret:          req.MaxBindingsPerSecret,
+		MaxEnvironmentsPerApplication: req.MaxEnvironmentsPerApplication,
+	}
+}
+
+// getTeamQuota no devuelve ETag: el uso cambia sin que cambie la versión
+// del team.
+func (s *Server) getTeamQuota(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
+		return
+	}
+
+	usage, err := s.api.GetTeamQuota(r.Context(), id)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("getTeamQuota error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, usage)
+}
@@ -9,10 +9,13 @@ import (
 
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
 	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/httpx"
 	"go.uber.org/zap"
 )
 
-func newTestServer() (*Server, *memoryrepo.TeamRepository, *memoryrepo.ApplicationRepository, *memoryrepo.EnvironmentRepository, *memoryrepo.ApplicationEnvironmentRepository, *memoryrepo.SecretRepository, *memoryrepo.SecretBindingRepository, *memoryrepo.CodeRepositoryRepository, *memoryrepo.DeploymentRepositoryRepository, *memoryrepo.GitOpsIntegrationRepository) {
+func newTestServer(opts ...Option) (*Server, *memoryrepo.TeamRepository, *memoryrepo.ApplicationRepository, *memoryrepo.EnvironmentRepository, *memoryrepo.ApplicationEnvironmentRepository, *memoryrepo.SecretRepository, *memoryrepo.SecretBindingRepository, *memoryrepo.CodeRepositoryRepository, *memoryrepo.DeploymentRepositoryRepository, *memoryrepo.GitOpsIntegrationRepository) {
 	teamRepo := memoryrepo.NewTeamRepository()
 	appRepo := memoryrepo.NewApplicationRepository()
 	envRepo := memoryrepo.NewEnvironmentRepository()
@@ -24,6 +27,7 @@ func newTestServer() (*Server, *memoryrepo.TeamRepository, *memoryrepo.Applicati
 	gitopsRepo := memoryrepo.NewGitOpsIntegrationRepository()
 
 	services := &application.Services{
+		Organizations:           memoryrepo.NewOrganizationRepository(),
 		Teams:                   teamRepo,
 		Applications:            appRepo,
 		Environments:            envRepo,
@@ -33,10 +37,15 @@ func newTestServer() (*Server, *memoryrepo.TeamRepository, *memoryrepo.Applicati
 		CodeRepositories:        codeRepo,
 		DeploymentRepositories:  depRepo,
 		GitOpsIntegrations:      gitopsRepo,
+		WebhookSubscriptions:    memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:       memoryrepo.NewWebhookDeliveryRepository(),
+		Approvals:               memoryrepo.NewApprovalRepository(),
+		Changes:                 memoryrepo.NewChangeFeed(64),
+		Audit:                   audit.NewMemoryStore(),
 	}
 
 	logger := zap.NewNop()
-	return NewServer(services, logger), teamRepo, appRepo, envRepo, appEnvRepo, secretRepo, secretBindingRepo, codeRepo, depRepo, gitopsRepo
+	return NewServer(services, logger, opts...), teamRepo, appRepo, envRepo, appEnvRepo, secretRepo, secretBindingRepo, codeRepo, depRepo, gitopsRepo
 }
 
 func TestCreateTeamEndpoint_CreatesTeam(t *testing.T) {
@@ -62,3 +71,33 @@ func TestCreateTeamEndpoint_CreatesTeam(t *testing.T) {
 		t.Fatalf("expected team to be created, got err=%v team=%v", err, team)
 	}
 }
+
+func TestTeamQuotaEndpoints_SetEnforceAndReport(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	if rec := postAs(mux, "", "/commands/teams", createTeamRequest{ID: "team-1", Name: "Platform"}, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("createTeam: expected 201, got %d", rec.Code)
+	}
+	if rec := postAs(mux, "", "/commands/teams/quota", setTeamQuotaRequest{ID: "team-1", MaxApplications: 1}, nil); rec.Code != http.StatusAccepted {
+		t.Fatalf("setTeamQuota: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := postAs(mux, "", "/commands/applications", createApplicationRequest{ID: "app-1", Name: "billing", TeamID: "team-1"}, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("createApplication app-1: expected 201, got %d", rec.Code)
+	}
+	rec := postAs(mux, "", "/commands/applications", createApplicationRequest{ID: "app-2", Name: "ledger", TeamID: "team-1"}, nil)
+	var problem httpx.Problem
+	_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+	if rec.Code != http.StatusBadRequest || problem.Code != "quota_exceeded" {
+		t.Fatalf("createApplication app-2: expected quota_exceeded, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/queries/team-quotas?id=team-1", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var usage domain.TeamQuotaUsage
+	_ = json.Unmarshal(rec.Body.Bytes(), &usage)
+	if rec.Code != http.StatusOK || usage.Applications != (domain.QuotaUsage{Used: 1, Limit: 1}) {
+		t.Fatalf("getTeamQuota: unexpected %d %s", rec.Code, rec.Body.String())
+	}
+}
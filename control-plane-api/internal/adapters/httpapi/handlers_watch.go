@@ -0,0 +1,108 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+)
+
+const (
+	// watchBuffer es cuántos eventos pendientes toleramos por suscriptor
+	// antes de cortar el stream; el cliente reanuda con Last-Event-ID.
+	watchBuffer = 256
+	// watchHeartbeat mantiene viva la conexión a través de proxies.
+	watchHeartbeat = 15 * time.Second
+)
+
+// lastEventID lee la posición desde la que reanudar: el header estándar
+// Last-Event-ID (lo envía EventSource al reconectar) o, para clientes que no
+// pueden fijar headers, el parámetro lastEventId.
+func lastEventID(r *http.Request) (int64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("lastEventId")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, perrors.Validation("invalid_last_event_id", "Last-Event-ID must be a non-negative integer", err)
+	}
+	return id, nil
+}
+
+func (s *Server) watch(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	afterID, err := lastEventID(r)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	q := r.URL.Query()
+	filter := domain.ChangeFilter{
+		ResourceType:  q.Get("type"),
+		ResourceID:    q.Get("id"),
+		TeamID:        q.Get("teamId"),
+		ApplicationID: q.Get("applicationId"),
+	}
+
+	sub, err := s.api.WatchChanges(r.Context(), afterID, watchBuffer)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	logger := observability.LoggerWithTrace(r.Context(), s.logger)
+	stream, err := httpx.NewEventStream(w)
+	if err != nil {
+		logger.Error("watch: cannot open event stream", zap.Error(err))
+		return
+	}
+	if sub.Gap {
+		// El cliente perdió eventos: debe releer el estado con las queries.
+		if err := stream.Send("", "reset", map[string]string{"reason": "events_expired"}); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(watchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if err := stream.Comment("heartbeat"); err != nil {
+				return
+			}
+		case ev, ok := <-sub.Events:
+			if !ok {
+				if r.Context().Err() != nil {
+					return
+				}
+				// Buffer del suscriptor lleno: cortamos y el cliente reanuda
+				// desde el último id recibido.
+				logger.Warn("watch: subscriber too slow, closing stream")
+				_ = stream.Comment("subscriber buffer overflow; reconnect with Last-Event-ID")
+				return
+			}
+			if !filter.Matches(ev) {
+				continue
+			}
+			if err := stream.Send(strconv.FormatInt(ev.ID, 10), "change", ev); err != nil {
+				return
+			}
+		}
+	}
+}
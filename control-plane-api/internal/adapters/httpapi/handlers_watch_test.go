@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readSSEEvent lee el siguiente evento (ignorando comentarios) del stream.
+func readSSEEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if ev.Event != "" || ev.Data != "" {
+				return ev
+			}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			ev.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openWatch(t *testing.T, ctx context.Context, url string, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /watch failed: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestWatch_StreamsFilteredChangesAndResumes(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	ts := httptest.NewServer(server.Routes())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := openWatch(t, ctx, ts.URL+"/watch?type=Application", "")
+
+	_ = server.services.CreateTeam(ctx, "team-1", "Platform", "alice")
+	_ = server.services.CreateApplication(ctx, "app-1", "App", "team-1", "alice")
+
+	ev := readSSEEvent(t, stream)
+	if ev.Event != "change" || ev.ID != "2" {
+		t.Fatalf("expected change event 2, got %+v", ev)
+	}
+	var change domain.ChangeEvent
+	if err := json.Unmarshal([]byte(ev.Data), &change); err != nil {
+		t.Fatalf("decoding change: %v", err)
+	}
+	if change.ResourceType != "Application" || change.ResourceID != "app-1" || change.TeamID != "team-1" ||
+		change.Action != domain.ChangeActionCreated {
+		t.Fatalf("unexpected change %+v", change)
+	}
+
+	// Reanudar desde el evento 1 reproduce la creación de la application.
+	resumed := openWatch(t, ctx, ts.URL+"/watch", "1")
+	if ev := readSSEEvent(t, resumed); ev.ID != "2" {
+		t.Fatalf("expected replay of event 2, got %+v", ev)
+	}
+
+	// Un Last-Event-ID desconocido (p. ej. tras reiniciar la API) emite reset.
+	reset := openWatch(t, ctx, ts.URL+"/watch", "99")
+	if ev := readSSEEvent(t, reset); ev.Event != "reset" {
+		t.Fatalf("expected reset event, got %+v", ev)
+	}
+}
+
+func TestWatch_RejectsInvalidLastEventID(t *testing.T) {
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/watch?lastEventId=abc", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,137 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"go.uber.org/zap"
+)
+
+type (
+	createWebhookSubscriptionRequest  = client.CreateWebhookSubscriptionRequest
+	disableWebhookSubscriptionRequest = client.DisableWebhookSubscriptionRequest
+	redeliverWebhookDeliveryRequest   = client.RedeliverWebhookDeliveryRequest
+)
+
+func webhookFilter(f client.WebhookFilter) domain.WebhookFilter {
+	actions := make([]domain.ChangeAction, len(f.Actions))
+	for i, a := range f.Actions {
+		actions[i] = domain.ChangeAction(a)
+	}
+	return domain.WebhookFilter{ResourceTypes: f.ResourceTypes, Actions: actions, ApplicationID: f.ApplicationID}
+}
+
+func (s *Server) createWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req createWebhookSubscriptionRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.CreateWebhookSubscription(r.Context(), req.ID, req.TeamID, req.URL, req.Secret, webhookFilter(req.Filter), actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("createWebhookSubscription error", zap.Error(err))
+		observability.ObserveDomainEvent("webhook_subscription_created", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("webhook_subscription_created", "success")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) disableWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req disableWebhookSubscriptionRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.DisableWebhookSubscription(r.Context(), req.ID, actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("disableWebhookSubscription error", zap.Error(err))
+		observability.ObserveDomainEvent("webhook_subscription_disabled", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("webhook_subscription_disabled", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) redeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req redeliverWebhookDeliveryRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := s.api.RedeliverWebhookDelivery(r.Context(), req.ID, actor(r)); err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("redeliverWebhookDelivery error", zap.Error(err))
+		observability.ObserveDomainEvent("webhook_delivery_redelivered", "error")
+		writeDomainError(w, r, err)
+		return
+	}
+
+	observability.ObserveDomainEvent("webhook_delivery_redelivered", "success")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) getWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	id, ok := httpx.RequireQuery(w, r, "id")
+	if !ok {
+		return
+	}
+
+	sub, err := s.api.GetWebhookSubscription(r.Context(), id)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("getWebhookSubscription error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	if httpx.NotModified(w, r, httpx.VersionETag(sub.Metadata.Version)) {
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, sub)
+}
+
+func (s *Server) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	subscriptionID, ok := httpx.RequireQuery(w, r, "subscriptionId")
+	if !ok {
+		return
+	}
+	state := domain.WebhookDeliveryState(r.URL.Query().Get("state"))
+
+	deliveries, err := s.api.ListWebhookDeliveries(r.Context(), subscriptionID, state)
+	if err != nil {
+		logger := observability.LoggerWithTrace(r.Context(), s.logger)
+		logger.Error("listWebhookDeliveries error", zap.Error(err))
+		writeDomainError(w, r, err)
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/webhookhttp"
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/webhook"
+)
+
+func TestWebhooks_SignedDeliveryVisibleInQuery(t *testing.T) {
+	const secret = "team-1-webhook-secret"
+
+	var received []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify([]byte(secret), r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, time.Now(), 0); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received = append(received, r.Header.Get(webhook.EventHeader)+" "+r.Header.Get(webhook.DeliveryHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	server, _, _, _, _, _, _, _, _, _ := newTestServer()
+	mux := server.Routes()
+	ctx := context.Background()
+	_ = server.services.CreateTeam(ctx, "team-1", "Platform", "alice")
+	// El receptor de httptest es http en loopback, como en modo dev.
+	server.services.AllowInsecureWebhooks = true
+
+	body, _ := json.Marshal(map[string]any{
+		"id":     "wh-1",
+		"teamId": "team-1",
+		"url":    receiver.URL,
+		"secret": secret,
+		"filter": map[string]any{"resourceTypes": []string{"Application"}},
+	})
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/commands/webhook-subscriptions", bytes.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	dispatcher := application.NewWebhookDispatcher(server.services, webhookhttp.NewSender(webhookhttp.AllowPrivateTargets()), application.WebhookDispatcherOptions{})
+	_ = server.services.CreateApplication(ctx, "app-1", "App", "team-1", "alice")
+	_ = dispatcher.Enqueue(ctx, domain.ChangeEvent{ID: 2, ResourceType: "Application", ResourceID: "app-1", TeamID: "team-1", Action: domain.ChangeActionCreated})
+	if _, err := dispatcher.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+
+	if len(received) != 1 || received[0] != "Application.created wh-1-2" {
+		t.Fatalf("expected one signed delivery, got %v", received)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/webhook-deliveries?subscriptionId=wh-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var deliveries []domain.WebhookDelivery
+	if err := json.Unmarshal(rec.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("decoding deliveries: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].State != domain.WebhookDeliveryStateSucceeded ||
+		len(deliveries[0].Attempts) != 1 || deliveries[0].Attempts[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected deliveries %+v", deliveries)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/queries/webhook-subscriptions?id=wh-1", nil))
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), secret) {
+		t.Fatalf("expected subscription without secret, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
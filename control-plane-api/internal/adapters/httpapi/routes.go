@@ -0,0 +1,281 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/openapi"
+)
+
+// route asocia una operación documentada en OpenAPI con su handler. La tabla
+// de rutas es la única fuente de verdad del contrato HTTP: Routes registra
+// los handlers a partir de ella y OpenAPI genera el documento.
+type route struct {
+	op      openapi.Operation
+	handler http.HandlerFunc
+}
+
+var idParam = []openapi.Param{{Name: "id", In: "query", Required: true}}
+
+var idempotencyKeyParam = openapi.Param{
+	Name:        httpx.IdempotencyKeyHeader,
+	In:          "header",
+	Description: "Key opcional; los reintentos con la misma key y payload devuelven la respuesta original",
+}
+
+var ifMatchParam = openapi.Param{
+	Name:        "If-Match",
+	In:          "header",
+	Description: "ETag leído en la query; si el recurso cambió desde entonces el comando responde 412",
+}
+
+var organizationParam = openapi.Param{
+	Name:        organizationHeader,
+	In:          "header",
+	Description: "Organización en la que opera la request; por defecto, la del token o la default",
+}
+
+// listWithoutETag describe las queries de listas, que no tienen versión
+// propia.
+const listWithoutETag = "Las listas no devuelven ETag ni aceptan If-None-Match. Para un comando condicional, leer el recurso con su query por id y enviar ese ETag en If-Match."
+
+var ifNoneMatchParam = openapi.Param{
+	Name:        "If-None-Match",
+	In:          "header",
+	Description: "ETag conocido por el cliente; si el recurso no cambió la query responde 304",
+}
+
+func command(path, id, summary string, status int, req any, h http.HandlerFunc, tags ...string) route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodPost,
+			Path:    path,
+			ID:      id,
+			Summary: summary,
+			Tags:    append([]string{"commands"}, tags...),
+			Params:  []openapi.Param{organizationParam, idempotencyKeyParam, ifMatchParam, dryRunParam},
+			Request: req,
+			Status:  status,
+		},
+		handler: h,
+	}
+}
+
+func query(path, id, summary string, resp any, h http.HandlerFunc, tags ...string) route {
+	return route{
+		op: openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     path,
+			ID:       id,
+			Summary:  summary,
+			Tags:     append([]string{"queries"}, tags...),
+			Params:   append(append([]openapi.Param{}, idParam...), organizationParam, ifNoneMatchParam),
+			Response: resp,
+		},
+		handler: h,
+	}
+}
+
+// watchRoute documenta el stream SSE de cambios. Cada evento "change" lleva
+// un domain.ChangeEvent como data y su posición en el feed como id.
+func (s *Server) watchRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/watch",
+			ID:      "watchChanges",
+			Summary: "Stream SSE de cambios de recursos",
+			Tags:    []string{"queries", "watch"},
+			Params: []openapi.Param{
+				{Name: "type", Description: "Tipo de recurso (p.ej. ApplicationEnvironment)"},
+				{Name: "id", Description: "ID del recurso"},
+				{Name: "teamId", Description: "Team dueño del recurso"},
+				{Name: "applicationId", Description: "Application a la que pertenece el recurso"},
+				{Name: "lastEventId", Description: "Alternativa al header Last-Event-ID"},
+				{Name: "Last-Event-ID", In: "header", Description: "Último id recibido; el stream se reanuda a partir de él"},
+				organizationParam,
+			},
+			Response:            domain.ChangeEvent{},
+			ResponseContentType: httpx.EventStreamContentType,
+		},
+		handler: s.watch,
+	}
+}
+
+// webhookDeliveriesRoute documenta la query de entregas, que se filtra por
+// suscripción en lugar de por id.
+func (s *Server) webhookDeliveriesRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/queries/webhook-deliveries",
+			ID:      "listWebhookDeliveries",
+			Summary: "Listar las entregas de una WebhookSubscription con sus intentos",
+			Tags:    []string{"queries", "webhooks"},
+			Params: []openapi.Param{
+				{Name: "subscriptionId", In: "query", Required: true},
+				{Name: "state", Description: "Pending, Succeeded o DeadLettered (dead-letter list)"},
+				organizationParam,
+			},
+			Description: listWithoutETag,
+			Response:    []domain.WebhookDelivery{},
+		},
+		handler: s.listWebhookDeliveries,
+	}
+}
+
+// applicationEnvironmentsRoute documenta la query de los environments de
+// una Application, que se filtra por Application en lugar de por id.
+func (s *Server) applicationEnvironmentsRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/queries/application-environments:list",
+			ID:      "listApplicationEnvironments",
+			Summary: "Listar los ApplicationEnvironments de una Application",
+			Tags:    []string{"queries", "application-environments"},
+			Params: []openapi.Param{
+				{Name: "applicationId", In: "query", Required: true},
+				organizationParam,
+			},
+			Description: listWithoutETag,
+			Response:    []domain.ApplicationEnvironment{},
+		},
+		handler: s.listApplicationEnvironments,
+	}
+}
+
+// secretBindingsRoute documenta la query de bindings, que se filtra por
+// el recurso al que apuntan.
+func (s *Server) secretBindingsRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/queries/secret-bindings",
+			ID:      "listSecretBindings",
+			Summary: "Listar los SecretBindings de un recurso",
+			Tags:    []string{"queries", "secrets"},
+			Params: []openapi.Param{
+				{Name: "targetType", In: "query", Required: true, Description: "Tipo del recurso (p.ej. ApplicationEnvironment)"},
+				{Name: "targetId", In: "query", Required: true},
+				organizationParam,
+			},
+			Description: listWithoutETag,
+			Response:    []domain.SecretBinding{},
+		},
+		handler: s.listSecretBindings,
+	}
+}
+
+// approvalsRoute documenta la bandeja de aprobaciones, que se filtra por
+// rol y estado en lugar de por id.
+func (s *Server) approvalsRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/queries/approval-requests",
+			ID:      "listApprovals",
+			Summary: "Listar pedidos de aprobación por rol",
+			Tags:    []string{"queries", "approvals"},
+			Params: []openapi.Param{
+				{Name: "role", Description: "Rol aprobador (repetible); por defecto, los roles del principal"},
+				{Name: "state", Description: "Pending (por defecto), Approved, Rejected o Expired"},
+				organizationParam,
+			},
+			Description: listWithoutETag,
+			Response:    []domain.Approval{},
+		},
+		handler: s.listApprovals,
+	}
+}
+
+// batchRoute documenta el endpoint de batch. Responde 200 aunque fallen
+// comandos: el resultado de cada uno va en results.
+// batchRouteID es el operationId de /commands:batch.
+const batchRouteID = "runBatch"
+
+func (s *Server) batchRoute() route {
+	return route{
+		op: openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/commands:batch",
+			ID:       batchRouteID,
+			Summary:  "Ejecutar una lista ordenada de comandos (atomic o bestEffort)",
+			Tags:     []string{"commands", "batch"},
+			Params:   []openapi.Param{organizationParam, idempotencyKeyParam, dryRunParam},
+			Request:  batchRequest{},
+			Response: batchResponse{},
+			Status:   http.StatusOK,
+		},
+		handler: s.runBatch,
+	}
+}
+
+func (s *Server) routeTable() []route {
+	return []route{
+		command("/commands/organizations", "createOrganization", "Crear una Organization (tenant) en estado Active", http.StatusCreated, createOrganizationRequest{}, s.createOrganization, "organizations"),
+		command("/commands/teams", "createTeam", "Crear un Team en estado Draft", http.StatusCreated, createTeamRequest{}, s.createTeam, "teams"),
+		command("/commands/teams/quota", "setTeamQuota", "Fijar la cuota de un Team; los límites en 0 usan la política por defecto", http.StatusAccepted, setTeamQuotaRequest{}, s.setTeamQuota, "teams"),
+		command("/commands/applications", "createApplication", "Crear una Application en estado Proposed", http.StatusCreated, createApplicationRequest{}, s.createApplication, "applications"),
+		command("/commands/applications/approve", "approveApplication", "Aprobar una Application (Proposed -> Approved)", http.StatusAccepted, approveApplicationRequest{}, s.approveApplication, "applications"),
+		command("/commands/applications/start-onboarding", "startApplicationOnboarding", "Iniciar onboarding (Approved -> Onboarding); uso interno", http.StatusAccepted, startApplicationOnboardingRequest{}, s.startApplicationOnboarding, "applications"),
+		command("/commands/applications/activate", "activateApplication", "Activar una Application (Onboarding -> Active); uso interno", http.StatusAccepted, activateApplicationRequest{}, s.activateApplication, "applications"),
+		command("/commands/applications/deprecate", "deprecateApplication", "Deprecar una Application (Active -> Deprecated)", http.StatusAccepted, deprecateApplicationRequest{}, s.deprecateApplication, "applications"),
+		command("/commands/applications/start-decommissioning", "startApplicationDecommissioning", "Iniciar el decommissioning aprobado (Deprecated -> Decommissioning); uso interno", http.StatusAccepted, startApplicationDecommissioningRequest{}, s.startApplicationDecommissioning, "applications"),
+		command("/commands/applications/archive", "archiveApplication", "Archivar una Application sin environments ni bindings activos (Decommissioning -> Archived); uso interno", http.StatusAccepted, archiveApplicationRequest{}, s.archiveApplication, "applications"),
+		command("/commands/environments", "createEnvironment", "Crear un Environment de la organización en estado Planned", http.StatusCreated, createEnvironmentRequest{}, s.createEnvironment, "environments"),
+		command("/commands/application-environments", "declareApplicationEnvironment", "Declarar un ApplicationEnvironment", http.StatusCreated, declareApplicationEnvironmentRequest{}, s.declareApplicationEnvironment, "application-environments"),
+		command("/commands/application-environments/complete-provisioning", "completeApplicationEnvironmentProvisioning", "Marcar un ApplicationEnvironment como Active; uso interno", http.StatusAccepted, completeApplicationEnvironmentProvisioningRequest{}, s.completeApplicationEnvironmentProvisioning, "application-environments"),
+		command("/commands/application-environments/decommission", "decommissionApplicationEnvironment", "Decomisionar un ApplicationEnvironment de una Application en Decommissioning; uso interno", http.StatusAccepted, decommissionApplicationEnvironmentRequest{}, s.decommissionApplicationEnvironment, "application-environments"),
+		command("/commands/secrets", "createSecret", "Crear un Secret en estado Declared", http.StatusCreated, createSecretRequest{}, s.createSecret, "secrets"),
+		command("/commands/secrets/start-rotation", "startSecretRotation", "Iniciar la rotación de un Secret (Active -> Rotating)", http.StatusAccepted, startSecretRotationRequest{}, s.startSecretRotation, "secrets"),
+		command("/commands/secrets/complete-rotation", "completeSecretRotation", "Completar la rotación de un Secret (Rotating -> Active); uso interno", http.StatusAccepted, completeSecretRotationRequest{}, s.completeSecretRotation, "secrets"),
+		command("/commands/secret-bindings", "declareSecretBinding", "Declarar un SecretBinding", http.StatusCreated, declareSecretBindingRequest{}, s.declareSecretBinding, "secrets"),
+		command("/commands/secret-bindings/revoke", "revokeSecretBinding", "Revocar un SecretBinding (-> Revoked)", http.StatusAccepted, revokeSecretBindingRequest{}, s.revokeSecretBinding, "secrets"),
+		command("/commands/code-repositories", "declareCodeRepository", "Declarar un CodeRepository", http.StatusCreated, declareCodeRepositoryRequest{}, s.declareCodeRepository, "repositories"),
+		command("/commands/deployment-repositories", "declareDeploymentRepository", "Declarar un DeploymentRepository", http.StatusCreated, declareDeploymentRepositoryRequest{}, s.declareDeploymentRepository, "repositories"),
+		command("/commands/gitops-integrations", "declareGitOpsIntegration", "Declarar una GitOpsIntegration", http.StatusCreated, declareGitOpsIntegrationRequest{}, s.declareGitOpsIntegration, "repositories"),
+		command("/commands/webhook-subscriptions", "createWebhookSubscription", "Crear una WebhookSubscription de un Team", http.StatusCreated, createWebhookSubscriptionRequest{}, s.createWebhookSubscription, "webhooks"),
+		command("/commands/webhook-subscriptions/disable", "disableWebhookSubscription", "Deshabilitar una WebhookSubscription (Active -> Disabled)", http.StatusAccepted, disableWebhookSubscriptionRequest{}, s.disableWebhookSubscription, "webhooks"),
+		command("/commands/webhook-deliveries/redeliver", "redeliverWebhookDelivery", "Re-encolar una entrega DeadLettered", http.StatusAccepted, redeliverWebhookDeliveryRequest{}, s.redeliverWebhookDelivery, "webhooks"),
+		command("/commands/approvals", "createApprovalRequest", "Crear un pedido de aprobación manual en estado Pending", http.StatusCreated, createApprovalRequestRequest{}, s.createApprovalRequest, "approvals"),
+		command("/commands/approvals/approve", "approveApproval", "Aprobar un pedido (Pending -> Approved) y señalizar su workflow", http.StatusAccepted, decideApprovalRequest{}, s.approveApproval, "approvals"),
+		command("/commands/approvals/reject", "rejectApproval", "Rechazar un pedido (Pending -> Rejected) y señalizar su workflow", http.StatusAccepted, decideApprovalRequest{}, s.rejectApproval, "approvals"),
+		s.batchRoute(),
+		query("/queries/organizations", "getOrganization", "Obtener una Organization por ID", domain.Organization{}, s.getOrganization, "organizations"),
+		query("/queries/team-quotas", "getTeamQuota", "Obtener la cuota efectiva de un Team y su uso", domain.TeamQuotaUsage{}, s.getTeamQuota, "teams"),
+		query("/queries/applications", "getApplication", "Obtener una Application por ID", domain.Application{}, s.getApplication, "applications"),
+		query("/queries/environments", "getEnvironment", "Obtener un Environment por ID", domain.Environment{}, s.getEnvironment, "environments"),
+		query("/queries/application-environments", "getApplicationEnvironment", "Obtener un ApplicationEnvironment por ID", domain.ApplicationEnvironment{}, s.getApplicationEnvironment, "application-environments"),
+		s.applicationEnvironmentsRoute(),
+		s.secretBindingsRoute(),
+		query("/queries/webhook-subscriptions", "getWebhookSubscription", "Obtener una WebhookSubscription por ID", domain.WebhookSubscription{}, s.getWebhookSubscription, "webhooks"),
+		s.webhookDeliveriesRoute(),
+		query("/queries/approvals", "getApproval", "Obtener un pedido de aprobación por ID", domain.Approval{}, s.getApproval, "approvals"),
+		s.approvalsRoute(),
+		s.auditRoute(),
+		s.auditVerifyRoute(),
+		s.watchRoute(),
+	}
+}
+
+// OpenAPI genera el documento OpenAPI 3 del servicio a partir de la tabla de rutas.
+func (s *Server) OpenAPI() *openapi.Document {
+	table := s.routeTable()
+	ops := make([]openapi.Operation, 0, len(table))
+	for _, rt := range table {
+		ops = append(ops, rt.op)
+	}
+
+	return openapi.Build(openapi.Spec{
+		Info: openapi.Info{
+			Title:       "control-plane-api",
+			Version:     "v1",
+			Description: "Comandos y consultas sobre el estado de dominio del IDP.",
+		},
+		Operations:       ops,
+		Error:            httpx.Problem{},
+		ErrorContentType: httpx.ProblemContentType,
+	})
+}
@@ -0,0 +1,69 @@
+package memoryrepo
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+type ApprovalRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.Approval
+}
+
+func NewApprovalRepository() *ApprovalRepository {
+	return &ApprovalRepository{items: make(map[string]*domain.Approval)}
+}
+
+func (r *ApprovalRepository) GetByID(ctx context.Context, id string) (*domain.Approval, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if a, ok := r.items[domain.ScopedID(ctx, id)]; ok {
+		return copyApproval(a), nil
+	}
+	return nil, nil
+}
+
+// List devuelve los pedidos de la organización de ctx.
+func (r *ApprovalRepository) List(ctx context.Context) ([]*domain.Approval, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*domain.Approval, 0, len(r.items))
+	for key, a := range r.items {
+		if inOrganization(ctx, key) {
+			out = append(out, copyApproval(a))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *ApprovalRepository) Save(ctx context.Context, a *domain.Approval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, a.ID)
+	if cur, ok := r.items[key]; ok && !a.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
+	r.items[key] = copyApproval(a)
+	return nil
+}
+
+func (r *ApprovalRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+func copyApproval(a *domain.Approval) *domain.Approval {
+	copy := *a
+	copy.RolesAllowed = append([]string(nil), a.RolesAllowed...)
+	if a.Decision != nil {
+		decision := *a.Decision
+		copy.Decision = &decision
+	}
+	return &copy
+}
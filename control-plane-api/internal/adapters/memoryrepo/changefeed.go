@@ -0,0 +1,118 @@
+package memoryrepo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+// ChangeFeed es un application.ChangeFeed en memoria: retiene los últimos
+// capacity eventos en un ring buffer para poder reanudar suscripciones, y
+// reparte los nuevos a cada suscriptor por un canal con buffer acotado. Un
+// suscriptor que no consume a tiempo se desconecta (se cierra su canal) en
+// lugar de frenar a los demás. Los IDs son globales, así que un suscriptor
+// de una organización ve huecos en la numeración.
+type ChangeFeed struct {
+	mu       sync.Mutex
+	capacity int
+	events   []domain.ChangeEvent
+	nextID   int64
+	subs     map[*changeSubscriber]struct{}
+}
+
+type changeSubscriber struct {
+	ch           chan domain.ChangeEvent
+	organization string
+	closed       bool
+}
+
+func (sub *changeSubscriber) wants(ev domain.ChangeEvent) bool {
+	return sub.organization == "" || sub.organization == ev.OrganizationID
+}
+
+// NewChangeFeed crea un feed que retiene hasta capacity eventos.
+func NewChangeFeed(capacity int) *ChangeFeed {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ChangeFeed{
+		capacity: capacity,
+		nextID:   1,
+		subs:     make(map[*changeSubscriber]struct{}),
+	}
+}
+
+func (f *ChangeFeed) Publish(_ context.Context, ev domain.ChangeEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ev.ID = f.nextID
+	f.nextID++
+
+	f.events = append(f.events, ev)
+	if len(f.events) > f.capacity {
+		f.events = append(f.events[:0:0], f.events[len(f.events)-f.capacity:]...)
+	}
+
+	for sub := range f.subs {
+		if !sub.wants(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			f.closeLocked(sub)
+		}
+	}
+}
+
+func (f *ChangeFeed) Subscribe(ctx context.Context, organizationID string, afterID int64, buffer int) (<-chan domain.ChangeEvent, bool, error) {
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// afterID == 0: sólo eventos nuevos. afterID fuera de rango: o es
+	// anterior al evento más viejo retenido, o es de una instancia anterior
+	// del feed (los IDs se reinician); se reproduce todo lo retenido.
+	oldest := f.nextID
+	if len(f.events) > 0 {
+		oldest = f.events[0].ID
+	}
+	gap := afterID >= f.nextID || (afterID > 0 && afterID < oldest-1)
+
+	sub := &changeSubscriber{organization: organizationID}
+	var replay []domain.ChangeEvent
+	for _, ev := range f.events {
+		if afterID > 0 && (gap || ev.ID > afterID) && sub.wants(ev) {
+			replay = append(replay, ev)
+		}
+	}
+
+	sub.ch = make(chan domain.ChangeEvent, len(replay)+buffer)
+	for _, ev := range replay {
+		sub.ch <- ev
+	}
+	f.subs[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.closeLocked(sub)
+	}()
+
+	return sub.ch, gap, nil
+}
+
+func (f *ChangeFeed) closeLocked(sub *changeSubscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(f.subs, sub)
+	close(sub.ch)
+}
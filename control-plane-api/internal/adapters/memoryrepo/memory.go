@@ -2,11 +2,26 @@ package memoryrepo
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/nuevo-idp/control-plane-api/internal/domain"
 )
 
+// Los repositorios de recursos son tenant-scoped: guardan cada recurso bajo
+// domain.ScopedID, así que sólo ven los de la organización del contexto.
+//
+// Ningún comando borra recursos: Delete sólo lo usan el commit de un batch
+// atómico, para deshacer sus creaciones si falla a mitad, y CreateApplication,
+// para deshacer la Application si no pudo guardar su pedido de aprobación.
+
+// inOrganization indica si key (un domain.ScopedID) pertenece a la
+// organización de ctx.
+func inOrganization(ctx context.Context, key string) bool {
+	return strings.HasPrefix(key, domain.OrganizationFromContext(ctx)+"/")
+}
+
 type TeamRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.Team
@@ -16,24 +31,43 @@ func NewTeamRepository() *TeamRepository {
 	return &TeamRepository{items: make(map[string]*domain.Team)}
 }
 
-func (r *TeamRepository) GetByID(_ context.Context, id string) (*domain.Team, error) {
+func (r *TeamRepository) GetByID(ctx context.Context, id string) (*domain.Team, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if t, ok := r.items[id]; ok {
-		copy := *t
-		return &copy, nil
+	if t, ok := r.items[domain.ScopedID(ctx, id)]; ok {
+		return copyTeam(t), nil
 	}
 	return nil, nil
 }
 
-func (r *TeamRepository) Save(_ context.Context, team *domain.Team) error {
+func (r *TeamRepository) Save(ctx context.Context, team *domain.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, team.ID)
+	if cur, ok := r.items[key]; ok && !team.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
+	r.items[key] = copyTeam(team)
+	return nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	copy := *team
-	r.items[team.ID] = &copy
+	delete(r.items, domain.ScopedID(ctx, id))
 	return nil
 }
 
+// copyTeam copia también la cuota, para que nadie modifique la guardada.
+func copyTeam(t *domain.Team) *domain.Team {
+	copy := *t
+	if t.Quota != nil {
+		quota := *t.Quota
+		copy.Quota = &quota
+	}
+	return &copy
+}
+
 type ApplicationRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.Application
@@ -43,24 +77,49 @@ func NewApplicationRepository() *ApplicationRepository {
 	return &ApplicationRepository{items: make(map[string]*domain.Application)}
 }
 
-func (r *ApplicationRepository) GetByID(_ context.Context, id string) (*domain.Application, error) {
+func (r *ApplicationRepository) GetByID(ctx context.Context, id string) (*domain.Application, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if a, ok := r.items[id]; ok {
+	if a, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *a
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *ApplicationRepository) Save(_ context.Context, app *domain.Application) error {
+func (r *ApplicationRepository) Save(ctx context.Context, app *domain.Application) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, app.ID)
+	if cur, ok := r.items[key]; ok && !app.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *app
-	r.items[app.ID] = &copy
+	r.items[key] = &copy
 	return nil
 }
 
+func (r *ApplicationRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+func (r *ApplicationRepository) ListByTeam(ctx context.Context, teamID string) ([]*domain.Application, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.Application
+	for key, a := range r.items {
+		if inOrganization(ctx, key) && a.TeamID == teamID {
+			copy := *a
+			out = append(out, &copy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
 type CodeRepositoryRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.CodeRepository
@@ -70,21 +129,32 @@ func NewCodeRepositoryRepository() *CodeRepositoryRepository {
 	return &CodeRepositoryRepository{items: make(map[string]*domain.CodeRepository)}
 }
 
-func (r *CodeRepositoryRepository) GetByID(_ context.Context, id string) (*domain.CodeRepository, error) {
+func (r *CodeRepositoryRepository) GetByID(ctx context.Context, id string) (*domain.CodeRepository, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if cr, ok := r.items[id]; ok {
+	if cr, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *cr
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *CodeRepositoryRepository) Save(_ context.Context, repo *domain.CodeRepository) error {
+func (r *CodeRepositoryRepository) Save(ctx context.Context, repo *domain.CodeRepository) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, repo.ID)
+	if cur, ok := r.items[key]; ok && !repo.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *repo
-	r.items[repo.ID] = &copy
+	r.items[key] = &copy
+	return nil
+}
+
+func (r *CodeRepositoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
 	return nil
 }
 
@@ -97,21 +167,32 @@ func NewEnvironmentRepository() *EnvironmentRepository {
 	return &EnvironmentRepository{items: make(map[string]*domain.Environment)}
 }
 
-func (r *EnvironmentRepository) GetByID(_ context.Context, id string) (*domain.Environment, error) {
+func (r *EnvironmentRepository) GetByID(ctx context.Context, id string) (*domain.Environment, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if e, ok := r.items[id]; ok {
+	if e, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *e
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *EnvironmentRepository) Save(_ context.Context, env *domain.Environment) error {
+func (r *EnvironmentRepository) Save(ctx context.Context, env *domain.Environment) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, env.ID)
+	if cur, ok := r.items[key]; ok && !env.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *env
-	r.items[env.ID] = &copy
+	r.items[key] = &copy
+	return nil
+}
+
+func (r *EnvironmentRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
 	return nil
 }
 
@@ -124,21 +205,21 @@ func NewApplicationEnvironmentRepository() *ApplicationEnvironmentRepository {
 	return &ApplicationEnvironmentRepository{items: make(map[string]*domain.ApplicationEnvironment)}
 }
 
-func (r *ApplicationEnvironmentRepository) GetByID(_ context.Context, id string) (*domain.ApplicationEnvironment, error) {
+func (r *ApplicationEnvironmentRepository) GetByID(ctx context.Context, id string) (*domain.ApplicationEnvironment, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if ae, ok := r.items[id]; ok {
+	if ae, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *ae
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *ApplicationEnvironmentRepository) GetByApplicationAndEnvironment(_ context.Context, applicationID, environmentID string) (*domain.ApplicationEnvironment, error) {
+func (r *ApplicationEnvironmentRepository) GetByApplicationAndEnvironment(ctx context.Context, applicationID, environmentID string) (*domain.ApplicationEnvironment, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	for _, ae := range r.items {
-		if ae.ApplicationID == applicationID && ae.EnvironmentID == environmentID {
+	for key, ae := range r.items {
+		if inOrganization(ctx, key) && ae.ApplicationID == applicationID && ae.EnvironmentID == environmentID {
 			copy := *ae
 			return &copy, nil
 		}
@@ -146,14 +227,39 @@ func (r *ApplicationEnvironmentRepository) GetByApplicationAndEnvironment(_ cont
 	return nil, nil
 }
 
-func (r *ApplicationEnvironmentRepository) Save(_ context.Context, appEnv *domain.ApplicationEnvironment) error {
+func (r *ApplicationEnvironmentRepository) Save(ctx context.Context, appEnv *domain.ApplicationEnvironment) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, appEnv.ID)
+	if cur, ok := r.items[key]; ok && !appEnv.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *appEnv
-	r.items[appEnv.ID] = &copy
+	r.items[key] = &copy
 	return nil
 }
 
+func (r *ApplicationEnvironmentRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+func (r *ApplicationEnvironmentRepository) ListByApplication(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.ApplicationEnvironment
+	for key, ae := range r.items {
+		if inOrganization(ctx, key) && ae.ApplicationID == applicationID {
+			copy := *ae
+			out = append(out, &copy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
 type DeploymentRepositoryRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.DeploymentRepository
@@ -163,21 +269,32 @@ func NewDeploymentRepositoryRepository() *DeploymentRepositoryRepository {
 	return &DeploymentRepositoryRepository{items: make(map[string]*domain.DeploymentRepository)}
 }
 
-func (r *DeploymentRepositoryRepository) GetByID(_ context.Context, id string) (*domain.DeploymentRepository, error) {
+func (r *DeploymentRepositoryRepository) GetByID(ctx context.Context, id string) (*domain.DeploymentRepository, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if dr, ok := r.items[id]; ok {
+	if dr, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *dr
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *DeploymentRepositoryRepository) Save(_ context.Context, repo *domain.DeploymentRepository) error {
+func (r *DeploymentRepositoryRepository) Save(ctx context.Context, repo *domain.DeploymentRepository) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, repo.ID)
+	if cur, ok := r.items[key]; ok && !repo.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *repo
-	r.items[repo.ID] = &copy
+	r.items[key] = &copy
+	return nil
+}
+
+func (r *DeploymentRepositoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
 	return nil
 }
 
@@ -190,24 +307,49 @@ func NewSecretRepository() *SecretRepository {
 	return &SecretRepository{items: make(map[string]*domain.Secret)}
 }
 
-func (r *SecretRepository) GetByID(_ context.Context, id string) (*domain.Secret, error) {
+func (r *SecretRepository) GetByID(ctx context.Context, id string) (*domain.Secret, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if s, ok := r.items[id]; ok {
+	if s, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *s
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *SecretRepository) Save(_ context.Context, s *domain.Secret) error {
+func (r *SecretRepository) Save(ctx context.Context, s *domain.Secret) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, s.ID)
+	if cur, ok := r.items[key]; ok && !s.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *s
-	r.items[s.ID] = &copy
+	r.items[key] = &copy
 	return nil
 }
 
+func (r *SecretRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+func (r *SecretRepository) ListByOwnerTeam(ctx context.Context, teamID string) ([]*domain.Secret, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.Secret
+	for key, s := range r.items {
+		if inOrganization(ctx, key) && s.OwnerTeam == teamID {
+			copy := *s
+			out = append(out, &copy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
 type SecretBindingRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.SecretBinding
@@ -217,24 +359,63 @@ func NewSecretBindingRepository() *SecretBindingRepository {
 	return &SecretBindingRepository{items: make(map[string]*domain.SecretBinding)}
 }
 
-func (r *SecretBindingRepository) GetByID(_ context.Context, id string) (*domain.SecretBinding, error) {
+func (r *SecretBindingRepository) GetByID(ctx context.Context, id string) (*domain.SecretBinding, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if b, ok := r.items[id]; ok {
+	if b, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *b
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *SecretBindingRepository) Save(_ context.Context, b *domain.SecretBinding) error {
+func (r *SecretBindingRepository) Save(ctx context.Context, b *domain.SecretBinding) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, b.ID)
+	if cur, ok := r.items[key]; ok && !b.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *b
-	r.items[b.ID] = &copy
+	r.items[key] = &copy
 	return nil
 }
 
+func (r *SecretBindingRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+func (r *SecretBindingRepository) ListBySecret(ctx context.Context, secretID string) ([]*domain.SecretBinding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.SecretBinding
+	for key, b := range r.items {
+		if inOrganization(ctx, key) && b.SecretID == secretID {
+			copy := *b
+			out = append(out, &copy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *SecretBindingRepository) ListByTarget(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.SecretBinding
+	for key, b := range r.items {
+		if inOrganization(ctx, key) && b.TargetType == targetType && b.TargetID == targetID {
+			copy := *b
+			out = append(out, &copy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
 type GitOpsIntegrationRepository struct {
 	mu    sync.RWMutex
 	items map[string]*domain.GitOpsIntegration
@@ -244,20 +425,31 @@ func NewGitOpsIntegrationRepository() *GitOpsIntegrationRepository {
 	return &GitOpsIntegrationRepository{items: make(map[string]*domain.GitOpsIntegration)}
 }
 
-func (r *GitOpsIntegrationRepository) GetByID(_ context.Context, id string) (*domain.GitOpsIntegration, error) {
+func (r *GitOpsIntegrationRepository) GetByID(ctx context.Context, id string) (*domain.GitOpsIntegration, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if gi, ok := r.items[id]; ok {
+	if gi, ok := r.items[domain.ScopedID(ctx, id)]; ok {
 		copy := *gi
 		return &copy, nil
 	}
 	return nil, nil
 }
 
-func (r *GitOpsIntegrationRepository) Save(_ context.Context, gi *domain.GitOpsIntegration) error {
+func (r *GitOpsIntegrationRepository) Save(ctx context.Context, gi *domain.GitOpsIntegration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, gi.ID)
+	if cur, ok := r.items[key]; ok && !gi.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
 	copy := *gi
-	r.items[gi.ID] = &copy
+	r.items[key] = &copy
+	return nil
+}
+
+func (r *GitOpsIntegrationRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
 	return nil
 }
@@ -0,0 +1,49 @@
+package memoryrepo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+// OrganizationRepository no es tenant-scoped: las organizaciones son el
+// tenant.
+type OrganizationRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.Organization
+}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{items: make(map[string]*domain.Organization)}
+}
+
+func (r *OrganizationRepository) GetByID(_ context.Context, id string) (*domain.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if o, ok := r.items[id]; ok {
+		copy := *o
+		return &copy, nil
+	}
+	return nil, nil
+}
+
+func (r *OrganizationRepository) Save(_ context.Context, org *domain.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := org.ID
+	if cur, ok := r.items[key]; ok && !org.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
+	copy := *org
+	r.items[key] = &copy
+	return nil
+}
+
+// Delete borra la organización id (ver el comentario de memory.go).
+func (r *OrganizationRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, id)
+	return nil
+}
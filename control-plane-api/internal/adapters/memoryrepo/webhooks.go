@@ -0,0 +1,134 @@
+package memoryrepo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+type WebhookSubscriptionRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.WebhookSubscription
+}
+
+func NewWebhookSubscriptionRepository() *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{items: make(map[string]*domain.WebhookSubscription)}
+}
+
+func (r *WebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, ok := r.items[domain.ScopedID(ctx, id)]; ok {
+		copy := *s
+		return &copy, nil
+	}
+	return nil, nil
+}
+
+func (r *WebhookSubscriptionRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*domain.WebhookSubscription, 0, len(r.items))
+	for key, s := range r.items {
+		if !inOrganization(ctx, key) {
+			continue
+		}
+		copy := *s
+		out = append(out, &copy)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *WebhookSubscriptionRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := domain.ScopedID(ctx, sub.ID)
+	if cur, ok := r.items[key]; ok && !sub.Metadata.Follows(cur.Metadata) {
+		return domain.ErrVersionConflict
+	}
+	copy := *sub
+	r.items[key] = &copy
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+type WebhookDeliveryRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.WebhookDelivery
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{items: make(map[string]*domain.WebhookDelivery)}
+}
+
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if d, ok := r.items[domain.ScopedID(ctx, id)]; ok {
+		return copyDelivery(d), nil
+	}
+	return nil, nil
+}
+
+func (r *WebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.WebhookDelivery
+	for key, d := range r.items {
+		if inOrganization(ctx, key) && d.SubscriptionID == subscriptionID {
+			out = append(out, copyDelivery(d))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Event.ID < out[j].Event.ID })
+	return out, nil
+}
+
+// ListDue no filtra por organización: el dispatcher entrega las de todos
+// los tenants.
+func (r *WebhookDeliveryRepository) ListDue(_ context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*domain.WebhookDelivery
+	for _, d := range r.items {
+		if d.State == domain.WebhookDeliveryStatePending && !d.NextAttemptAt.After(now) {
+			out = append(out, copyDelivery(d))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NextAttemptAt.Before(out[j].NextAttemptAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *WebhookDeliveryRepository) Save(ctx context.Context, d *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[domain.ScopedID(ctx, d.ID)] = copyDelivery(d)
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, domain.ScopedID(ctx, id))
+	return nil
+}
+
+// copyDelivery copia también los intentos: el dispatcher los va agregando
+// sobre la copia que leyó.
+func copyDelivery(d *domain.WebhookDelivery) *domain.WebhookDelivery {
+	copy := *d
+	copy.Attempts = append([]domain.WebhookAttempt(nil), d.Attempts...)
+	return &copy
+}
@@ -2,6 +2,7 @@ package pgrepo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -20,44 +21,89 @@ func NewTeamRepository(pool *pgxpool.Pool) *TeamRepository {
 	return &TeamRepository{pool: pool}
 }
 
+// GetByID busca el team dentro de la organización de ctx.
 func (r *TeamRepository) GetByID(ctx context.Context, id string) (*domain.Team, error) {
-	const query = `SELECT id, name, state, created_by, created_at FROM teams WHERE id = $1`
+	const query = `SELECT id, organization_id, name, state, quota, version, created_by, created_at
+                   FROM teams WHERE organization_id = $1 AND id = $2`
 
 	var (
 		team      domain.Team
+		quota     []byte
 		createdBy string
 		createdAt time.Time
 	)
 
-	row := r.pool.QueryRow(ctx, query, id)
-	if err := row.Scan(&team.ID, &team.Name, &team.State, &createdBy, &createdAt); err != nil {
-				if errors.Is(err, pgx.ErrNoRows) {
+	row := r.pool.QueryRow(ctx, query, domain.OrganizationFromContext(ctx), id)
+	if err := row.Scan(&team.ID, &team.OrganizationID, &team.Name, &team.State, &quota, &team.Metadata.Version, &createdBy, &createdAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("scanning team: %w", err)
 	}
 
+	if quota != nil {
+		team.Quota = &domain.TeamQuota{}
+		if err := json.Unmarshal(quota, team.Quota); err != nil {
+			return nil, fmt.Errorf("decoding team quota: %w", err)
+		}
+	}
+
 	team.Metadata.CreatedBy = createdBy
 	team.Metadata.CreatedAt = createdAt
 	return &team, nil
 }
 
+// Save guarda el team en la organización de ctx; el ID es único dentro de
+// la organización. Un team existente sólo se reemplaza si la fila sigue en
+// la versión anterior a la de team (compare-and-swap); si no, devuelve
+// domain.ErrVersionConflict.
 func (r *TeamRepository) Save(ctx context.Context, team *domain.Team) error {
-	const stmt = `INSERT INTO teams (id, name, state, created_by, created_at)
-                  VALUES ($1, $2, $3, $4, $5)
-                  ON CONFLICT (id) DO UPDATE
+	const stmt = `INSERT INTO teams (organization_id, id, name, state, quota, version, created_by, created_at)
+                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                  ON CONFLICT (organization_id, id) DO UPDATE
                   SET name = EXCLUDED.name,
-                      state = EXCLUDED.state`
+                      state = EXCLUDED.state,
+                      quota = EXCLUDED.quota,
+                      version = EXCLUDED.version
+                  WHERE teams.version = EXCLUDED.version - 1`
+
+	// Sin cuota propia la columna queda NULL.
+	var quota []byte
+	if team.Quota != nil {
+		encoded, err := json.Marshal(team.Quota)
+		if err != nil {
+			return fmt.Errorf("encoding team quota: %w", err)
+		}
+		quota = encoded
+	}
 
-	_, err := r.pool.Exec(ctx, stmt,
+	tag, err := r.pool.Exec(ctx, stmt,
+		domain.OrganizationFromContext(ctx),
 		team.ID,
 		team.Name,
 		team.State,
+		quota,
+		team.Metadata.Version,
 		team.Metadata.CreatedBy,
 		team.Metadata.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("saving team: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrVersionConflict
+	}
+	return nil
+}
+
+// Delete borra el team de la organización de ctx. Ningún comando borra
+// teams: sólo lo usa el commit de un batch atómico para deshacer una
+// creación si falla a mitad.
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	const stmt = `DELETE FROM teams WHERE organization_id = $1 AND id = $2`
+
+	if _, err := r.pool.Exec(ctx, stmt, domain.OrganizationFromContext(ctx), id); err != nil {
+		return fmt.Errorf("deleting team: %w", err)
+	}
 	return nil
 }
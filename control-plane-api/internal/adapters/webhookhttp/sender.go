@@ -0,0 +1,153 @@
+// Package webhookhttp entrega los webhooks salientes del control plane por
+// HTTP, firmados con platform/webhook.
+package webhookhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/webhook"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var _ application.WebhookSender = (*Sender)(nil)
+
+type Sender struct {
+	httpClient   *http.Client
+	now          func() time.Time
+	allowPrivate bool
+}
+
+// Option configura un Sender.
+type Option func(*Sender)
+
+// AllowPrivateTargets deja entregar a direcciones privadas, loopback y
+// link-local. Sólo para modo dev, con receptores locales.
+func AllowPrivateTargets() Option {
+	return func(s *Sender) { s.allowPrivate = true }
+}
+
+// errBlockedTarget es el error de dial hacia una dirección interna.
+var errBlockedTarget = errors.New("webhook target resolves to a private, loopback or link-local address")
+
+// blockedPrefixes son rangos internos que netip no clasifica como privados:
+// el espacio compartido de CGNAT y el prefijo NAT64, que traduce a una IPv4
+// cualquiera (también interna).
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// NewSender crea un Sender. Los endpoints de los teams son externos, así que
+// el timeout es corto: un endpoint lento consume reintentos, no bloquea al
+// dispatcher.
+//
+// Las URLs las elige cada team, así que el Sender no puede usarse para
+// alcanzar la red interna: la IP se valida en el Control del dialer, después
+// de resolver DNS (un nombre que re-resuelve a una IP interna también se
+// corta), no hay proxy y no se siguen redirects; un 3xx cuenta como fallo.
+func NewSender(opts ...Option) *Sender {
+	s := &Sender{now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: s.checkTarget}
+	s.httpClient = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// checkTarget rechaza conexiones a direcciones internas salvo con
+// AllowPrivateTargets.
+func (s *Sender) checkTarget(_, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("parse webhook target %q: %w", address, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("parse webhook target %q: %w", address, err)
+	}
+	ip = ip.Unmap()
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", errBlockedTarget, ip)
+		}
+	}
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("%w: %s", errBlockedTarget, ip)
+	}
+	return nil
+}
+
+// Send hace un POST del ChangeEvent a la URL de la suscripción, firmado con
+// su secreto. El ID de la entrega viaja en un header para que el receptor
+// pueda deduplicar reintentos.
+func (s *Sender) Send(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "webhookhttp.Send")
+	span.SetAttributes(
+		attribute.String("webhook.subscription_id", sub.ID),
+		attribute.String("webhook.delivery_id", d.ID),
+	)
+	defer span.End()
+
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return 0, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create webhook request: %w", err)
+	}
+	sentAt := s.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, d.Event.ResourceType+"."+string(d.Event.Action))
+	req.Header.Set(webhook.DeliveryHeader, d.ID)
+	req.Header.Set(webhook.TimestampHeader, fmt.Sprintf("%d", sentAt.Unix()))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte(sub.Secret), sentAt, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		observability.ObserveDomainEvent("webhook_delivery_attempted", "error")
+		return 0, fmt.Errorf("call webhook endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drenamos un poco del body para reutilizar la conexión.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		observability.ObserveDomainEvent("webhook_delivery_attempted", "error")
+	} else {
+		observability.ObserveDomainEvent("webhook_delivery_attempted", "success")
+	}
+	return resp.StatusCode, nil
+}
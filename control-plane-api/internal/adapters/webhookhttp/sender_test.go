@@ -0,0 +1,70 @@
+package webhookhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+func testDelivery() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{ID: "wh-1-1", Event: domain.ChangeEvent{ID: 1, ResourceType: "Application", Action: domain.ChangeActionCreated}}
+}
+
+func TestSender_BlocksPrivateTargets(t *testing.T) {
+	hits := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	sub := &domain.WebhookSubscription{ID: "wh-1", URL: receiver.URL, Secret: "0123456789abcdef"}
+
+	if _, err := NewSender().Send(context.Background(), sub, testDelivery()); !errors.Is(err, errBlockedTarget) {
+		t.Fatalf("expected loopback target to be blocked, got %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("expected the receiver not to be called, got %d hits", hits)
+	}
+
+	status, err := NewSender(AllowPrivateTargets()).Send(context.Background(), sub, testDelivery())
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("expected delivery in dev mode, got %d %v", status, err)
+	}
+}
+
+func TestSender_BlocksCGNATAndNAT64Targets(t *testing.T) {
+	s := NewSender()
+	for _, addr := range []string{"100.64.0.1:443", "100.127.255.254:443", "[64:ff9b::a00:1]:443", "[::ffff:100.64.0.1]:443"} {
+		if err := s.checkTarget("tcp", addr, nil); !errors.Is(err, errBlockedTarget) {
+			t.Errorf("expected %s to be blocked, got %v", addr, err)
+		}
+	}
+	if err := s.checkTarget("tcp", "100.128.0.1:443", nil); err != nil {
+		t.Errorf("expected public target to be allowed, got %v", err)
+	}
+}
+
+func TestSender_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+	sub := &domain.WebhookSubscription{ID: "wh-1", URL: receiver.URL + "/hooks", Secret: "0123456789abcdef"}
+
+	status, err := NewSender(AllowPrivateTargets()).Send(context.Background(), sub, testDelivery())
+	if err != nil || status != http.StatusTemporaryRedirect {
+		t.Fatalf("expected the redirect status to be returned, got %d %v", status, err)
+	}
+	if redirected {
+		t.Fatalf("expected the redirect not to be followed")
+	}
+}
@@ -0,0 +1,97 @@
+// Package workflowenginehttp reenvía señales a los workflows a través del
+// endpoint interno de workflow-engine (POST /internal/signals). El control
+// plane no habla con Temporal directamente.
+package workflowenginehttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/application"
+	"github.com/nuevo-idp/platform/auth"
+	"github.com/nuevo-idp/platform/config"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var _ application.WorkflowSignaler = (*Signaler)(nil)
+
+type signalRequest struct {
+	WorkflowID string `json:"workflowId"`
+	Signal     string `json:"signal"`
+	Payload    any    `json:"payload"`
+}
+
+type Signaler struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewSignaler crea un Signaler contra el workflow-engine en baseURL.
+func NewSignaler(baseURL string) *Signaler {
+	return &Signaler{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Signal envía signalName con payload al workflow workflowID. Los errores de
+// workflow-engine conservan su code: workflow_not_found (404) y
+// workflow_not_running (409) llegan tal cual al cliente del control plane.
+func (s *Signaler) Signal(ctx context.Context, workflowID, signalName string, payload any) error {
+	ctx, span := tracing.StartSpan(ctx, "workflowenginehttp.Signal")
+	span.SetAttributes(
+		attribute.String("workflow.id", workflowID),
+		attribute.String("workflow.signal", signalName),
+	)
+	defer span.End()
+
+	body, err := json.Marshal(signalRequest{WorkflowID: workflowID, Signal: signalName, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("marshal signal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/internal/signals", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create signal request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := config.Get("INTERNAL_AUTH_TOKEN", ""); token != "" {
+		req.Header.Set(auth.InternalTokenHeader, token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return perrors.Upstream("workflow_engine_unavailable", "failed to reach workflow-engine", err)
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+		return nil
+	}
+
+	p := httpx.ReadProblem(resp)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return perrors.NotFound(codeOr(p.Code, "workflow_not_found"), p.Detail, nil)
+	case http.StatusConflict:
+		return perrors.Conflict(codeOr(p.Code, "workflow_not_running"), p.Detail, nil)
+	default:
+		return perrors.Upstream("workflow_engine_error", fmt.Sprintf("workflow-engine responded %d: %s", resp.StatusCode, p.Detail), nil)
+	}
+}
+
+func codeOr(code, fallback string) string {
+	if code == "" {
+		return fallback
+	}
+	return code
+}
@@ -0,0 +1,58 @@
+package workflowenginehttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+func TestSignaler_PostsSignalAndMapsErrors(t *testing.T) {
+	var got signalRequest
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/signals" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		switch got.WorkflowID {
+		case "wf-done":
+			httpx.WriteError(w, r, perrors.Conflict("workflow_not_running", "workflow wf-done is COMPLETED", nil))
+		case "wf-missing":
+			httpx.WriteError(w, r, perrors.NotFound("workflow_not_found", "workflow wf-missing not found", nil))
+		case "wf-broken":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer engine.Close()
+
+	s := NewSignaler(engine.URL)
+	ctx := context.Background()
+	if err := s.Signal(ctx, "wf-1", "DecommissioningApproval", map[string]any{"approved": true}); err != nil {
+		t.Fatalf("Signal failed: %v", err)
+	}
+	if got.WorkflowID != "wf-1" || got.Signal != "DecommissioningApproval" {
+		t.Fatalf("unexpected request %+v", got)
+	}
+
+	cases := map[string]struct {
+		kind perrors.Kind
+		code string
+	}{
+		"wf-done":    {perrors.KindConflict, "workflow_not_running"},
+		"wf-missing": {perrors.KindNotFound, "workflow_not_found"},
+		"wf-broken":  {perrors.KindUpstream, "workflow_engine_error"},
+	}
+	for workflowID, want := range cases {
+		err := s.Signal(ctx, workflowID, "DecommissioningApproval", nil)
+		if perrors.KindOf(err) != want.kind || perrors.Code(err) != want.code {
+			t.Errorf("%s: expected %s/%s, got %v", workflowID, want.kind, want.code, err)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
+)
+
+// DefaultApprovalTimeout es el vencimiento de un pedido sin timeout
+// explícito; coincide con el waitForApproval de ApplicationDecommissioning
+// en el estado deseado (172800s).
+const DefaultApprovalTimeout = 48 * time.Hour
+
+// maxApprovalCommentLength acota reason y comment.
+const maxApprovalCommentLength = 1000
+
+// ManualApprovalRoles son los roles de approvalTypes.manual.rolesAllowed.
+var ManualApprovalRoles = []string{RolePlatformAdmin, RoleSecurityAdmin}
+
+type ApprovalRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.Approval, error)
+	List(ctx context.Context) ([]*domain.Approval, error)
+	Save(ctx context.Context, a *domain.Approval) error
+}
+
+// WorkflowSignaler reenvía una señal a un workflow en ejecución (en
+// producción, a través de workflow-engine). Devuelve NotFound si el
+// workflow no existe y Conflict si ya terminó.
+type WorkflowSignaler interface {
+	Signal(ctx context.Context, workflowID, signalName string, payload any) error
+}
+
+// ApprovalSignal es el payload de la señal que recibe el workflow cuando se
+// decide un pedido.
+type ApprovalSignal struct {
+	ApprovalID string    `json:"approvalId"`
+	Approved   bool      `json:"approved"`
+	By         string    `json:"by"`
+	Role       string    `json:"role,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// ApprovalRequest son los datos de un pedido de aprobación nuevo.
+type ApprovalRequest struct {
+	ID           string
+	Type         string
+	ResourceType string
+	ResourceID   string
+	// RolesAllowed vacío usa ManualApprovalRoles.
+	RolesAllowed []string
+	Reason       string
+	// WorkflowID y SignalName, si se informan, reciben la decisión.
+	WorkflowID string
+	SignalName string
+	// Timeout <= 0 usa DefaultApprovalTimeout.
+	Timeout time.Duration
+}
+
+// ApprovalFilter selecciona pedidos en ListApprovals. Roles vacío no
+// filtra; si no, se listan los pedidos que alguno de esos roles puede
+// decidir.
+type ApprovalFilter struct {
+	Roles []string
+	State domain.ApprovalState
+}
+
+// ApplicationApprovalID es el ID del pedido de aprobación que se crea junto
+// con cada Application.
+func ApplicationApprovalID(applicationID string) string {
+	return "application-approval-" + applicationID
+}
+
+// CreateApprovalRequest registra un pedido de aprobación Pending sobre un
+// recurso existente.
+func (s *Services) CreateApprovalRequest(ctx context.Context, req ApprovalRequest, createdBy string) error {
+	if s.Approvals == nil {
+		return perrors.Internal("approval_repository_not_configured", "approval repository not configured", nil)
+	}
+
+	v := validation.New().ID("id", req.ID).Name("type", req.Type).ID("resourceId", req.ResourceID).
+		MaxLength("reason", req.Reason, maxApprovalCommentLength)
+	if req.WorkflowID != "" || req.SignalName != "" {
+		v.Name("workflowId", req.WorkflowID).Name("signalName", req.SignalName)
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	roles := req.RolesAllowed
+	if len(roles) == 0 {
+		roles = ManualApprovalRoles
+	}
+	for _, role := range roles {
+		if !containsString(ManualApprovalRoles, role) {
+			return perrors.Validation("invalid_approval_role", fmt.Sprintf("role %q cannot approve manual approvals", role), nil).
+				WithFields(perrors.FieldError{Field: "rolesAllowed", Message: "must be one of platformAdmin, securityAdmin"})
+		}
+	}
+
+	if existing, _ := s.Approvals.GetByID(ctx, req.ID); existing != nil {
+		return perrors.Conflict("approval_already_exists", "approval already exists", nil)
+	}
+
+	teamID, err := s.approvalResourceTeam(ctx, req.ResourceType, req.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	now := time.Now().UTC()
+	a := &domain.Approval{
+		ID:           req.ID,
+		Type:         req.Type,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		TeamID:       teamID,
+		RolesAllowed: append([]string(nil), roles...),
+		Reason:       req.Reason,
+		WorkflowID:   req.WorkflowID,
+		SignalName:   req.SignalName,
+		ExpiresAt:    now.Add(timeout),
+		State:        domain.ApprovalStatePending,
+		Metadata:     domain.NewMetadata(createdBy, now),
+	}
+	if err := save(ctx, s, s.Approvals.Save, a); err != nil {
+		return fmt.Errorf("saving approval: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionCreated, a, createdBy)
+
+	return nil
+}
+
+// ApproveApproval aprueba un pedido Pending. Si el pedido es la aprobación
+// de una Application, además la transiciona a Approved.
+func (s *Services) ApproveApproval(ctx context.Context, id, comment, approvedBy string) error {
+	return s.decideApproval(ctx, id, true, comment, approvedBy)
+}
+
+// RejectApproval rechaza un pedido Pending.
+func (s *Services) RejectApproval(ctx context.Context, id, comment, rejectedBy string) error {
+	return s.decideApproval(ctx, id, false, comment, rejectedBy)
+}
+
+func (s *Services) decideApproval(ctx context.Context, id string, approved bool, comment, by string) error {
+	if s.Approvals == nil {
+		return perrors.Internal("approval_repository_not_configured", "approval repository not configured", nil)
+	}
+	if err := validation.New().MaxLength("comment", comment, maxApprovalCommentLength).Err(); err != nil {
+		return err
+	}
+
+	a, err := s.Approvals.GetByID(ctx, id)
+	if err != nil || a == nil {
+		return perrors.NotFound("approval_not_found", "approval not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, a.Metadata); err != nil {
+		return err
+	}
+
+	// Aprobar la aprobación de una Application es aprobar la Application.
+	if approved && a.Type == domain.ApprovalTypeApplication {
+		if s.Applications == nil {
+			return perrors.Internal("application_repository_not_configured", "application repository not configured", nil)
+		}
+		app, err := s.Applications.GetByID(ctx, a.ResourceID)
+		if err != nil || app == nil {
+			return perrors.NotFound("application_not_found", "application not found", err)
+		}
+		return s.approveApplication(ctx, app, a, comment, by)
+	}
+
+	return s.resolveApproval(ctx, a, approved, comment, by)
+}
+
+// resolveApproval valida que a se pueda decidir, reenvía la decisión al
+// workflow y la registra. La señal va primero: si el workflow ya no la
+// puede recibir, la decisión no queda registrada y se puede reintentar.
+func (s *Services) resolveApproval(ctx context.Context, a *domain.Approval, approved bool, comment, by string) error {
+	now := time.Now().UTC()
+	if a.ExpiredAt(now) {
+		a.Metadata.RecordTransition(string(a.State), string(domain.ApprovalStateExpired), by, now)
+		a.State = domain.ApprovalStateExpired
+		if err := save(ctx, s, s.Approvals.Save, a); err != nil {
+			return fmt.Errorf("saving expired approval: %w", err)
+		}
+		s.recordChange(ctx, domain.ChangeActionTransitioned, a, by)
+		return perrors.Conflict("approval_expired", "approval expired at "+a.ExpiresAt.Format(time.RFC3339), nil)
+	}
+	if a.State != domain.ApprovalStatePending {
+		return perrors.Domain("approval_already_decided", "approval can only be decided from Pending state", nil)
+	}
+
+	role, err := approverRole(ctx, a)
+	if err != nil {
+		return err
+	}
+
+	if a.WorkflowID != "" {
+		if s.Signals == nil {
+			return perrors.Internal("workflow_signaler_not_configured", "workflow signaler not configured", nil)
+		}
+		signal := ApprovalSignal{ApprovalID: a.ID, Approved: approved, By: by, Role: role, Comment: comment, At: now}
+		if err := s.Signals.Signal(ctx, a.WorkflowID, a.SignalName, signal); err != nil {
+			return err
+		}
+	}
+
+	to := domain.ApprovalStateRejected
+	if approved {
+		to = domain.ApprovalStateApproved
+	}
+	a.Metadata.RecordTransition(string(a.State), string(to), by, now)
+	a.State = to
+	a.Decision = &domain.ApprovalDecision{Approved: approved, By: by, Role: role, Comment: comment, At: now}
+	if err := save(ctx, s, s.Approvals.Save, a); err != nil {
+		return fmt.Errorf("saving approval decision: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionTransitioned, a, by)
+
+	return nil
+}
+
+// approverRole devuelve el rol con el que el principal de ctx decide a. Sin
+// principal autenticado (modo dev, llamadas internas) no se exige rol.
+func approverRole(ctx context.Context, a *domain.Approval) (string, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || p.Kind == auth.PrincipalAnonymous {
+		return "", nil
+	}
+	for _, role := range a.RolesAllowed {
+		if p.HasRole(role) {
+			return role, nil
+		}
+	}
+	return "", perrors.Forbidden("approval_role_required", "approval "+a.ID+" requires one of the roles "+fmt.Sprint(a.RolesAllowed), nil)
+}
+
+func (s *Services) GetApproval(ctx context.Context, id string) (*domain.Approval, error) {
+	if s.Approvals == nil {
+		return nil, perrors.Internal("approval_repository_not_configured", "approval repository not configured", nil)
+	}
+
+	a, err := s.Approvals.GetByID(ctx, id)
+	if err != nil {
+		return nil, perrors.Internal("approval_repository_error", "error loading approval", err)
+	}
+	if a == nil {
+		return nil, perrors.NotFound("approval_not_found", "approval not found", nil)
+	}
+
+	markExpired(a, time.Now().UTC())
+	return a, nil
+}
+
+// ListApprovals devuelve los pedidos que cumplen filter. Los pedidos
+// Pending vencidos se informan como Expired aunque nadie los haya decidido.
+func (s *Services) ListApprovals(ctx context.Context, filter ApprovalFilter) ([]*domain.Approval, error) {
+	if s.Approvals == nil {
+		return nil, perrors.Internal("approval_repository_not_configured", "approval repository not configured", nil)
+	}
+
+	all, err := s.Approvals.List(ctx)
+	if err != nil {
+		return nil, perrors.Internal("approval_repository_error", "error loading approvals", err)
+	}
+
+	now := time.Now().UTC()
+	out := make([]*domain.Approval, 0, len(all))
+	for _, a := range all {
+		markExpired(a, now)
+		if filter.State != "" && a.State != filter.State {
+			continue
+		}
+		if len(filter.Roles) > 0 && !anyRoleAllowed(a, filter.Roles) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func markExpired(a *domain.Approval, now time.Time) {
+	if a.ExpiredAt(now) {
+		a.State = domain.ApprovalStateExpired
+	}
+}
+
+func anyRoleAllowed(a *domain.Approval, roles []string) bool {
+	for _, role := range roles {
+		if a.AllowsRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalResourceTeam valida que el recurso exista y devuelve su team.
+func (s *Services) approvalResourceTeam(ctx context.Context, resourceType, resourceID string) (string, error) {
+	switch resourceType {
+	case "Application":
+		if s.Applications == nil {
+			return "", perrors.Internal("application_repository_not_configured", "application repository not configured", nil)
+		}
+		app, err := s.Applications.GetByID(ctx, resourceID)
+		if err != nil || app == nil {
+			return "", perrors.NotFound("application_not_found", "application not found", err)
+		}
+		return app.TeamID, nil
+	case "Secret":
+		if s.Secrets == nil {
+			return "", perrors.Internal("secret_repository_not_configured", "secret repository not configured", nil)
+		}
+		sec, err := s.Secrets.GetByID(ctx, resourceID)
+		if err != nil || sec == nil {
+			return "", perrors.NotFound("secret_not_found", "secret not found", err)
+		}
+		return sec.OwnerTeam, nil
+	default:
+		return "", perrors.Validation("invalid_approval_resource_type", "approvals can target an Application or a Secret", nil).
+			WithFields(perrors.FieldError{Field: "resourceType", Message: "must be Application or Secret"})
+	}
+}
+
+func (s *Services) approvalTeam(ctx context.Context, id string) string {
+	if s.Approvals == nil {
+		return ""
+	}
+	a, _ := s.Approvals.GetByID(ctx, id)
+	if a == nil {
+		return ""
+	}
+	return a.TeamID
+}
+
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
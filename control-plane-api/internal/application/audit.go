@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nuevo-idp/platform/audit"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// AuditResourceTypes es el tipo de recurso que afecta cada comando, por
+// nombre de caso de uso (el mismo que en Policy). RunBatch no figura: un
+// batch se audita como un único registro sin recurso.
+var AuditResourceTypes = map[string]string{
+	"CreateOrganization":                         "Organization",
+	"CreateTeam":                                 "Team",
+	"SetTeamQuota":                               "Team",
+	"CreateApplication":                          "Application",
+	"ApproveApplication":                         "Application",
+	"StartApplicationOnboarding":                 "Application",
+	"ActivateApplication":                        "Application",
+	"DeprecateApplication":                       "Application",
+	"StartApplicationDecommissioning":            "Application",
+	"ArchiveApplication":                         "Application",
+	"CreateEnvironment":                          "Environment",
+	"DeclareApplicationEnvironment":              "ApplicationEnvironment",
+	"CompleteApplicationEnvironmentProvisioning": "ApplicationEnvironment",
+	"DecommissionApplicationEnvironment":         "ApplicationEnvironment",
+	"CreateSecret":                               "Secret",
+	"StartSecretRotation":                        "Secret",
+	"CompleteSecretRotation":                     "Secret",
+	"DeclareSecretBinding":                       "SecretBinding",
+	"RevokeSecretBinding":                        "SecretBinding",
+	"DeclareCodeRepository":                      "CodeRepository",
+	"DeclareDeploymentRepository":                "DeploymentRepository",
+	"DeclareGitOpsIntegration":                   "GitOpsIntegration",
+	"CreateWebhookSubscription":                  "WebhookSubscription",
+	"DisableWebhookSubscription":                 "WebhookSubscription",
+	"RedeliverWebhookDelivery":                   "WebhookDelivery",
+	"CreateApprovalRequest":                      "Approval",
+	"ApproveApproval":                            "Approval",
+	"RejectApproval":                             "Approval",
+}
+
+// AuditCommandName convierte un operationId HTTP o un método gRPC en el
+// nombre del caso de uso con el que se audita (createTeam -> CreateTeam).
+func AuditCommandName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// QueryAudit devuelve los registros de auditoría que cumplen filter.
+func (s *Services) QueryAudit(ctx context.Context, filter audit.Filter) ([]audit.Record, error) {
+	if s.Audit == nil {
+		return nil, perrors.Internal("audit_store_not_configured", "audit store not configured", nil)
+	}
+	return s.Audit.Query(ctx, filter)
+}
+
+// VerifyAudit recorre el log de auditoría y comprueba su cadena de hashes.
+func (s *Services) VerifyAudit(ctx context.Context) (audit.Verification, error) {
+	if s.Audit == nil {
+		return audit.Verification{}, perrors.Internal("audit_store_not_configured", "audit store not configured", nil)
+	}
+	return s.Audit.Verify(ctx)
+}
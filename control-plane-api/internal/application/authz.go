@@ -0,0 +1,665 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// Roles definidos en el modelo de estado deseado (approvalTypes.manual).
+const (
+	RolePlatformAdmin = "platformAdmin"
+	RoleSecurityAdmin = "securityAdmin"
+)
+
+// API es la superficie de casos de uso que consumen los adapters. La
+// implementan Services y Authorizer, de modo que la autorización se puede
+// interponer sin que los adapters lo noten.
+type API interface {
+	GetOrganization(ctx context.Context, id string) (*domain.Organization, error)
+	GetTeamQuota(ctx context.Context, teamID string) (*domain.TeamQuotaUsage, error)
+	GetApplication(ctx context.Context, id string) (*domain.Application, error)
+	GetEnvironment(ctx context.Context, id string) (*domain.Environment, error)
+	GetApplicationEnvironment(ctx context.Context, id string) (*domain.ApplicationEnvironment, error)
+	ListApplicationEnvironments(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error)
+	ListSecretBindings(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error)
+	WatchChanges(ctx context.Context, afterID int64, buffer int) (ChangeSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, state domain.WebhookDeliveryState) ([]*domain.WebhookDelivery, error)
+	GetApproval(ctx context.Context, id string) (*domain.Approval, error)
+	ListApprovals(ctx context.Context, filter ApprovalFilter) ([]*domain.Approval, error)
+	QueryAudit(ctx context.Context, filter audit.Filter) ([]audit.Record, error)
+	VerifyAudit(ctx context.Context) (audit.Verification, error)
+
+	CreateOrganization(ctx context.Context, id, name, createdBy string) error
+	CreateTeam(ctx context.Context, id, name, createdBy string) error
+	SetTeamQuota(ctx context.Context, teamID string, quota domain.TeamQuota, setBy string) error
+	CreateApplication(ctx context.Context, id, name, teamID, createdBy string) error
+	ApproveApplication(ctx context.Context, id, approvedBy string) error
+	StartApplicationOnboarding(ctx context.Context, id, startedBy string) error
+	ActivateApplication(ctx context.Context, id, activatedBy string) error
+	DeprecateApplication(ctx context.Context, id, deprecatedBy string) error
+	DeclareCodeRepository(ctx context.Context, id, applicationID, createdBy string) error
+	CreateEnvironment(ctx context.Context, id, name, createdBy string) error
+	DeclareDeploymentRepository(ctx context.Context, id, applicationID, deploymentModel, createdBy string) error
+	DeclareApplicationEnvironment(ctx context.Context, id, applicationID, environmentID, createdBy string) error
+	CompleteApplicationEnvironmentProvisioning(ctx context.Context, id, completedBy string) error
+	DeclareGitOpsIntegration(ctx context.Context, id, applicationID, deploymentRepoID, createdBy string) error
+	CreateSecret(ctx context.Context, id, ownerTeamID, purpose, sensitivity, createdBy string) error
+	StartSecretRotation(ctx context.Context, id, startedBy string) error
+	CompleteSecretRotation(ctx context.Context, id, completedBy string) error
+	DeclareSecretBinding(ctx context.Context, id, secretID, targetID, targetType, createdBy string) error
+	StartApplicationDecommissioning(ctx context.Context, id, startedBy string) error
+	DecommissionApplicationEnvironment(ctx context.Context, id, decommissionedBy string) error
+	RevokeSecretBinding(ctx context.Context, id, revokedBy string) error
+	ArchiveApplication(ctx context.Context, id, archivedBy string) error
+	CreateWebhookSubscription(ctx context.Context, id, teamID, rawURL, secret string, filter domain.WebhookFilter, createdBy string) error
+	DisableWebhookSubscription(ctx context.Context, id, disabledBy string) error
+	RedeliverWebhookDelivery(ctx context.Context, id, requestedBy string) error
+	CreateApprovalRequest(ctx context.Context, req ApprovalRequest, createdBy string) error
+	ApproveApproval(ctx context.Context, id, comment, approvedBy string) error
+	RejectApproval(ctx context.Context, id, comment, rejectedBy string) error
+
+	RunBatch(ctx context.Context, mode BatchMode, steps []BatchStep) ([]error, error)
+	DryRun(ctx context.Context, mode BatchMode, steps []BatchStep) ([]DryRunResult, error)
+}
+
+var (
+	_ API = (*Services)(nil)
+	_ API = (*Authorizer)(nil)
+)
+
+// Rule describe quién puede ejecutar un comando. Basta con que se cumpla
+// una de las condiciones.
+type Rule struct {
+	// Roles que otorgan acceso sin importar el team.
+	Roles []string
+	// TeamMember otorga acceso a los miembros del team dueño del recurso
+	// (el principal tiene un grupo con el ID del team).
+	TeamMember bool
+	// Service otorga acceso a principals de servicio (workflow-engine).
+	Service bool
+	// Authenticated otorga acceso a cualquier principal autenticado.
+	Authenticated bool
+}
+
+// Policy mapea el nombre de cada comando/consulta a su regla. Un comando
+// ausente de la tabla se deniega.
+type Policy map[string]Rule
+
+// DefaultPolicy es la política de la plataforma: los teams son soberanos
+// sobre sus recursos, la aprobación es manual por platformAdmin o
+// securityAdmin, y los secretos los gobierna securityAdmin junto al team
+// dueño.
+func DefaultPolicy() Policy {
+	platformOrTeam := Rule{Roles: []string{RolePlatformAdmin}, TeamMember: true}
+	platformTeamOrService := Rule{Roles: []string{RolePlatformAdmin}, TeamMember: true, Service: true}
+	securityOrTeam := Rule{Roles: []string{RoleSecurityAdmin}, TeamMember: true}
+	workflowStep := Rule{Roles: []string{RolePlatformAdmin}, Service: true}
+	approvalReaders := Rule{Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}, TeamMember: true, Service: true}
+
+	return Policy{
+		"GetApplication":              {Authenticated: true},
+		"GetEnvironment":              {Authenticated: true},
+		"GetApplicationEnvironment":   {Authenticated: true},
+		"ListApplicationEnvironments": {Authenticated: true},
+		"WatchChanges":                {Authenticated: true},
+		"GetOrganization":             {Authenticated: true},
+
+		"CreateOrganization": {Roles: []string{RolePlatformAdmin}},
+		"CreateTeam":         {Roles: []string{RolePlatformAdmin}},
+		"CreateEnvironment":  {Roles: []string{RolePlatformAdmin}},
+
+		// Las cuotas las fija la plataforma; el team sólo consulta la suya.
+		"SetTeamQuota": {Roles: []string{RolePlatformAdmin}},
+		"GetTeamQuota": platformOrTeam,
+
+		"CreateApplication":          platformOrTeam,
+		"ApproveApplication":         {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}},
+		"StartApplicationOnboarding": workflowStep,
+		"ActivateApplication":        workflowStep,
+		"DeprecateApplication":       platformOrTeam,
+
+		"DeclareCodeRepository":                      platformTeamOrService,
+		"DeclareDeploymentRepository":                platformTeamOrService,
+		"DeclareGitOpsIntegration":                   platformTeamOrService,
+		"DeclareApplicationEnvironment":              platformTeamOrService,
+		"CompleteApplicationEnvironmentProvisioning": workflowStep,
+
+		"CreateSecret":           securityOrTeam,
+		"StartSecretRotation":    securityOrTeam,
+		"CompleteSecretRotation": {Roles: []string{RoleSecurityAdmin}, Service: true},
+		"DeclareSecretBinding":   securityOrTeam,
+
+		// El decommissioning lo ejecuta el workflow una vez aprobado; los
+		// bindings de un target pueden ser de secretos de varios teams.
+		"StartApplicationDecommissioning":    workflowStep,
+		"DecommissionApplicationEnvironment": workflowStep,
+		"ArchiveApplication":                 workflowStep,
+		"RevokeSecretBinding":                {Roles: []string{RoleSecurityAdmin}, Service: true},
+		"ListSecretBindings":                 {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}, Service: true},
+
+		// Las suscripciones exponen URLs y entregas del team: ni siquiera
+		// las queries son abiertas a cualquier autenticado.
+		"CreateWebhookSubscription":  platformOrTeam,
+		"DisableWebhookSubscription": platformOrTeam,
+		"GetWebhookSubscription":     platformOrTeam,
+		"ListWebhookDeliveries":      platformOrTeam,
+		"RedeliverWebhookDelivery":   platformOrTeam,
+
+		// Los pedidos de aprobación los crean los workflows o los teams
+		// sobre sus recursos; decidirlos exige un rol de approvalTypes.manual
+		// (Services además exige uno de los RolesAllowed del pedido). Los
+		// leen el team dueño, los aprobadores y los workflows; ListApprovals
+		// le muestra a un team sólo sus pedidos.
+		"GetApproval":           approvalReaders,
+		"ListApprovals":         approvalReaders,
+		"CreateApprovalRequest": platformTeamOrService,
+		"ApproveApproval":       {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}},
+		"RejectApproval":        {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}},
+
+		// El log de auditoría cruza todos los teams.
+		"QueryAudit":  {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}},
+		"VerifyAudit": {Roles: []string{RolePlatformAdmin, RoleSecurityAdmin}},
+
+		// El batch y el dry-run en sí sólo exigen autenticación: cada
+		// comando se autoriza con su propia regla.
+		"RunBatch": {Authenticated: true},
+		"DryRun":   {Authenticated: true},
+	}
+}
+
+// installWideCommands operan sobre toda la instalación y no dentro de una
+// organización: un principal ligado a una organización no puede
+// ejecutarlos, tenga el rol que tenga.
+var installWideCommands = map[string]bool{
+	"CreateOrganization": true,
+	"QueryAudit":         true,
+	"VerifyAudit":        true,
+}
+
+// allows indica si la regla deja pasar a p sobre un recurso del team teamID
+// de la organización org.
+func (r Rule) allows(p auth.Principal, org, teamID string) bool {
+	if r.Authenticated && p.Kind != auth.PrincipalAnonymous {
+		return true
+	}
+	if r.Service && p.Kind == auth.PrincipalService {
+		return true
+	}
+	for _, role := range r.Roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return r.TeamMember && teamID != "" && memberOrganization(p) == org && p.InGroup(teamID)
+}
+
+// memberOrganization es la organización en la que cuentan los grupos de p:
+// la suya o, si el token no la restringe, la default. Los IDs de team sólo
+// son únicos dentro de una organización, así que el grupo "payments" no da
+// acceso al team payments de otra.
+func memberOrganization(p auth.Principal) string {
+	if p.Organization == "" {
+		return domain.DefaultOrganizationID
+	}
+	return p.Organization
+}
+
+// Denial es el registro de auditoría de una llamada denegada.
+type Denial struct {
+	Command      string
+	Subject      string
+	Kind         auth.PrincipalKind
+	Organization string
+	TeamID       string
+	At           time.Time
+}
+
+// DenialAuditor registra cada llamada denegada por el Authorizer.
+type DenialAuditor interface {
+	AuditDenied(ctx context.Context, d Denial)
+}
+
+// AuthorizerOptions configura un Authorizer.
+type AuthorizerOptions struct {
+	Policy  Policy
+	Auditor DenialAuditor
+	// AllowAnonymous deja pasar al principal anónimo en cualquier comando.
+	// Sólo tiene sentido en modo dev, cuando no hay JWT configurado.
+	AllowAnonymous bool
+}
+
+// Authorizer decora Services aplicando la política antes de cada llamada.
+// El principal se toma del contexto (ver auth.WithPrincipal); sin principal,
+// o sin regla para el comando, la llamada se deniega.
+type Authorizer struct {
+	next *Services
+	opts AuthorizerOptions
+}
+
+// NewAuthorizer envuelve next con la política dada. Si opts.Policy es nil se
+// usa DefaultPolicy.
+func NewAuthorizer(next *Services, opts AuthorizerOptions) *Authorizer {
+	if opts.Policy == nil {
+		opts.Policy = DefaultPolicy()
+	}
+	return &Authorizer{next: next, opts: opts}
+}
+
+// authorize evalúa la regla de command para el principal de ctx. teamID es
+// el team dueño del recurso, o "" si no aplica o no se pudo resolver. Un
+// principal ligado a una organización sólo opera dentro de ella.
+func (a *Authorizer) authorize(ctx context.Context, command, teamID string) error {
+	if a.allowed(ctx, command, teamID) {
+		return nil
+	}
+
+	p, _ := auth.PrincipalFromContext(ctx)
+	org := domain.OrganizationFromContext(ctx)
+	if a.opts.Auditor != nil {
+		a.opts.Auditor.AuditDenied(ctx, Denial{
+			Command:      command,
+			Subject:      p.Subject,
+			Kind:         p.Kind,
+			Organization: org,
+			TeamID:       teamID,
+			At:           time.Now().UTC(),
+		})
+	}
+	return perrors.Forbidden("forbidden", "principal is not allowed to perform "+command, nil)
+}
+
+// allowed es authorize sin auditar la denegación, para filtrar listados.
+func (a *Authorizer) allowed(ctx context.Context, command, teamID string) bool {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if ok && p.Kind == auth.PrincipalAnonymous && a.opts.AllowAnonymous {
+		return true
+	}
+	org := domain.OrganizationFromContext(ctx)
+	inOrganization := p.Organization == "" || (p.Organization == org && !installWideCommands[command])
+	rule, found := a.opts.Policy[command]
+	return ok && found && inOrganization && rule.allows(p, org, teamID)
+}
+
+// applicationTeam resuelve el team dueño de una Application. Los errores se
+// ignoran: el comando subyacente devolverá el NotFound que corresponda si
+// la regla lo deja pasar.
+func (a *Authorizer) applicationTeam(ctx context.Context, applicationID string) string {
+	return a.next.applicationTeam(ctx, applicationID)
+}
+
+// applicationEnvironmentTeam resuelve el team dueño de la Application de un
+// ApplicationEnvironment.
+func (a *Authorizer) applicationEnvironmentTeam(ctx context.Context, id string) string {
+	if a.next.ApplicationEnvironments == nil {
+		return ""
+	}
+	ae, _ := a.next.ApplicationEnvironments.GetByID(ctx, id)
+	if ae == nil {
+		return ""
+	}
+	return a.applicationTeam(ctx, ae.ApplicationID)
+}
+
+func (a *Authorizer) secretTeam(ctx context.Context, secretID string) string {
+	if a.next.Secrets == nil {
+		return ""
+	}
+	sec, _ := a.next.Secrets.GetByID(ctx, secretID)
+	if sec == nil {
+		return ""
+	}
+	return sec.OwnerTeam
+}
+
+// GetOrganization se autoriza como si la request operara en id, así un
+// principal ligado a otra organización no la puede leer.
+func (a *Authorizer) GetOrganization(ctx context.Context, id string) (*domain.Organization, error) {
+	if err := a.authorize(domain.WithOrganization(ctx, id), "GetOrganization", ""); err != nil {
+		return nil, err
+	}
+	return a.next.GetOrganization(ctx, id)
+}
+
+func (a *Authorizer) CreateOrganization(ctx context.Context, id, name, createdBy string) error {
+	if err := a.authorize(ctx, "CreateOrganization", ""); err != nil {
+		return err
+	}
+	return a.next.CreateOrganization(ctx, id, name, createdBy)
+}
+
+func (a *Authorizer) GetApplication(ctx context.Context, id string) (*domain.Application, error) {
+	if err := a.authorize(ctx, "GetApplication", ""); err != nil {
+		return nil, err
+	}
+	return a.next.GetApplication(ctx, id)
+}
+
+func (a *Authorizer) GetEnvironment(ctx context.Context, id string) (*domain.Environment, error) {
+	if err := a.authorize(ctx, "GetEnvironment", ""); err != nil {
+		return nil, err
+	}
+	return a.next.GetEnvironment(ctx, id)
+}
+
+func (a *Authorizer) GetApplicationEnvironment(ctx context.Context, id string) (*domain.ApplicationEnvironment, error) {
+	if err := a.authorize(ctx, "GetApplicationEnvironment", ""); err != nil {
+		return nil, err
+	}
+	return a.next.GetApplicationEnvironment(ctx, id)
+}
+
+func (a *Authorizer) WatchChanges(ctx context.Context, afterID int64, buffer int) (ChangeSubscription, error) {
+	if err := a.authorize(ctx, "WatchChanges", ""); err != nil {
+		return ChangeSubscription{}, err
+	}
+	return a.next.WatchChanges(ctx, afterID, buffer)
+}
+
+func (a *Authorizer) CreateTeam(ctx context.Context, id, name, createdBy string) error {
+	if err := a.authorize(ctx, "CreateTeam", id); err != nil {
+		return err
+	}
+	return a.next.CreateTeam(ctx, id, name, createdBy)
+}
+
+func (a *Authorizer) GetTeamQuota(ctx context.Context, teamID string) (*domain.TeamQuotaUsage, error) {
+	if err := a.authorize(ctx, "GetTeamQuota", teamID); err != nil {
+		return nil, err
+	}
+	return a.next.GetTeamQuota(ctx, teamID)
+}
+
+func (a *Authorizer) SetTeamQuota(ctx context.Context, teamID string, quota domain.TeamQuota, setBy string) error {
+	if err := a.authorize(ctx, "SetTeamQuota", teamID); err != nil {
+		return err
+	}
+	return a.next.SetTeamQuota(ctx, teamID, quota, setBy)
+}
+
+func (a *Authorizer) CreateApplication(ctx context.Context, id, name, teamID, createdBy string) error {
+	if err := a.authorize(ctx, "CreateApplication", teamID); err != nil {
+		return err
+	}
+	return a.next.CreateApplication(ctx, id, name, teamID, createdBy)
+}
+
+func (a *Authorizer) ApproveApplication(ctx context.Context, id, approvedBy string) error {
+	if err := a.authorize(ctx, "ApproveApplication", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.ApproveApplication(ctx, id, approvedBy)
+}
+
+func (a *Authorizer) StartApplicationOnboarding(ctx context.Context, id, startedBy string) error {
+	if err := a.authorize(ctx, "StartApplicationOnboarding", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.StartApplicationOnboarding(ctx, id, startedBy)
+}
+
+func (a *Authorizer) ActivateApplication(ctx context.Context, id, activatedBy string) error {
+	if err := a.authorize(ctx, "ActivateApplication", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.ActivateApplication(ctx, id, activatedBy)
+}
+
+func (a *Authorizer) DeprecateApplication(ctx context.Context, id, deprecatedBy string) error {
+	if err := a.authorize(ctx, "DeprecateApplication", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.DeprecateApplication(ctx, id, deprecatedBy)
+}
+
+func (a *Authorizer) DeclareCodeRepository(ctx context.Context, id, applicationID, createdBy string) error {
+	if err := a.authorize(ctx, "DeclareCodeRepository", a.applicationTeam(ctx, applicationID)); err != nil {
+		return err
+	}
+	return a.next.DeclareCodeRepository(ctx, id, applicationID, createdBy)
+}
+
+func (a *Authorizer) CreateEnvironment(ctx context.Context, id, name, createdBy string) error {
+	if err := a.authorize(ctx, "CreateEnvironment", ""); err != nil {
+		return err
+	}
+	return a.next.CreateEnvironment(ctx, id, name, createdBy)
+}
+
+func (a *Authorizer) DeclareDeploymentRepository(ctx context.Context, id, applicationID, deploymentModel, createdBy string) error {
+	if err := a.authorize(ctx, "DeclareDeploymentRepository", a.applicationTeam(ctx, applicationID)); err != nil {
+		return err
+	}
+	return a.next.DeclareDeploymentRepository(ctx, id, applicationID, deploymentModel, createdBy)
+}
+
+func (a *Authorizer) DeclareApplicationEnvironment(ctx context.Context, id, applicationID, environmentID, createdBy string) error {
+	if err := a.authorize(ctx, "DeclareApplicationEnvironment", a.applicationTeam(ctx, applicationID)); err != nil {
+		return err
+	}
+	return a.next.DeclareApplicationEnvironment(ctx, id, applicationID, environmentID, createdBy)
+}
+
+func (a *Authorizer) CompleteApplicationEnvironmentProvisioning(ctx context.Context, id, completedBy string) error {
+	if err := a.authorize(ctx, "CompleteApplicationEnvironmentProvisioning", a.applicationEnvironmentTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.CompleteApplicationEnvironmentProvisioning(ctx, id, completedBy)
+}
+
+func (a *Authorizer) DeclareGitOpsIntegration(ctx context.Context, id, applicationID, deploymentRepoID, createdBy string) error {
+	if err := a.authorize(ctx, "DeclareGitOpsIntegration", a.applicationTeam(ctx, applicationID)); err != nil {
+		return err
+	}
+	return a.next.DeclareGitOpsIntegration(ctx, id, applicationID, deploymentRepoID, createdBy)
+}
+
+func (a *Authorizer) CreateSecret(ctx context.Context, id, ownerTeamID, purpose, sensitivity, createdBy string) error {
+	if err := a.authorize(ctx, "CreateSecret", ownerTeamID); err != nil {
+		return err
+	}
+	return a.next.CreateSecret(ctx, id, ownerTeamID, purpose, sensitivity, createdBy)
+}
+
+func (a *Authorizer) StartSecretRotation(ctx context.Context, id, startedBy string) error {
+	if err := a.authorize(ctx, "StartSecretRotation", a.secretTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.StartSecretRotation(ctx, id, startedBy)
+}
+
+func (a *Authorizer) CompleteSecretRotation(ctx context.Context, id, completedBy string) error {
+	if err := a.authorize(ctx, "CompleteSecretRotation", a.secretTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.CompleteSecretRotation(ctx, id, completedBy)
+}
+
+func (a *Authorizer) DeclareSecretBinding(ctx context.Context, id, secretID, targetID, targetType, createdBy string) error {
+	if err := a.authorize(ctx, "DeclareSecretBinding", a.secretTeam(ctx, secretID)); err != nil {
+		return err
+	}
+	return a.next.DeclareSecretBinding(ctx, id, secretID, targetID, targetType, createdBy)
+}
+
+func (a *Authorizer) ListApplicationEnvironments(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error) {
+	if err := a.authorize(ctx, "ListApplicationEnvironments", ""); err != nil {
+		return nil, err
+	}
+	return a.next.ListApplicationEnvironments(ctx, applicationID)
+}
+
+func (a *Authorizer) ListSecretBindings(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error) {
+	if err := a.authorize(ctx, "ListSecretBindings", ""); err != nil {
+		return nil, err
+	}
+	return a.next.ListSecretBindings(ctx, targetType, targetID)
+}
+
+func (a *Authorizer) StartApplicationDecommissioning(ctx context.Context, id, startedBy string) error {
+	if err := a.authorize(ctx, "StartApplicationDecommissioning", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.StartApplicationDecommissioning(ctx, id, startedBy)
+}
+
+func (a *Authorizer) DecommissionApplicationEnvironment(ctx context.Context, id, decommissionedBy string) error {
+	if err := a.authorize(ctx, "DecommissionApplicationEnvironment", a.applicationEnvironmentTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.DecommissionApplicationEnvironment(ctx, id, decommissionedBy)
+}
+
+func (a *Authorizer) RevokeSecretBinding(ctx context.Context, id, revokedBy string) error {
+	teamID := ""
+	if a.next.SecretBindings != nil {
+		if b, _ := a.next.SecretBindings.GetByID(ctx, id); b != nil {
+			teamID = a.secretTeam(ctx, b.SecretID)
+		}
+	}
+	if err := a.authorize(ctx, "RevokeSecretBinding", teamID); err != nil {
+		return err
+	}
+	return a.next.RevokeSecretBinding(ctx, id, revokedBy)
+}
+
+func (a *Authorizer) ArchiveApplication(ctx context.Context, id, archivedBy string) error {
+	if err := a.authorize(ctx, "ArchiveApplication", a.applicationTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.ArchiveApplication(ctx, id, archivedBy)
+}
+
+func (a *Authorizer) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	if err := a.authorize(ctx, "GetWebhookSubscription", a.next.webhookSubscriptionTeam(ctx, id)); err != nil {
+		return nil, err
+	}
+	return a.next.GetWebhookSubscription(ctx, id)
+}
+
+func (a *Authorizer) ListWebhookDeliveries(ctx context.Context, subscriptionID string, state domain.WebhookDeliveryState) ([]*domain.WebhookDelivery, error) {
+	if err := a.authorize(ctx, "ListWebhookDeliveries", a.next.webhookSubscriptionTeam(ctx, subscriptionID)); err != nil {
+		return nil, err
+	}
+	return a.next.ListWebhookDeliveries(ctx, subscriptionID, state)
+}
+
+func (a *Authorizer) CreateWebhookSubscription(ctx context.Context, id, teamID, rawURL, secret string, filter domain.WebhookFilter, createdBy string) error {
+	if err := a.authorize(ctx, "CreateWebhookSubscription", teamID); err != nil {
+		return err
+	}
+	return a.next.CreateWebhookSubscription(ctx, id, teamID, rawURL, secret, filter, createdBy)
+}
+
+func (a *Authorizer) DisableWebhookSubscription(ctx context.Context, id, disabledBy string) error {
+	if err := a.authorize(ctx, "DisableWebhookSubscription", a.next.webhookSubscriptionTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.DisableWebhookSubscription(ctx, id, disabledBy)
+}
+
+func (a *Authorizer) RedeliverWebhookDelivery(ctx context.Context, id, requestedBy string) error {
+	if err := a.authorize(ctx, "RedeliverWebhookDelivery", a.next.webhookDeliveryTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.RedeliverWebhookDelivery(ctx, id, requestedBy)
+}
+
+func (a *Authorizer) GetApproval(ctx context.Context, id string) (*domain.Approval, error) {
+	if err := a.authorize(ctx, "GetApproval", a.next.approvalTeam(ctx, id)); err != nil {
+		return nil, err
+	}
+	return a.next.GetApproval(ctx, id)
+}
+
+// ListApprovals devuelve el listado completo a quien la regla admite sin
+// team (aprobadores y workflows). A cualquier otro principal autenticado le
+// devuelve sólo los pedidos de los teams a los que pertenece.
+func (a *Authorizer) ListApprovals(ctx context.Context, filter ApprovalFilter) ([]*domain.Approval, error) {
+	if a.allowed(ctx, "ListApprovals", "") {
+		return a.next.ListApprovals(ctx, filter)
+	}
+	if p, ok := auth.PrincipalFromContext(ctx); !ok || p.Kind == auth.PrincipalAnonymous {
+		return nil, a.authorize(ctx, "ListApprovals", "")
+	}
+
+	all, err := a.next.ListApprovals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*domain.Approval, 0, len(all))
+	for _, approval := range all {
+		if a.allowed(ctx, "ListApprovals", approval.TeamID) {
+			out = append(out, approval)
+		}
+	}
+	return out, nil
+}
+
+func (a *Authorizer) QueryAudit(ctx context.Context, filter audit.Filter) ([]audit.Record, error) {
+	if err := a.authorize(ctx, "QueryAudit", ""); err != nil {
+		return nil, err
+	}
+	return a.next.QueryAudit(ctx, filter)
+}
+
+func (a *Authorizer) VerifyAudit(ctx context.Context) (audit.Verification, error) {
+	if err := a.authorize(ctx, "VerifyAudit", ""); err != nil {
+		return audit.Verification{}, err
+	}
+	return a.next.VerifyAudit(ctx)
+}
+
+func (a *Authorizer) CreateApprovalRequest(ctx context.Context, req ApprovalRequest, createdBy string) error {
+	teamID, _ := a.next.approvalResourceTeam(ctx, req.ResourceType, req.ResourceID)
+	if err := a.authorize(ctx, "CreateApprovalRequest", teamID); err != nil {
+		return err
+	}
+	return a.next.CreateApprovalRequest(ctx, req, createdBy)
+}
+
+func (a *Authorizer) ApproveApproval(ctx context.Context, id, comment, approvedBy string) error {
+	if err := a.authorize(ctx, "ApproveApproval", a.next.approvalTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.ApproveApproval(ctx, id, comment, approvedBy)
+}
+
+func (a *Authorizer) RejectApproval(ctx context.Context, id, comment, rejectedBy string) error {
+	if err := a.authorize(ctx, "RejectApproval", a.next.approvalTeam(ctx, id)); err != nil {
+		return err
+	}
+	return a.next.RejectApproval(ctx, id, comment, rejectedBy)
+}
+
+// RunBatch autoriza cada step con su propia regla: los steps reciben un
+// Authorizer con la misma política sobre los Services del batch, de modo
+// que en modo atómico los teams se resuelven también contra lo creado en
+// el mismo batch.
+func (a *Authorizer) RunBatch(ctx context.Context, mode BatchMode, steps []BatchStep) ([]error, error) {
+	if err := a.authorize(ctx, "RunBatch", ""); err != nil {
+		return nil, err
+	}
+	return a.next.runBatch(ctx, mode, steps, func(staged *Services) API {
+		if staged == a.next {
+			return a
+		}
+		return &Authorizer{next: staged, opts: a.opts}
+	})
+}
+
+// DryRun autoriza cada step con su propia regla, igual que RunBatch: un
+// comando que se denegaría responde forbidden también en dry-run.
+func (a *Authorizer) DryRun(ctx context.Context, mode BatchMode, steps []BatchStep) ([]DryRunResult, error) {
+	if err := a.authorize(ctx, "DryRun", ""); err != nil {
+		return nil, err
+	}
+	return a.next.dryRun(ctx, mode, steps, func(staged *Services) API {
+		return &Authorizer{next: staged, opts: a.opts}
+	})
+}
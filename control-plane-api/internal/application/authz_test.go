@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+type recordingAuditor struct {
+	denials []Denial
+}
+
+func (r *recordingAuditor) AuditDenied(_ context.Context, d Denial) {
+	r.denials = append(r.denials, d)
+}
+
+func newAuthzFixture(t *testing.T) (*Services, *recordingAuditor, *Authorizer) {
+	t.Helper()
+
+	services := &Services{
+		Teams:        memoryrepo.NewTeamRepository(),
+		Applications: memoryrepo.NewApplicationRepository(),
+		Secrets:      memoryrepo.NewSecretRepository(),
+	}
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-a", "A", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-a", "App A", "team-a", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	if err := services.Secrets.Save(ctx, &domain.Secret{ID: "sec-a", OwnerTeam: "team-a", State: domain.SecretStateActive}); err != nil {
+		t.Fatalf("save secret: %v", err)
+	}
+
+	auditor := &recordingAuditor{}
+	return services, auditor, NewAuthorizer(services, AuthorizerOptions{Auditor: auditor})
+}
+
+func as(p auth.Principal) context.Context {
+	return auth.WithPrincipal(context.Background(), p)
+}
+
+func TestAuthorizer_EnforcesRolesAndTeamScope(t *testing.T) {
+	var (
+		platformAdmin = auth.Principal{Subject: "pat", Kind: auth.PrincipalUser, Roles: []string{RolePlatformAdmin}}
+		securityAdmin = auth.Principal{Subject: "sam", Kind: auth.PrincipalUser, Roles: []string{RoleSecurityAdmin}}
+		teamAMember   = auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"team-a"}}
+		teamBMember   = auth.Principal{Subject: "ben", Kind: auth.PrincipalUser, Groups: []string{"team-b"}}
+		engine        = auth.Principal{Subject: "workflow-engine", Kind: auth.PrincipalService}
+	)
+
+	cases := []struct {
+		name    string
+		who     auth.Principal
+		call    func(ctx context.Context, a *Authorizer) error
+		allowed bool
+	}{
+		{"platformAdmin approves", platformAdmin, func(ctx context.Context, a *Authorizer) error {
+			return a.ApproveApplication(ctx, "app-a", "pat")
+		}, true},
+		{"securityAdmin approves", securityAdmin, func(ctx context.Context, a *Authorizer) error {
+			return a.ApproveApplication(ctx, "app-a", "sam")
+		}, true},
+		{"team member cannot approve own app", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.ApproveApplication(ctx, "app-a", "ana")
+		}, false},
+		{"team member creates application in own team", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.CreateApplication(ctx, "app-a2", "App", "team-a", "ana")
+		}, true},
+		{"team member cannot create application in other team", teamBMember, func(ctx context.Context, a *Authorizer) error {
+			return a.CreateApplication(ctx, "app-b", "App", "team-a", "ben")
+		}, false},
+		{"team member creates secret for own team", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.CreateSecret(ctx, "sec-a2", "team-a", "runtime", "high", "ana")
+		}, true},
+		{"team member cannot create secret for other team", teamBMember, func(ctx context.Context, a *Authorizer) error {
+			return a.CreateSecret(ctx, "sec-b", "team-a", "runtime", "high", "ben")
+		}, false},
+		{"team member starts rotation of own secret", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.StartSecretRotation(ctx, "sec-a", "ana")
+		}, true},
+		{"other team cannot start rotation", teamBMember, func(ctx context.Context, a *Authorizer) error {
+			return a.StartSecretRotation(ctx, "sec-a", "ben")
+		}, false},
+		{"platformAdmin cannot start rotation", platformAdmin, func(ctx context.Context, a *Authorizer) error {
+			return a.StartSecretRotation(ctx, "sec-a", "pat")
+		}, false},
+		{"only platformAdmin creates teams", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.CreateTeam(ctx, "team-c", "C", "ana")
+		}, false},
+		{"engine activates application", engine, func(ctx context.Context, a *Authorizer) error {
+			return a.StartApplicationOnboarding(ctx, "app-a", "workflow-engine")
+		}, true},
+		{"team member cannot drive workflow transitions", teamAMember, func(ctx context.Context, a *Authorizer) error {
+			return a.StartApplicationOnboarding(ctx, "app-a", "ana")
+		}, false},
+		{"any user can query", teamBMember, func(ctx context.Context, a *Authorizer) error {
+			_, err := a.GetApplication(ctx, "app-a")
+			return err
+		}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, auditor, authz := newAuthzFixture(t)
+			err := tc.call(as(tc.who), authz)
+
+			denied := perrors.IsKind(err, perrors.KindForbidden)
+			if denied == tc.allowed {
+				t.Fatalf("expected allowed=%v, got err=%v", tc.allowed, err)
+			}
+			if denied && (len(auditor.denials) != 1 || auditor.denials[0].Subject != tc.who.Subject) {
+				t.Fatalf("expected denial to be audited, got %+v", auditor.denials)
+			}
+			if !denied && len(auditor.denials) != 0 {
+				t.Fatalf("expected no audited denials, got %+v", auditor.denials)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_DeniesByDefault(t *testing.T) {
+	services, auditor, _ := newAuthzFixture(t)
+	admin := auth.Principal{Subject: "pat", Kind: auth.PrincipalUser, Roles: []string{RolePlatformAdmin}}
+
+	// Política sin regla para el comando.
+	authz := NewAuthorizer(services, AuthorizerOptions{Policy: Policy{}, Auditor: auditor})
+	if err := authz.CreateTeam(as(admin), "team-c", "C", "pat"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden without rule, got %v", err)
+	}
+
+	// Contexto sin principal.
+	authz = NewAuthorizer(services, AuthorizerOptions{Auditor: auditor})
+	if err := authz.CreateTeam(context.Background(), "team-c", "C", "pat"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden without principal, got %v", err)
+	}
+
+	// Anónimo sólo pasa en modo dev.
+	if err := authz.CreateTeam(as(auth.Anonymous), "team-c", "C", "anonymous"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden for anonymous, got %v", err)
+	}
+	dev := NewAuthorizer(services, AuthorizerOptions{AllowAnonymous: true})
+	if err := dev.CreateTeam(as(auth.Anonymous), "team-c", "C", "anonymous"); err != nil {
+		t.Fatalf("expected anonymous to be allowed in dev mode, got %v", err)
+	}
+
+	if len(auditor.denials) != 3 {
+		t.Fatalf("expected 3 audited denials, got %d", len(auditor.denials))
+	}
+}
@@ -0,0 +1,551 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// MaxBatchSize acota la cantidad de comandos de un batch.
+const MaxBatchSize = 100
+
+// BatchMode define qué pasa con un batch cuando falla uno de sus comandos.
+type BatchMode string
+
+const (
+	// BatchBestEffort ejecuta todos los comandos; cada uno se aplica o falla
+	// por su cuenta.
+	BatchBestEffort BatchMode = "bestEffort"
+	// BatchAtomic aplica todos los comandos o ninguno: el primer fallo
+	// descarta los anteriores y no ejecuta los siguientes.
+	BatchAtomic BatchMode = "atomic"
+)
+
+var (
+	ErrBatchRolledBack = perrors.Conflict("batch_rolled_back", "command succeeded but was rolled back because a later command in the batch failed", nil)
+	ErrBatchAborted    = perrors.Conflict("batch_aborted", "command was not executed because an earlier command in the batch failed", nil)
+)
+
+// BatchStep es un comando de un batch. Debe ejecutarse sobre la API que
+// recibe: en modo atómico es una vista de los repositorios cuyos cambios
+// sólo se aplican si todo el batch termina bien.
+type BatchStep func(ctx context.Context, api API) error
+
+// RunBatch ejecuta steps en orden y devuelve un error por step (nil si se
+// aplicó). El error final sólo se devuelve si el batch en sí es inválido o
+// si no se pudieron aplicar los cambios de un batch atómico.
+func (s *Services) RunBatch(ctx context.Context, mode BatchMode, steps []BatchStep) ([]error, error) {
+	return s.runBatch(ctx, mode, steps, func(staged *Services) API { return staged })
+}
+
+// runBatch implementa RunBatch; wrap construye la API que ven los steps a
+// partir de los Services (reales o en staging), para que el Authorizer
+// pueda interponerse en cada comando.
+func (s *Services) runBatch(ctx context.Context, mode BatchMode, steps []BatchStep, wrap func(*Services) API) ([]error, error) {
+	if err := validateBatch(mode, steps); err != nil {
+		return nil, err
+	}
+
+	results := make([]error, len(steps))
+	switch mode {
+	case BatchBestEffort:
+		api := wrap(s)
+		for i, step := range steps {
+			results[i] = step(ctx, api)
+		}
+		return results, nil
+
+	case BatchAtomic:
+		// Los batches atómicos se serializan entre sí para que ningún otro
+		// batch escriba entre la lectura en staging y el commit. Los
+		// comandos sueltos sí pueden escribir en el medio: el commit lo
+		// detecta y el batch responde concurrent_modification.
+		s.batchMu.Lock()
+		defer s.batchMu.Unlock()
+
+		tx := &batchTx{}
+		staged := s.staged(tx)
+		defer staged.batchQuota.release()
+		api := wrap(staged)
+		for i, step := range steps {
+			if err := step(ctx, api); err != nil {
+				rollBack(results, i, err)
+				return results, nil
+			}
+		}
+		if err := tx.commit(ctx, &s.writeMu, s.Changes); err != nil {
+			if perrors.Code(err) == perrors.CodeConcurrentModification {
+				return nil, err
+			}
+			return nil, perrors.Internal("batch_commit_failed", "error applying batch", err)
+		}
+	}
+	return results, nil
+}
+
+// rollBack registra que el step i de un batch atómico falló con err: los
+// anteriores se descartan y los siguientes no se ejecutan.
+func rollBack(results []error, i int, err error) {
+	for j := 0; j < i; j++ {
+		results[j] = ErrBatchRolledBack
+	}
+	results[i] = err
+	for j := i + 1; j < len(results); j++ {
+		results[j] = ErrBatchAborted
+	}
+}
+
+func validateBatch(mode BatchMode, steps []BatchStep) error {
+	if len(steps) == 0 {
+		return perrors.Validation("empty_batch", "batch must contain at least one command", nil)
+	}
+	if len(steps) > MaxBatchSize {
+		return perrors.Validation("batch_too_large", fmt.Sprintf("batch must contain at most %d commands", MaxBatchSize), nil)
+	}
+	if mode != BatchAtomic && mode != BatchBestEffort {
+		return perrors.Validation("invalid_batch_mode", fmt.Sprintf("batch mode must be %q or %q", BatchAtomic, BatchBestEffort), nil)
+	}
+	return nil
+}
+
+// batchTx acumula las escrituras, las señales a workflows y los eventos de
+// un batch atómico.
+//
+// Los repositorios no exponen transacciones, así que commit aplica todo o
+// nada de otra forma. Toma writeMu en exclusiva, con lo que ninguna
+// escritura suelta puede intercalarse, y antes de escribir nada comprueba
+// que cada recurso siga en la versión que leyó el batch (checks). Después
+// envía las señales y aplica las escrituras en el orden en que se hicieron
+// (las entidades padre antes que las hijas). Si el storage falla a mitad,
+// deshace las escrituras ya aplicadas (undos, en orden inverso). Los
+// eventos se publican recién al final.
+type batchTx struct {
+	checks  []func(ctx context.Context) error
+	signals []func(ctx context.Context) error
+	writes  []func(ctx context.Context) error
+	undos   []func(ctx context.Context) error
+	events  []domain.ChangeEvent
+	// saved son copias de lo guardado, en orden; las usa DryRun.
+	saved []any
+}
+
+func (tx *batchTx) commit(ctx context.Context, mu *sync.RWMutex, feed ChangeFeed) error {
+	if err := tx.apply(ctx, mu); err != nil {
+		return err
+	}
+	if feed != nil {
+		for _, ev := range tx.events {
+			feed.Publish(ctx, ev)
+		}
+	}
+	return nil
+}
+
+func (tx *batchTx) apply(ctx context.Context, mu *sync.RWMutex) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, check := range tx.checks {
+		if err := check(ctx); err != nil {
+			return err
+		}
+	}
+	// Como en los comandos sueltos, la señal va antes que la escritura: si
+	// el workflow no la puede recibir, no se guarda nada.
+	for _, signal := range tx.signals {
+		if err := signal(ctx); err != nil {
+			return err
+		}
+	}
+	for _, write := range tx.writes {
+		if err := write(ctx); err != nil {
+			if undoErr := tx.undo(ctx); undoErr != nil {
+				return errors.Join(err, fmt.Errorf("undoing batch writes: %w", undoErr))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// undo deshace las escrituras aplicadas, de la última a la primera.
+func (tx *batchTx) undo(ctx context.Context) error {
+	var errs []error
+	for i := len(tx.undos) - 1; i >= 0; i-- {
+		if err := tx.undos[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// save guarda item con saveFn, uno de los Save de los repositorios de s.
+// Las escrituras sueltas toman writeMu en lectura para que el commit de un
+// batch atómico, que lo toma en exclusiva, no las vea a mitad.
+func save[T any](ctx context.Context, s *Services, saveFn func(context.Context, *T) error, item *T) error {
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return saveFn(ctx, item)
+}
+
+// staged devuelve unos Services que leen de los repositorios de s pero
+// guardan las escrituras y los eventos en tx.
+func (s *Services) staged(tx *batchTx) *Services {
+	out := &Services{DefaultQuota: s.DefaultQuota, batchQuota: &batchQuotaLocks{locks: &s.quotaLocks}}
+	if s.Organizations != nil {
+		out.Organizations = stage(tx, s.Organizations, func(o *domain.Organization) string { return o.ID }, func(o *domain.Organization) *domain.Metadata { return &o.Metadata })
+	}
+	if s.Teams != nil {
+		out.Teams = stage(tx, s.Teams, func(t *domain.Team) string { return t.ID }, func(t *domain.Team) *domain.Metadata { return &t.Metadata })
+	}
+	if s.Applications != nil {
+		out.Applications = &stagedApplications{
+			stagedRepo: stage(tx, s.Applications, func(a *domain.Application) string { return a.ID }, func(a *domain.Application) *domain.Metadata { return &a.Metadata }),
+			base:       s.Applications,
+		}
+	}
+	if s.CodeRepositories != nil {
+		out.CodeRepositories = stage(tx, s.CodeRepositories, func(r *domain.CodeRepository) string { return r.ID }, func(r *domain.CodeRepository) *domain.Metadata { return &r.Metadata })
+	}
+	if s.Environments != nil {
+		out.Environments = stage(tx, s.Environments, func(e *domain.Environment) string { return e.ID }, func(e *domain.Environment) *domain.Metadata { return &e.Metadata })
+	}
+	if s.ApplicationEnvironments != nil {
+		out.ApplicationEnvironments = &stagedApplicationEnvironments{
+			stagedRepo: stage(tx, s.ApplicationEnvironments, func(ae *domain.ApplicationEnvironment) string { return ae.ID }, func(ae *domain.ApplicationEnvironment) *domain.Metadata { return &ae.Metadata }),
+			base:       s.ApplicationEnvironments,
+		}
+	}
+	if s.Secrets != nil {
+		out.Secrets = &stagedSecrets{
+			stagedRepo: stage(tx, s.Secrets, func(sec *domain.Secret) string { return sec.ID }, func(sec *domain.Secret) *domain.Metadata { return &sec.Metadata }),
+			base:       s.Secrets,
+		}
+	}
+	if s.SecretBindings != nil {
+		out.SecretBindings = &stagedSecretBindings{
+			stagedRepo: stage(tx, s.SecretBindings, func(b *domain.SecretBinding) string { return b.ID }, func(b *domain.SecretBinding) *domain.Metadata { return &b.Metadata }),
+			base:       s.SecretBindings,
+		}
+	}
+	if s.DeploymentRepositories != nil {
+		out.DeploymentRepositories = stage(tx, s.DeploymentRepositories, func(r *domain.DeploymentRepository) string { return r.ID }, func(r *domain.DeploymentRepository) *domain.Metadata { return &r.Metadata })
+	}
+	if s.GitOpsIntegrations != nil {
+		out.GitOpsIntegrations = stage(tx, s.GitOpsIntegrations, func(gi *domain.GitOpsIntegration) string { return gi.ID }, func(gi *domain.GitOpsIntegration) *domain.Metadata { return &gi.Metadata })
+	}
+	if s.WebhookSubscriptions != nil {
+		out.WebhookSubscriptions = &stagedWebhookSubscriptions{
+			stagedRepo: stage(tx, s.WebhookSubscriptions, func(sub *domain.WebhookSubscription) string { return sub.ID }, func(sub *domain.WebhookSubscription) *domain.Metadata { return &sub.Metadata }),
+			base:       s.WebhookSubscriptions,
+		}
+	}
+	if s.WebhookDeliveries != nil {
+		// Las entregas no tienen versión: el commit no las compara.
+		out.WebhookDeliveries = &stagedWebhookDeliveries{
+			stagedRepo: stage(tx, s.WebhookDeliveries, func(d *domain.WebhookDelivery) string { return d.ID }, nil),
+			base:       s.WebhookDeliveries,
+		}
+	}
+	if s.Approvals != nil {
+		out.Approvals = &stagedApprovals{
+			stagedRepo: stage(tx, s.Approvals, func(a *domain.Approval) string { return a.ID }, func(a *domain.Approval) *domain.Metadata { return &a.Metadata }),
+			base:       s.Approvals,
+		}
+	}
+	if s.Signals != nil {
+		out.Signals = stagedSignaler{tx: tx, base: s.Signals}
+	}
+	if s.Changes != nil {
+		out.Changes = stagedChangeFeed{tx: tx}
+	}
+	return out
+}
+
+// repository es lo que stage usa de un repositorio base.
+type repository[T any] interface {
+	GetByID(ctx context.Context, id string) (*T, error)
+	Save(ctx context.Context, item *T) error
+}
+
+// deleter lo implementan los repositorios que pueden borrar un recurso; el
+// commit de un batch atómico lo usa para deshacer una creación.
+type deleter interface {
+	Delete(ctx context.Context, id string) error
+}
+
+// stagedRepo es un repositorio en staging: las lecturas ven primero lo
+// escrito en el batch y después el repositorio base.
+type stagedRepo[T any] struct {
+	tx   *batchTx
+	repo repository[T]
+	idOf func(item *T) string
+	// metaOf devuelve la metadata de un item; nil si el recurso no tiene
+	// versión.
+	metaOf  func(item *T) *domain.Metadata
+	items   map[string]*T
+	order   []string
+	tracked map[string]*stagedCommit[T]
+}
+
+// stagedCommit sigue un recurso del batch durante el commit: base es lo que
+// estaba guardado al validarlo y applied, la última versión escrita.
+type stagedCommit[T any] struct {
+	base, applied *T
+}
+
+func stage[T any](tx *batchTx, repo repository[T], idOf func(*T) string, metaOf func(*T) *domain.Metadata) *stagedRepo[T] {
+	return &stagedRepo[T]{tx: tx, repo: repo, idOf: idOf, metaOf: metaOf, items: make(map[string]*T), tracked: make(map[string]*stagedCommit[T])}
+}
+
+func (r *stagedRepo[T]) GetByID(ctx context.Context, id string) (*T, error) {
+	if item, ok := r.items[id]; ok {
+		copy := *item
+		return &copy, nil
+	}
+	return r.repo.GetByID(ctx, id)
+}
+
+func (r *stagedRepo[T]) Save(_ context.Context, item *T) error {
+	id := r.idOf(item)
+	c, ok := r.tracked[id]
+	if !ok {
+		c = &stagedCommit[T]{}
+		r.tracked[id] = c
+		r.order = append(r.order, id)
+		// Cada Save avanza una versión, así que el batch leyó la anterior
+		// a la primera que guarda (0 si lo crea).
+		read := r.version(item) - 1
+		r.tx.checks = append(r.tx.checks, func(ctx context.Context) error { return r.check(ctx, id, read, c) })
+		r.tx.undos = append(r.tx.undos, func(ctx context.Context) error { return r.undo(ctx, id, c) })
+	}
+	copy := *item
+	r.items[id] = &copy
+	// Cada Save del batch se aplica como una escritura propia: los
+	// repositorios sólo aceptan la versión siguiente a la guardada, así que
+	// dos cambios a un recurso se escriben en orden, uno por versión.
+	snapshot := *item
+	r.tx.writes = append(r.tx.writes, func(ctx context.Context) error {
+		if err := r.repo.Save(ctx, &snapshot); err != nil {
+			return err
+		}
+		c.applied = &snapshot
+		return nil
+	})
+	r.tx.saved = append(r.tx.saved, &snapshot)
+	return nil
+}
+
+func (r *stagedRepo[T]) version(item *T) int64 {
+	if item == nil || r.metaOf == nil {
+		return 0
+	}
+	return r.metaOf(item).Version
+}
+
+// check comprueba que id siga guardado en la versión read y recuerda lo
+// guardado para poder deshacer.
+func (r *stagedRepo[T]) check(ctx context.Context, id string, read int64, c *stagedCommit[T]) error {
+	cur, err := r.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if r.metaOf != nil && r.version(cur) != read {
+		return domain.ErrVersionConflict
+	}
+	c.base = cur
+	return nil
+}
+
+// undo devuelve id a lo que estaba guardado antes del commit: lo borra si el
+// batch lo creó o guarda su contenido anterior como una versión nueva.
+func (r *stagedRepo[T]) undo(ctx context.Context, id string, c *stagedCommit[T]) error {
+	switch {
+	case c.applied == nil:
+		return nil
+	case c.base == nil:
+		d, ok := r.repo.(deleter)
+		if !ok {
+			return fmt.Errorf("repository cannot delete %q", id)
+		}
+		return d.Delete(ctx, id)
+	}
+	restore := *c.base
+	if r.metaOf != nil {
+		r.metaOf(&restore).Version = r.metaOf(c.applied).Version + 1
+	}
+	return r.repo.Save(ctx, &restore)
+}
+
+// merge superpone lo escrito en el batch sobre un listado del repositorio
+// base; keep filtra los items sólo escritos en el batch.
+func (r *stagedRepo[T]) merge(base []*T, keep func(*T) bool) []*T {
+	out := make([]*T, 0, len(base))
+	seen := make(map[string]bool, len(base))
+	for _, item := range base {
+		id := r.idOf(item)
+		seen[id] = true
+		if staged, ok := r.items[id]; ok {
+			copy := *staged
+			item = &copy
+		}
+		out = append(out, item)
+	}
+	for _, id := range r.order {
+		if !seen[id] && keep(r.items[id]) {
+			copy := *r.items[id]
+			out = append(out, &copy)
+		}
+	}
+	return out
+}
+
+type stagedApplications struct {
+	*stagedRepo[domain.Application]
+	base ApplicationRepository
+}
+
+func (r *stagedApplications) ListByTeam(ctx context.Context, teamID string) ([]*domain.Application, error) {
+	base, err := r.base.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(a *domain.Application) bool { return a.TeamID == teamID }), nil
+}
+
+type stagedApplicationEnvironments struct {
+	*stagedRepo[domain.ApplicationEnvironment]
+	base ApplicationEnvironmentRepository
+}
+
+func (r *stagedApplicationEnvironments) GetByApplicationAndEnvironment(ctx context.Context, applicationID, environmentID string) (*domain.ApplicationEnvironment, error) {
+	for _, id := range r.order {
+		if ae := r.items[id]; ae.ApplicationID == applicationID && ae.EnvironmentID == environmentID {
+			copy := *ae
+			return &copy, nil
+		}
+	}
+	ae, err := r.base.GetByApplicationAndEnvironment(ctx, applicationID, environmentID)
+	if err != nil || ae == nil {
+		return ae, err
+	}
+	return r.GetByID(ctx, ae.ID)
+}
+
+func (r *stagedApplicationEnvironments) ListByApplication(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error) {
+	base, err := r.base.ListByApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(ae *domain.ApplicationEnvironment) bool { return ae.ApplicationID == applicationID }), nil
+}
+
+type stagedSecrets struct {
+	*stagedRepo[domain.Secret]
+	base SecretRepository
+}
+
+func (r *stagedSecrets) ListByOwnerTeam(ctx context.Context, teamID string) ([]*domain.Secret, error) {
+	base, err := r.base.ListByOwnerTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(sec *domain.Secret) bool { return sec.OwnerTeam == teamID }), nil
+}
+
+type stagedSecretBindings struct {
+	*stagedRepo[domain.SecretBinding]
+	base SecretBindingRepository
+}
+
+func (r *stagedSecretBindings) ListBySecret(ctx context.Context, secretID string) ([]*domain.SecretBinding, error) {
+	base, err := r.base.ListBySecret(ctx, secretID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(b *domain.SecretBinding) bool { return b.SecretID == secretID }), nil
+}
+
+func (r *stagedSecretBindings) ListByTarget(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error) {
+	base, err := r.base.ListByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(b *domain.SecretBinding) bool { return b.TargetType == targetType && b.TargetID == targetID }), nil
+}
+
+type stagedWebhookSubscriptions struct {
+	*stagedRepo[domain.WebhookSubscription]
+	base WebhookSubscriptionRepository
+}
+
+func (r *stagedWebhookSubscriptions) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	base, err := r.base.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(*domain.WebhookSubscription) bool { return true }), nil
+}
+
+type stagedWebhookDeliveries struct {
+	*stagedRepo[domain.WebhookDelivery]
+	base WebhookDeliveryRepository
+}
+
+func (r *stagedWebhookDeliveries) ListBySubscription(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error) {
+	base, err := r.base.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(d *domain.WebhookDelivery) bool { return d.SubscriptionID == subscriptionID }), nil
+}
+
+// ListDue sólo lo usa el WebhookDispatcher, que nunca corre en staging.
+func (r *stagedWebhookDeliveries) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	return r.base.ListDue(ctx, now, limit)
+}
+
+type stagedApprovals struct {
+	*stagedRepo[domain.Approval]
+	base ApprovalRepository
+}
+
+func (r *stagedApprovals) List(ctx context.Context) ([]*domain.Approval, error) {
+	base, err := r.base.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.merge(base, func(*domain.Approval) bool { return true }), nil
+}
+
+// stagedSignaler retiene las señales del batch hasta el commit, para que un
+// workflow no reciba una decisión que después se descarta.
+type stagedSignaler struct {
+	tx   *batchTx
+	base WorkflowSignaler
+}
+
+func (s stagedSignaler) Signal(_ context.Context, workflowID, signalName string, payload any) error {
+	s.tx.signals = append(s.tx.signals, func(ctx context.Context) error {
+		return s.base.Signal(ctx, workflowID, signalName, payload)
+	})
+	return nil
+}
+
+// stagedChangeFeed retiene los eventos del batch hasta el commit.
+type stagedChangeFeed struct {
+	tx *batchTx
+}
+
+func (f stagedChangeFeed) Publish(_ context.Context, ev domain.ChangeEvent) {
+	f.tx.events = append(f.tx.events, ev)
+}
+
+func (f stagedChangeFeed) Subscribe(context.Context, string, int64, int) (<-chan domain.ChangeEvent, bool, error) {
+	return nil, false, perrors.Internal("change_feed_not_available", "change feed is not available inside a batch", nil)
+}
@@ -0,0 +1,261 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func bootstrapSteps(appTeam string) []BatchStep {
+	return []BatchStep{
+		func(ctx context.Context, api API) error { return api.CreateTeam(ctx, "team-1", "Platform", "alice") },
+		func(ctx context.Context, api API) error { return api.CreateEnvironment(ctx, "dev", "Dev", "alice") },
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-1", "App", appTeam, "alice")
+		},
+		func(ctx context.Context, api API) error {
+			return api.DeclareApplicationEnvironment(ctx, "ae-1", "app-1", "dev", "alice")
+		},
+	}
+}
+
+func TestRunBatch_AtomicCommitsAllAndPublishesAfterCommit(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, _ := services.WatchChanges(ctx, 0, 16)
+
+	results, err := services.RunBatch(ctx, BatchAtomic, bootstrapSteps("team-1"))
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	for i, r := range results {
+		if r != nil {
+			t.Fatalf("step %d failed: %v", i, r)
+		}
+	}
+
+	// Los steps ven lo creado por los anteriores dentro del mismo batch.
+	ae, err := services.GetApplicationEnvironment(ctx, "ae-1")
+	if err != nil || ae.ApplicationID != "app-1" {
+		t.Fatalf("expected committed application environment, got %+v (%v)", ae, err)
+	}
+	events := drain(sub.Events)
+	if len(events) != 4 || events[3].ResourceType != "ApplicationEnvironment" || events[3].TeamID != "team-1" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
+
+func TestRunBatch_AtomicRollsBackOnFailure(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, _ := services.WatchChanges(ctx, 0, 16)
+
+	results, err := services.RunBatch(ctx, BatchAtomic, bootstrapSteps("missing-team"))
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+
+	want := []string{"batch_rolled_back", "batch_rolled_back", "team_not_found", "batch_aborted"}
+	for i, code := range want {
+		if got := perrors.Code(results[i]); got != code {
+			t.Errorf("step %d: expected %s, got %v", i, code, results[i])
+		}
+	}
+	if team, _ := services.Teams.GetByID(ctx, "team-1"); team != nil {
+		t.Fatalf("expected team creation to be rolled back")
+	}
+	if events := drain(sub.Events); len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+}
+
+func TestRunBatch_AtomicChangesAnExistingResourceTwice(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	services.Approvals = memoryrepo.NewApprovalRepository()
+	ctx := context.Background()
+	if _, err := services.RunBatch(ctx, BatchBestEffort, bootstrapSteps("team-1")[:3]); err != nil {
+		t.Fatalf("bootstrap failed: %v", err)
+	}
+
+	results, err := services.RunBatch(ctx, BatchAtomic, []BatchStep{
+		func(ctx context.Context, api API) error { return api.ApproveApplication(ctx, "app-1", "bob") },
+		func(ctx context.Context, api API) error { return api.StartApplicationOnboarding(ctx, "app-1", "bob") },
+	})
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	for i, r := range results {
+		if r != nil {
+			t.Fatalf("step %d failed: %v", i, r)
+		}
+	}
+
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	if app.State != domain.ApplicationStateOnboarding || app.Metadata.Version != 3 {
+		t.Fatalf("expected onboarding application at version 3, got %+v", app)
+	}
+	approval, _ := services.Approvals.GetByID(ctx, ApplicationApprovalID("app-1"))
+	if approval == nil || approval.State != domain.ApprovalStateApproved {
+		t.Fatalf("expected resolved approval, got %+v", approval)
+	}
+}
+
+func TestRunBatch_AtomicFailsWithoutApplyingWhenASingleCommandWroteInBetween(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx := context.Background()
+	if _, err := services.RunBatch(ctx, BatchBestEffort, bootstrapSteps("team-1")[:3]); err != nil {
+		t.Fatalf("bootstrap failed: %v", err)
+	}
+
+	_, err := services.RunBatch(ctx, BatchAtomic, []BatchStep{
+		func(ctx context.Context, api API) error { return api.CreateTeam(ctx, "team-2", "Payments", "alice") },
+		func(ctx context.Context, api API) error { return api.ApproveApplication(ctx, "app-1", "alice") },
+		// Un comando suelto aprueba app-1 después de que el batch la leyó.
+		func(ctx context.Context, api API) error { return services.ApproveApplication(ctx, "app-1", "bob") },
+	})
+	if !perrors.IsKind(err, perrors.KindConflict) || perrors.Code(err) != perrors.CodeConcurrentModification {
+		t.Fatalf("expected concurrent_modification, got %v", err)
+	}
+	if team, _ := services.Teams.GetByID(ctx, "team-2"); team != nil {
+		t.Fatalf("expected team-2 not to be created")
+	}
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	if app.Metadata.Version != 2 || app.Metadata.History[0].By != "bob" {
+		t.Fatalf("expected only bob's approval, got %+v", app.Metadata)
+	}
+}
+
+// failingEnvironments falla al guardar, como un storage caído a mitad del
+// commit.
+type failingEnvironments struct {
+	*memoryrepo.EnvironmentRepository
+}
+
+func (failingEnvironments) Save(context.Context, *domain.Environment) error {
+	return errors.New("storage unavailable")
+}
+
+func TestRunBatch_AtomicUndoesAppliedWritesWhenStorageFails(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx := context.Background()
+	if _, err := services.RunBatch(ctx, BatchBestEffort, bootstrapSteps("team-1")[:3]); err != nil {
+		t.Fatalf("bootstrap failed: %v", err)
+	}
+	services.Environments = failingEnvironments{EnvironmentRepository: memoryrepo.NewEnvironmentRepository()}
+
+	_, err := services.RunBatch(ctx, BatchAtomic, []BatchStep{
+		func(ctx context.Context, api API) error { return api.CreateTeam(ctx, "team-2", "Payments", "alice") },
+		func(ctx context.Context, api API) error { return api.ApproveApplication(ctx, "app-1", "alice") },
+		func(ctx context.Context, api API) error { return api.CreateEnvironment(ctx, "prod", "Prod", "alice") },
+	})
+	if perrors.Code(err) != "batch_commit_failed" {
+		t.Fatalf("expected batch_commit_failed, got %v", err)
+	}
+	if team, _ := services.Teams.GetByID(ctx, "team-2"); team != nil {
+		t.Fatalf("expected team-2 creation to be undone")
+	}
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	if app.State != domain.ApplicationStateProposed || len(app.Metadata.History) != 0 {
+		t.Fatalf("expected app-1 approval to be undone, got %s %+v", app.State, app.Metadata)
+	}
+
+	// Deshacer guarda una versión nueva, así que app-1 sigue aceptando
+	// escrituras.
+	if err := services.ApproveApplication(ctx, "app-1", "bob"); err != nil {
+		t.Fatalf("ApproveApplication after undo failed: %v", err)
+	}
+}
+
+func TestRunBatch_BestEffortAppliesIndependently(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx := context.Background()
+
+	results, err := services.RunBatch(ctx, BatchBestEffort, bootstrapSteps("missing-team"))
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	if results[0] != nil || results[1] != nil || perrors.Code(results[2]) != "team_not_found" {
+		t.Fatalf("unexpected results %v", results)
+	}
+	if results[3] == nil {
+		t.Fatalf("expected declare to fail without application")
+	}
+	if team, _ := services.Teams.GetByID(ctx, "team-1"); team == nil {
+		t.Fatalf("expected team to be created")
+	}
+}
+
+func TestRunBatch_RejectsInvalidBatches(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx := context.Background()
+
+	if _, err := services.RunBatch(ctx, BatchAtomic, nil); perrors.Code(err) != "empty_batch" {
+		t.Fatalf("expected empty_batch, got %v", err)
+	}
+	if _, err := services.RunBatch(ctx, "sometimes", bootstrapSteps("team-1")); perrors.Code(err) != "invalid_batch_mode" {
+		t.Fatalf("expected invalid_batch_mode, got %v", err)
+	}
+	steps := make([]BatchStep, MaxBatchSize+1)
+	if _, err := services.RunBatch(ctx, BatchBestEffort, steps); perrors.Code(err) != "batch_too_large" {
+		t.Fatalf("expected batch_too_large, got %v", err)
+	}
+}
+
+func TestAuthorizer_RunBatchAuthorizesEachStep(t *testing.T) {
+	_, auditor, authz := newAuthzFixture(t)
+	teamAMember := auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"team-a"}}
+
+	steps := []BatchStep{
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-2", "App 2", "team-a", "ana")
+		},
+		// CreateTeam exige platformAdmin.
+		func(ctx context.Context, api API) error { return api.CreateTeam(ctx, "team-c", "C", "ana") },
+	}
+	results, err := authz.RunBatch(as(teamAMember), BatchAtomic, steps)
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	if !errors.Is(results[0], ErrBatchRolledBack) || !perrors.IsKind(results[1], perrors.KindForbidden) {
+		t.Fatalf("unexpected results %v", results)
+	}
+	if len(auditor.denials) != 1 || auditor.denials[0].Command != "CreateTeam" {
+		t.Fatalf("expected CreateTeam denial, got %+v", auditor.denials)
+	}
+
+	// Sin principal ni siquiera el batch está permitido.
+	if _, err := authz.RunBatch(context.Background(), BatchBestEffort, steps); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
+
+func TestAuthorizer_RunBatchResolvesTeamsCreatedInTheBatch(t *testing.T) {
+	services, auditor, authz := newAuthzFixture(t)
+	teamAMember := auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"team-a"}}
+
+	steps := []BatchStep{
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-2", "App 2", "team-a", "ana")
+		},
+		func(ctx context.Context, api API) error { return api.DeprecateApplication(ctx, "app-2", "ana") },
+	}
+	results, err := authz.RunBatch(as(teamAMember), BatchAtomic, steps)
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	// app-2 sólo existe en el batch, pero su team se resuelve igual: la
+	// autorización pasa y falla la transición (Proposed -> Deprecated).
+	if len(auditor.denials) != 0 || !perrors.IsKind(results[1], perrors.KindDomain) {
+		t.Fatalf("expected domain error without denials, got %v (%+v)", results[1], auditor.denials)
+	}
+	if app, _ := services.Applications.GetByID(context.Background(), "app-2"); app != nil {
+		t.Fatalf("expected app-2 to be rolled back, got %+v", app)
+	}
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// ChangeFeed es el log de cambios de recursos del control plane. Publish
+// asigna el ID del evento; Subscribe reproduce los eventos posteriores a
+// afterID y luego entrega los nuevos (ver ChangeSubscription). gap indica
+// que afterID ya no está retenido. Los IDs son únicos en toda la
+// instalación; organizationID vacío no filtra por organización.
+type ChangeFeed interface {
+	Publish(ctx context.Context, ev domain.ChangeEvent)
+	Subscribe(ctx context.Context, organizationID string, afterID int64, buffer int) (events <-chan domain.ChangeEvent, gap bool, err error)
+}
+
+// ChangeSubscription es una suscripción al ChangeFeed. Events se cierra
+// cuando ctx termina o cuando el suscriptor no consume a tiempo y su buffer
+// se llena; en ese caso debe re-suscribirse desde el último ID recibido.
+type ChangeSubscription struct {
+	Events <-chan domain.ChangeEvent
+	// Gap indica que afterID ya no está en el feed: se perdieron eventos y
+	// el cliente debe releer el estado con las queries.
+	Gap bool
+}
+
+// WatchChanges suscribe al change feed a partir de afterID (0 = sólo
+// eventos nuevos). Sólo entrega los eventos de la organización de ctx.
+func (s *Services) WatchChanges(ctx context.Context, afterID int64, buffer int) (ChangeSubscription, error) {
+	return s.subscribeChanges(ctx, domain.OrganizationFromContext(ctx), afterID, buffer)
+}
+
+// subscribeChanges suscribe al feed de organizationID; "" recibe los de
+// todas, como necesita el WebhookDispatcher.
+func (s *Services) subscribeChanges(ctx context.Context, organizationID string, afterID int64, buffer int) (ChangeSubscription, error) {
+	if s.Changes == nil {
+		return ChangeSubscription{}, perrors.Internal("change_feed_not_configured", "change feed not configured", nil)
+	}
+	events, gap, err := s.Changes.Subscribe(ctx, organizationID, afterID, buffer)
+	if err != nil {
+		return ChangeSubscription{}, fmt.Errorf("subscribing to change feed: %w", err)
+	}
+	return ChangeSubscription{Events: events, Gap: gap}, nil
+}
+
+// recordChange publica en el change feed la creación o transición de
+// resource. Es best-effort: el feed no forma parte de la transacción.
+func (s *Services) recordChange(ctx context.Context, action domain.ChangeAction, resource any, by string) {
+	if s.Changes == nil {
+		return
+	}
+
+	ev := domain.ChangeEvent{OrganizationID: domain.OrganizationFromContext(ctx), Action: action, By: by, At: time.Now().UTC()}
+	switch r := resource.(type) {
+	case *domain.Organization:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Organization", r.ID, string(r.State), r.Metadata.Version
+		ev.OrganizationID = r.ID
+	case *domain.Team:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Team", r.ID, string(r.State), r.Metadata.Version
+		ev.TeamID = r.ID
+	case *domain.Application:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Application", r.ID, string(r.State), r.Metadata.Version
+		ev.TeamID, ev.ApplicationID = r.TeamID, r.ID
+	case *domain.CodeRepository:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "CodeRepository", r.ID, string(r.State), r.Metadata.Version
+		ev.ApplicationID, ev.TeamID = r.ApplicationID, s.applicationTeam(ctx, r.ApplicationID)
+	case *domain.DeploymentRepository:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "DeploymentRepository", r.ID, string(r.State), r.Metadata.Version
+		ev.ApplicationID, ev.TeamID = r.ApplicationID, s.applicationTeam(ctx, r.ApplicationID)
+	case *domain.GitOpsIntegration:
+		ev.ResourceType, ev.ResourceID, ev.Version = "GitOpsIntegration", r.ID, r.Metadata.Version
+		ev.ApplicationID, ev.TeamID = r.ApplicationID, s.applicationTeam(ctx, r.ApplicationID)
+	case *domain.Environment:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Environment", r.ID, string(r.State), r.Metadata.Version
+	case *domain.ApplicationEnvironment:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "ApplicationEnvironment", r.ID, string(r.State), r.Metadata.Version
+		ev.ApplicationID, ev.TeamID = r.ApplicationID, s.applicationTeam(ctx, r.ApplicationID)
+	case *domain.Secret:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Secret", r.ID, string(r.State), r.Metadata.Version
+		ev.TeamID = r.OwnerTeam
+	case *domain.SecretBinding:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "SecretBinding", r.ID, string(r.State), r.Metadata.Version
+		if s.Secrets != nil {
+			if sec, _ := s.Secrets.GetByID(ctx, r.SecretID); sec != nil {
+				ev.TeamID = sec.OwnerTeam
+			}
+		}
+	case *domain.Approval:
+		ev.ResourceType, ev.ResourceID, ev.State, ev.Version = "Approval", r.ID, string(r.State), r.Metadata.Version
+		ev.TeamID = r.TeamID
+		if r.ResourceType == "Application" {
+			ev.ApplicationID = r.ResourceID
+		}
+	default:
+		return
+	}
+
+	s.Changes.Publish(ctx, ev)
+}
+
+func (s *Services) applicationTeam(ctx context.Context, applicationID string) string {
+	if s.Applications == nil {
+		return ""
+	}
+	app, _ := s.Applications.GetByID(ctx, applicationID)
+	if app == nil {
+		return ""
+	}
+	return app.TeamID
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+type expectedVersionCtxKey struct{}
+
+// WithExpectedVersion devuelve una copia de ctx que condiciona el próximo
+// comando a que el recurso siga en la versión v (If-Match). Si la versión
+// cambió, el comando falla con KindPreconditionFailed sin modificar nada.
+func WithExpectedVersion(ctx context.Context, v int64) context.Context {
+	return context.WithValue(ctx, expectedVersionCtxKey{}, v)
+}
+
+// checkExpectedVersion valida la versión esperada de ctx, si la hay, contra
+// la metadata del recurso recién cargado.
+func checkExpectedVersion(ctx context.Context, m domain.Metadata) error {
+	want, ok := ctx.Value(expectedVersionCtxKey{}).(int64)
+	if !ok || want == m.Version {
+		return nil
+	}
+	return perrors.PreconditionFailed("version_mismatch",
+		fmt.Sprintf("resource was modified: expected version %d, current version is %d", want, m.Version), nil)
+}
@@ -0,0 +1,219 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
+)
+
+// Comandos del workflow ApplicationDecommissioning. Cada uno es una
+// transición del estado deseado: la Application pasa a Decommissioning,
+// sus ApplicationEnvironments también, se revocan sus SecretBindings y
+// recién entonces la Application se archiva.
+
+// StartApplicationDecommissioning mueve una Application de Deprecated a
+// Decommissioning, una vez aprobado el decommissioning.
+func (s *Services) StartApplicationDecommissioning(ctx context.Context, id, startedBy string) error {
+	if s.Applications == nil {
+		return perrors.Internal("application_repository_not_configured", "application repository not configured", nil)
+	}
+
+	app, err := s.Applications.GetByID(ctx, id)
+	if err != nil || app == nil {
+		return perrors.NotFound("application_not_found", "application not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
+	if app.State != domain.ApplicationStateDeprecated {
+		return perrors.Domain("application_invalid_state_for_decommissioning", "application can only start decommissioning from Deprecated state", nil)
+	}
+
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateDecommissioning), startedBy, time.Now().UTC())
+	app.State = domain.ApplicationStateDecommissioning
+
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
+		return fmt.Errorf("starting application decommissioning: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, startedBy)
+
+	return nil
+}
+
+// DecommissionApplicationEnvironment mueve un ApplicationEnvironment a
+// Decommissioning. Sólo se decomisionan los environments de una
+// Application que ya está en Decommissioning.
+func (s *Services) DecommissionApplicationEnvironment(ctx context.Context, id, decommissionedBy string) error {
+	if s.ApplicationEnvironments == nil || s.Applications == nil {
+		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
+	}
+
+	appEnv, err := s.ApplicationEnvironments.GetByID(ctx, id)
+	if err != nil || appEnv == nil {
+		return ErrApplicationEnvironmentNotFound
+	}
+
+	if err := checkExpectedVersion(ctx, appEnv.Metadata); err != nil {
+		return err
+	}
+
+	app, err := s.Applications.GetByID(ctx, appEnv.ApplicationID)
+	if err != nil || app == nil {
+		return perrors.NotFound("application_not_found", "application not found", err)
+	}
+	if app.State != domain.ApplicationStateDecommissioning {
+		return perrors.Domain("application_not_decommissioning", "application environments can only be decommissioned while their application is Decommissioning", nil)
+	}
+
+	if appEnv.State == domain.ApplicationEnvironmentStateDecommissioning || appEnv.State == domain.ApplicationEnvironmentStateRetired {
+		return perrors.Domain("application_environment_invalid_state_for_decommissioning", "application environment is already "+string(appEnv.State), nil)
+	}
+
+	appEnv.Metadata.RecordTransition(string(appEnv.State), string(domain.ApplicationEnvironmentStateDecommissioning), decommissionedBy, time.Now().UTC())
+	appEnv.State = domain.ApplicationEnvironmentStateDecommissioning
+
+	if err := save(ctx, s, s.ApplicationEnvironments.Save, appEnv); err != nil {
+		return fmt.Errorf("decommissioning application environment: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionTransitioned, appEnv, decommissionedBy)
+
+	return nil
+}
+
+// RevokeSecretBinding mueve un SecretBinding a Revoked. Es terminal: un
+// binding revocado no se reactiva.
+func (s *Services) RevokeSecretBinding(ctx context.Context, id, revokedBy string) error {
+	if s.SecretBindings == nil {
+		return perrors.Internal("secret_binding_repository_not_configured", "secret binding repository not configured", nil)
+	}
+
+	binding, err := s.SecretBindings.GetByID(ctx, id)
+	if err != nil || binding == nil {
+		return perrors.NotFound("secret_binding_not_found", "secret binding not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, binding.Metadata); err != nil {
+		return err
+	}
+
+	if binding.State == domain.SecretBindingStateRevoked {
+		return perrors.Domain("secret_binding_already_revoked", "secret binding is already revoked", nil)
+	}
+
+	binding.Metadata.RecordTransition(string(binding.State), string(domain.SecretBindingStateRevoked), revokedBy, time.Now().UTC())
+	binding.State = domain.SecretBindingStateRevoked
+
+	if err := save(ctx, s, s.SecretBindings.Save, binding); err != nil {
+		return fmt.Errorf("revoking secret binding: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionTransitioned, binding, revokedBy)
+
+	return nil
+}
+
+// ArchiveApplication mueve una Application de Decommissioning a Archived.
+// Invariantes: todos sus ApplicationEnvironments están en Decommissioning
+// o Retired, y ninguno conserva SecretBindings sin revocar.
+func (s *Services) ArchiveApplication(ctx context.Context, id, archivedBy string) error {
+	if s.Applications == nil || s.ApplicationEnvironments == nil || s.SecretBindings == nil {
+		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
+	}
+
+	app, err := s.Applications.GetByID(ctx, id)
+	if err != nil || app == nil {
+		return perrors.NotFound("application_not_found", "application not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
+	if app.State != domain.ApplicationStateDecommissioning {
+		return perrors.Domain("application_invalid_state_for_archival", "application can only be archived from Decommissioning state", nil)
+	}
+
+	if err := s.checkDecommissioned(ctx, app.ID); err != nil {
+		return err
+	}
+
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateArchived), archivedBy, time.Now().UTC())
+	app.State = domain.ApplicationStateArchived
+
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
+		return fmt.Errorf("archiving application: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, archivedBy)
+
+	return nil
+}
+
+// checkDecommissioned valida las invariantes de ArchiveApplication sobre
+// los ApplicationEnvironments de applicationID y sus SecretBindings.
+func (s *Services) checkDecommissioned(ctx context.Context, applicationID string) error {
+	appEnvs, err := s.ApplicationEnvironments.ListByApplication(ctx, applicationID)
+	if err != nil {
+		return perrors.Internal("application_environment_repository_error", "error listing application environments", err)
+	}
+	for _, appEnv := range appEnvs {
+		if appEnv.State != domain.ApplicationEnvironmentStateDecommissioning && appEnv.State != domain.ApplicationEnvironmentStateRetired {
+			return perrors.Domain("application_environments_not_decommissioned", "application environment "+appEnv.ID+" is still "+string(appEnv.State), nil)
+		}
+		bindings, err := s.SecretBindings.ListByTarget(ctx, domain.SecretBindingTargetApplicationEnvironment, appEnv.ID)
+		if err != nil {
+			return perrors.Internal("secret_binding_repository_error", "error listing secret bindings", err)
+		}
+		for _, b := range bindings {
+			if b.State != domain.SecretBindingStateRevoked {
+				return perrors.Domain("secret_bindings_not_revoked", "secret binding "+b.ID+" of application environment "+appEnv.ID+" is not revoked", nil)
+			}
+		}
+	}
+	return nil
+}
+
+// ListApplicationEnvironments devuelve los ApplicationEnvironments de una
+// Application, ordenados por ID.
+func (s *Services) ListApplicationEnvironments(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error) {
+	if s.ApplicationEnvironments == nil || s.Applications == nil {
+		return nil, perrors.Internal("repositories_not_configured", "repositories not configured", nil)
+	}
+
+	app, err := s.Applications.GetByID(ctx, applicationID)
+	if err != nil || app == nil {
+		return nil, perrors.NotFound("application_not_found", "application not found", err)
+	}
+
+	appEnvs, err := s.ApplicationEnvironments.ListByApplication(ctx, app.ID)
+	if err != nil {
+		return nil, perrors.Internal("application_environment_repository_error", "error listing application environments", err)
+	}
+	return appEnvs, nil
+}
+
+// ListSecretBindings devuelve los SecretBindings de un recurso objetivo,
+// ordenados por ID.
+func (s *Services) ListSecretBindings(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error) {
+	if s.SecretBindings == nil {
+		return nil, perrors.Internal("secret_binding_repository_not_configured", "secret binding repository not configured", nil)
+	}
+
+	if err := validation.New().ID("targetId", targetID).MaxLength("targetType", targetType, validation.MaxIDLength).Err(); err != nil {
+		return nil, err
+	}
+
+	bindings, err := s.SecretBindings.ListByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, perrors.Internal("secret_binding_repository_error", "error listing secret bindings", err)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// newDecommissioningFixture deja app-1 en Deprecated con dos
+// ApplicationEnvironments Active y un SecretBinding Active sobre appenv-1.
+func newDecommissioningFixture(t *testing.T) *Services {
+	t.Helper()
+	services := &Services{
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		Secrets:                 memoryrepo.NewSecretRepository(),
+		SecretBindings:          memoryrepo.NewSecretBindingRepository(),
+	}
+
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	app.State = domain.ApplicationStateDeprecated
+	app.Metadata.Version++
+	if err := services.Applications.Save(ctx, app); err != nil {
+		t.Fatalf("saving app failed: %v", err)
+	}
+
+	for _, id := range []string{"appenv-1", "appenv-2"} {
+		appEnv := &domain.ApplicationEnvironment{ID: id, ApplicationID: "app-1", EnvironmentID: "env-" + id, State: domain.ApplicationEnvironmentStateActive}
+		if err := services.ApplicationEnvironments.Save(ctx, appEnv); err != nil {
+			t.Fatalf("saving %s failed: %v", id, err)
+		}
+	}
+
+	if err := services.CreateSecret(ctx, "sec-1", "team-1", "runtime", "high", "test"); err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+	binding := &domain.SecretBinding{ID: "bind-1", SecretID: "sec-1", TargetID: "appenv-1", TargetType: domain.SecretBindingTargetApplicationEnvironment, State: domain.SecretBindingStateActive}
+	if err := services.SecretBindings.Save(ctx, binding); err != nil {
+		t.Fatalf("saving binding failed: %v", err)
+	}
+	return services
+}
+
+func TestApplicationDecommissioning_ArchivesOnceEverythingIsDecommissioned(t *testing.T) {
+	services := newDecommissioningFixture(t)
+	ctx := context.Background()
+
+	if err := services.StartApplicationDecommissioning(ctx, "app-1", "workflow"); err != nil {
+		t.Fatalf("StartApplicationDecommissioning failed: %v", err)
+	}
+
+	appEnvs, err := services.ListApplicationEnvironments(ctx, "app-1")
+	if err != nil || len(appEnvs) != 2 {
+		t.Fatalf("expected 2 application environments, got %v (err=%v)", appEnvs, err)
+	}
+	for _, appEnv := range appEnvs {
+		if err := services.DecommissionApplicationEnvironment(ctx, appEnv.ID, "workflow"); err != nil {
+			t.Fatalf("DecommissionApplicationEnvironment(%s) failed: %v", appEnv.ID, err)
+		}
+	}
+
+	if err := services.ArchiveApplication(ctx, "app-1", "workflow"); perrors.Code(err) != "secret_bindings_not_revoked" {
+		t.Fatalf("expected secret_bindings_not_revoked, got %v", err)
+	}
+
+	bindings, err := services.ListSecretBindings(ctx, domain.SecretBindingTargetApplicationEnvironment, "appenv-1")
+	if err != nil || len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %v (err=%v)", bindings, err)
+	}
+	if err := services.RevokeSecretBinding(ctx, "bind-1", "workflow"); err != nil {
+		t.Fatalf("RevokeSecretBinding failed: %v", err)
+	}
+	if err := services.RevokeSecretBinding(ctx, "bind-1", "workflow"); perrors.Code(err) != "secret_binding_already_revoked" {
+		t.Fatalf("expected secret_binding_already_revoked, got %v", err)
+	}
+
+	if err := services.ArchiveApplication(ctx, "app-1", "workflow"); err != nil {
+		t.Fatalf("ArchiveApplication failed: %v", err)
+	}
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	if app.State != domain.ApplicationStateArchived {
+		t.Fatalf("expected Archived, got %q", app.State)
+	}
+}
+
+func TestApplicationDecommissioning_EnforcesStates(t *testing.T) {
+	services := newDecommissioningFixture(t)
+	ctx := context.Background()
+
+	if err := services.DecommissionApplicationEnvironment(ctx, "appenv-1", "workflow"); perrors.Code(err) != "application_not_decommissioning" {
+		t.Fatalf("expected application_not_decommissioning, got %v", err)
+	}
+	if err := services.ArchiveApplication(ctx, "app-1", "workflow"); perrors.Code(err) != "application_invalid_state_for_archival" {
+		t.Fatalf("expected application_invalid_state_for_archival, got %v", err)
+	}
+
+	if err := services.StartApplicationDecommissioning(ctx, "app-1", "workflow"); err != nil {
+		t.Fatalf("StartApplicationDecommissioning failed: %v", err)
+	}
+	if err := services.StartApplicationDecommissioning(ctx, "app-1", "workflow"); perrors.Code(err) != "application_invalid_state_for_decommissioning" {
+		t.Fatalf("expected application_invalid_state_for_decommissioning, got %v", err)
+	}
+	if err := services.ArchiveApplication(ctx, "app-1", "workflow"); perrors.Code(err) != "application_environments_not_decommissioned" {
+		t.Fatalf("expected application_environments_not_decommissioned, got %v", err)
+	}
+
+	if err := services.DecommissionApplicationEnvironment(ctx, "appenv-1", "workflow"); err != nil {
+		t.Fatalf("DecommissionApplicationEnvironment failed: %v", err)
+	}
+	if err := services.DecommissionApplicationEnvironment(ctx, "appenv-1", "workflow"); perrors.Code(err) != "application_environment_invalid_state_for_decommissioning" {
+		t.Fatalf("expected application_environment_invalid_state_for_decommissioning, got %v", err)
+	}
+
+	if _, err := services.ListApplicationEnvironments(ctx, "missing"); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("expected not found for a missing application, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package application
+
+import "context"
+
+// DryRunResult es lo que habría hecho un step de DryRun.
+type DryRunResult struct {
+	// Err es el error con el que habría fallado el comando, o nil si se
+	// habría aplicado.
+	Err error
+	// Resource es el primer recurso que habría guardado el comando (el
+	// recurso sobre el que opera), tal como habría quedado.
+	Resource any
+}
+
+// DryRun ejecuta steps con la misma semántica que RunBatch pero sin aplicar
+// nada: cada comando corre su validación, sus invariantes y, detrás del
+// Authorizer, su autorización sobre una vista en staging que después se
+// descarta. No se persiste nada, no se publican eventos ni se señaliza a
+// los workflows.
+func (s *Services) DryRun(ctx context.Context, mode BatchMode, steps []BatchStep) ([]DryRunResult, error) {
+	return s.dryRun(ctx, mode, steps, func(staged *Services) API { return staged })
+}
+
+func (s *Services) dryRun(ctx context.Context, mode BatchMode, steps []BatchStep, wrap func(*Services) API) ([]DryRunResult, error) {
+	if err := validateBatch(mode, steps); err != nil {
+		return nil, err
+	}
+
+	tx := &batchTx{}
+	staged := s.staged(tx)
+	// Nada se guarda, así que no hace falta retener los locks de cuota (y
+	// retenerlos fuera de batchMu podría cruzarse con un batch atómico).
+	staged.batchQuota = nil
+	api := wrap(staged)
+	errs := make([]error, len(steps))
+	results := make([]DryRunResult, len(steps))
+	for i, step := range steps {
+		saved := len(tx.saved)
+		if err := step(ctx, api); err != nil {
+			errs[i] = err
+			if mode == BatchAtomic {
+				rollBack(errs, i, err)
+				break
+			}
+			continue
+		}
+		if len(tx.saved) > saved {
+			results[i].Resource = tx.saved[saved]
+		}
+	}
+	for i := range results {
+		results[i].Err = errs[i]
+	}
+	return results, nil
+}
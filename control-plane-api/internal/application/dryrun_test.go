@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func TestDryRun_ReturnsResourcesWithoutApplying(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, _ := services.WatchChanges(ctx, 0, 16)
+
+	results, err := services.DryRun(ctx, BatchAtomic, bootstrapSteps("team-1"))
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("step %d failed: %v", i, r.Err)
+		}
+	}
+	app, ok := results[2].Resource.(*domain.Application)
+	if !ok || app.ID != "app-1" || app.State != domain.ApplicationStateProposed || app.Metadata.CreatedBy != "alice" {
+		t.Fatalf("unexpected application %+v", results[2].Resource)
+	}
+
+	if team, _ := services.Teams.GetByID(ctx, "team-1"); team != nil {
+		t.Fatalf("expected nothing to be persisted, got %+v", team)
+	}
+	if events := drain(sub.Events); len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+}
+
+func TestDryRun_ReportsDomainErrors(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx := context.Background()
+
+	results, err := services.DryRun(ctx, BatchAtomic, bootstrapSteps("missing-team"))
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	want := []string{"batch_rolled_back", "batch_rolled_back", "team_not_found", "batch_aborted"}
+	for i, code := range want {
+		if got := perrors.Code(results[i].Err); got != code {
+			t.Errorf("step %d: expected %s, got %v", i, code, results[i].Err)
+		}
+	}
+
+	results, err = services.DryRun(ctx, BatchBestEffort, bootstrapSteps("missing-team"))
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	if results[0].Err != nil || results[0].Resource == nil || perrors.Code(results[2].Err) != "team_not_found" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	if _, err := services.DryRun(ctx, "sometimes", bootstrapSteps("team-1")); perrors.Code(err) != "invalid_batch_mode" {
+		t.Fatalf("expected invalid_batch_mode, got %v", err)
+	}
+}
+
+func TestAuthorizer_DryRunAuthorizesEachStep(t *testing.T) {
+	_, auditor, authz := newAuthzFixture(t)
+	teamAMember := auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"team-a"}}
+
+	results, err := authz.DryRun(as(teamAMember), BatchBestEffort, []BatchStep{
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-2", "App 2", "team-a", "ana")
+		},
+		func(ctx context.Context, api API) error { return api.CreateTeam(ctx, "team-c", "C", "ana") },
+	})
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	if results[0].Err != nil || !perrors.IsKind(results[1].Err, perrors.KindForbidden) {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if len(auditor.denials) != 1 || auditor.denials[0].Command != "CreateTeam" {
+		t.Fatalf("expected CreateTeam denial, got %+v", auditor.denials)
+	}
+
+	if _, err := authz.DryRun(context.Background(), BatchAtomic, nil); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden without principal, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
+)
+
+var ErrOrganizationNotFound = perrors.NotFound("organization_not_found", "organization not found", nil)
+
+// OrganizationRepository guarda las organizaciones. A diferencia del resto
+// de los repositorios no es tenant-scoped.
+type OrganizationRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.Organization, error)
+	Save(ctx context.Context, org *domain.Organization) error
+}
+
+// OrganizationScope devuelve ctx acotado a la organización en la que opera
+// una request. requested es la que pidió el cliente (header o metadata);
+// si no pidió ninguna se usa la del principal y, si no tiene, la default.
+// Que el principal pueda operar en esa organización lo decide el
+// Authorizer.
+func OrganizationScope(ctx context.Context, requested string) (context.Context, error) {
+	org := requested
+	if org == "" {
+		if p, ok := auth.PrincipalFromContext(ctx); ok {
+			org = p.Organization
+		}
+	}
+	if org == "" {
+		org = domain.DefaultOrganizationID
+	}
+	if org != domain.DefaultOrganizationID {
+		if err := validation.New().ID("organizationId", org).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return domain.WithOrganization(ctx, org), nil
+}
+
+// CreateOrganization da de alta una organización Active. Sus Teams y
+// Environments se crean después, con requests dentro de la organización.
+func (s *Services) CreateOrganization(ctx context.Context, id, name, createdBy string) error {
+	if s.Organizations == nil {
+		return perrors.Internal("organization_repository_not_configured", "organization repository not configured", nil)
+	}
+
+	if err := validation.New().ID("id", id).Name("name", name).Err(); err != nil {
+		return err
+	}
+
+	if existing, _ := s.Organizations.GetByID(ctx, id); existing != nil || id == domain.DefaultOrganizationID {
+		return perrors.Conflict("organization_already_exists", "organization already exists", nil)
+	}
+
+	org := &domain.Organization{
+		ID:       id,
+		Name:     name,
+		State:    domain.OrganizationStateActive,
+		Metadata: domain.NewMetadata(createdBy, time.Now().UTC()),
+	}
+
+	if err := save(ctx, s, s.Organizations.Save, org); err != nil {
+		return fmt.Errorf("saving organization: %w", err)
+	}
+
+	s.recordChange(ctx, domain.ChangeActionCreated, org, createdBy)
+
+	return nil
+}
+
+// GetOrganization devuelve la organización id. La default existe siempre,
+// aunque no esté guardada.
+func (s *Services) GetOrganization(ctx context.Context, id string) (*domain.Organization, error) {
+	var org *domain.Organization
+	if s.Organizations != nil {
+		var err error
+		if org, err = s.Organizations.GetByID(ctx, id); err != nil {
+			return nil, perrors.Internal("organization_repository_error", "error loading organization", err)
+		}
+	}
+	if org == nil && id == domain.DefaultOrganizationID {
+		org = &domain.Organization{ID: id, Name: id, State: domain.OrganizationStateActive}
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// requireOrganization verifica que la organización de ctx exista y esté
+// activa. Se exige al crear los recursos raíz (Teams y Environments): el
+// resto cuelga de ellos y se resuelve dentro de la misma organización.
+func (s *Services) requireOrganization(ctx context.Context) error {
+	org, err := s.GetOrganization(ctx, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if org.State != domain.OrganizationStateActive {
+		return perrors.Domain("organization_not_active", "organization is not active", nil)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
+)
+
+// quotaExceeded es el error de los comandos que superarían un límite de la
+// cuota del team.
+func quotaExceeded(teamID, what string, limit int) error {
+	return perrors.Domain("quota_exceeded", fmt.Sprintf("team %s reached its quota of %d %s", teamID, limit, what), nil)
+}
+
+// quotaFor devuelve la cuota efectiva de team: la propia, completada con
+// Services.DefaultQuota y, por último, con domain.DefaultTeamQuota.
+func (s *Services) quotaFor(team *domain.Team) domain.TeamQuota {
+	var quota domain.TeamQuota
+	if team != nil && team.Quota != nil {
+		quota = *team.Quota
+	}
+	return quota.Or(s.DefaultQuota).Or(domain.DefaultTeamQuota())
+}
+
+// teamQuota carga el team y devuelve su cuota efectiva. Un team inexistente
+// usa la política por defecto (la existencia del team la valida cada
+// comando), pero un error del repositorio se devuelve: con la política por
+// defecto se podría superar una cuota propia más baja.
+func (s *Services) teamQuota(ctx context.Context, teamID string) (domain.TeamQuota, error) {
+	if s.Teams == nil || teamID == "" {
+		return s.quotaFor(nil), nil
+	}
+	team, err := s.Teams.GetByID(ctx, teamID)
+	if err != nil {
+		return domain.TeamQuota{}, perrors.Internal("team_repository_error", "error loading team", err)
+	}
+	return s.quotaFor(team), nil
+}
+
+// teamLocks serializa, por team, el chequeo de cuota y el Save del recurso
+// que la ocupa: sin el lock, dos creaciones concurrentes ven el mismo uso y
+// las dos pasan el límite. Cada lock vive mientras alguien lo use.
+type teamLocks struct {
+	mu    sync.Mutex
+	locks map[string]*teamLock
+}
+
+type teamLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (l *teamLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*teamLock)
+	}
+	tl := l.locks[key]
+	if tl == nil {
+		tl = &teamLock{}
+		l.locks[key] = tl
+	}
+	tl.refs++
+	l.mu.Unlock()
+
+	tl.mu.Lock()
+	return func() {
+		tl.mu.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if tl.refs--; tl.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
+
+// batchQuotaLocks son los locks de cuota de un batch atómico. Se conservan
+// hasta el commit, porque el Save real ocurre recién ahí. Los batches se
+// serializan entre sí y un comando suelto toma un único lock, así que
+// retenerlos no puede trabar a nadie en ciclo.
+type batchQuotaLocks struct {
+	locks  *teamLocks
+	unlock map[string]func()
+}
+
+func (b *batchQuotaLocks) hold(key string) {
+	if _, ok := b.unlock[key]; ok {
+		return
+	}
+	if b.unlock == nil {
+		b.unlock = make(map[string]func())
+	}
+	b.unlock[key] = b.locks.lock(key)
+}
+
+func (b *batchQuotaLocks) release() {
+	for _, unlock := range b.unlock {
+		unlock()
+	}
+	b.unlock = nil
+}
+
+// lockTeamQuota toma el lock de cuota de teamID y devuelve la función que lo
+// libera. Se toma antes del chequeo de cuota y se libera después del Save.
+// Dentro de un batch atómico el lock queda tomado hasta el commit.
+func (s *Services) lockTeamQuota(ctx context.Context, teamID string) func() {
+	key := domain.ScopedID(ctx, teamID)
+	if s.batchQuota != nil {
+		s.batchQuota.hold(key)
+		return func() {}
+	}
+	return s.quotaLocks.lock(key)
+}
+
+// checkApplicationQuota valida que team pueda sumar una Application.
+func (s *Services) checkApplicationQuota(ctx context.Context, team *domain.Team) error {
+	apps, err := s.Applications.ListByTeam(ctx, team.ID)
+	if err != nil {
+		return perrors.Internal("application_repository_error", "error listing applications", err)
+	}
+	if limit := s.quotaFor(team).MaxApplications; countTowardQuota(apps) >= limit {
+		return quotaExceeded(team.ID, "applications", limit)
+	}
+	return nil
+}
+
+// checkSecretQuota valida que team pueda sumar un Secret.
+func (s *Services) checkSecretQuota(ctx context.Context, team *domain.Team) error {
+	secrets, err := s.Secrets.ListByOwnerTeam(ctx, team.ID)
+	if err != nil {
+		return perrors.Internal("secret_repository_error", "error listing secrets", err)
+	}
+	if limit := s.quotaFor(team).MaxSecrets; countTowardQuota(secrets) >= limit {
+		return quotaExceeded(team.ID, "secrets", limit)
+	}
+	return nil
+}
+
+// checkSecretBindingQuota valida que secret admita un binding más, según la
+// cuota del team dueño.
+func (s *Services) checkSecretBindingQuota(ctx context.Context, secret *domain.Secret) error {
+	bindings, err := s.SecretBindings.ListBySecret(ctx, secret.ID)
+	if err != nil {
+		return perrors.Internal("secret_binding_repository_error", "error listing secret bindings", err)
+	}
+	quota, err := s.teamQuota(ctx, secret.OwnerTeam)
+	if err != nil {
+		return err
+	}
+	if limit := quota.MaxBindingsPerSecret; countTowardQuota(bindings) >= limit {
+		return quotaExceeded(secret.OwnerTeam, "bindings per secret", limit)
+	}
+	return nil
+}
+
+// checkApplicationEnvironmentQuota valida que app admita un
+// ApplicationEnvironment más, según la cuota de su team.
+func (s *Services) checkApplicationEnvironmentQuota(ctx context.Context, app *domain.Application) error {
+	appEnvs, err := s.ApplicationEnvironments.ListByApplication(ctx, app.ID)
+	if err != nil {
+		return perrors.Internal("application_environment_repository_error", "error listing application environments", err)
+	}
+	quota, err := s.teamQuota(ctx, app.TeamID)
+	if err != nil {
+		return err
+	}
+	if limit := quota.MaxEnvironmentsPerApplication; countTowardQuota(appEnvs) >= limit {
+		return quotaExceeded(app.TeamID, "environments per application", limit)
+	}
+	return nil
+}
+
+// quotaCounted son los recursos que ocupan cuota mientras están vivos.
+type quotaCounted interface {
+	CountsTowardQuota() bool
+}
+
+func countTowardQuota[T quotaCounted](items []T) int {
+	n := 0
+	for _, item := range items {
+		if item.CountsTowardQuota() {
+			n++
+		}
+	}
+	return n
+}
+
+// GetTeamQuota devuelve la cuota efectiva del team y cuánto de ella usa.
+func (s *Services) GetTeamQuota(ctx context.Context, teamID string) (*domain.TeamQuotaUsage, error) {
+	if s.Teams == nil || s.Applications == nil || s.ApplicationEnvironments == nil || s.Secrets == nil || s.SecretBindings == nil {
+		return nil, perrors.Internal("repositories_not_configured", "repositories not configured", nil)
+	}
+
+	team, err := s.Teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, perrors.Internal("team_repository_error", "error loading team", err)
+	}
+	if team == nil {
+		return nil, perrors.NotFound("team_not_found", "team not found", nil)
+	}
+
+	quota := s.quotaFor(team)
+	usage := &domain.TeamQuotaUsage{
+		TeamID:                     team.ID,
+		Quota:                      quota,
+		BindingsPerSecret:          make(map[string]domain.QuotaUsage),
+		EnvironmentsPerApplication: make(map[string]domain.QuotaUsage),
+	}
+
+	apps, err := s.Applications.ListByTeam(ctx, team.ID)
+	if err != nil {
+		return nil, perrors.Internal("application_repository_error", "error listing applications", err)
+	}
+	usage.Applications = domain.QuotaUsage{Used: countTowardQuota(apps), Limit: quota.MaxApplications}
+	if err := s.environmentsUsage(ctx, apps, usage); err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.Secrets.ListByOwnerTeam(ctx, team.ID)
+	if err != nil {
+		return nil, perrors.Internal("secret_repository_error", "error listing secrets", err)
+	}
+	usage.Secrets = domain.QuotaUsage{Used: countTowardQuota(secrets), Limit: quota.MaxSecrets}
+	if err := s.bindingsUsage(ctx, secrets, usage); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// environmentsUsage completa el uso de ApplicationEnvironments de cada
+// Application que ocupa cuota.
+func (s *Services) environmentsUsage(ctx context.Context, apps []*domain.Application, usage *domain.TeamQuotaUsage) error {
+	for _, app := range apps {
+		if !app.CountsTowardQuota() {
+			continue
+		}
+		appEnvs, err := s.ApplicationEnvironments.ListByApplication(ctx, app.ID)
+		if err != nil {
+			return perrors.Internal("application_environment_repository_error", "error listing application environments", err)
+		}
+		usage.EnvironmentsPerApplication[app.ID] = domain.QuotaUsage{Used: countTowardQuota(appEnvs), Limit: usage.Quota.MaxEnvironmentsPerApplication}
+	}
+	return nil
+}
+
+// bindingsUsage completa el uso de bindings de cada Secret que ocupa cuota.
+func (s *Services) bindingsUsage(ctx context.Context, secrets []*domain.Secret, usage *domain.TeamQuotaUsage) error {
+	for _, sec := range secrets {
+		if !sec.CountsTowardQuota() {
+			continue
+		}
+		bindings, err := s.SecretBindings.ListBySecret(ctx, sec.ID)
+		if err != nil {
+			return perrors.Internal("secret_binding_repository_error", "error listing secret bindings", err)
+		}
+		usage.BindingsPerSecret[sec.ID] = domain.QuotaUsage{Used: countTowardQuota(bindings), Limit: usage.Quota.MaxBindingsPerSecret}
+	}
+	return nil
+}
+
+// SetTeamQuota reemplaza la cuota propia del team. Los límites en 0 vuelven
+// a la política por defecto; bajar un límite por debajo del uso actual no
+// afecta a los recursos existentes, sólo impide crear nuevos.
+func (s *Services) SetTeamQuota(ctx context.Context, teamID string, quota domain.TeamQuota, setBy string) error {
+	if s.Teams == nil {
+		return perrors.Internal("team_repository_not_configured", "team repository not configured", nil)
+	}
+
+	if err := validation.New().
+		ID("id", teamID).
+		NonNegative("maxApplications", quota.MaxApplications).
+		NonNegative("maxSecrets", quota.MaxSecrets).
+		NonNegative("maxBindingsPerSecret", quota.MaxBindingsPerSecret).
+		NonNegative("maxEnvironmentsPerApplication", quota.MaxEnvironmentsPerApplication).
+		Err(); err != nil {
+		return err
+	}
+
+	team, err := s.Teams.GetByID(ctx, teamID)
+	if err != nil || team == nil {
+		return perrors.NotFound("team_not_found", "team not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, team.Metadata); err != nil {
+		return err
+	}
+
+	team.Quota = nil
+	if quota != (domain.TeamQuota{}) {
+		team.Quota = &quota
+	}
+	team.Metadata.Version++
+
+	if err := save(ctx, s, s.Teams.Save, team); err != nil {
+		return fmt.Errorf("saving team quota: %w", err)
+	}
+
+	return nil
+}
@@ -2,11 +2,15 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/audit"
 	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
 )
 
 var (
@@ -22,6 +26,7 @@ type TeamRepository interface {
 
 type ApplicationRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Application, error)
+	ListByTeam(ctx context.Context, teamID string) ([]*domain.Application, error)
 	Save(ctx context.Context, app *domain.Application) error
 }
 
@@ -38,16 +43,20 @@ type EnvironmentRepository interface {
 type ApplicationEnvironmentRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.ApplicationEnvironment, error)
 	GetByApplicationAndEnvironment(ctx context.Context, applicationID, environmentID string) (*domain.ApplicationEnvironment, error)
+	ListByApplication(ctx context.Context, applicationID string) ([]*domain.ApplicationEnvironment, error)
 	Save(ctx context.Context, appEnv *domain.ApplicationEnvironment) error
 }
 
 type SecretRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Secret, error)
+	ListByOwnerTeam(ctx context.Context, teamID string) ([]*domain.Secret, error)
 	Save(ctx context.Context, s *domain.Secret) error
 }
 
 type SecretBindingRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.SecretBinding, error)
+	ListBySecret(ctx context.Context, secretID string) ([]*domain.SecretBinding, error)
+	ListByTarget(ctx context.Context, targetType, targetID string) ([]*domain.SecretBinding, error)
 	Save(ctx context.Context, b *domain.SecretBinding) error
 }
 
@@ -61,7 +70,15 @@ type GitOpsIntegrationRepository interface {
 	Save(ctx context.Context, gi *domain.GitOpsIntegration) error
 }
 
+// Services implementa los casos de uso. Todos operan dentro de la
+// organización del contexto (ver OrganizationScope): los repositorios de
+// recursos son tenant-scoped, así que un comando no puede referenciar
+// recursos de otra organización.
 type Services struct {
+	// Organizations es opcional: sin repositorio sólo existe la
+	// organización default.
+	Organizations OrganizationRepository
+
 	Teams                   TeamRepository
 	Applications            ApplicationRepository
 	CodeRepositories        CodeRepositoryRepository
@@ -71,6 +88,40 @@ type Services struct {
 	SecretBindings          SecretBindingRepository
 	DeploymentRepositories  DeploymentRepositoryRepository
 	GitOpsIntegrations      GitOpsIntegrationRepository
+
+	// DefaultQuota es la cuota de los teams sin cuota propia; sus límites
+	// en 0 toman los de domain.DefaultTeamQuota.
+	DefaultQuota domain.TeamQuota
+
+	WebhookSubscriptions WebhookSubscriptionRepository
+	WebhookDeliveries    WebhookDeliveryRepository
+	// AllowInsecureWebhooks acepta URLs http en las suscripciones. Sólo para
+	// modo dev, donde los receptores suelen ser locales.
+	AllowInsecureWebhooks bool
+
+	// Approvals guarda los pedidos de aprobación manual; Signals reenvía
+	// sus decisiones a los workflows que las esperan.
+	Approvals ApprovalRepository
+	Signals   WorkflowSignaler
+
+	// Changes recibe un evento por cada recurso creado o transicionado.
+	// Opcional: sin feed no se publican cambios.
+	Changes ChangeFeed
+
+	// Audit es el log de auditoría de comandos. Lo escriben los adapters
+	// (middleware HTTP e interceptor gRPC); Services sólo lo consulta.
+	Audit audit.Store
+
+	// batchMu serializa los batches atómicos (ver RunBatch). writeMu lo
+	// toman en lectura las escrituras sueltas y en exclusiva el commit de
+	// un batch atómico (ver save).
+	batchMu sync.Mutex
+	writeMu sync.RWMutex
+	// quotaLocks serializa por team los chequeos de cuota con su Save;
+	// batchQuota los retiene hasta el commit en los Services de staging de
+	// un batch atómico (ver lockTeamQuota).
+	quotaLocks teamLocks
+	batchQuota *batchQuotaLocks
 }
 
 func (s *Services) GetApplication(ctx context.Context, id string) (*domain.Application, error) {
@@ -126,24 +177,32 @@ func (s *Services) CreateTeam(ctx context.Context, id, name, createdBy string) e
 		return perrors.Internal("team_repository_not_configured", "team repository not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).Name("name", name).Err(); err != nil {
+		return err
+	}
+
+	if err := s.requireOrganization(ctx); err != nil {
+		return err
+	}
+
 	if existing, _ := s.Teams.GetByID(ctx, id); existing != nil {
 		return ErrTeamAlreadyExists
 	}
 
 	team := &domain.Team{
-		ID:    id,
-		Name:  name,
-		State: domain.TeamStateDraft,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		ID:             id,
+		OrganizationID: domain.OrganizationFromContext(ctx),
+		Name:           name,
+		State:          domain.TeamStateDraft,
+		Metadata:       domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.Teams.Save(ctx, team); err != nil {
+	if err := save(ctx, s, s.Teams.Save, team); err != nil {
 		return fmt.Errorf("saving team: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, team, createdBy)
+
 	return nil
 }
 
@@ -152,6 +211,10 @@ func (s *Services) CreateApplication(ctx context.Context, id, name, teamID, crea
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).Name("name", name).ID("teamId", teamID).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.Applications.GetByID(ctx, id); existing != nil {
 		return ErrApplicationAlreadyExists
 	}
@@ -161,27 +224,64 @@ func (s *Services) CreateApplication(ctx context.Context, id, name, teamID, crea
 		return perrors.NotFound("team_not_found", "team not found", err)
 	}
 
+	unlock := s.lockTeamQuota(ctx, team.ID)
+	defer unlock()
+	if err := s.checkApplicationQuota(ctx, team); err != nil {
+		return err
+	}
+
 	app := &domain.Application{
-		ID:     id,
-		Name:   name,
-		TeamID: teamID,
-		State:  domain.ApplicationStateProposed,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		ID:       id,
+		Name:     name,
+		TeamID:   teamID,
+		State:    domain.ApplicationStateProposed,
+		Metadata: domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.Applications.Save(ctx, app); err != nil {
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
 		return fmt.Errorf("saving application: %w", err)
 	}
 
+	// Toda Application nace con su pedido de aprobación manual pendiente. Si
+	// el pedido no se puede guardar, la Application se borra: sin él nadie
+	// podría aprobarla.
+	var approval *domain.Approval
+	if s.Approvals != nil {
+		approval = &domain.Approval{
+			ID:           ApplicationApprovalID(id),
+			Type:         domain.ApprovalTypeApplication,
+			ResourceType: "Application",
+			ResourceID:   id,
+			TeamID:       teamID,
+			RolesAllowed: append([]string(nil), ManualApprovalRoles...),
+			State:        domain.ApprovalStatePending,
+			Metadata:     domain.NewMetadata(createdBy, app.Metadata.CreatedAt),
+		}
+		if err := save(ctx, s, s.Approvals.Save, approval); err != nil {
+			if d, ok := s.Applications.(deleter); ok {
+				s.writeMu.RLock()
+				derr := d.Delete(ctx, id)
+				s.writeMu.RUnlock()
+				if derr != nil {
+					err = errors.Join(err, fmt.Errorf("removing application: %w", derr))
+				}
+			}
+			return fmt.Errorf("saving application approval: %w", err)
+		}
+	}
+
+	s.recordChange(ctx, domain.ChangeActionCreated, app, createdBy)
+	if approval != nil {
+		s.recordChange(ctx, domain.ChangeActionCreated, approval, createdBy)
+	}
+
 	return nil
 }
 
 // ApproveApplication transitions an Application from Proposed to Approved.
 // Este método modela el "onApplicationApproved" del estado deseado: una vez
-// en Approved, un workflow de onboarding puede ser disparado.
+// en Approved, un workflow de onboarding puede ser disparado. Si la
+// Application tiene pedido de aprobación, lo resuelve con el aprobador.
 func (s *Services) ApproveApplication(ctx context.Context, id, approvedBy string) error {
 	if s.Applications == nil {
 		return perrors.Internal("application_repository_not_configured", "application repository not configured", nil)
@@ -192,17 +292,39 @@ func (s *Services) ApproveApplication(ctx context.Context, id, approvedBy string
 		return perrors.NotFound("application_not_found", "application not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
+	var approval *domain.Approval
+	if s.Approvals != nil {
+		approval, _ = s.Approvals.GetByID(ctx, ApplicationApprovalID(id))
+	}
+	return s.approveApplication(ctx, app, approval, "", approvedBy)
+}
+
+// approveApplication aprueba app y, si existe, resuelve su pedido de
+// aprobación registrando quién aprobó y con qué rol.
+func (s *Services) approveApplication(ctx context.Context, app *domain.Application, approval *domain.Approval, comment, approvedBy string) error {
 	if app.State != domain.ApplicationStateProposed {
 		return perrors.Domain("application_invalid_state_for_approval", "application can only be approved from Proposed state", nil)
 	}
 
+	if approval != nil {
+		if err := s.resolveApproval(ctx, approval, true, comment, approvedBy); err != nil {
+			return err
+		}
+	}
+
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateApproved), approvedBy, time.Now().UTC())
 	app.State = domain.ApplicationStateApproved
-	_ = approvedBy
 
-	if err := s.Applications.Save(ctx, app); err != nil {
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
 		return fmt.Errorf("saving approved application: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, approvedBy)
+
 	return nil
 }
 
@@ -219,17 +341,23 @@ func (s *Services) StartApplicationOnboarding(ctx context.Context, id, startedBy
 		return perrors.NotFound("application_not_found", "application not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
 	if app.State != domain.ApplicationStateApproved {
 		return perrors.Domain("application_invalid_state_for_onboarding", "application can only start onboarding from Approved state", nil)
 	}
 
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateOnboarding), startedBy, time.Now().UTC())
 	app.State = domain.ApplicationStateOnboarding
-	_ = startedBy
 
-	if err := s.Applications.Save(ctx, app); err != nil {
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
 		return fmt.Errorf("starting application onboarding: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, startedBy)
+
 	return nil
 }
 
@@ -246,17 +374,23 @@ func (s *Services) ActivateApplication(ctx context.Context, id, activatedBy stri
 		return perrors.NotFound("application_not_found", "application not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
 	if app.State != domain.ApplicationStateOnboarding {
 		return perrors.Domain("application_invalid_state_for_activation", "application can only be activated from Onboarding state", nil)
 	}
 
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateActive), activatedBy, time.Now().UTC())
 	app.State = domain.ApplicationStateActive
-	_ = activatedBy
 
-	if err := s.Applications.Save(ctx, app); err != nil {
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
 		return fmt.Errorf("activating application: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, activatedBy)
+
 	return nil
 }
 
@@ -266,6 +400,10 @@ func (s *Services) DeclareCodeRepository(ctx context.Context, id, applicationID,
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("applicationId", applicationID).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.CodeRepositories.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("code_repository_already_exists", "code repository already exists", nil)
 	}
@@ -279,43 +417,51 @@ func (s *Services) DeclareCodeRepository(ctx context.Context, id, applicationID,
 		ID:            id,
 		ApplicationID: applicationID,
 		State:         domain.CodeRepositoryStateDeclared,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:      domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.CodeRepositories.Save(ctx, repo); err != nil {
+	if err := save(ctx, s, s.CodeRepositories.Save, repo); err != nil {
 		return fmt.Errorf("saving code repository: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, repo, createdBy)
+
 	return nil
 }
 
-// CreateEnvironment declares a new global Environment in Planned state.
+// CreateEnvironment declares a new Environment of the context's Organization
+// in Planned state.
 func (s *Services) CreateEnvironment(ctx context.Context, id, name, createdBy string) error {
 	if s.Environments == nil {
 		return perrors.Internal("environment_repository_not_configured", "environment repository not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).Name("name", name).Err(); err != nil {
+		return err
+	}
+
+	if err := s.requireOrganization(ctx); err != nil {
+		return err
+	}
+
 	if existing, _ := s.Environments.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("environment_already_exists", "environment already exists", nil)
 	}
 
 	env := &domain.Environment{
-		ID:    id,
-		Name:  name,
-		State: domain.EnvironmentStatePlanned,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		ID:             id,
+		OrganizationID: domain.OrganizationFromContext(ctx),
+		Name:           name,
+		State:          domain.EnvironmentStatePlanned,
+		Metadata:       domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.Environments.Save(ctx, env); err != nil {
+	if err := save(ctx, s, s.Environments.Save, env); err != nil {
 		return fmt.Errorf("saving environment: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, env, createdBy)
+
 	return nil
 }
 
@@ -325,6 +471,10 @@ func (s *Services) DeclareDeploymentRepository(ctx context.Context, id, applicat
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("applicationId", applicationID).MaxLength("deploymentModel", deploymentModel, validation.MaxIDLength).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.DeploymentRepositories.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("deployment_repository_already_exists", "deployment repository already exists", nil)
 	}
@@ -339,16 +489,15 @@ func (s *Services) DeclareDeploymentRepository(ctx context.Context, id, applicat
 		ApplicationID:   applicationID,
 		DeploymentModel: deploymentModel,
 		State:           domain.DeploymentRepositoryStateDeclared,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:        domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.DeploymentRepositories.Save(ctx, repo); err != nil {
+	if err := save(ctx, s, s.DeploymentRepositories.Save, repo); err != nil {
 		return fmt.Errorf("saving deployment repository: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, repo, createdBy)
+
 	return nil
 }
 
@@ -359,6 +508,10 @@ func (s *Services) DeclareApplicationEnvironment(ctx context.Context, id, applic
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("applicationId", applicationID).ID("environmentId", environmentID).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.ApplicationEnvironments.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("application_environment_already_exists", "application environment already exists", nil)
 	}
@@ -380,21 +533,26 @@ func (s *Services) DeclareApplicationEnvironment(ctx context.Context, id, applic
 		return perrors.Conflict("application_environment_pair_already_exists", "application environment pair already exists", nil)
 	}
 
+	unlock := s.lockTeamQuota(ctx, app.TeamID)
+	defer unlock()
+	if err := s.checkApplicationEnvironmentQuota(ctx, app); err != nil {
+		return err
+	}
+
 	appEnv := &domain.ApplicationEnvironment{
 		ID:            id,
 		ApplicationID: applicationID,
 		EnvironmentID: environmentID,
 		State:         domain.ApplicationEnvironmentStateDeclared,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:      domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.ApplicationEnvironments.Save(ctx, appEnv); err != nil {
+	if err := save(ctx, s, s.ApplicationEnvironments.Save, appEnv); err != nil {
 		return fmt.Errorf("saving application environment: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, appEnv, createdBy)
+
 	return nil
 }
 
@@ -411,18 +569,23 @@ func (s *Services) CompleteApplicationEnvironmentProvisioning(ctx context.Contex
 		return ErrApplicationEnvironmentNotFound
 	}
 
+	if err := checkExpectedVersion(ctx, appEnv.Metadata); err != nil {
+		return err
+	}
+
 	if appEnv.State != domain.ApplicationEnvironmentStateDeclared && appEnv.State != domain.ApplicationEnvironmentStateProvisioning {
 		return perrors.Domain("application_environment_invalid_state_for_activation", "application environment cannot be activated from current state", nil)
 	}
 
+	appEnv.Metadata.RecordTransition(string(appEnv.State), string(domain.ApplicationEnvironmentStateActive), completedBy, time.Now().UTC())
 	appEnv.State = domain.ApplicationEnvironmentStateActive
-	// For ahora mantenemos solo Created* en Metadata; podríamos extender con Updated* más adelante.
-	_ = completedBy
 
-	if err := s.ApplicationEnvironments.Save(ctx, appEnv); err != nil {
+	if err := save(ctx, s, s.ApplicationEnvironments.Save, appEnv); err != nil {
 		return fmt.Errorf("completing application environment provisioning: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, appEnv, completedBy)
+
 	return nil
 }
 
@@ -438,17 +601,23 @@ func (s *Services) DeprecateApplication(ctx context.Context, id, deprecatedBy st
 		return perrors.NotFound("application_not_found", "application not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, app.Metadata); err != nil {
+		return err
+	}
+
 	if app.State != domain.ApplicationStateActive {
 		return perrors.Domain("application_invalid_state_for_deprecation", "application can only be deprecated from Active state", nil)
 	}
 
+	app.Metadata.RecordTransition(string(app.State), string(domain.ApplicationStateDeprecated), deprecatedBy, time.Now().UTC())
 	app.State = domain.ApplicationStateDeprecated
-	_ = deprecatedBy
 
-	if err := s.Applications.Save(ctx, app); err != nil {
+	if err := save(ctx, s, s.Applications.Save, app); err != nil {
 		return fmt.Errorf("deprecating application: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, app, deprecatedBy)
+
 	return nil
 }
 
@@ -459,6 +628,10 @@ func (s *Services) DeclareGitOpsIntegration(ctx context.Context, id, application
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("applicationId", applicationID).ID("deploymentRepositoryId", deploymentRepoID).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.GitOpsIntegrations.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("gitops_integration_already_exists", "gitops integration already exists", nil)
 	}
@@ -481,16 +654,15 @@ func (s *Services) DeclareGitOpsIntegration(ctx context.Context, id, application
 		ID:                     id,
 		ApplicationID:          applicationID,
 		DeploymentRepositoryID: deploymentRepoID,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:               domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.GitOpsIntegrations.Save(ctx, gi); err != nil {
+	if err := save(ctx, s, s.GitOpsIntegrations.Save, gi); err != nil {
 		return fmt.Errorf("saving gitops integration: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, gi, createdBy)
+
 	return nil
 }
 
@@ -506,6 +678,10 @@ func (s *Services) CreateSecret(ctx context.Context, id, ownerTeamID, purpose, s
 		return perrors.Validation("owner_team_required", "owner team is required", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("ownerTeamId", ownerTeamID).MaxLength("purpose", purpose, validation.MaxNameLength).MaxLength("sensitivity", sensitivity, validation.MaxIDLength).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.Secrets.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("secret_already_exists", "secret already exists", nil)
 	}
@@ -515,22 +691,27 @@ func (s *Services) CreateSecret(ctx context.Context, id, ownerTeamID, purpose, s
 		return perrors.NotFound("owner_team_not_found", "owner team not found", err)
 	}
 
+	unlock := s.lockTeamQuota(ctx, team.ID)
+	defer unlock()
+	if err := s.checkSecretQuota(ctx, team); err != nil {
+		return err
+	}
+
 	secret := &domain.Secret{
 		ID:          id,
 		OwnerTeam:   ownerTeamID,
 		Purpose:     purpose,
 		Sensitivity: sensitivity,
 		State:       domain.SecretStateDeclared,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:    domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.Secrets.Save(ctx, secret); err != nil {
+	if err := save(ctx, s, s.Secrets.Save, secret); err != nil {
 		return fmt.Errorf("saving secret: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, secret, createdBy)
+
 	return nil
 }
 
@@ -546,17 +727,23 @@ func (s *Services) StartSecretRotation(ctx context.Context, id, startedBy string
 		return perrors.NotFound("secret_not_found", "secret not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, sec.Metadata); err != nil {
+		return err
+	}
+
 	if sec.State != domain.SecretStateActive {
 		return perrors.Domain("secret_invalid_state_for_start_rotation", "secret can only start rotation from Active state", nil)
 	}
 
+	sec.Metadata.RecordTransition(string(sec.State), string(domain.SecretStateRotating), startedBy, time.Now().UTC())
 	sec.State = domain.SecretStateRotating
-	_ = startedBy
 
-	if err := s.Secrets.Save(ctx, sec); err != nil {
+	if err := save(ctx, s, s.Secrets.Save, sec); err != nil {
 		return fmt.Errorf("starting secret rotation: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, sec, startedBy)
+
 	return nil
 }
 
@@ -573,17 +760,23 @@ func (s *Services) CompleteSecretRotation(ctx context.Context, id, completedBy s
 		return perrors.NotFound("secret_not_found", "secret not found", err)
 	}
 
+	if err := checkExpectedVersion(ctx, sec.Metadata); err != nil {
+		return err
+	}
+
 	if sec.State != domain.SecretStateRotating {
 		return perrors.Domain("secret_invalid_state_for_complete_rotation", "secret can only complete rotation from Rotating state", nil)
 	}
 
+	sec.Metadata.RecordTransition(string(sec.State), string(domain.SecretStateActive), completedBy, time.Now().UTC())
 	sec.State = domain.SecretStateActive
-	_ = completedBy
 
-	if err := s.Secrets.Save(ctx, sec); err != nil {
+	if err := save(ctx, s, s.Secrets.Save, sec); err != nil {
 		return fmt.Errorf("completing secret rotation: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionTransitioned, sec, completedBy)
+
 	return nil
 }
 
@@ -595,6 +788,10 @@ func (s *Services) DeclareSecretBinding(ctx context.Context, id, secretID, targe
 		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
 	}
 
+	if err := validation.New().ID("id", id).ID("secretId", secretID).ID("targetId", targetID).MaxLength("targetType", targetType, validation.MaxIDLength).Err(); err != nil {
+		return err
+	}
+
 	if existing, _ := s.SecretBindings.GetByID(ctx, id); existing != nil {
 		return perrors.Conflict("secret_binding_already_exists", "secret binding already exists", nil)
 	}
@@ -608,21 +805,26 @@ func (s *Services) DeclareSecretBinding(ctx context.Context, id, secretID, targe
 		return perrors.Domain("binding_requires_active_secret", "binding requires active secret", nil)
 	}
 
+	unlock := s.lockTeamQuota(ctx, secret.OwnerTeam)
+	defer unlock()
+	if err := s.checkSecretBindingQuota(ctx, secret); err != nil {
+		return err
+	}
+
 	binding := &domain.SecretBinding{
 		ID:         id,
 		SecretID:   secretID,
 		TargetID:   targetID,
 		TargetType: targetType,
 		State:      domain.SecretBindingStateDeclared,
-		Metadata: domain.Metadata{
-			CreatedBy: createdBy,
-			CreatedAt: time.Now().UTC(),
-		},
+		Metadata:   domain.NewMetadata(createdBy, time.Now().UTC()),
 	}
 
-	if err := s.SecretBindings.Save(ctx, binding); err != nil {
+	if err := save(ctx, s, s.SecretBindings.Save, binding); err != nil {
 		return fmt.Errorf("saving secret binding: %w", err)
 	}
 
+	s.recordChange(ctx, domain.ChangeActionCreated, binding, createdBy)
+
 	return nil
 }
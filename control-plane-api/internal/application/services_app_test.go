@@ -2,10 +2,12 @@ package application
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
 	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
 )
 
 func TestCreateTeam_InitialStateDraft(t *testing.T) {
@@ -100,3 +102,48 @@ func TestCreateApplication_FailsOnDuplicateID(t *testing.T) {
 		t.Fatalf("expected error on duplicate application id, got nil")
 	}
 }
+
+func TestCreateCommands_RejectInvalidIdentifiersAndNames(t *testing.T) {
+	services := &Services{
+		Teams:        memoryrepo.NewTeamRepository(),
+		Applications: memoryrepo.NewApplicationRepository(),
+		Environments: memoryrepo.NewEnvironmentRepository(),
+	}
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		call   func() error
+		fields []string
+	}{
+		{"spaces and slashes", func() error { return services.CreateTeam(ctx, "my team/1", "Team", "test") }, []string{"id"}},
+		{"uppercase", func() error { return services.CreateEnvironment(ctx, "Dev", "Dev", "test") }, []string{"id"}},
+		{"too long", func() error { return services.CreateTeam(ctx, strings.Repeat("a", 500), "Team", "test") }, []string{"id"}},
+		{"reserved word", func() error { return services.CreateEnvironment(ctx, "new", "New", "test") }, []string{"id"}},
+		{"several fields", func() error { return services.CreateApplication(ctx, "app_1", " App", "Team 1", "test") }, []string{"id", "name", "teamId"}},
+	}
+	for _, tc := range cases {
+		err := tc.call()
+		if perrors.Code(err) != "invalid_arguments" || !perrors.IsKind(err, perrors.KindValidation) {
+			t.Errorf("%s: expected invalid_arguments, got %v", tc.name, err)
+			continue
+		}
+		fields := perrors.Fields(err)
+		if len(fields) != len(tc.fields) {
+			t.Errorf("%s: expected fields %v, got %+v", tc.name, tc.fields, fields)
+			continue
+		}
+		for i, f := range tc.fields {
+			if fields[i].Field != f {
+				t.Errorf("%s: expected field %s, got %+v", tc.name, f, fields[i])
+			}
+		}
+	}
+
+	if app, _ := services.Applications.GetByID(ctx, "app_1"); app != nil {
+		t.Fatalf("expected invalid application not to be saved")
+	}
+}
@@ -0,0 +1,329 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+type sentSignal struct {
+	workflowID, name string
+	payload          ApprovalSignal
+}
+
+type fakeSignaler struct {
+	sent []sentSignal
+	err  error
+}
+
+func (f *fakeSignaler) Signal(_ context.Context, workflowID, signalName string, payload any) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentSignal{workflowID: workflowID, name: signalName, payload: payload.(ApprovalSignal)})
+	return nil
+}
+
+func newApprovalsFixture(t *testing.T) (*Services, *fakeSignaler) {
+	t.Helper()
+
+	signaler := &fakeSignaler{}
+	services := &Services{
+		Teams:        memoryrepo.NewTeamRepository(),
+		Applications: memoryrepo.NewApplicationRepository(),
+		Secrets:      memoryrepo.NewSecretRepository(),
+		Approvals:    memoryrepo.NewApprovalRepository(),
+		Signals:      signaler,
+	}
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	return services, signaler
+}
+
+func decommissioningRequest() ApprovalRequest {
+	return ApprovalRequest{
+		ID:           "decommission-app-1",
+		Type:         "ApplicationDecommissioning",
+		ResourceType: "Application",
+		ResourceID:   "app-1",
+		RolesAllowed: []string{RolePlatformAdmin},
+		Reason:       "app retired",
+		WorkflowID:   "application-decommissioning-app-1",
+		SignalName:   "DecommissioningApproval",
+	}
+}
+
+func TestApproveApplication_RecordsApproverAndRole(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "sam", Kind: auth.PrincipalUser, Roles: []string{RoleSecurityAdmin}})
+
+	pending, err := services.GetApproval(admin, ApplicationApprovalID("app-1"))
+	if err != nil || pending.State != domain.ApprovalStatePending || pending.TeamID != "team-1" {
+		t.Fatalf("expected pending application approval, got %+v (%v)", pending, err)
+	}
+
+	if err := services.ApproveApplication(admin, "app-1", "sam"); err != nil {
+		t.Fatalf("ApproveApplication failed: %v", err)
+	}
+
+	a, _ := services.GetApproval(admin, ApplicationApprovalID("app-1"))
+	if a.State != domain.ApprovalStateApproved || a.Decision == nil || a.Decision.By != "sam" || a.Decision.Role != RoleSecurityAdmin {
+		t.Fatalf("expected decision recorded, got %+v", a)
+	}
+	app, _ := services.GetApplication(admin, "app-1")
+	if app.State != domain.ApplicationStateApproved {
+		t.Fatalf("expected application Approved, got %q", app.State)
+	}
+}
+
+func TestApproveApproval_ApprovesApplicationWithComment(t *testing.T) {
+	services, signaler := newApprovalsFixture(t)
+	ctx := context.Background()
+
+	if err := services.ApproveApproval(ctx, ApplicationApprovalID("app-1"), "looks good", "pat"); err != nil {
+		t.Fatalf("ApproveApproval failed: %v", err)
+	}
+
+	app, _ := services.GetApplication(ctx, "app-1")
+	a, _ := services.GetApproval(ctx, ApplicationApprovalID("app-1"))
+	if app.State != domain.ApplicationStateApproved || a.Decision.Comment != "looks good" {
+		t.Fatalf("expected approved application and comment, got app=%q approval=%+v", app.State, a)
+	}
+	if len(signaler.sent) != 0 {
+		t.Fatalf("application approvals have no workflow, got %+v", signaler.sent)
+	}
+
+	// Una aprobación ya decidida no se vuelve a decidir.
+	if err := services.RejectApproval(ctx, ApplicationApprovalID("app-1"), "", "pat"); perrors.Code(err) != "approval_already_decided" {
+		t.Fatalf("expected approval_already_decided, got %v", err)
+	}
+}
+
+func TestRejectApproval_LeavesApplicationProposed(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	ctx := context.Background()
+
+	if err := services.RejectApproval(ctx, ApplicationApprovalID("app-1"), "missing owner", "pat"); err != nil {
+		t.Fatalf("RejectApproval failed: %v", err)
+	}
+
+	app, _ := services.GetApplication(ctx, "app-1")
+	a, _ := services.GetApproval(ctx, ApplicationApprovalID("app-1"))
+	if app.State != domain.ApplicationStateProposed || a.State != domain.ApprovalStateRejected || a.Decision.Approved {
+		t.Fatalf("expected rejected approval and Proposed app, got app=%q approval=%+v", app.State, a)
+	}
+}
+
+func TestApproveApproval_SignalsWorkflowWithDecision(t *testing.T) {
+	services, signaler := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "workflow-engine"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+
+	securityAdmin := auth.WithPrincipal(ctx, auth.Principal{Subject: "sam", Kind: auth.PrincipalUser, Roles: []string{RoleSecurityAdmin}})
+	if err := services.ApproveApproval(securityAdmin, "decommission-app-1", "", "sam"); perrors.KindOf(err) != perrors.KindForbidden {
+		t.Fatalf("expected forbidden for a role outside rolesAllowed, got %v", err)
+	}
+
+	platformAdmin := auth.WithPrincipal(ctx, auth.Principal{Subject: "pat", Kind: auth.PrincipalUser, Roles: []string{RolePlatformAdmin}})
+	if err := services.ApproveApproval(platformAdmin, "decommission-app-1", "ok to retire", "pat"); err != nil {
+		t.Fatalf("ApproveApproval failed: %v", err)
+	}
+
+	if len(signaler.sent) != 1 {
+		t.Fatalf("expected 1 signal, got %+v", signaler.sent)
+	}
+	got := signaler.sent[0]
+	if got.workflowID != "application-decommissioning-app-1" || got.name != "DecommissioningApproval" ||
+		!got.payload.Approved || got.payload.By != "pat" || got.payload.Role != RolePlatformAdmin || got.payload.Comment != "ok to retire" {
+		t.Fatalf("unexpected signal %+v", got)
+	}
+	a, _ := services.GetApproval(ctx, "decommission-app-1")
+	if a.State != domain.ApprovalStateApproved || a.Decision.Role != RolePlatformAdmin {
+		t.Fatalf("expected recorded decision, got %+v", a)
+	}
+}
+
+func TestDecideApproval_SignalFailureKeepsPending(t *testing.T) {
+	services, signaler := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "workflow-engine"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+
+	signaler.err = perrors.Conflict("workflow_not_running", "workflow is COMPLETED", nil)
+	if err := services.RejectApproval(ctx, "decommission-app-1", "", "pat"); perrors.Code(err) != "workflow_not_running" {
+		t.Fatalf("expected workflow_not_running, got %v", err)
+	}
+	a, _ := services.GetApproval(ctx, "decommission-app-1")
+	if a.State != domain.ApprovalStatePending || a.Decision != nil {
+		t.Fatalf("expected approval to stay Pending, got %+v", a)
+	}
+}
+
+func TestDecideApproval_ExpiredApprovalIsRejected(t *testing.T) {
+	services, signaler := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "workflow-engine"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+	a, _ := services.Approvals.GetByID(ctx, "decommission-app-1")
+	a.ExpiresAt = time.Now().Add(-time.Minute)
+	a.Metadata.Version++
+	_ = services.Approvals.Save(ctx, a)
+
+	// Las queries lo informan vencido sin necesidad de decidirlo.
+	if got, _ := services.GetApproval(ctx, "decommission-app-1"); got.State != domain.ApprovalStateExpired {
+		t.Fatalf("expected Expired in query, got %q", got.State)
+	}
+
+	if err := services.ApproveApproval(ctx, "decommission-app-1", "", "pat"); perrors.Code(err) != "approval_expired" {
+		t.Fatalf("expected approval_expired, got %v", err)
+	}
+	stored, _ := services.Approvals.GetByID(ctx, "decommission-app-1")
+	if stored.State != domain.ApprovalStateExpired || len(signaler.sent) != 0 {
+		t.Fatalf("expected stored Expired without signal, got %+v (%d signals)", stored, len(signaler.sent))
+	}
+}
+
+func TestCreateApprovalRequest_Validates(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		edit func(*ApprovalRequest)
+		code string
+	}{
+		{"unknown role", func(r *ApprovalRequest) { r.RolesAllowed = []string{"teamLead"} }, "invalid_approval_role"},
+		{"unknown resource type", func(r *ApprovalRequest) { r.ResourceType = "Team" }, "invalid_approval_resource_type"},
+		{"missing resource", func(r *ApprovalRequest) { r.ResourceID = "app-404" }, "application_not_found"},
+		{"invalid id", func(r *ApprovalRequest) { r.ID = "Bad ID" }, "invalid_arguments"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := decommissioningRequest()
+			tc.edit(&req)
+			if err := services.CreateApprovalRequest(ctx, req, "test"); perrors.Code(err) != tc.code {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "test"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "test"); perrors.Code(err) != "approval_already_exists" {
+		t.Fatalf("expected approval_already_exists, got %v", err)
+	}
+}
+
+func TestListApprovals_FiltersByRoleAndState(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "workflow-engine"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+
+	forSecurity, _ := services.ListApprovals(ctx, ApprovalFilter{Roles: []string{RoleSecurityAdmin}, State: domain.ApprovalStatePending})
+	if len(forSecurity) != 1 || forSecurity[0].ID != ApplicationApprovalID("app-1") {
+		t.Fatalf("securityAdmin should only see the application approval, got %+v", forSecurity)
+	}
+	forPlatform, _ := services.ListApprovals(ctx, ApprovalFilter{Roles: []string{RolePlatformAdmin}})
+	if len(forPlatform) != 2 {
+		t.Fatalf("platformAdmin should see both approvals, got %+v", forPlatform)
+	}
+	approved, _ := services.ListApprovals(ctx, ApprovalFilter{State: domain.ApprovalStateApproved})
+	if len(approved) != 0 {
+		t.Fatalf("expected no approved approvals, got %+v", approved)
+	}
+}
+
+func TestAuthorizer_ScopesApprovalReadsToTheOwningTeam(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-2", "Payments", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-2", "Ledger", "team-2", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	authz := NewAuthorizer(services, AuthorizerOptions{})
+	member := as(auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"team-1"}})
+
+	if _, err := authz.GetApproval(member, ApplicationApprovalID("app-1")); err != nil {
+		t.Fatalf("expected team member to read its approval, got %v", err)
+	}
+	if _, err := authz.GetApproval(member, ApplicationApprovalID("app-2")); perrors.KindOf(err) != perrors.KindForbidden {
+		t.Fatalf("expected forbidden for another team's approval, got %v", err)
+	}
+	if list, err := authz.ListApprovals(member, ApprovalFilter{}); err != nil || len(list) != 1 || list[0].TeamID != "team-1" {
+		t.Fatalf("expected only team-1 approvals, got %+v, %v", list, err)
+	}
+
+	security := as(auth.Principal{Subject: "sam", Kind: auth.PrincipalUser, Roles: []string{RoleSecurityAdmin}})
+	if list, _ := authz.ListApprovals(security, ApprovalFilter{}); len(list) != 2 {
+		t.Fatalf("expected approvers to see every approval, got %+v", list)
+	}
+	if _, err := authz.ListApprovals(context.Background(), ApprovalFilter{}); perrors.KindOf(err) != perrors.KindForbidden {
+		t.Fatalf("expected forbidden without principal, got %v", err)
+	}
+}
+
+// failingApprovals falla al guardar, como un storage caído.
+type failingApprovals struct {
+	*memoryrepo.ApprovalRepository
+}
+
+func (failingApprovals) Save(context.Context, *domain.Approval) error {
+	return errors.New("storage unavailable")
+}
+
+func TestCreateApplication_RemovesTheApplicationWhenItsApprovalCannotBeSaved(t *testing.T) {
+	services, _ := newApprovalsFixture(t)
+	services.Approvals = failingApprovals{ApprovalRepository: memoryrepo.NewApprovalRepository()}
+	ctx := context.Background()
+
+	if err := services.CreateApplication(ctx, "app-2", "Ledger", "team-1", "test"); err == nil {
+		t.Fatalf("expected CreateApplication to fail")
+	}
+	if app, _ := services.Applications.GetByID(ctx, "app-2"); app != nil {
+		t.Fatalf("expected the application to be removed, got %+v", app)
+	}
+}
+
+func TestRunBatch_AtomicRollbackDoesNotSignal(t *testing.T) {
+	services, signaler := newApprovalsFixture(t)
+	ctx := context.Background()
+	if err := services.CreateApprovalRequest(ctx, decommissioningRequest(), "workflow-engine"); err != nil {
+		t.Fatalf("CreateApprovalRequest failed: %v", err)
+	}
+
+	results, err := services.RunBatch(ctx, BatchAtomic, []BatchStep{
+		func(ctx context.Context, api API) error {
+			return api.ApproveApproval(ctx, "decommission-app-1", "", "pat")
+		},
+		func(context.Context, API) error { return errors.New("boom") },
+	})
+	if err != nil || !errors.Is(results[0], ErrBatchRolledBack) {
+		t.Fatalf("expected rolled back batch, got %v (%v)", results, err)
+	}
+	if len(signaler.sent) != 0 {
+		t.Fatalf("rolled back decision must not be signaled, got %+v", signaler.sent)
+	}
+	if a, _ := services.GetApproval(ctx, "decommission-app-1"); a.State != domain.ApprovalStatePending {
+		t.Fatalf("expected approval to stay Pending, got %q", a.State)
+	}
+}
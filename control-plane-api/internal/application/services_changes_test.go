@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+)
+
+func newChangesFixture(capacity int) (*Services, *memoryrepo.ChangeFeed) {
+	feed := memoryrepo.NewChangeFeed(capacity)
+	return &Services{
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		Environments:            memoryrepo.NewEnvironmentRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		Changes:                 feed,
+	}, feed
+}
+
+func drain(ch <-chan domain.ChangeEvent) []domain.ChangeEvent {
+	var out []domain.ChangeEvent
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return out
+			}
+			out = append(out, ev)
+		default:
+			return out
+		}
+	}
+}
+
+func TestServices_PublishChangesWithTeamAndApplication(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, err := services.WatchChanges(ctx, 0, 16)
+	if err != nil {
+		t.Fatalf("WatchChanges failed: %v", err)
+	}
+
+	_ = services.CreateTeam(ctx, "team-1", "Platform", "alice")
+	_ = services.CreateApplication(ctx, "app-1", "App", "team-1", "alice")
+	_ = services.CreateEnvironment(ctx, "dev", "Dev", "alice")
+	_ = services.DeclareApplicationEnvironment(ctx, "ae-1", "app-1", "dev", "alice")
+	if err := services.CompleteApplicationEnvironmentProvisioning(ctx, "ae-1", "workflow-engine"); err != nil {
+		t.Fatalf("CompleteApplicationEnvironmentProvisioning failed: %v", err)
+	}
+
+	events := drain(sub.Events)
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d: %+v", len(events), events)
+	}
+	last := events[4]
+	if last.ID != 5 || last.ResourceType != "ApplicationEnvironment" || last.Action != domain.ChangeActionTransitioned ||
+		last.State != string(domain.ApplicationEnvironmentStateActive) || last.TeamID != "team-1" ||
+		last.ApplicationID != "app-1" || last.By != "workflow-engine" || last.Version != 2 {
+		t.Fatalf("unexpected last event %+v", last)
+	}
+}
+
+func TestServices_WatchChangesResumesAndReportsGaps(t *testing.T) {
+	services, _ := newChangesFixture(3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		_ = services.CreateTeam(ctx, id, id, "alice")
+	}
+
+	// El feed retiene 3..5: reanudar desde 3 entrega 4 y 5 sin gap.
+	sub, _ := services.WatchChanges(ctx, 3, 8)
+	if events := drain(sub.Events); sub.Gap || len(events) != 2 || events[0].ID != 4 {
+		t.Fatalf("expected resume from 4 without gap, got gap=%v %+v", sub.Gap, events)
+	}
+
+	// Desde 1 se perdió el 2: se reporta gap y se reproduce lo retenido.
+	sub, _ = services.WatchChanges(ctx, 1, 8)
+	if events := drain(sub.Events); !sub.Gap || len(events) != 3 || events[0].ID != 3 {
+		t.Fatalf("expected gap and replay from 3, got gap=%v %+v", sub.Gap, events)
+	}
+
+	// Sin Last-Event-ID sólo llegan eventos nuevos.
+	sub, _ = services.WatchChanges(ctx, 0, 8)
+	if events := drain(sub.Events); len(events) != 0 {
+		t.Fatalf("expected no replay, got %+v", events)
+	}
+}
+
+func TestServices_WatchChangesDropsSlowSubscribers(t *testing.T) {
+	services, _ := newChangesFixture(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, _ := services.WatchChanges(ctx, 0, 1)
+	_ = services.CreateTeam(ctx, "t1", "t1", "alice")
+	_ = services.CreateTeam(ctx, "t2", "t2", "alice")
+
+	events := drain(sub.Events)
+	if len(events) != 1 {
+		t.Fatalf("expected only the buffered event, got %+v", events)
+	}
+	if _, ok := <-sub.Events; ok {
+		t.Fatalf("expected subscription to be closed after overflow")
+	}
+}
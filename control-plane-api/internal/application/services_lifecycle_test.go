@@ -6,6 +6,7 @@ import (
 
 	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
 	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
 )
 
 func TestApproveApplication_TransitionsProposedToApproved(t *testing.T) {
@@ -61,6 +62,7 @@ func TestApproveApplication_FailsFromNonProposed(t *testing.T) {
 		t.Fatalf("expected app, got err=%v app=%v", err, app)
 	}
 	app.State = domain.ApplicationStateActive
+	app.Metadata.Version++
 	if err := appRepo.Save(ctx, app); err != nil {
 		t.Fatalf("saving app failed: %v", err)
 	}
@@ -93,6 +95,7 @@ func TestDeprecateApplication_TransitionsActiveToDeprecated(t *testing.T) {
 		t.Fatalf("expected app, got err=%v app=%v", err, app)
 	}
 	app.State = domain.ApplicationStateActive
+	app.Metadata.Version++
 	if err := appRepo.Save(ctx, app); err != nil {
 		t.Fatalf("saving app failed: %v", err)
 	}
@@ -272,6 +275,7 @@ func TestStartSecretRotation_TransitionsActiveToRotating(t *testing.T) { //nolin
 		t.Fatalf("expected secret, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving secret failed: %v", err)
 	}
@@ -335,6 +339,7 @@ func TestCompleteSecretRotation_TransitionsRotatingToActive(t *testing.T) { //no
 		t.Fatalf("expected secret, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateRotating
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving secret failed: %v", err)
 	}
@@ -374,3 +379,113 @@ func TestCompleteSecretRotation_FailsFromNonRotating(t *testing.T) {
 		t.Fatalf("expected error when completing rotation from non-Rotating state, got nil")
 	}
 }
+
+func TestApplicationLifecycle_RecordsTransitionHistory(t *testing.T) {
+	teamRepo := memoryrepo.NewTeamRepository()
+	appRepo := memoryrepo.NewApplicationRepository()
+
+	services := &Services{
+		Teams:        teamRepo,
+		Applications: appRepo,
+	}
+
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "alice"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "App", "team-1", "alice"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	if err := services.ApproveApplication(ctx, "app-1", "bob"); err != nil {
+		t.Fatalf("ApproveApplication failed: %v", err)
+	}
+	if err := services.StartApplicationOnboarding(ctx, "app-1", "workflow-engine"); err != nil {
+		t.Fatalf("StartApplicationOnboarding failed: %v", err)
+	}
+
+	app, _ := appRepo.GetByID(ctx, "app-1")
+	if app.Metadata.CreatedBy != "alice" {
+		t.Fatalf("expected createdBy 'alice', got %q", app.Metadata.CreatedBy)
+	}
+
+	want := []domain.Transition{
+		{From: "Proposed", To: "Approved", By: "bob"},
+		{From: "Approved", To: "Onboarding", By: "workflow-engine"},
+	}
+	if len(app.Metadata.History) != len(want) {
+		t.Fatalf("expected %d transitions, got %+v", len(want), app.Metadata.History)
+	}
+	for i, w := range want {
+		got := app.Metadata.History[i]
+		if got.From != w.From || got.To != w.To || got.By != w.By || got.At.IsZero() {
+			t.Fatalf("transition %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+func TestApplicationTransition_RejectsStaleExpectedVersion(t *testing.T) {
+	services := &Services{
+		Teams:        memoryrepo.NewTeamRepository(),
+		Applications: memoryrepo.NewApplicationRepository(),
+	}
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	err := services.ApproveApplication(WithExpectedVersion(ctx, 2), "app-1", "alice")
+	if !perrors.IsKind(err, perrors.KindPreconditionFailed) {
+		t.Fatalf("expected precondition failed, got %v", err)
+	}
+
+	if err := services.ApproveApplication(WithExpectedVersion(ctx, 1), "app-1", "alice"); err != nil {
+		t.Fatalf("expected approval with current version, got %v", err)
+	}
+	app, _ := services.Applications.GetByID(ctx, "app-1")
+	if app.State != domain.ApplicationStateApproved || app.Metadata.Version != 2 {
+		t.Fatalf("expected Approved at version 2, got %s at %d", app.State, app.Metadata.Version)
+	}
+}
+
+// snapshotApplications devuelve siempre la misma lectura, como si otro
+// escritor hubiera guardado entre el GetByID y el Save.
+type snapshotApplications struct {
+	*memoryrepo.ApplicationRepository
+	snapshot *domain.Application
+}
+
+func (r snapshotApplications) GetByID(context.Context, string) (*domain.Application, error) {
+	copy := *r.snapshot
+	return &copy, nil
+}
+
+func TestApplicationTransition_SaveRejectsConcurrentWriterWithSameVersion(t *testing.T) {
+	apps := memoryrepo.NewApplicationRepository()
+	services := &Services{Teams: memoryrepo.NewTeamRepository(), Applications: apps}
+	ctx := context.Background()
+	if err := services.CreateTeam(ctx, "team-1", "Platform", "test"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "App", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	snapshot, _ := apps.GetByID(ctx, "app-1")
+
+	if err := services.ApproveApplication(WithExpectedVersion(ctx, 1), "app-1", "alice"); err != nil {
+		t.Fatalf("expected the first writer to succeed, got %v", err)
+	}
+
+	// El segundo escritor leyó la versión 1 antes de que se guardara la 2.
+	stale := &Services{Teams: services.Teams, Applications: snapshotApplications{ApplicationRepository: apps, snapshot: snapshot}}
+	err := stale.ApproveApplication(WithExpectedVersion(ctx, 1), "app-1", "bob")
+	if !perrors.IsKind(err, perrors.KindConflict) || perrors.Code(err) != perrors.CodeConcurrentModification {
+		t.Fatalf("expected concurrent_modification from Save, got %v", err)
+	}
+	app, _ := apps.GetByID(ctx, "app-1")
+	if app.Metadata.Version != 2 || app.Metadata.History[len(app.Metadata.History)-1].By != "alice" {
+		t.Fatalf("expected alice's write to be kept, got %+v", app.Metadata)
+	}
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func newOrganizationsFixture(t *testing.T) (*Services, context.Context, context.Context) {
+	t.Helper()
+	services := &Services{
+		Organizations:           memoryrepo.NewOrganizationRepository(),
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		Environments:            memoryrepo.NewEnvironmentRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		WebhookSubscriptions:    memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:       memoryrepo.NewWebhookDeliveryRepository(),
+		Changes:                 memoryrepo.NewChangeFeed(64),
+	}
+	ctx := context.Background()
+	for _, id := range []string{"acme", "globex"} {
+		if err := services.CreateOrganization(ctx, id, id, "root"); err != nil {
+			t.Fatalf("CreateOrganization %s failed: %v", id, err)
+		}
+	}
+	return services, domain.WithOrganization(ctx, "acme"), domain.WithOrganization(ctx, "globex")
+}
+
+func TestCreateOrganization_ValidatesAndRejectsDuplicates(t *testing.T) {
+	services, _, _ := newOrganizationsFixture(t)
+	ctx := context.Background()
+
+	for id, code := range map[string]string{"acme": "organization_already_exists", "default": "organization_already_exists", "Bad ID": "invalid_arguments"} {
+		if err := services.CreateOrganization(ctx, id, "x", "root"); perrors.Code(err) != code {
+			t.Errorf("%q: expected %s, got %v", id, code, err)
+		}
+	}
+	if _, err := services.GetOrganization(ctx, "missing"); perrors.Code(err) != "organization_not_found" {
+		t.Fatalf("expected organization_not_found, got %v", err)
+	}
+	// La default existe aunque nadie la haya creado.
+	if org, err := services.GetOrganization(ctx, domain.DefaultOrganizationID); err != nil || org.State != domain.OrganizationStateActive {
+		t.Fatalf("expected the implicit default organization, got %+v (%v)", org, err)
+	}
+}
+
+func TestServices_OrganizationsIsolateResources(t *testing.T) {
+	services, acme, globex := newOrganizationsFixture(t)
+
+	// Los mismos IDs conviven en dos organizaciones.
+	for _, ctx := range []context.Context{acme, globex} {
+		if err := services.CreateTeam(ctx, "team-1", "Payments", "root"); err != nil {
+			t.Fatalf("CreateTeam failed: %v", err)
+		}
+		if err := services.CreateEnvironment(ctx, "prod", "Prod", "root"); err != nil {
+			t.Fatalf("CreateEnvironment failed: %v", err)
+		}
+	}
+	env, _ := services.GetEnvironment(globex, "prod")
+	if env == nil || env.OrganizationID != "globex" {
+		t.Fatalf("expected prod of globex, got %+v", env)
+	}
+
+	if err := services.CreateTeam(acme, "team-2", "Ops", "root"); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	if err := services.CreateApplication(acme, "app-1", "billing", "team-2", "root"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	// Ningún comando alcanza recursos de otra organización.
+	if err := services.CreateApplication(globex, "app-2", "billing", "team-2", "root"); perrors.Code(err) != "team_not_found" {
+		t.Fatalf("expected team_not_found across organizations, got %v", err)
+	}
+	if err := services.DeclareApplicationEnvironment(globex, "ae-1", "app-1", "prod", "root"); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("expected not_found across organizations, got %v", err)
+	}
+	if _, err := services.GetApplication(globex, "app-1"); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("expected app-1 to be invisible from globex, got %v", err)
+	}
+
+	if err := services.CreateTeam(domain.WithOrganization(context.Background(), "missing"), "team-1", "x", "root"); perrors.Code(err) != "organization_not_found" {
+		t.Fatalf("expected organization_not_found, got %v", err)
+	}
+}
+
+func TestServices_WatchChangesOnlyDeliversOwnOrganization(t *testing.T) {
+	services, acme, globex := newOrganizationsFixture(t)
+	ctx, cancel := context.WithCancel(acme)
+	defer cancel()
+
+	sub, err := services.WatchChanges(ctx, 0, 8)
+	if err != nil {
+		t.Fatalf("WatchChanges failed: %v", err)
+	}
+	_ = services.CreateTeam(globex, "team-1", "Globex", "root")
+	_ = services.CreateTeam(acme, "team-1", "Acme", "root")
+
+	events := drain(sub.Events)
+	if len(events) != 1 || events[0].OrganizationID != "acme" || events[0].ResourceID != "team-1" {
+		t.Fatalf("expected only the acme event, got %+v", events)
+	}
+}
+
+func TestWebhookDispatcher_MatchesSubscriptionsOfTheEventOrganization(t *testing.T) {
+	services, acme, globex := newOrganizationsFixture(t)
+	for _, ctx := range []context.Context{acme, globex} {
+		_ = services.CreateTeam(ctx, "team-1", "Payments", "root")
+		if err := services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "root"); err != nil {
+			t.Fatalf("CreateWebhookSubscription failed: %v", err)
+		}
+	}
+
+	sender := &fakeWebhookSender{statuses: []int{200}}
+	dispatcher := NewWebhookDispatcher(services, sender, WebhookDispatcherOptions{})
+	ev := domain.ChangeEvent{ID: 42, OrganizationID: "acme", ResourceType: "Team", ResourceID: "team-1", TeamID: "team-1", Action: domain.ChangeActionCreated, At: time.Now()}
+	if err := dispatcher.Enqueue(context.Background(), ev); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if n, err := dispatcher.DeliverDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected one due delivery, got %d (%v)", n, err)
+	}
+
+	acmeDeliveries, _ := services.ListWebhookDeliveries(acme, "wh-1", domain.WebhookDeliveryStateSucceeded)
+	globexDeliveries, _ := services.ListWebhookDeliveries(globex, "wh-1", "")
+	if len(acmeDeliveries) != 1 || len(globexDeliveries) != 0 {
+		t.Fatalf("expected a single succeeded delivery in acme, got acme=%+v globex=%+v", acmeDeliveries, globexDeliveries)
+	}
+}
+
+func TestAuthorizer_BindsPrincipalsToTheirOrganization(t *testing.T) {
+	services, _, _ := newOrganizationsFixture(t)
+	authz := NewAuthorizer(services, AuthorizerOptions{})
+	acmeAdmin := auth.Principal{Subject: "pat", Kind: auth.PrincipalUser, Roles: []string{RolePlatformAdmin}, Organization: "acme"}
+	installAdmin := auth.Principal{Subject: "root", Kind: auth.PrincipalUser, Roles: []string{RolePlatformAdmin}}
+
+	in := func(p auth.Principal, org string) context.Context {
+		ctx, err := OrganizationScope(auth.WithPrincipal(context.Background(), p), org)
+		if err != nil {
+			t.Fatalf("OrganizationScope failed: %v", err)
+		}
+		return ctx
+	}
+
+	// Sin organización pedida, el principal opera en la suya.
+	if err := authz.CreateTeam(in(acmeAdmin, ""), "team-1", "Payments", "pat"); err != nil {
+		t.Fatalf("CreateTeam in own organization failed: %v", err)
+	}
+	if err := authz.CreateTeam(in(acmeAdmin, "globex"), "team-1", "Payments", "pat"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden in another organization, got %v", err)
+	}
+	if _, err := authz.GetOrganization(in(acmeAdmin, ""), "globex"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden reading another organization, got %v", err)
+	}
+	if err := authz.CreateOrganization(in(acmeAdmin, ""), "initech", "Initech", "pat"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected install-wide commands to be forbidden to org-bound principals, got %v", err)
+	}
+
+	// Un principal sin organización puede operar en cualquiera.
+	if err := authz.CreateTeam(in(installAdmin, "globex"), "team-1", "Payments", "root"); err != nil {
+		t.Fatalf("CreateTeam as install admin failed: %v", err)
+	}
+	if err := authz.CreateOrganization(in(installAdmin, ""), "initech", "Initech", "root"); err != nil {
+		t.Fatalf("CreateOrganization as install admin failed: %v", err)
+	}
+}
+
+func TestAuthorizer_TeamMembershipIsScopedToThePrincipalOrganization(t *testing.T) {
+	services, acme, globex := newOrganizationsFixture(t)
+	for _, ctx := range []context.Context{acme, globex} {
+		if err := services.CreateTeam(ctx, "payments", "Payments", "root"); err != nil {
+			t.Fatalf("CreateTeam failed: %v", err)
+		}
+	}
+	authz := NewAuthorizer(services, AuthorizerOptions{})
+
+	in := func(p auth.Principal, org string) context.Context {
+		ctx, err := OrganizationScope(auth.WithPrincipal(context.Background(), p), org)
+		if err != nil {
+			t.Fatalf("OrganizationScope failed: %v", err)
+		}
+		return ctx
+	}
+	acmeMember := auth.Principal{Subject: "ana", Kind: auth.PrincipalUser, Groups: []string{"payments"}, Organization: "acme"}
+	unboundMember := auth.Principal{Subject: "ben", Kind: auth.PrincipalUser, Groups: []string{"payments"}}
+
+	if err := authz.CreateApplication(in(acmeMember, ""), "app-1", "App", "payments", "ana"); err != nil {
+		t.Fatalf("CreateApplication in own organization failed: %v", err)
+	}
+	if err := authz.CreateApplication(in(acmeMember, "globex"), "app-1", "App", "payments", "ana"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden on the payments team of another organization, got %v", err)
+	}
+	// Sin organización en el token, los grupos sólo cuentan en la default.
+	if err := authz.CreateApplication(in(unboundMember, "globex"), "app-2", "App", "payments", "ben"); !perrors.IsKind(err, perrors.KindForbidden) {
+		t.Fatalf("expected forbidden for an unbound team member, got %v", err)
+	}
+	if app, _ := services.Applications.GetByID(globex, "app-1"); app != nil {
+		t.Fatalf("expected no application in globex, got %+v", app)
+	}
+}
+
+func TestOrganizationScope_RejectsInvalidIDs(t *testing.T) {
+	if _, err := OrganizationScope(context.Background(), "Not Valid!"); !perrors.IsKind(err, perrors.KindValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	ctx, err := OrganizationScope(context.Background(), "")
+	if err != nil || domain.OrganizationFromContext(ctx) != domain.DefaultOrganizationID {
+		t.Fatalf("expected the default organization, got %q (%v)", domain.OrganizationFromContext(ctx), err)
+	}
+}
@@ -0,0 +1,279 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func newQuotaServices(t *testing.T) (*Services, *memoryrepo.SecretRepository) {
+	t.Helper()
+	secretRepo := memoryrepo.NewSecretRepository()
+	services := &Services{
+		Teams:                   memoryrepo.NewTeamRepository(),
+		Applications:            memoryrepo.NewApplicationRepository(),
+		Environments:            memoryrepo.NewEnvironmentRepository(),
+		ApplicationEnvironments: memoryrepo.NewApplicationEnvironmentRepository(),
+		Secrets:                 secretRepo,
+		SecretBindings:          memoryrepo.NewSecretBindingRepository(),
+	}
+	ctx := context.Background()
+	for _, team := range []string{"team-1", "team-2"} {
+		if err := services.CreateTeam(ctx, team, "Team "+team, "test"); err != nil {
+			t.Fatalf("CreateTeam %s failed: %v", team, err)
+		}
+	}
+	return services, secretRepo
+}
+
+func TestCreateApplication_EnforcesTeamQuota(t *testing.T) {
+	services, _ := newQuotaServices(t)
+	ctx := context.Background()
+
+	if err := services.SetTeamQuota(ctx, "team-1", domain.TeamQuota{MaxApplications: 1}, "admin"); err != nil {
+		t.Fatalf("SetTeamQuota failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "billing", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication app-1 failed: %v", err)
+	}
+	err := services.CreateApplication(ctx, "app-2", "ledger", "team-1", "test")
+	if perrors.Code(err) != "quota_exceeded" || !perrors.IsKind(err, perrors.KindDomain) {
+		t.Fatalf("expected quota_exceeded domain error, got %v", err)
+	}
+
+	// La cuota es por team: team-2 sigue con la política por defecto.
+	if err := services.CreateApplication(ctx, "app-2", "ledger", "team-2", "test"); err != nil {
+		t.Fatalf("CreateApplication in team-2 failed: %v", err)
+	}
+
+	// Un límite en 0 vuelve a la política por defecto.
+	if err := services.SetTeamQuota(ctx, "team-1", domain.TeamQuota{}, "admin"); err != nil {
+		t.Fatalf("SetTeamQuota reset failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-3", "ledger", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication after reset failed: %v", err)
+	}
+}
+
+// slowApplications demora la respuesta de ListByTeam para que las creaciones
+// concurrentes lean el uso de la cuota antes de que alguna guarde.
+type slowApplications struct {
+	*memoryrepo.ApplicationRepository
+}
+
+func (s slowApplications) ListByTeam(ctx context.Context, teamID string) ([]*domain.Application, error) {
+	apps, err := s.ApplicationRepository.ListByTeam(ctx, teamID)
+	time.Sleep(10 * time.Millisecond)
+	return apps, err
+}
+
+func TestCreateApplication_ConcurrentCreatesRespectTeamQuota(t *testing.T) {
+	services, _ := newQuotaServices(t)
+	services.Applications = slowApplications{services.Applications.(*memoryrepo.ApplicationRepository)}
+	ctx := context.Background()
+	if err := services.SetTeamQuota(ctx, "team-1", domain.TeamQuota{MaxApplications: 1}, "admin"); err != nil {
+		t.Fatalf("SetTeamQuota failed: %v", err)
+	}
+
+	const n = 8
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := "app-" + strconv.Itoa(i)
+			errs[i] = services.CreateApplication(ctx, id, id, "team-1", "test")
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case perrors.Code(err) != "quota_exceeded":
+			t.Fatalf("expected quota_exceeded, got %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly one application to be created, got %d", created)
+	}
+}
+
+// failingTeams falla GetByID una vez activado, para simular un repositorio
+// caído después de crear los recursos.
+type failingTeams struct {
+	*memoryrepo.TeamRepository
+	fail bool
+}
+
+func (f *failingTeams) GetByID(ctx context.Context, id string) (*domain.Team, error) {
+	if f.fail {
+		return nil, errors.New("connection refused")
+	}
+	return f.TeamRepository.GetByID(ctx, id)
+}
+
+func TestQuotas_TeamRepositoryErrorIsNotTheDefaultPolicy(t *testing.T) {
+	services, secretRepo := newQuotaServices(t)
+	teams := &failingTeams{TeamRepository: services.Teams.(*memoryrepo.TeamRepository)}
+	services.Teams = teams
+	ctx := context.Background()
+
+	if err := services.CreateSecret(ctx, "sec-1", "team-1", "runtime", "high", "test"); err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+	sec, _ := secretRepo.GetByID(ctx, "sec-1")
+	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
+	if err := secretRepo.Save(ctx, sec); err != nil {
+		t.Fatalf("saving secret failed: %v", err)
+	}
+
+	teams.fail = true
+	err := services.DeclareSecretBinding(ctx, "bind-1", "sec-1", "target-1", "CodeRepository", "test")
+	if perrors.Code(err) != "team_repository_error" || !perrors.IsKind(err, perrors.KindInternal) {
+		t.Fatalf("expected team_repository_error, got %v", err)
+	}
+	if binding, _ := services.SecretBindings.GetByID(ctx, "bind-1"); binding != nil {
+		t.Fatalf("expected no binding to be saved, got %+v", binding)
+	}
+}
+
+func TestQuotas_LimitSecretsBindingsAndEnvironments(t *testing.T) {
+	services, secretRepo := newQuotaServices(t)
+	services.DefaultQuota = domain.TeamQuota{MaxSecrets: 1, MaxBindingsPerSecret: 1, MaxEnvironmentsPerApplication: 1}
+	ctx := context.Background()
+
+	if err := services.CreateSecret(ctx, "sec-1", "team-1", "runtime", "high", "test"); err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+	if err := services.CreateSecret(ctx, "sec-2", "team-1", "runtime", "high", "test"); perrors.Code(err) != "quota_exceeded" {
+		t.Fatalf("expected quota_exceeded for second secret, got %v", err)
+	}
+
+	sec, _ := secretRepo.GetByID(ctx, "sec-1")
+	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
+	if err := secretRepo.Save(ctx, sec); err != nil {
+		t.Fatalf("saving secret failed: %v", err)
+	}
+	if err := services.DeclareSecretBinding(ctx, "bind-1", "sec-1", "target-1", "CodeRepository", "test"); err != nil {
+		t.Fatalf("DeclareSecretBinding failed: %v", err)
+	}
+	if err := services.DeclareSecretBinding(ctx, "bind-2", "sec-1", "target-2", "CodeRepository", "test"); perrors.Code(err) != "quota_exceeded" {
+		t.Fatalf("expected quota_exceeded for second binding, got %v", err)
+	}
+
+	if err := services.CreateApplication(ctx, "app-1", "billing", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	for _, env := range []string{"dev", "prod"} {
+		if err := services.CreateEnvironment(ctx, env, env, "test"); err != nil {
+			t.Fatalf("CreateEnvironment %s failed: %v", env, err)
+		}
+	}
+	if err := services.DeclareApplicationEnvironment(ctx, "app-1-dev", "app-1", "dev", "test"); err != nil {
+		t.Fatalf("DeclareApplicationEnvironment failed: %v", err)
+	}
+	if err := services.DeclareApplicationEnvironment(ctx, "app-1-prod", "app-1", "prod", "test"); perrors.Code(err) != "quota_exceeded" {
+		t.Fatalf("expected quota_exceeded for second environment, got %v", err)
+	}
+
+	// Un secreto revocado libera su lugar.
+	sec.State = domain.SecretStateRevoked
+	sec.Metadata.Version++
+	if err := secretRepo.Save(ctx, sec); err != nil {
+		t.Fatalf("saving secret failed: %v", err)
+	}
+	if err := services.CreateSecret(ctx, "sec-2", "team-1", "runtime", "high", "test"); err != nil {
+		t.Fatalf("CreateSecret after revoking sec-1 failed: %v", err)
+	}
+}
+
+func TestGetTeamQuota_ReportsUsageAgainstLimits(t *testing.T) {
+	services, _ := newQuotaServices(t)
+	ctx := context.Background()
+
+	if err := services.SetTeamQuota(ctx, "team-1", domain.TeamQuota{MaxApplications: 5}, "admin"); err != nil {
+		t.Fatalf("SetTeamQuota failed: %v", err)
+	}
+	if err := services.CreateApplication(ctx, "app-1", "billing", "team-1", "test"); err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	if err := services.CreateEnvironment(ctx, "dev", "dev", "test"); err != nil {
+		t.Fatalf("CreateEnvironment failed: %v", err)
+	}
+	if err := services.DeclareApplicationEnvironment(ctx, "app-1-dev", "app-1", "dev", "test"); err != nil {
+		t.Fatalf("DeclareApplicationEnvironment failed: %v", err)
+	}
+	if err := services.CreateSecret(ctx, "sec-1", "team-1", "runtime", "high", "test"); err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+
+	usage, err := services.GetTeamQuota(ctx, "team-1")
+	if err != nil {
+		t.Fatalf("GetTeamQuota failed: %v", err)
+	}
+	defaults := domain.DefaultTeamQuota()
+	if usage.Applications != (domain.QuotaUsage{Used: 1, Limit: 5}) {
+		t.Fatalf("unexpected applications usage %+v", usage.Applications)
+	}
+	if usage.Secrets != (domain.QuotaUsage{Used: 1, Limit: defaults.MaxSecrets}) {
+		t.Fatalf("unexpected secrets usage %+v", usage.Secrets)
+	}
+	if got := usage.EnvironmentsPerApplication["app-1"]; got != (domain.QuotaUsage{Used: 1, Limit: defaults.MaxEnvironmentsPerApplication}) {
+		t.Fatalf("unexpected environments usage %+v", got)
+	}
+	if got := usage.BindingsPerSecret["sec-1"]; got != (domain.QuotaUsage{Used: 0, Limit: defaults.MaxBindingsPerSecret}) {
+		t.Fatalf("unexpected bindings usage %+v", got)
+	}
+
+	if _, err := services.GetTeamQuota(ctx, "missing"); !perrors.IsKind(err, perrors.KindNotFound) {
+		t.Fatalf("expected not found for unknown team, got %v", err)
+	}
+}
+
+func TestSetTeamQuota_RejectsNegativeLimits(t *testing.T) {
+	services, _ := newQuotaServices(t)
+
+	err := services.SetTeamQuota(context.Background(), "team-1", domain.TeamQuota{MaxSecrets: -1}, "admin")
+	if perrors.Code(err) != "invalid_arguments" {
+		t.Fatalf("expected invalid_arguments, got %v", err)
+	}
+}
+
+func TestRunBatch_AtomicCountsStagedResourcesTowardQuota(t *testing.T) {
+	services, _ := newQuotaServices(t)
+	ctx := context.Background()
+	if err := services.SetTeamQuota(ctx, "team-1", domain.TeamQuota{MaxApplications: 1}, "admin"); err != nil {
+		t.Fatalf("SetTeamQuota failed: %v", err)
+	}
+
+	errs, err := services.RunBatch(ctx, BatchAtomic, []BatchStep{
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-1", "billing", "team-1", "test")
+		},
+		func(ctx context.Context, api API) error {
+			return api.CreateApplication(ctx, "app-2", "ledger", "team-1", "test")
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	if perrors.Code(errs[1]) != "quota_exceeded" {
+		t.Fatalf("expected second command to exceed the quota, got %v", errs)
+	}
+	if app, _ := services.Applications.GetByID(ctx, "app-1"); app != nil {
+		t.Fatalf("expected app-1 to be rolled back, got %+v", app)
+	}
+}
@@ -84,6 +84,7 @@ func TestDeclareSecretBinding_RequiresActiveSecret(t *testing.T) {
 		t.Fatalf("expected secret to exist, got err=%v sec=%v", err, sec)
 	}
 	sec.State = domain.SecretStateActive
+	sec.Metadata.Version++
 	if err := secretRepo.Save(ctx, sec); err != nil {
 		t.Fatalf("saving updated secret failed: %v", err)
 	}
@@ -0,0 +1,303 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/adapters/memoryrepo"
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+type fakeWebhookSender struct {
+	mu       sync.Mutex
+	statuses []int
+	sent     []string
+}
+
+func (f *fakeWebhookSender) Send(_ context.Context, _ *domain.WebhookSubscription, d *domain.WebhookDelivery) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, d.ID)
+	if len(f.statuses) == 0 {
+		return 0, errors.New("connection refused")
+	}
+	status := f.statuses[0]
+	f.statuses = f.statuses[1:]
+	return status, nil
+}
+
+func newWebhookFixture(t *testing.T) (*Services, *fakeWebhookSender, *WebhookDispatcher, *time.Time) {
+	t.Helper()
+	services := &Services{
+		Teams:                memoryrepo.NewTeamRepository(),
+		WebhookSubscriptions: memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:    memoryrepo.NewWebhookDeliveryRepository(),
+	}
+	ctx := context.Background()
+	_ = services.CreateTeam(ctx, "team-1", "Platform", "alice")
+
+	sender := &fakeWebhookSender{}
+	dispatcher := NewWebhookDispatcher(services, sender, WebhookDispatcherOptions{
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     90 * time.Second,
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	return services, sender, dispatcher, &now
+}
+
+func TestCreateWebhookSubscription_Validates(t *testing.T) {
+	services, _, _, _ := newWebhookFixture(t)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		team, url, secret string
+		code              string
+	}{
+		"relative url": {"team-1", "/hooks", testWebhookSecret, "invalid_webhook_url"},
+		"bad scheme":   {"team-1", "ftp://example.com/hooks", testWebhookSecret, "invalid_webhook_url"},
+		"plain http":   {"team-1", "http://example.com/hooks", testWebhookSecret, "invalid_webhook_url"},
+		"short secret": {"team-1", "https://example.com/hooks", "short", "invalid_webhook_secret"},
+		"unknown team": {"team-x", "https://example.com/hooks", testWebhookSecret, "team_not_found"},
+	}
+	for name, tc := range cases {
+		err := services.CreateWebhookSubscription(ctx, "wh-"+strings.ReplaceAll(name, " ", "-"), tc.team, tc.url, tc.secret, domain.WebhookFilter{}, "alice")
+		if perrors.Code(err) != tc.code {
+			t.Errorf("%s: expected %s, got %v", name, tc.code, err)
+		}
+	}
+
+	if err := services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice"); err != nil {
+		t.Fatalf("CreateWebhookSubscription failed: %v", err)
+	}
+	sub, _ := services.GetWebhookSubscription(ctx, "wh-1")
+	if sub.State != domain.WebhookSubscriptionStateActive || sub.Metadata.Version != 1 {
+		t.Fatalf("unexpected subscription %+v", sub)
+	}
+
+	// En modo dev se aceptan receptores http.
+	services.AllowInsecureWebhooks = true
+	if err := services.CreateWebhookSubscription(ctx, "wh-2", "team-1", "http://localhost:9000/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice"); err != nil {
+		t.Fatalf("CreateWebhookSubscription over http in dev mode failed: %v", err)
+	}
+}
+
+func TestWebhookDispatcher_EnqueuesOnlyMatchingTeamEvents(t *testing.T) {
+	services, _, dispatcher, _ := newWebhookFixture(t)
+	ctx := context.Background()
+	_ = services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret,
+		domain.WebhookFilter{ResourceTypes: []string{"ApplicationEnvironment"}, Actions: []domain.ChangeAction{domain.ChangeActionTransitioned}}, "alice")
+
+	events := []domain.ChangeEvent{
+		{ID: 1, ResourceType: "ApplicationEnvironment", TeamID: "team-1", Action: domain.ChangeActionTransitioned},
+		{ID: 2, ResourceType: "ApplicationEnvironment", TeamID: "team-2", Action: domain.ChangeActionTransitioned},
+		{ID: 3, ResourceType: "Application", TeamID: "team-1", Action: domain.ChangeActionTransitioned},
+		{ID: 4, ResourceType: "ApplicationEnvironment", TeamID: "team-1", Action: domain.ChangeActionCreated},
+	}
+	for _, ev := range events {
+		if err := dispatcher.Enqueue(ctx, ev); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	// Reprocesar un evento no duplica la entrega.
+	_ = dispatcher.Enqueue(ctx, events[0])
+
+	deliveries, err := services.ListWebhookDeliveries(ctx, "wh-1", "")
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries failed: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != "wh-1-1" {
+		t.Fatalf("expected only delivery wh-1-1, got %+v", deliveries)
+	}
+}
+
+func TestWebhookDispatcher_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	services, sender, dispatcher, now := newWebhookFixture(t)
+	ctx := context.Background()
+	_ = services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice")
+	_ = dispatcher.Enqueue(ctx, domain.ChangeEvent{ID: 7, ResourceType: "Team", TeamID: "team-1"})
+
+	sender.statuses = []int{http500}
+	if n, _ := dispatcher.DeliverDue(ctx); n != 1 {
+		t.Fatalf("expected 1 due delivery, got %d", n)
+	}
+	d, _ := services.WebhookDeliveries.GetByID(ctx, "wh-1-7")
+	if d.State != domain.WebhookDeliveryStatePending || !d.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected retry in 1m, got %+v", d)
+	}
+
+	// Antes del backoff no hay nada vencido.
+	if n, _ := dispatcher.DeliverDue(ctx); n != 0 {
+		t.Fatalf("expected no due deliveries before backoff, got %d", n)
+	}
+
+	// Segundo fallo: el backoff se duplica pero queda acotado por MaxBackoff.
+	*now = now.Add(time.Minute)
+	_, _ = dispatcher.DeliverDue(ctx)
+	d, _ = services.WebhookDeliveries.GetByID(ctx, "wh-1-7")
+	if !d.NextAttemptAt.Equal(now.Add(90 * time.Second)) {
+		t.Fatalf("expected retry capped at 90s, got %v", d.NextAttemptAt.Sub(*now))
+	}
+
+	*now = now.Add(90 * time.Second)
+	_, _ = dispatcher.DeliverDue(ctx)
+	dead, _ := services.ListWebhookDeliveries(ctx, "wh-1", domain.WebhookDeliveryStateDeadLettered)
+	if len(dead) != 1 || len(dead[0].Attempts) != 3 || dead[0].Attempts[0].StatusCode != http500 {
+		t.Fatalf("expected dead-lettered delivery with 3 attempts, got %+v", dead)
+	}
+
+	// Re-entrega manual: nuevo ciclo y éxito.
+	if err := services.RedeliverWebhookDelivery(ctx, "wh-1-7", "alice"); err != nil {
+		t.Fatalf("RedeliverWebhookDelivery failed: %v", err)
+	}
+	if err := services.RedeliverWebhookDelivery(ctx, "wh-1-7", "alice"); perrors.Code(err) != "webhook_delivery_invalid_state_for_redelivery" {
+		t.Fatalf("expected invalid state on pending redelivery, got %v", err)
+	}
+	sender.statuses = []int{204}
+	*now = now.Add(time.Hour)
+	_, _ = dispatcher.DeliverDue(ctx)
+	d, _ = services.WebhookDeliveries.GetByID(ctx, "wh-1-7")
+	if d.State != domain.WebhookDeliveryStateSucceeded || len(d.Attempts) != 4 || d.RedeliveredBy != "alice" {
+		t.Fatalf("expected succeeded after redelivery, got %+v", d)
+	}
+	if len(sender.sent) != 4 {
+		t.Fatalf("expected 4 sends, got %d", len(sender.sent))
+	}
+}
+
+func TestWebhookDispatcher_DeadLettersDeliveriesOfDisabledSubscriptions(t *testing.T) {
+	services, sender, dispatcher, _ := newWebhookFixture(t)
+	ctx := context.Background()
+	_ = services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice")
+	_ = dispatcher.Enqueue(ctx, domain.ChangeEvent{ID: 1, ResourceType: "Team", TeamID: "team-1"})
+
+	if err := services.DisableWebhookSubscription(ctx, "wh-1", "alice"); err != nil {
+		t.Fatalf("DisableWebhookSubscription failed: %v", err)
+	}
+	_, _ = dispatcher.DeliverDue(ctx)
+
+	d, _ := services.WebhookDeliveries.GetByID(ctx, "wh-1-1")
+	if d.State != domain.WebhookDeliveryStateDeadLettered || len(sender.sent) != 0 {
+		t.Fatalf("expected dead-lettered without sending, got %+v (sent %v)", d, sender.sent)
+	}
+}
+
+// failingDeliveries falla al guardar la entrega failID.
+type failingDeliveries struct {
+	*memoryrepo.WebhookDeliveryRepository
+	failID string
+}
+
+func (r failingDeliveries) Save(ctx context.Context, d *domain.WebhookDelivery) error {
+	if d.ID == r.failID && len(d.Attempts) > 0 {
+		return errors.New("storage unavailable")
+	}
+	return r.WebhookDeliveryRepository.Save(ctx, d)
+}
+
+func TestWebhookDispatcher_KeepsDeliveringAfterASaveError(t *testing.T) {
+	services, sender, dispatcher, _ := newWebhookFixture(t)
+	services.WebhookDeliveries = failingDeliveries{WebhookDeliveryRepository: memoryrepo.NewWebhookDeliveryRepository(), failID: "wh-1-1"}
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	dispatcher.opts.OnError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	ctx := context.Background()
+	_ = services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice")
+	for _, id := range []int64{1, 2, 3} {
+		_ = dispatcher.Enqueue(ctx, domain.ChangeEvent{ID: id, ResourceType: "Team", TeamID: "team-1"})
+	}
+
+	sender.statuses = []int{204, 204, 204}
+	if n, err := dispatcher.DeliverDue(ctx); n != 3 || err != nil {
+		t.Fatalf("expected 3 deliveries without error, got %d, %v", n, err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "wh-1-1") {
+		t.Fatalf("expected one reported error for wh-1-1, got %v", errs)
+	}
+	for _, id := range []string{"wh-1-2", "wh-1-3"} {
+		if d, _ := services.WebhookDeliveries.GetByID(ctx, id); d.State != domain.WebhookDeliveryStateSucceeded {
+			t.Fatalf("expected %s to succeed, got %+v", id, d)
+		}
+	}
+}
+
+// blockingWebhookSender avisa en started y no responde hasta que se cierra
+// release.
+type blockingWebhookSender struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s blockingWebhookSender) Send(ctx context.Context, _ *domain.WebhookSubscription, _ *domain.WebhookDelivery) (int, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	select {
+	case <-s.release:
+		return 204, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestWebhookDispatcher_RunKeepsConsumingTheFeedWhileADeliveryIsSlow(t *testing.T) {
+	feed := memoryrepo.NewChangeFeed(16)
+	services := &Services{
+		Teams:                memoryrepo.NewTeamRepository(),
+		WebhookSubscriptions: memoryrepo.NewWebhookSubscriptionRepository(),
+		WebhookDeliveries:    memoryrepo.NewWebhookDeliveryRepository(),
+		Changes:              feed,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = services.CreateTeam(ctx, "team-1", "Platform", "alice")
+	_ = services.CreateWebhookSubscription(ctx, "wh-1", "team-1", "https://example.com/hooks", testWebhookSecret, domain.WebhookFilter{}, "alice")
+
+	sender := blockingWebhookSender{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(sender.release)
+	dispatcher := NewWebhookDispatcher(services, sender, WebhookDispatcherOptions{PollInterval: time.Millisecond})
+	_ = dispatcher.Enqueue(ctx, domain.ChangeEvent{ID: 100, ResourceType: "Team", TeamID: "team-1"})
+	go func() { _ = dispatcher.Run(ctx) }()
+
+	select {
+	case <-sender.started:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the due delivery to be sent")
+	}
+	// Con la entrega colgada, los eventos nuevos se siguen encolando. Run se
+	// suscribe en paralelo, así que se publica hasta que alguno llega.
+	waitFor(t, func() bool {
+		feed.Publish(ctx, domain.ChangeEvent{ResourceType: "Team", ResourceID: "team-1", TeamID: "team-1"})
+		all, _ := services.WebhookDeliveries.ListBySubscription(ctx, "wh-1")
+		return len(all) > 1
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// http500 evita importar net/http en los tests de la capa de aplicación.
+const http500 = 500
@@ -0,0 +1,446 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nuevo-idp/control-plane-api/internal/domain"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/validation"
+)
+
+// minWebhookSecretLength evita secretos triviales para la firma HMAC.
+const minWebhookSecretLength = 16
+
+type WebhookSubscriptionRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	Save(ctx context.Context, sub *domain.WebhookSubscription) error
+}
+
+type WebhookDeliveryRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// ListBySubscription devuelve las entregas de una suscripción, de la más
+	// vieja a la más nueva.
+	ListBySubscription(ctx context.Context, subscriptionID string) ([]*domain.WebhookDelivery, error)
+	// ListDue devuelve hasta limit entregas Pending con NextAttemptAt <= now.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	Save(ctx context.Context, d *domain.WebhookDelivery) error
+}
+
+// WebhookSender hace un intento de entrega firmado con el secreto de la
+// suscripción. Devuelve el status HTTP de la respuesta (0 si no hubo
+// respuesta); cualquier status fuera de 2xx se trata como fallo.
+type WebhookSender interface {
+	Send(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) (int, error)
+}
+
+func (s *Services) CreateWebhookSubscription(ctx context.Context, id, teamID, rawURL, secret string, filter domain.WebhookFilter, createdBy string) error {
+	if s.WebhookSubscriptions == nil || s.Teams == nil {
+		return perrors.Internal("repositories_not_configured", "repositories not configured", nil)
+	}
+
+	if err := validation.New().ID("id", id).ID("teamId", teamID).Err(); err != nil {
+		return err
+	}
+
+	if existing, _ := s.WebhookSubscriptions.GetByID(ctx, id); existing != nil {
+		return perrors.Conflict("webhook_subscription_already_exists", "webhook subscription already exists", nil)
+	}
+
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return perrors.Validation("invalid_webhook_url", "webhook url must be an absolute http(s) URL", err)
+	} else if u.Scheme != "https" && !s.AllowInsecureWebhooks {
+		return perrors.Validation("invalid_webhook_url", "webhook url must use https", nil)
+	}
+	if len(secret) < minWebhookSecretLength {
+		return perrors.Validation("invalid_webhook_secret", fmt.Sprintf("webhook secret must have at least %d characters", minWebhookSecretLength), nil)
+	}
+
+	team, err := s.Teams.GetByID(ctx, teamID)
+	if err != nil || team == nil {
+		return perrors.NotFound("team_not_found", "team not found", err)
+	}
+
+	sub := &domain.WebhookSubscription{
+		ID:       id,
+		TeamID:   teamID,
+		URL:      rawURL,
+		Filter:   filter,
+		Secret:   secret,
+		State:    domain.WebhookSubscriptionStateActive,
+		Metadata: domain.NewMetadata(createdBy, time.Now().UTC()),
+	}
+
+	if err := save(ctx, s, s.WebhookSubscriptions.Save, sub); err != nil {
+		return fmt.Errorf("saving webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// DisableWebhookSubscription deja de entregar eventos a la suscripción. Las
+// entregas pendientes se descartan al llegar su turno.
+func (s *Services) DisableWebhookSubscription(ctx context.Context, id, disabledBy string) error {
+	if s.WebhookSubscriptions == nil {
+		return perrors.Internal("webhook_subscription_repository_not_configured", "webhook subscription repository not configured", nil)
+	}
+
+	sub, err := s.WebhookSubscriptions.GetByID(ctx, id)
+	if err != nil || sub == nil {
+		return perrors.NotFound("webhook_subscription_not_found", "webhook subscription not found", err)
+	}
+
+	if err := checkExpectedVersion(ctx, sub.Metadata); err != nil {
+		return err
+	}
+
+	if sub.State != domain.WebhookSubscriptionStateActive {
+		return perrors.Domain("webhook_subscription_invalid_state_for_disable", "webhook subscription can only be disabled from Active state", nil)
+	}
+
+	sub.Metadata.RecordTransition(string(sub.State), string(domain.WebhookSubscriptionStateDisabled), disabledBy, time.Now().UTC())
+	sub.State = domain.WebhookSubscriptionStateDisabled
+
+	if err := save(ctx, s, s.WebhookSubscriptions.Save, sub); err != nil {
+		return fmt.Errorf("disabling webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Services) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	if s.WebhookSubscriptions == nil {
+		return nil, perrors.Internal("webhook_subscription_repository_not_configured", "webhook subscription repository not configured", nil)
+	}
+
+	sub, err := s.WebhookSubscriptions.GetByID(ctx, id)
+	if err != nil {
+		return nil, perrors.Internal("webhook_subscription_repository_error", "error loading webhook subscription", err)
+	}
+	if sub == nil {
+		return nil, perrors.NotFound("webhook_subscription_not_found", "webhook subscription not found", nil)
+	}
+
+	return sub, nil
+}
+
+// ListWebhookDeliveries devuelve las entregas de una suscripción con sus
+// intentos. state vacío no filtra; DeadLettered da la dead-letter list.
+func (s *Services) ListWebhookDeliveries(ctx context.Context, subscriptionID string, state domain.WebhookDeliveryState) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.GetWebhookSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if s.WebhookDeliveries == nil {
+		return nil, perrors.Internal("webhook_delivery_repository_not_configured", "webhook delivery repository not configured", nil)
+	}
+
+	all, err := s.WebhookDeliveries.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, perrors.Internal("webhook_delivery_repository_error", "error loading webhook deliveries", err)
+	}
+
+	out := make([]*domain.WebhookDelivery, 0, len(all))
+	for _, d := range all {
+		if state == "" || d.State == state {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// RedeliverWebhookDelivery vuelve a encolar una entrega DeadLettered con un
+// ciclo de reintentos nuevo.
+func (s *Services) RedeliverWebhookDelivery(ctx context.Context, id, requestedBy string) error {
+	if s.WebhookDeliveries == nil {
+		return perrors.Internal("webhook_delivery_repository_not_configured", "webhook delivery repository not configured", nil)
+	}
+
+	d, err := s.WebhookDeliveries.GetByID(ctx, id)
+	if err != nil || d == nil {
+		return perrors.NotFound("webhook_delivery_not_found", "webhook delivery not found", err)
+	}
+
+	if d.State != domain.WebhookDeliveryStateDeadLettered {
+		return perrors.Domain("webhook_delivery_invalid_state_for_redelivery", "only dead-lettered deliveries can be redelivered", nil)
+	}
+
+	d.State = domain.WebhookDeliveryStatePending
+	d.RetryCount = 0
+	d.NextAttemptAt = time.Time{} // vence en la próxima pasada del dispatcher
+	d.RedeliveredBy = requestedBy
+
+	if err := save(ctx, s, s.WebhookDeliveries.Save, d); err != nil {
+		return fmt.Errorf("redelivering webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Services) webhookSubscriptionTeam(ctx context.Context, id string) string {
+	if s.WebhookSubscriptions == nil {
+		return ""
+	}
+	sub, _ := s.WebhookSubscriptions.GetByID(ctx, id)
+	if sub == nil {
+		return ""
+	}
+	return sub.TeamID
+}
+
+func (s *Services) webhookDeliveryTeam(ctx context.Context, id string) string {
+	if s.WebhookDeliveries == nil {
+		return ""
+	}
+	d, _ := s.WebhookDeliveries.GetByID(ctx, id)
+	if d == nil {
+		return ""
+	}
+	return d.TeamID
+}
+
+// WebhookDispatcherOptions configura los reintentos del WebhookDispatcher.
+// Los valores cero toman los defaults.
+type WebhookDispatcherOptions struct {
+	// MaxAttempts por ciclo de entrega antes de pasar a DeadLettered.
+	MaxAttempts int
+	// InitialBackoff se duplica en cada fallo hasta MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// PollInterval es cada cuánto se buscan entregas vencidas.
+	PollInterval time.Duration
+	// BatchSize acota las entregas procesadas por pasada.
+	BatchSize int
+	// Workers acota las entregas en curso a la vez.
+	Workers int
+	// OnError recibe los errores que el dispatcher no puede devolver
+	// (repositorios, change feed). Opcional; se llama desde varias
+	// goroutines.
+	OnError func(err error)
+}
+
+func (o WebhookDispatcherOptions) withDefaults() WebhookDispatcherOptions {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 8
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 10 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = time.Hour
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.Workers <= 0 {
+		o.Workers = 8
+	}
+	if o.OnError == nil {
+		o.OnError = func(error) {}
+	}
+	return o
+}
+
+// WebhookDispatcher convierte los eventos del ChangeFeed en entregas de
+// webhook y las envía con reintentos y backoff exponencial. Corre dentro del
+// proceso de la API, fuera de la política de autorización.
+type WebhookDispatcher struct {
+	services *Services
+	sender   WebhookSender
+	opts     WebhookDispatcherOptions
+	now      func() time.Time
+}
+
+func NewWebhookDispatcher(services *Services, sender WebhookSender, opts WebhookDispatcherOptions) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		services: services,
+		sender:   sender,
+		opts:     opts.withDefaults(),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run consume el change feed de todas las organizaciones y entrega los
+// webhooks vencidos hasta que ctx termine. Si el feed corta la suscripción,
+// reanuda desde el último evento procesado. Las entregas corren en otra
+// goroutine, para que un endpoint lento no frene el consumo del feed.
+func (d *WebhookDispatcher) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.deliverLoop(ctx)
+	}()
+
+	var lastID int64
+	var events <-chan domain.ChangeEvent
+	for {
+		if events == nil {
+			sub, err := d.services.subscribeChanges(ctx, "", lastID, 1024)
+			if err != nil {
+				return err
+			}
+			if sub.Gap {
+				d.opts.OnError(fmt.Errorf("webhook dispatcher missed events after %d", lastID))
+			}
+			events = sub.Events
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			lastID = ev.ID
+			if err := d.Enqueue(ctx, ev); err != nil {
+				d.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// deliverLoop entrega los webhooks vencidos cada PollInterval hasta que ctx
+// termine.
+func (d *WebhookDispatcher) deliverLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DeliverDue(ctx); err != nil {
+				d.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// Enqueue crea una entrega Pending por cada suscripción que acepta ev. El ID
+// de la entrega es estable (<suscripción>-<evento>), así que reprocesar un
+// evento no duplica entregas. Sólo aplican las suscripciones de la
+// organización del evento.
+func (d *WebhookDispatcher) Enqueue(ctx context.Context, ev domain.ChangeEvent) error {
+	ctx = domain.WithOrganization(ctx, ev.OrganizationID)
+	subs, err := d.services.WebhookSubscriptions.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing webhook subscriptions: %w", err)
+	}
+
+	now := d.now()
+	for _, sub := range subs {
+		if !sub.Matches(ev) {
+			continue
+		}
+		id := fmt.Sprintf("%s-%d", sub.ID, ev.ID)
+		if existing, _ := d.services.WebhookDeliveries.GetByID(ctx, id); existing != nil {
+			continue
+		}
+		delivery := &domain.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			TeamID:         sub.TeamID,
+			Event:          ev,
+			State:          domain.WebhookDeliveryStatePending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		if err := save(ctx, d.services, d.services.WebhookDeliveries.Save, delivery); err != nil {
+			return fmt.Errorf("saving webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeliverDue hace un intento por cada entrega vencida, con hasta Workers en
+// paralelo, y devuelve cuántas procesó. Vuelve cuando terminan todas, así
+// la pasada siguiente no relee una entrega en curso. El error de una
+// entrega va a OnError y no frena las demás.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.services.WebhookDeliveries.ListDue(ctx, d.now(), d.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing due webhook deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, d.opts.Workers)
+	for _, delivery := range due {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			if err := d.attempt(ctx, delivery); err != nil {
+				d.opts.OnError(fmt.Errorf("webhook delivery %s: %w", delivery.ID, err))
+			}
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx = domain.WithOrganization(ctx, delivery.Event.OrganizationID)
+	sub, _ := d.services.WebhookSubscriptions.GetByID(ctx, delivery.SubscriptionID)
+	if sub == nil || sub.State != domain.WebhookSubscriptionStateActive {
+		delivery.State = domain.WebhookDeliveryStateDeadLettered
+		delivery.Attempts = append(delivery.Attempts, domain.WebhookAttempt{At: d.now(), Error: "subscription disabled"})
+		return d.save(ctx, delivery)
+	}
+
+	start := d.now()
+	status, err := d.sender.Send(ctx, sub, delivery)
+	attempt := domain.WebhookAttempt{At: start, StatusCode: status, DurationMs: d.now().Sub(start).Milliseconds()}
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case status < 200 || status > 299:
+		attempt.Error = fmt.Sprintf("unexpected status %d", status)
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	if attempt.Error == "" {
+		delivery.State = domain.WebhookDeliveryStateSucceeded
+		delivery.NextAttemptAt = time.Time{}
+		return d.save(ctx, delivery)
+	}
+
+	delivery.RetryCount++
+	if delivery.RetryCount >= d.opts.MaxAttempts {
+		delivery.State = domain.WebhookDeliveryStateDeadLettered
+		delivery.NextAttemptAt = time.Time{}
+	} else {
+		delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.RetryCount))
+	}
+	return d.save(ctx, delivery)
+}
+
+// backoff devuelve la espera tras el fallo número n (n >= 1).
+func (d *WebhookDispatcher) backoff(n int) time.Duration {
+	wait := d.opts.InitialBackoff
+	for i := 1; i < n && wait < d.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.opts.MaxBackoff)
+}
+
+func (d *WebhookDispatcher) save(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := save(ctx, d.services, d.services.WebhookDeliveries.Save, delivery); err != nil {
+		return fmt.Errorf("saving webhook delivery: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+type ApprovalState string
+
+const (
+	ApprovalStatePending  ApprovalState = "Pending"
+	ApprovalStateApproved ApprovalState = "Approved"
+	ApprovalStateRejected ApprovalState = "Rejected"
+	ApprovalStateExpired  ApprovalState = "Expired"
+)
+
+// ApprovalTypeApplication es la aprobación manual de una Application
+// (Proposed -> Approved); se crea junto con la Application.
+const ApprovalTypeApplication = "ApplicationApproval"
+
+// ApprovalDecision registra quién decidió una aprobación, con qué rol y por
+// qué.
+type ApprovalDecision struct {
+	Approved bool      `json:"approved"`
+	By       string    `json:"by"`
+	Role     string    `json:"role,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Approval es un pedido de aprobación manual (approvalTypes.manual del
+// estado deseado). Lo crean los workflows o los comandos que necesitan una
+// decisión humana; si tiene WorkflowID, la decisión se reenvía al workflow
+// como la señal SignalName.
+// Invariants a nivel de dominio:
+// - approval_decided_once (sólo se decide desde Pending)
+// - approval_requires_allowed_role
+type Approval struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	ResourceType string   `json:"resourceType"`
+	ResourceID   string   `json:"resourceId"`
+	TeamID       string   `json:"teamId,omitempty"`
+	RolesAllowed []string `json:"rolesAllowed"`
+	Reason       string   `json:"reason,omitempty"`
+	WorkflowID   string   `json:"workflowId,omitempty"`
+	SignalName   string   `json:"signalName,omitempty"`
+	// ExpiresAt es el vencimiento del pedido; cero no vence.
+	ExpiresAt time.Time         `json:"expiresAt,omitempty"`
+	State     ApprovalState     `json:"state"`
+	Decision  *ApprovalDecision `json:"decision,omitempty"`
+	Metadata  Metadata          `json:"metadata"`
+}
+
+// ExpiredAt indica si el pedido sigue Pending pero ya venció en now.
+func (a *Approval) ExpiredAt(now time.Time) bool {
+	return a.State == ApprovalStatePending && !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt)
+}
+
+// AllowsRole indica si role puede decidir el pedido.
+func (a *Approval) AllowsRole(role string) bool {
+	for _, r := range a.RolesAllowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
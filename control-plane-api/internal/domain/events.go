@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// ChangeAction describe qué le pasó al recurso.
+type ChangeAction string
+
+const (
+	ChangeActionCreated      ChangeAction = "created"
+	ChangeActionTransitioned ChangeAction = "transitioned"
+)
+
+// ChangeEvent es una entrada del change feed del control plane: un recurso
+// fue creado o cambió de estado. ID es la posición en el feed (creciente) y
+// sirve como Last-Event-ID para reanudar un stream. El feed es único para
+// toda la instalación; OrganizationID dice a qué tenant pertenece el evento.
+type ChangeEvent struct {
+	ID             int64        `json:"id"`
+	OrganizationID string       `json:"organizationId"`
+	ResourceType   string       `json:"resourceType"`
+	ResourceID     string       `json:"resourceId"`
+	TeamID         string       `json:"teamId,omitempty"`
+	ApplicationID  string       `json:"applicationId,omitempty"`
+	Action         ChangeAction `json:"action"`
+	State          string       `json:"state,omitempty"`
+	Version        int64        `json:"version"`
+	By             string       `json:"by"`
+	At             time.Time    `json:"at"`
+}
+
+// ChangeFilter selecciona eventos del change feed para un suscriptor de
+// /watch. Los campos vacíos no filtran.
+type ChangeFilter struct {
+	ResourceType  string
+	ResourceID    string
+	TeamID        string
+	ApplicationID string
+}
+
+func (f ChangeFilter) Matches(ev ChangeEvent) bool {
+	return (f.ResourceType == "" || f.ResourceType == ev.ResourceType) &&
+		(f.ResourceID == "" || f.ResourceID == ev.ResourceID) &&
+		(f.TeamID == "" || f.TeamID == ev.TeamID) &&
+		(f.ApplicationID == "" || f.ApplicationID == ev.ApplicationID)
+}
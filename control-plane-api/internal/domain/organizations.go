@@ -0,0 +1,48 @@
+package domain
+
+import "context"
+
+type OrganizationState string
+
+const OrganizationStateActive OrganizationState = "Active"
+
+// DefaultOrganizationID es la organización implícita: la de las requests
+// que no indican una y la de los datos anteriores a multi-tenancy. Existe
+// siempre, aunque no esté guardada.
+const DefaultOrganizationID = "default"
+
+// Organization es el tenant de la plataforma (p.ej. una unidad de negocio).
+// Es dueña de sus Teams y Environments; los IDs de los recursos son únicos
+// dentro de la organización, no en toda la instalación.
+// Invariants a nivel de dominio:
+// - no_cross_organization_references
+type Organization struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	State    OrganizationState `json:"state"`
+	Metadata Metadata          `json:"metadata"`
+}
+
+type organizationCtxKey struct{}
+
+// WithOrganization devuelve una copia de ctx que opera dentro de la
+// organización orgID: los repositorios sólo ven y guardan recursos de esa
+// organización.
+func WithOrganization(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, organizationCtxKey{}, orgID)
+}
+
+// OrganizationFromContext devuelve la organización de ctx, o
+// DefaultOrganizationID si no tiene.
+func OrganizationFromContext(ctx context.Context) string {
+	if org, ok := ctx.Value(organizationCtxKey{}).(string); ok && org != "" {
+		return org
+	}
+	return DefaultOrganizationID
+}
+
+// ScopedID es la clave de un recurso en su organización. Los IDs validados
+// no contienen "/", así que dos organizaciones nunca comparten clave.
+func ScopedID(ctx context.Context, id string) string {
+	return OrganizationFromContext(ctx) + "/" + id
+}
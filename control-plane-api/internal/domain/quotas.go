@@ -0,0 +1,79 @@
+package domain
+
+// TeamQuota acota cuántos recursos puede declarar un Team. Un límite en 0
+// no está definido y toma el de la política por defecto.
+type TeamQuota struct {
+	MaxApplications               int `json:"maxApplications,omitempty"`
+	MaxSecrets                    int `json:"maxSecrets,omitempty"`
+	MaxBindingsPerSecret          int `json:"maxBindingsPerSecret,omitempty"`
+	MaxEnvironmentsPerApplication int `json:"maxEnvironmentsPerApplication,omitempty"`
+}
+
+// DefaultTeamQuota es la política por defecto de la plataforma para los
+// Teams sin cuota propia.
+func DefaultTeamQuota() TeamQuota {
+	return TeamQuota{
+		MaxApplications:               50,
+		MaxSecrets:                    100,
+		MaxBindingsPerSecret:          20,
+		MaxEnvironmentsPerApplication: 10,
+	}
+}
+
+// Or completa los límites no definidos de q con los de fallback.
+func (q TeamQuota) Or(fallback TeamQuota) TeamQuota {
+	if q.MaxApplications <= 0 {
+		q.MaxApplications = fallback.MaxApplications
+	}
+	if q.MaxSecrets <= 0 {
+		q.MaxSecrets = fallback.MaxSecrets
+	}
+	if q.MaxBindingsPerSecret <= 0 {
+		q.MaxBindingsPerSecret = fallback.MaxBindingsPerSecret
+	}
+	if q.MaxEnvironmentsPerApplication <= 0 {
+		q.MaxEnvironmentsPerApplication = fallback.MaxEnvironmentsPerApplication
+	}
+	return q
+}
+
+// QuotaUsage es el consumo de un límite.
+type QuotaUsage struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// TeamQuotaUsage es el consumo de la cuota efectiva de un Team. Los
+// límites por Secret y por Application se informan para cada uno de los
+// del team, por ID.
+type TeamQuotaUsage struct {
+	TeamID                     string                `json:"teamId"`
+	Quota                      TeamQuota             `json:"quota"`
+	Applications               QuotaUsage            `json:"applications"`
+	Secrets                    QuotaUsage            `json:"secrets"`
+	BindingsPerSecret          map[string]QuotaUsage `json:"bindingsPerSecret"`
+	EnvironmentsPerApplication map[string]QuotaUsage `json:"environmentsPerApplication"`
+}
+
+// CountsTowardQuota indica si la Application ocupa cuota: las archivadas
+// la liberan.
+func (a *Application) CountsTowardQuota() bool {
+	return a.State != ApplicationStateArchived
+}
+
+// CountsTowardQuota indica si el Secret ocupa cuota: los revocados y
+// archivados la liberan.
+func (s *Secret) CountsTowardQuota() bool {
+	return s.State != SecretStateRevoked && s.State != SecretStateArchived
+}
+
+// CountsTowardQuota indica si el binding ocupa cuota de su Secret.
+func (b *SecretBinding) CountsTowardQuota() bool {
+	return b.State != SecretBindingStateRevoked
+}
+
+// CountsTowardQuota indica si el ApplicationEnvironment ocupa cuota de su
+// Application.
+func (ae *ApplicationEnvironment) CountsTowardQuota() bool {
+	return ae.State != ApplicationEnvironmentStateRetired
+}
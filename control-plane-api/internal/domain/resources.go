@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
 
 // Core resource aggregates inspired by ejemplo_estado_Deseado.json.
 
@@ -72,16 +76,58 @@ const (
 )
 
 type Metadata struct {
-	CreatedBy string    `json:"createdBy"`
-	CreatedAt time.Time `json:"createdAt"`
-	Tags      []string  `json:"tags,omitempty"`
+	// Version se incrementa en cada cambio del recurso; es la base del ETag
+	// de las queries y de la validación de If-Match en los comandos.
+	Version   int64        `json:"version"`
+	CreatedBy string       `json:"createdBy"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Tags      []string     `json:"tags,omitempty"`
+	History   []Transition `json:"history,omitempty"`
+}
+
+// Transition registra un cambio de estado de un recurso y quién lo provocó
+// (el subject autenticado, o el servicio interno que lo disparó).
+type Transition struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	By   string    `json:"by"`
+	At   time.Time `json:"at"`
+}
+
+// NewMetadata devuelve la metadata de un recurso recién creado (versión 1).
+func NewMetadata(createdBy string, at time.Time) Metadata {
+	return Metadata{Version: 1, CreatedBy: createdBy, CreatedAt: at}
+}
+
+// ErrVersionConflict es el error de los Save de los repositorios cuando el
+// recurso cambió desde que se leyó: dos escritores que partieron de la
+// misma versión no pueden guardar los dos. A diferencia de un If-Match
+// vencido (412 version_mismatch), el cliente no pidió ninguna versión, así
+// que es un conflicto reintentable: el comando relee el recurso y vuelve a
+// aplicarse.
+var ErrVersionConflict = perrors.Conflict(perrors.CodeConcurrentModification, "resource was modified concurrently; retry the command", nil)
+
+// Follows indica si m es la versión siguiente a prev. Los repositorios sólo
+// reemplazan un recurso guardado con uno que lo sigue (compare-and-swap).
+func (m Metadata) Follows(prev Metadata) bool {
+	return m.Version == prev.Version+1
+}
+
+// RecordTransition agrega una transición al historial del recurso y avanza
+// su versión.
+func (m *Metadata) RecordTransition(from, to, by string, at time.Time) {
+	m.History = append(m.History, Transition{From: from, To: to, By: by, At: at})
+	m.Version++
 }
 
 type Team struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	State    TeamState `json:"state"`
-	Metadata Metadata  `json:"metadata"`
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	State          TeamState `json:"state"`
+	// Quota es la cuota propia del team; nil usa la política por defecto.
+	Quota    *TeamQuota `json:"quota,omitempty"`
+	Metadata Metadata   `json:"metadata"`
 }
 
 type Application struct {
@@ -120,12 +166,14 @@ type GitOpsIntegration struct {
 	Metadata               Metadata `json:"metadata"`
 }
 
-// Environment is a global environment (dev, staging, prod...).
+// Environment is an environment (dev, staging, prod...) of an Organization,
+// shared by all of its Teams.
 type Environment struct {
-	ID       string           `json:"id"`
-	Name     string           `json:"name"`
-	State    EnvironmentState `json:"state"`
-	Metadata Metadata         `json:"metadata"`
+	ID             string           `json:"id"`
+	OrganizationID string           `json:"organizationId"`
+	Name           string           `json:"name"`
+	State          EnvironmentState `json:"state"`
+	Metadata       Metadata         `json:"metadata"`
 }
 
 // ApplicationEnvironment links an Application to an Environment.
@@ -153,6 +201,10 @@ type Secret struct {
 	Metadata    Metadata    `json:"metadata"`
 }
 
+// SecretBindingTargetApplicationEnvironment es el TargetType de los
+// bindings a un ApplicationEnvironment; se revocan al decomisionarlo.
+const SecretBindingTargetApplicationEnvironment = "ApplicationEnvironment"
+
 // SecretBinding vincula un Secret con un recurso objetivo
 // (CodeRepository, DeploymentRepository, ApplicationEnvironment...).
 // Invariants a nivel de dominio:
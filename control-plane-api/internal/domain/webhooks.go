@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+type WebhookSubscriptionState string
+
+type WebhookDeliveryState string
+
+const (
+	WebhookSubscriptionStateActive   WebhookSubscriptionState = "Active"
+	WebhookSubscriptionStateDisabled WebhookSubscriptionState = "Disabled"
+
+	WebhookDeliveryStatePending      WebhookDeliveryState = "Pending"
+	WebhookDeliveryStateSucceeded    WebhookDeliveryState = "Succeeded"
+	WebhookDeliveryStateDeadLettered WebhookDeliveryState = "DeadLettered"
+)
+
+// WebhookFilter selecciona los ChangeEvent que recibe una suscripción. Las
+// listas vacías no filtran.
+type WebhookFilter struct {
+	ResourceTypes []string       `json:"resourceTypes,omitempty"`
+	Actions       []ChangeAction `json:"actions,omitempty"`
+	ApplicationID string         `json:"applicationId,omitempty"`
+}
+
+// WebhookSubscription es un endpoint de un Team que recibe sus cambios.
+// Invariants a nivel de dominio:
+// - webhook_subscription_scoped_to_owner_team (sólo recibe eventos de su team)
+type WebhookSubscription struct {
+	ID     string        `json:"id"`
+	TeamID string        `json:"teamId"`
+	URL    string        `json:"url"`
+	Filter WebhookFilter `json:"filter"`
+	// Secret firma las entregas; no se expone en las queries.
+	Secret   string                   `json:"-"`
+	State    WebhookSubscriptionState `json:"state"`
+	Metadata Metadata                 `json:"metadata"`
+}
+
+// Matches indica si ev debe entregarse a la suscripción.
+func (s *WebhookSubscription) Matches(ev ChangeEvent) bool {
+	if s.State != WebhookSubscriptionStateActive || ev.TeamID != s.TeamID {
+		return false
+	}
+	if s.Filter.ApplicationID != "" && s.Filter.ApplicationID != ev.ApplicationID {
+		return false
+	}
+	return containsOrEmpty(s.Filter.ResourceTypes, ev.ResourceType) && containsOrEmpty(s.Filter.Actions, ev.Action)
+}
+
+func containsOrEmpty[T comparable](list []T, v T) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookAttempt registra un intento de entrega.
+type WebhookAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// WebhookDelivery es la entrega de un ChangeEvent a una suscripción. Se
+// reintenta con backoff hasta agotar los intentos; entonces queda en
+// DeadLettered hasta que alguien pida re-entregarla.
+type WebhookDelivery struct {
+	ID             string               `json:"id"`
+	SubscriptionID string               `json:"subscriptionId"`
+	TeamID         string               `json:"teamId"`
+	Event          ChangeEvent          `json:"event"`
+	State          WebhookDeliveryState `json:"state"`
+	Attempts       []WebhookAttempt     `json:"attempts,omitempty"`
+	// RetryCount cuenta los fallos del ciclo actual; una re-entrega manual
+	// lo vuelve a cero.
+	RetryCount    int       `json:"retryCount"`
+	NextAttemptAt time.Time `json:"nextAttemptAt,omitempty"`
+	RedeliveredBy string    `json:"redeliveredBy,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
@@ -13,22 +13,33 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/nuevo-idp/execution-workers/internal/harbor"
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/auth"
 	"github.com/nuevo-idp/platform/config"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/health"
 	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/observability"
 	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/validation"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 )
 
 type createRepoRequest struct {
-	Owner   string `json:"owner"`
-	Name    string `json:"name"`
+	Owner   string `json:"owner" validate:"required"`
+	Name    string `json:"name" validate:"required"`
 	Private bool   `json:"private"`
 }
 
 const internalAuthHeader = "X-Internal-Token"
 
+// defaultRateLimits se ajustan con RATE_LIMIT_INTERNAL_<SCOPE>.
+var defaultRateLimits = map[string]httpx.RateLimit{
+	httpx.RateLimitScopeService: {Rate: 20, Burst: 50},
+	httpx.RateLimitScopeClient:  {Rate: 5, Burst: 10},
+}
+
 // requireInternalAuth aplica autenticación interna para llamadas servicio-a-servicio.
 // Si INTERNAL_AUTH_TOKEN no está configurado, no se aplica enforcement (modo dev).
 func requireInternalAuth(w http.ResponseWriter, r *http.Request) bool {
@@ -37,12 +48,58 @@ func requireInternalAuth(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	}
 	if r.Header.Get(internalAuthHeader) != token {
-		httpx.WriteText(w, http.StatusUnauthorized, "missing or invalid internal auth token")
+		httpx.WriteError(w, r, perrors.Unauthorized("invalid_internal_token", "missing or invalid internal auth token", nil))
 		return false
 	}
 	return true
 }
 
+// mountRoutes registra la tabla de rutas en mux. Orden: principal del token
+// interno -> auditoría -> rate limit -> idempotencia. El principal sólo
+// atribuye los registros de auditoría; requireInternalAuth sigue decidiendo
+// en cada handler, así que los rechazos quedan auditados como anonymous.
+func mountRoutes(mux *http.ServeMux, logger *zap.Logger, auditLog *audit.Recorder) {
+	authn := auth.NewAuthenticator(auth.Options{
+		InternalToken:   config.Get("INTERNAL_AUTH_TOKEN", ""),
+		InternalSubject: "workflow-engine",
+	})
+	// Los reintentos de las actividades reenvían la misma Idempotency-Key;
+	// así no repetimos side-effects externos (crear repos, etc.).
+	idempotency := auth.IdempotencyStore{Next: httpx.NewMemoryIdempotencyStore(24 * time.Hour)}
+	// Back-pressure frente a tormentas de reintentos: cada servicio interno
+	// tiene su bucket, identificado por su token.
+	limiter := httpx.NewRateLimiter(httpx.ServiceTokenKeys(internalAuthHeader))
+	policy, err := httpx.RateLimitPolicyFromEnv("internal", defaultRateLimits)
+	if err != nil {
+		logger.Warn("ignoring invalid rate limits", zap.Error(err))
+	}
+	for _, rt := range routeTable(logger, auditLog) {
+		var h http.Handler = rt.handler
+		if rt.op.Method == http.MethodPost {
+			h = auditLog.Middleware(rt.op.ID, rt.resourceType, rt.resource, limiter.Middleware(policy, httpx.Idempotent(idempotency, h)))
+		} else {
+			h = limiter.Middleware(policy, h)
+		}
+		mux.Handle(rt.op.Path, authn.Middleware(h))
+	}
+}
+
+// handleAuditQuery sirve GET /audit (ver audit.FilterFromQuery).
+func handleAuditQuery(auditLog *audit.Recorder, w http.ResponseWriter, r *http.Request) {
+	if !requireInternalAuth(w, r) {
+		return
+	}
+	auditLog.QueryHandler().ServeHTTP(w, r)
+}
+
+// handleAuditVerify sirve GET /audit/verify.
+func handleAuditVerify(auditLog *audit.Recorder, w http.ResponseWriter, r *http.Request) {
+	if !requireInternalAuth(w, r) {
+		return
+	}
+	auditLog.VerifyHandler().ServeHTTP(w, r)
+}
+
 func main() {
 	logger, err := observability.NewLogger()
 	if err != nil {
@@ -54,46 +111,45 @@ func main() {
 
 	observability.InitMetrics()
 
-	// Inicializar tracing global para el servicio.
+	// Inicializar tracing global para el servicio. El flush de spans es un
+	// paso del drenado.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	shutdownTracing, err := tracing.InitTracing(ctx, "execution-workers")
 	if err != nil {
 		logger.Warn("failed to initialize tracing", zap.Error(err))
-	} else {
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = shutdownTracing(shutdownCtx)
-		}()
+	}
+
+	// Readiness: los proveedores externos sólo se observan; si GitHub o
+	// Harbor no responden, las actividades fallan y Temporal las reintenta.
+	checker := health.NewChecker(0)
+	if _, ok := config.Require("GITHUB_TOKEN"); ok {
+		checker.Observe("github", health.HTTPCheck(nil, config.Get("GITHUB_API_URL", "https://api.github.com/")))
+	}
+	if harborURL, ok := config.Require("HARBOR_URL"); ok {
+		checker.Observe("harbor", health.HTTPCheck(nil, harborURL))
 	}
 
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		httpx.WriteText(w, http.StatusOK, "ok")
-	})
+	checker.Register(mux)
 	mux.Handle("/metrics", promhttp.Handler())
 
-	mux.HandleFunc("/github/repos", func(w http.ResponseWriter, r *http.Request) {
-		handleCreateGitHubRepo(logger, w, r)
-	})
-	mux.HandleFunc("/appenv/branch-protection", func(w http.ResponseWriter, r *http.Request) {
-		handleAppEnvBranchProtection(logger, w, r)
-	})
-	mux.HandleFunc("/appenv/secrets", func(w http.ResponseWriter, r *http.Request) {
-		handleAppEnvSecrets(logger, w, r)
-	})
-	mux.HandleFunc("/appenv/secret-bindings", func(w http.ResponseWriter, r *http.Request) {
-		handleAppEnvSecretBindings(logger, w, r)
-	})
-	mux.HandleFunc("/appenv/gitops-verify", func(w http.ResponseWriter, r *http.Request) {
-		handleAppEnvGitOpsVerify(logger, w, r)
-	})
-
-	mux.HandleFunc("/secrets/bindings/update", func(w http.ResponseWriter, r *http.Request) {
-		handleSecretBindingsUpdate(logger, w, r)
+	// Log de auditoría de comandos: archivo en AUDIT_LOG_PATH o memoria. Un
+	// log con la cadena rota no se sigue escribiendo.
+	auditStore, closeAudit, err := audit.NewStoreFromEnv()
+	if err != nil {
+		logger.Fatal("failed to open audit log", zap.Error(err))
+	}
+	auditLog := audit.NewRecorder(auditStore, audit.RecorderOptions{
+		Service: "execution-workers",
+		OnError: func(ctx context.Context, r audit.Record, err error) {
+			observability.LoggerWithTrace(ctx, logger).Error("audit append failed",
+				zap.String("command", r.Command), zap.String("resource_id", r.ResourceID), zap.Error(err))
+		},
 	})
+	mountRoutes(mux, logger, auditLog)
+	mux.Handle("/openapi.json", openAPIDocument().Handler())
 
 	server := &http.Server{
 		Addr:         ":8082",
@@ -103,9 +159,26 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	logger.Info("execution-workers listening", zap.String("addr", server.Addr))
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		logger.Fatal("http server failed", zap.Error(err))
+	go func() {
+		logger.Info("execution-workers listening", zap.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("http server failed", zap.Error(err))
+		}
+	}()
+
+	sigCtx, stop := health.SignalContext(context.Background())
+	defer stop()
+	<-sigCtx.Done()
+	logger.Info("shutting down execution-workers")
+
+	// Drenado: las requests en curso (llamadas a GitHub/Harbor) terminan
+	// antes del flush de tracing.
+	drainer := health.NewDrainer(checker, logger, health.DrainOptionsFromEnv())
+	drainer.Add("http", health.ShutdownHTTP(server))
+	drainer.Add("audit", func(context.Context) error { return closeAudit() })
+	drainer.Add("tracing", shutdownTracing)
+	if err := drainer.Drain(context.Background()); err != nil {
+		logger.Error("execution-workers shutdown incomplete", zap.Error(err))
 	}
 }
 
@@ -123,27 +196,19 @@ func handleCreateGitHubRepo(baseLogger *zap.Logger, w http.ResponseWriter, r *ht
 	}
 
 	var req createRepoRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json body") {
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 	span.SetAttributes(
 		attribute.String("git.owner", req.Owner),
 		attribute.String("git.repo", req.Name),
 	)
-	if req.Name == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "name is required")
-		return
-	}
-	if req.Owner == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "owner is required")
-		return
-	}
 
 	token, ok := config.Require("GITHUB_TOKEN")
 	if !ok {
 		logger.Error("GITHUB_TOKEN not configured")
 		observability.ObserveDomainEvent("github_repo_created", "error")
-		httpx.WriteText(w, http.StatusInternalServerError, "GITHUB_TOKEN not configured")
+		httpx.WriteError(w, r, perrors.Internal("github_token_not_configured", "GITHUB_TOKEN not configured", nil))
 		return
 	}
 
@@ -170,7 +235,7 @@ func handleCreateGitHubRepo(baseLogger *zap.Logger, w http.ResponseWriter, r *ht
 	if err != nil {
 		logger.Error("error creating repo in GitHub", zap.Error(err))
 		observability.ObserveDomainEvent("github_repo_created", "error")
-		httpx.WriteText(w, http.StatusBadGateway, "failed to create repository in GitHub")
+		httpx.WriteError(w, r, perrors.Upstream("github_create_repository_failed", "failed to create repository in GitHub", nil))
 		return
 	}
 
@@ -179,11 +244,11 @@ func handleCreateGitHubRepo(baseLogger *zap.Logger, w http.ResponseWriter, r *ht
 }
 
 type appEnvRequest struct {
-	ApplicationEnvironmentID string `json:"applicationEnvironmentId"`
+	ApplicationEnvironmentID string `json:"applicationEnvironmentId" validate:"required"`
 }
 
 type secretBindingsUpdateRequest struct {
-	SecretID string `json:"secretId"`
+	SecretID string `json:"secretId" validate:"required"`
 }
 
 func handleAppEnvBranchProtection(baseLogger *zap.Logger, w http.ResponseWriter, r *http.Request) {
@@ -200,22 +265,25 @@ func handleAppEnvBranchProtection(baseLogger *zap.Logger, w http.ResponseWriter,
 	}
 
 	var req appEnvRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json body") {
-		return
-	}
-	if req.ApplicationEnvironmentID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "applicationEnvironmentId is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
 	appEnvID := req.ApplicationEnvironmentID
 	span.SetAttributes(attribute.String("appenv.id", appEnvID))
 
+	repo, err := validation.RepositoryName("appenv", appEnvID)
+	if err != nil {
+		observability.ObserveDomainEvent("appenv_branch_protection_applied", "error")
+		httpx.WriteError(w, r, err)
+		return
+	}
+
 	token, ok := config.Require("GITHUB_TOKEN")
 	if !ok {
 		logger.Error("GITHUB_TOKEN not configured for branch protection")
 		observability.ObserveDomainEvent("appenv_branch_protection_applied", "error")
-		httpx.WriteText(w, http.StatusInternalServerError, "GITHUB_TOKEN not configured")
+		httpx.WriteError(w, r, perrors.Internal("github_token_not_configured", "GITHUB_TOKEN not configured", nil))
 		return
 	}
 
@@ -234,7 +302,6 @@ func handleAppEnvBranchProtection(baseLogger *zap.Logger, w http.ResponseWriter,
 	}
 
 	owner := "platform"
-	repo := "appenv-" + appEnvID
 	branch := "main"
 
 	span.SetAttributes(
@@ -249,12 +316,12 @@ func handleAppEnvBranchProtection(baseLogger *zap.Logger, w http.ResponseWriter,
 		},
 	}
 
-	_, _, err := client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, protReq)
+	_, _, err = client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, protReq)
 	if err != nil {
 		logger.Error("error applying branch protection in GitHub", zap.Error(err),
 			zap.String("owner", owner), zap.String("repo", repo), zap.String("branch", branch))
 		observability.ObserveDomainEvent("appenv_branch_protection_applied", "error")
-		httpx.WriteText(w, http.StatusBadGateway, "failed to apply branch protection in GitHub")
+		httpx.WriteError(w, r, perrors.Upstream("github_branch_protection_failed", "failed to apply branch protection in GitHub", nil))
 		return
 	}
 
@@ -293,11 +360,7 @@ func handleAppEnvAction(
 	}
 
 	var req appEnvRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json body") {
-		return
-	}
-	if req.ApplicationEnvironmentID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "applicationEnvironmentId is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -308,7 +371,7 @@ func handleAppEnvAction(
 	if endpoint == "" {
 		logger.Error(endpointNotConfiguredLog)
 		observability.ObserveDomainEvent(domainEvent, "error")
-		httpx.WriteText(w, http.StatusInternalServerError, endpointNotConfiguredText)
+		httpx.WriteError(w, r, perrors.Internal("endpoint_not_configured", endpointNotConfiguredText, nil))
 		return
 	}
 
@@ -320,7 +383,7 @@ func handleAppEnvAction(
 	if err != nil {
 		logger.Error(requestErrorLog, zap.Error(err))
 		observability.ObserveDomainEvent(domainEvent, "error")
-		httpx.WriteText(w, http.StatusBadGateway, requestErrorText)
+		httpx.WriteError(w, r, perrors.Upstream("upstream_request_failed", requestErrorText, nil))
 		return
 	}
 	reqOut.Header.Set("Content-Type", "application/json")
@@ -329,7 +392,7 @@ func handleAppEnvAction(
 	if err != nil {
 		logger.Error(callErrorLog, zap.Error(err))
 		observability.ObserveDomainEvent(domainEvent, "error")
-		httpx.WriteText(w, http.StatusBadGateway, callErrorText)
+		httpx.WriteError(w, r, perrors.Upstream("upstream_unreachable", callErrorText, nil))
 		return
 	}
 	defer func() {
@@ -339,7 +402,7 @@ func handleAppEnvAction(
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		logger.Error(non2xxLog, zap.Int("status", resp.StatusCode))
 		observability.ObserveDomainEvent(domainEvent, "error")
-		httpx.WriteText(w, http.StatusBadGateway, non2xxText)
+		httpx.WriteError(w, r, perrors.Upstream("upstream_error_status", non2xxText, nil))
 		return
 	}
 
@@ -422,11 +485,7 @@ func handleSecretBindingsUpdate(baseLogger *zap.Logger, w http.ResponseWriter, r
 	}
 
 	var req secretBindingsUpdateRequest
-	if !httpx.DecodeJSON(w, r, &req, "invalid json body") {
-		return
-	}
-	if req.SecretID == "" {
-		httpx.WriteText(w, http.StatusBadRequest, "secretId is required")
+	if !httpx.DecodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -2,11 +2,13 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/nuevo-idp/platform/audit"
 	"go.uber.org/zap"
 	"io"
 	"strings"
@@ -381,3 +383,75 @@ func TestHandleSecretBindingsUpdate_RequiresInternalAuth(t *testing.T) {
 		t.Fatalf("expected %d when missing internal auth token, got %d", http.StatusUnauthorized, rec.Code)
 	}
 }
+
+func TestOpenAPIDocument_DocumentsEveryRoute(t *testing.T) {
+	doc := openAPIDocument()
+	for _, rt := range routeTable(zap.NewNop(), nil) {
+		if _, ok := doc.Paths[rt.op.Path][strings.ToLower(rt.op.Method)]; !ok {
+			t.Errorf("expected %s %s to be documented", rt.op.Method, rt.op.Path)
+		}
+	}
+}
+
+func TestHandleCreateGitHubRepo_RejectsUnknownFields(t *testing.T) {
+	body := bytes.NewBufferString(`{"owner":"me","name":"repo","visibility":"private"}`)
+	req := httptest.NewRequest(http.MethodPost, "/github/repos", body)
+	rec := httptest.NewRecorder()
+
+	handleCreateGitHubRepo(zap.NewNop(), rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"field":"visibility"`) {
+		t.Fatalf("expected field-level error for 'visibility', got %s", rec.Body.String())
+	}
+}
+
+func TestMountRoutes_AuditsCommands(t *testing.T) {
+	_ = os.Setenv("INTERNAL_AUTH_TOKEN", "test-token")
+	t.Cleanup(func() { _ = os.Unsetenv("INTERNAL_AUTH_TOKEN") })
+	_ = os.Unsetenv("HARBOR_URL")
+
+	store := audit.NewMemoryStore()
+	mux := http.NewServeMux()
+	mountRoutes(mux, zap.NewNop(), audit.NewRecorder(store, audit.RecorderOptions{Service: "execution-workers"}))
+
+	send := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set(internalAuthHeader, token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(http.MethodPost, "/secrets/bindings/update", `{"secretId":"sec-1"}`, "test-token"); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d", http.StatusAccepted, rec.Code)
+	}
+	if rec := send(http.MethodPost, "/secrets/bindings/update", `{"secretId":"sec-1"}`, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec := send(http.MethodGet, "/audit", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the audit query to require internal auth, got %d", rec.Code)
+	}
+
+	rec := send(http.MethodGet, "/audit?resourceType=Secret&resourceId=sec-1", "", "test-token")
+	var records []audit.Record
+	_ = json.Unmarshal(rec.Body.Bytes(), &records)
+	if rec.Code != http.StatusOK || len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d %s", rec.Code, rec.Body.String())
+	}
+	if r := records[0]; r.Actor != "workflow-engine" || r.Command != "updateSecretBindings" || r.Outcome != audit.OutcomeSuccess {
+		t.Fatalf("unexpected record %+v", r)
+	}
+	if r := records[1]; r.Actor != "anonymous" || r.Outcome != audit.OutcomeDenied || r.ErrorCode != "invalid_internal_token" {
+		t.Fatalf("unexpected denied record %+v", r)
+	}
+
+	rec = send(http.MethodGet, "/audit/verify", "", "test-token")
+	if !strings.Contains(rec.Body.String(), `"valid":true`) {
+		t.Fatalf("expected a valid chain, got %s", rec.Body.String())
+	}
+}
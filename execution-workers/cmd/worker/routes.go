@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nuevo-idp/platform/audit"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/openapi"
+	"go.uber.org/zap"
+)
+
+// route asocia una operación documentada en OpenAPI con su handler. main
+// registra los handlers a partir de esta tabla y /openapi.json se genera
+// desde la misma fuente.
+type route struct {
+	op      openapi.Operation
+	handler http.HandlerFunc
+	// resourceType y resource identifican en el log de auditoría el recurso
+	// que afecta un comando.
+	resourceType string
+	resource     audit.ResourceFunc
+}
+
+// audited declara el recurso que afecta el comando de rt.
+func (rt route) audited(resourceType string, resource audit.ResourceFunc) route {
+	rt.resourceType, rt.resource = resourceType, resource
+	return rt
+}
+
+func internalCommand(path, id, summary string, status int, req any, h http.HandlerFunc, tags ...string) route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodPost,
+			Path:    path,
+			ID:      id,
+			Summary: summary,
+			Tags:    tags,
+			Params: []openapi.Param{
+				{Name: internalAuthHeader, In: "header", Description: "Token interno; requerido si INTERNAL_AUTH_TOKEN está configurado"},
+				{Name: httpx.IdempotencyKeyHeader, In: "header", Description: "Key opcional; los reintentos con la misma key y payload devuelven la respuesta original"},
+			},
+			Request: req,
+			Status:  status,
+		},
+		handler: h,
+	}
+}
+
+func internalQuery(path, id, summary string, params []openapi.Param, resp any, h http.HandlerFunc, tags ...string) route {
+	return route{
+		op: openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    path,
+			ID:      id,
+			Summary: summary,
+			Tags:    tags,
+			Params: append([]openapi.Param{
+				{Name: internalAuthHeader, In: "header", Description: "Token interno; requerido si INTERNAL_AUTH_TOKEN está configurado"},
+			}, params...),
+			Response: resp,
+		},
+		handler: h,
+	}
+}
+
+var auditQueryParams = []openapi.Param{
+	{Name: "actor", Description: "Subject del principal"},
+	{Name: "resourceType", Description: "Tipo de recurso (p.ej. Secret)"},
+	{Name: "resourceId", Description: "ID del recurso"},
+	{Name: "command", Description: "operationId del comando"},
+	{Name: "from", Description: "Desde (RFC 3339, inclusivo)"},
+	{Name: "to", Description: "Hasta (RFC 3339, exclusivo)"},
+	{Name: "afterSeq", Description: "Sólo registros posteriores a este seq; para paginar"},
+	{Name: "limit", Description: "Máximo de registros (por defecto 100, hasta 1000)"},
+}
+
+// routeTable arma las rutas del servicio. auditLog sirve las queries de
+// auditoría; puede ser nil si sólo se necesita el contrato (OpenAPI).
+func routeTable(logger *zap.Logger, auditLog *audit.Recorder) []route {
+	return []route{
+		internalCommand("/github/repos", "createGitHubRepository", "Crear un repositorio en GitHub", http.StatusCreated, createRepoRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleCreateGitHubRepo(logger, w, r) }, "github").
+			audited("GitHubRepository", audit.JSONField("owner", "name")),
+		internalCommand("/appenv/branch-protection", "applyBranchProtection", "Aplicar branch protection al repo de un ApplicationEnvironment", http.StatusAccepted, appEnvRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleAppEnvBranchProtection(logger, w, r) }, "appenv").
+			audited("ApplicationEnvironment", audit.JSONField("applicationEnvironmentId")),
+		internalCommand("/appenv/secrets", "provisionAppEnvSecrets", "Provisionar secretos de un ApplicationEnvironment", http.StatusAccepted, appEnvRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleAppEnvSecrets(logger, w, r) }, "appenv").
+			audited("ApplicationEnvironment", audit.JSONField("applicationEnvironmentId")),
+		internalCommand("/appenv/secret-bindings", "createAppEnvSecretBindings", "Crear SecretBindings de un ApplicationEnvironment", http.StatusAccepted, appEnvRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleAppEnvSecretBindings(logger, w, r) }, "appenv").
+			audited("ApplicationEnvironment", audit.JSONField("applicationEnvironmentId")),
+		internalCommand("/appenv/gitops-verify", "verifyAppEnvGitOps", "Verificar la reconciliación GitOps de un ApplicationEnvironment", http.StatusAccepted, appEnvRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleAppEnvGitOpsVerify(logger, w, r) }, "appenv").
+			audited("ApplicationEnvironment", audit.JSONField("applicationEnvironmentId")),
+		internalCommand("/secrets/bindings/update", "updateSecretBindings", "Propagar la rotación de un Secret a sus SecretBindings", http.StatusAccepted, secretBindingsUpdateRequest{},
+			func(w http.ResponseWriter, r *http.Request) { handleSecretBindingsUpdate(logger, w, r) }, "secrets").
+			audited("Secret", audit.JSONField("secretId")),
+		internalQuery("/audit", "queryAudit", "Consultar el log de auditoría de comandos", auditQueryParams, []audit.Record{},
+			func(w http.ResponseWriter, r *http.Request) { handleAuditQuery(auditLog, w, r) }, "audit"),
+		internalQuery("/audit/verify", "verifyAudit", "Verificar la cadena de hashes del log de auditoría", nil, audit.Verification{},
+			func(w http.ResponseWriter, r *http.Request) { handleAuditVerify(auditLog, w, r) }, "audit"),
+	}
+}
+
+// openAPIDocument genera el documento OpenAPI 3 de execution-workers.
+func openAPIDocument() *openapi.Document {
+	table := routeTable(zap.NewNop(), nil)
+	ops := make([]openapi.Operation, 0, len(table))
+	for _, rt := range table {
+		ops = append(ops, rt.op)
+	}
+
+	return openapi.Build(openapi.Spec{
+		Info: openapi.Info{
+			Title:       "execution-workers",
+			Version:     "v1",
+			Description: "Side-effects e integraciones externas invocadas por workflow-engine.",
+		},
+		Operations:       ops,
+		Error:            httpx.Problem{},
+		ErrorContentType: httpx.ProblemContentType,
+	})
+}
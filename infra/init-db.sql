@@ -1,7 +1,24 @@
 CREATE TABLE IF NOT EXISTS teams (
-    id          TEXT PRIMARY KEY,
-    name        TEXT NOT NULL,
-    state       TEXT NOT NULL,
-    created_by  TEXT NOT NULL,
-    created_at  TIMESTAMPTZ NOT NULL
+    organization_id TEXT NOT NULL DEFAULT 'default',
+    id              TEXT NOT NULL,
+    name            TEXT NOT NULL,
+    state           TEXT NOT NULL,
+    quota           JSONB,
+    version         BIGINT NOT NULL DEFAULT 1,
+    created_by      TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (organization_id, id)
 );
+
+-- Bases creadas antes de que los recursos tuvieran versión (ETag/If-Match).
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- Bases creadas antes de las organizaciones: los teams existentes quedan en
+-- la organización default y el ID pasa a ser único dentro de la organización.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_pkey;
+ALTER TABLE teams ADD PRIMARY KEY (organization_id, id);
+
+-- Bases creadas antes de las cuotas: los teams existentes usan la política
+-- por defecto.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS quota JSONB;
@@ -0,0 +1,216 @@
+// Package audit registra cada comando ejecutado por los servicios en un log
+// append-only encadenado por hash.
+//
+// Cada Record lleva el hash del anterior (PrevHash) y su propio Hash, el
+// SHA-256 de su contenido canónico incluido PrevHash. Modificar, borrar o
+// reordenar un registro rompe la cadena a partir de ese punto, y Verify lo
+// detecta. El log no evita la manipulación: la hace evidente.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// Outcome es el resultado de un comando auditado.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+	// OutcomeDenied es un comando rechazado por autenticación o
+	// autorización.
+	OutcomeDenied Outcome = "denied"
+)
+
+// GenesisHash es el PrevHash del primer registro.
+const GenesisHash = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+// Record es un comando auditado.
+type Record struct {
+	// Seq es la posición en el log, desde 1; la asigna el Store.
+	Seq     int64     `json:"seq"`
+	At      time.Time `json:"at"`
+	Service string    `json:"service"`
+	// Actor y ActorKind identifican al principal (ver auth.Principal).
+	Actor        string `json:"actor"`
+	ActorKind    string `json:"actorKind,omitempty"`
+	Command      string `json:"command"`
+	ResourceType string `json:"resourceType,omitempty"`
+	ResourceID   string `json:"resourceId,omitempty"`
+	// PayloadHash es el SHA-256 del payload del comando (ver PayloadHash);
+	// el payload en sí no se guarda porque puede llevar secretos.
+	PayloadHash string  `json:"payloadHash,omitempty"`
+	Outcome     Outcome `json:"outcome"`
+	// Status es el status HTTP de la respuesta, o el equivalente para
+	// transports que no son HTTP.
+	Status    int    `json:"status,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+	TraceID   string `json:"traceId,omitempty"`
+	PrevHash  string `json:"prevHash"`
+	Hash      string `json:"hash"`
+}
+
+// Filter selecciona registros en Query. Los campos vacíos no filtran; From
+// es inclusivo y To exclusivo.
+type Filter struct {
+	Actor        string
+	Command      string
+	ResourceType string
+	ResourceID   string
+	From, To     time.Time
+	// AfterSeq pagina: sólo registros con Seq mayor.
+	AfterSeq int64
+	// Limit <= 0 usa DefaultQueryLimit; se acota a MaxQueryLimit.
+	Limit int
+}
+
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// Verification es el resultado de Verify.
+type Verification struct {
+	Valid   bool  `json:"valid"`
+	Records int64 `json:"records"`
+	// Head es el Hash del último registro válido.
+	Head string `json:"head"`
+	// BrokenAt es el Seq del primer registro que no encadena; 0 si Valid.
+	BrokenAt int64 `json:"brokenAt,omitempty"`
+}
+
+// Store es un log de auditoría append-only.
+type Store interface {
+	// Append encadena r al final del log y devuelve el registro con Seq,
+	// PrevHash y Hash asignados.
+	Append(ctx context.Context, r Record) (Record, error)
+	// Query devuelve los registros que cumplen f, en orden de Seq.
+	Query(ctx context.Context, f Filter) ([]Record, error)
+	// Verify recorre el log y comprueba la cadena de hashes.
+	Verify(ctx context.Context) (Verification, error)
+}
+
+// PayloadHash devuelve el hash con el que se registra un payload.
+func PayloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ComputeHash devuelve el hash de r: el SHA-256 de su JSON sin Hash. El
+// JSON de un struct tiene orden de campos fijo, así que es canónico.
+func ComputeHash(r Record) string {
+	r.Hash = ""
+	raw, err := json.Marshal(r)
+	if err != nil {
+		// Record sólo tiene tipos serializables.
+		panic(fmt.Sprintf("audit: marshal record: %v", err))
+	}
+	return PayloadHash(raw)
+}
+
+// verifyChain comprueba records desde el principio del log.
+func verifyChain(records []Record) Verification {
+	v := Verification{Valid: true, Head: GenesisHash}
+	for _, r := range records {
+		if r.Seq != v.Records+1 || r.PrevHash != v.Head || ComputeHash(r) != r.Hash {
+			v.Valid = false
+			v.BrokenAt = v.Records + 1
+			return v
+		}
+		v.Records++
+		v.Head = r.Hash
+	}
+	return v
+}
+
+// MemoryStore es un Store en memoria. Es la base de FileStore.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records []Record
+	// persist, si no es nil, escribe el registro antes de aceptarlo.
+	persist func(r Record) error
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore crea un Store en memoria; no sobrevive a un reinicio.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(_ context.Context, r Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.Seq = int64(len(s.records)) + 1
+	r.PrevHash = GenesisHash
+	if n := len(s.records); n > 0 {
+		r.PrevHash = s.records[n-1].Hash
+	}
+	r.At = r.At.UTC()
+	r.Hash = ComputeHash(r)
+	if s.persist != nil {
+		if err := s.persist(r); err != nil {
+			return Record{}, perrors.Internal("audit_write_failed", "error writing audit record", err)
+		}
+	}
+	s.records = append(s.records, r)
+	return r, nil
+}
+
+func (s *MemoryStore) Query(_ context.Context, f Filter) ([]Record, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Record, 0)
+	// Seq es la posición + 1: AfterSeq se salta sin recorrer.
+	start := f.AfterSeq
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < int64(len(s.records)) && len(out) < limit; i++ {
+		if r := s.records[i]; f.matches(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) Verify(_ context.Context) (Verification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return verifyChain(s.records), nil
+}
+
+func (f Filter) matches(r Record) bool {
+	switch {
+	case f.Actor != "" && r.Actor != f.Actor:
+		return false
+	case f.Command != "" && r.Command != f.Command:
+		return false
+	case f.ResourceType != "" && r.ResourceType != f.ResourceType:
+		return false
+	case f.ResourceID != "" && r.ResourceID != f.ResourceID:
+		return false
+	case !f.From.IsZero() && r.At.Before(f.From):
+		return false
+	case !f.To.IsZero() && !r.At.Before(f.To):
+		return false
+	}
+	return true
+}
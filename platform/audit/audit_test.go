@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func TestMemoryStore_ChainsAndQueries(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, actor := range []string{"alice", "bob", "alice"} {
+		_, _ = s.Append(ctx, Record{At: base.Add(time.Duration(i) * time.Hour), Actor: actor, Command: "createTeam", ResourceID: "team-1"})
+	}
+
+	all, _ := s.Query(ctx, Filter{})
+	if len(all) != 3 || all[0].PrevHash != GenesisHash || all[1].PrevHash != all[0].Hash || all[2].Seq != 3 {
+		t.Fatalf("unexpected chain %+v", all)
+	}
+
+	got, _ := s.Query(ctx, Filter{Actor: "alice", From: base.Add(time.Hour)})
+	if len(got) != 1 || got[0].Seq != 3 {
+		t.Fatalf("expected only the second alice record, got %+v", got)
+	}
+	got, _ = s.Query(ctx, Filter{To: base.Add(time.Hour)})
+	if len(got) != 1 || got[0].Seq != 1 {
+		t.Fatalf("expected To to be exclusive, got %+v", got)
+	}
+	got, _ = s.Query(ctx, Filter{AfterSeq: 1, Limit: 1})
+	if len(got) != 1 || got[0].Seq != 2 {
+		t.Fatalf("expected page after seq 1, got %+v", got)
+	}
+
+	v, _ := s.Verify(ctx)
+	if !v.Valid || v.Records != 3 || v.Head != all[2].Hash {
+		t.Fatalf("expected valid chain, got %+v", v)
+	}
+
+	s.records[1].Actor = "mallory"
+	v, _ = s.Verify(ctx)
+	if v.Valid || v.BrokenAt != 2 {
+		t.Fatalf("expected tampering at seq 2 to be detected, got %+v", v)
+	}
+}
+
+func TestFileStore_ReloadsAndDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _ = s.Append(ctx, Record{At: time.Now(), Actor: "alice", Command: "createTeam"})
+	_, _ = s.Append(ctx, Record{At: time.Now(), Actor: "bob", Command: "createApplication"})
+	_ = s.Close()
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	r, _ := s.Append(ctx, Record{At: time.Now(), Actor: "carol", Command: "createSecret"})
+	_ = s.Close()
+	if r.Seq != 3 {
+		t.Fatalf("expected the chain to continue after reopening, got seq %d", r.Seq)
+	}
+
+	raw, _ := os.ReadFile(path)
+	_ = os.WriteFile(path, []byte(strings.Replace(string(raw), `"actor":"bob"`, `"actor":"eve"`, 1)), 0o600)
+	if _, err := OpenFileStore(path); perrors.Code(err) != "audit_chain_broken" {
+		t.Fatalf("expected audit_chain_broken, got %v", err)
+	}
+}
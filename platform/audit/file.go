@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nuevo-idp/platform/config"
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// maxFileLine acota el tamaño de un registro al releer el archivo.
+const maxFileLine = 1 << 20
+
+// FileStore es un Store respaldado por un archivo JSONL append-only: una
+// línea por registro, sincronizada a disco antes de confirmar el Append.
+// Las queries se sirven desde memoria.
+type FileStore struct {
+	*MemoryStore
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileStore abre (o crea) el log en path, relee los registros y
+// verifica la cadena. Si la cadena está rota devuelve un error
+// audit_chain_broken: el servicio no debe seguir escribiendo sobre un log
+// manipulado.
+func OpenFileStore(path string) (*FileStore, error) {
+	records, err := readRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	if v := verifyChain(records); !v.Valid {
+		return nil, perrors.Internal("audit_chain_broken",
+			fmt.Sprintf("audit log %s is broken at seq %d", path, v.BrokenAt), nil)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, perrors.Internal("audit_open_failed", "error opening audit log", err)
+	}
+	s := &FileStore{MemoryStore: &MemoryStore{records: records}, file: f}
+	s.MemoryStore.persist = s.write
+	return s, nil
+}
+
+// NewStoreFromEnv abre un FileStore en AUDIT_LOG_PATH o, si no está
+// definida, devuelve un MemoryStore. close libera el archivo; es un no-op
+// en memoria.
+func NewStoreFromEnv() (store Store, closeFn func() error, err error) {
+	path := config.Get("AUDIT_LOG_PATH", "")
+	if path == "" {
+		return NewMemoryStore(), func() error { return nil }, nil
+	}
+	fs, err := OpenFileStore(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fs, fs.Close, nil
+}
+
+// Close cierra el archivo. Los Append posteriores fallan.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err //nolint:wrapcheck // error de cierre del archivo, sin contexto adicional
+}
+
+// write se llama con el lock del MemoryStore tomado, así que las líneas
+// quedan en el mismo orden que la cadena.
+func (s *FileStore) write(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err //nolint:wrapcheck // lo envuelve MemoryStore.Append
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err //nolint:wrapcheck // lo envuelve MemoryStore.Append
+	}
+	return s.file.Sync() //nolint:wrapcheck // lo envuelve MemoryStore.Append
+}
+
+func readRecords(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, perrors.Internal("audit_open_failed", "error opening audit log", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var records []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxFileLine)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, perrors.Internal("audit_chain_broken",
+				fmt.Sprintf("audit log %s has an unreadable record after seq %d", path, len(records)), err)
+		}
+		records = append(records, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, perrors.Internal("audit_open_failed", "error reading audit log", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,218 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+const (
+	// maxHashedBody acota cuánto del body se lee para el hash; el handler
+	// recibe el body completo igual.
+	maxHashedBody = 1 << 20
+	// maxProblemBody acota cuánto de una respuesta de error se guarda para
+	// extraer su code.
+	maxProblemBody = 64 << 10
+)
+
+// ResourceFunc extrae el ID del recurso afectado a partir de la request y su
+// body. Devuelve "" si no lo encuentra.
+type ResourceFunc func(r *http.Request, body []byte) string
+
+// JSONField devuelve un ResourceFunc que lee los campos string dados del
+// body JSON y los une con "/" (p.ej. JSONField("owner", "name")).
+func JSONField(names ...string) ResourceFunc {
+	return func(_ *http.Request, body []byte) string {
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			v, _ := fields[name].(string)
+			if v == "" {
+				return ""
+			}
+			parts = append(parts, v)
+		}
+		return strings.Join(parts, "/")
+	}
+}
+
+// Middleware audita cada request a next como el comando command sobre un
+// recurso de tipo resourceType. Registra el hash del body, el status de la
+// respuesta y, si es un error, el code del problem+json. resource puede ser
+// nil.
+//
+// Va después de la autenticación, para que el principal esté en el
+// contexto; sin principal el registro se atribuye a auth.Anonymous.
+func (rc *Recorder) Middleware(command, resourceType string, resource ResourceFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxHashedBody))
+		if err != nil {
+			httpx.WriteError(w, r, perrors.Validation("invalid_request_body", "could not read request body", err))
+			return
+		}
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		entry := Record{
+			Command:      command,
+			ResourceType: resourceType,
+			PayloadHash:  PayloadHash(body),
+			Status:       rec.status,
+		}
+		if resource != nil {
+			entry.ResourceID = resource(r, body)
+		}
+		if rec.status >= http.StatusBadRequest {
+			entry.ErrorCode = httpx.ParseProblem(rec.status, rec.body.Bytes()).Code
+		}
+		rc.Record(r.Context(), entry)
+	})
+}
+
+// QueryHandler responde GET con los registros que cumplen los parámetros
+// de FilterFromQuery. La autorización queda a cargo de quien lo monta.
+func (rc *Recorder) QueryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !httpx.RequireMethod(w, r, http.MethodGet) {
+			return
+		}
+		f, err := FilterFromQuery(r.URL.Query())
+		if err != nil {
+			httpx.WriteError(w, r, err)
+			return
+		}
+		records, err := rc.store.Query(r.Context(), f)
+		if err != nil {
+			httpx.WriteError(w, r, err)
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, records)
+	})
+}
+
+// VerifyHandler responde GET con el resultado de Verify.
+func (rc *Recorder) VerifyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !httpx.RequireMethod(w, r, http.MethodGet) {
+			return
+		}
+		v, err := rc.store.Verify(r.Context())
+		if err != nil {
+			httpx.WriteError(w, r, err)
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, v)
+	})
+}
+
+// FilterFromQuery arma un Filter a partir de los parámetros actor,
+// command, resourceType, resourceId, from y to (RFC 3339), afterSeq y
+// limit.
+func FilterFromQuery(q url.Values) (Filter, error) {
+	f := Filter{
+		Actor:        q.Get("actor"),
+		Command:      q.Get("command"),
+		ResourceType: q.Get("resourceType"),
+		ResourceID:   q.Get("resourceId"),
+	}
+	var fields []perrors.FieldError
+	parseTime := func(name string, dst *time.Time) {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				fields = append(fields, perrors.FieldError{Field: name, Message: "must be an RFC 3339 timestamp"})
+				return
+			}
+			*dst = t
+		}
+	}
+	parseInt := func(name string, dst func(n int64)) {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				fields = append(fields, perrors.FieldError{Field: name, Message: "must be a non-negative integer"})
+				return
+			}
+			dst(n)
+		}
+	}
+	parseTime("from", &f.From)
+	parseTime("to", &f.To)
+	parseInt("afterSeq", func(n int64) { f.AfterSeq = n })
+	parseInt("limit", func(n int64) { f.Limit = int(min(n, MaxQueryLimit)) })
+	if len(fields) > 0 {
+		return Filter{}, perrors.Validation("invalid_audit_filter", "invalid audit query parameters", nil).WithFields(fields...)
+	}
+	return f, nil
+}
+
+// FilterQuery es la inversa de FilterFromQuery: arma los parámetros de
+// query de f, omitiendo los campos vacíos.
+func FilterQuery(f Filter) url.Values {
+	q := url.Values{}
+	for name, v := range map[string]string{
+		"actor":        f.Actor,
+		"command":      f.Command,
+		"resourceType": f.ResourceType,
+		"resourceId":   f.ResourceID,
+	} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	if !f.From.IsZero() {
+		q.Set("from", f.From.Format(time.RFC3339Nano))
+	}
+	if !f.To.IsZero() {
+		q.Set("to", f.To.Format(time.RFC3339Nano))
+	}
+	if f.AfterSeq > 0 {
+		q.Set("afterSeq", strconv.FormatInt(f.AfterSeq, 10))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return q
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// statusRecorder captura el status y, si es un error, el body.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	if r.status >= http.StatusBadRequest && r.body.Len() < maxProblemBody {
+		r.body.Write(b[:min(len(b), maxProblemBody-r.body.Len())])
+	}
+	return r.ResponseWriter.Write(b) //nolint:wrapcheck // passthrough del ResponseWriter original
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+func TestMiddleware_RecordsOutcome(t *testing.T) {
+	store := NewMemoryStore()
+	rc := NewRecorder(store, RecorderOptions{Service: "test"})
+	var seen string
+	h := rc.Middleware("createRepo", "CodeRepository", JSONField("owner", "name"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen = string(body)
+		if strings.Contains(seen, "taken") {
+			httpx.WriteError(w, r, perrors.Conflict("repo_exists", "repository exists", nil))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	send := func(body string) {
+		req := httptest.NewRequest(http.MethodPost, "/repos", strings.NewReader(body))
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: "workflow-engine", Kind: auth.PrincipalService}))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send(`{"owner":"acme","name":"api"}`)
+	send(`{"owner":"acme","name":"taken"}`)
+
+	if seen != `{"owner":"acme","name":"taken"}` {
+		t.Fatalf("handler did not receive the full body: %q", seen)
+	}
+	records, _ := store.Query(context.Background(), Filter{})
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	ok, failed := records[0], records[1]
+	if ok.Service != "test" || ok.Actor != "workflow-engine" || ok.ActorKind != "service" || ok.ResourceID != "acme/api" ||
+		ok.Outcome != OutcomeSuccess || ok.Status != http.StatusCreated || ok.PayloadHash != PayloadHash([]byte(`{"owner":"acme","name":"api"}`)) {
+		t.Fatalf("unexpected success record %+v", ok)
+	}
+	if failed.Outcome != OutcomeFailure || failed.Status != http.StatusConflict || failed.ErrorCode != "repo_exists" {
+		t.Fatalf("unexpected failure record %+v", failed)
+	}
+}
+
+func TestRecorder_ReportsStoreErrors(t *testing.T) {
+	var got error
+	rc := NewRecorder(failingStore{}, RecorderOptions{OnError: func(_ context.Context, _ Record, err error) { got = err }})
+	rc.Record(context.Background(), Record{Command: "createTeam", Status: http.StatusForbidden})
+	if got == nil {
+		t.Fatal("expected OnError to be called")
+	}
+}
+
+func TestFilterFromQuery(t *testing.T) {
+	f, err := FilterFromQuery(url.Values{"actor": {"alice"}, "from": {"2026-01-01T00:00:00Z"}, "limit": {"5000"}})
+	if err != nil || f.Actor != "alice" || f.From.IsZero() || f.Limit != MaxQueryLimit {
+		t.Fatalf("unexpected filter %+v, %v", f, err)
+	}
+	if _, err := FilterFromQuery(url.Values{"to": {"yesterday"}}); !perrors.IsKind(err, perrors.KindValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestFilterQuery_RoundTrips(t *testing.T) {
+	want := Filter{
+		Actor:        "alice",
+		ResourceType: "Application",
+		From:         time.Date(2026, 1, 1, 0, 0, 0, 500, time.UTC),
+		AfterSeq:     7,
+		Limit:        20,
+	}
+	got, err := FilterFromQuery(FilterQuery(want))
+	if err != nil {
+		t.Fatalf("FilterFromQuery: %v", err)
+	}
+	if !got.From.Equal(want.From) {
+		t.Fatalf("from = %v, want %v", got.From, want.From)
+	}
+	got.From = want.From
+	if got != want {
+		t.Fatalf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+type failingStore struct{ Store }
+
+func (failingStore) Append(context.Context, Record) (Record, error) {
+	return Record{}, errors.New("disk full")
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecorderOptions configura un Recorder.
+type RecorderOptions struct {
+	// Service se registra en cada Record.
+	Service string
+	// OnError recibe los errores de Append. El comando ya se ejecutó, así
+	// que el Recorder no puede hacerlo fallar; como mínimo hay que loguear.
+	OnError func(ctx context.Context, r Record, err error)
+	// Now permite fijar el reloj en tests.
+	Now func() time.Time
+}
+
+// Recorder completa los registros con los datos del contexto (principal,
+// trace) y los agrega al Store.
+type Recorder struct {
+	store Store
+	opts  RecorderOptions
+}
+
+// NewRecorder crea un Recorder sobre store.
+func NewRecorder(store Store, opts RecorderOptions) *Recorder {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Recorder{store: store, opts: opts}
+}
+
+// Store devuelve el Store del Recorder, para exponer queries.
+func (rc *Recorder) Store() Store {
+	return rc.store
+}
+
+// Record agrega r al log. Completa At, Service, Actor, ActorKind y TraceID
+// si vienen vacíos.
+func (rc *Recorder) Record(ctx context.Context, r Record) {
+	if r.At.IsZero() {
+		r.At = rc.opts.Now()
+	}
+	if r.Service == "" {
+		r.Service = rc.opts.Service
+	}
+	if r.Actor == "" {
+		p, ok := auth.PrincipalFromContext(ctx)
+		if !ok {
+			p = auth.Anonymous
+		}
+		r.Actor, r.ActorKind = p.Subject, string(p.Kind)
+	}
+	if r.TraceID == "" {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			r.TraceID = sc.TraceID().String()
+		}
+	}
+	if r.Outcome == "" {
+		r.Outcome = OutcomeFor(r.Status)
+	}
+	if _, err := rc.store.Append(ctx, r); err != nil && rc.opts.OnError != nil {
+		rc.opts.OnError(ctx, r, err)
+	}
+}
+
+// OutcomeFor clasifica un status HTTP.
+func OutcomeFor(status int) Outcome {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= http.StatusBadRequest:
+		return OutcomeFailure
+	default:
+		return OutcomeSuccess
+	}
+}
@@ -0,0 +1,364 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+type testKeys struct {
+	rsa  *rsa.PrivateKey
+	ec   *ecdsa.PrivateKey
+	jwks []byte
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+			"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "use": "sig", "alg": "ES256", "crv": "P-256",
+			"x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+	}}
+	raw, _ := json.Marshal(set)
+	return testKeys{rsa: rsaKey, ec: ecKey, jwks: raw}
+}
+
+func (k testKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		s, err := rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign rs256: %v", err)
+		}
+		sig = s
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err != nil {
+			t.Fatalf("sign es256: %v", err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + b64(sig)
+}
+
+func writeJWKS(t *testing.T, raw []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+	return path
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":    "alice",
+		"iss":    "https://idp.test",
+		"aud":    []string{"control-plane-api"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"team-platform"},
+		"roles":  "platform-admin",
+	}
+}
+
+func TestVerifier_AcceptsRS256AndES256(t *testing.T) {
+	keys := newTestKeys(t)
+	v := NewVerifier(NewFileKeySet(writeJWKS(t, keys.jwks)), VerifierConfig{Issuer: "https://idp.test", Audience: "control-plane-api"})
+
+	for _, tc := range []struct{ alg, kid string }{{"RS256", "rsa-1"}, {"ES256", "ec-1"}} {
+		p, err := v.Verify(context.Background(), keys.sign(t, tc.alg, tc.kid, validClaims()))
+		if err != nil {
+			t.Fatalf("%s: expected valid token, got %v", tc.alg, err)
+		}
+		if p.Subject != "alice" || p.Kind != PrincipalUser || !p.InGroup("team-platform") || !p.HasRole("platform-admin") {
+			t.Fatalf("%s: unexpected principal %+v", tc.alg, p)
+		}
+		if p.Organization != "" {
+			t.Fatalf("%s: token without org claim bound to %q", tc.alg, p.Organization)
+		}
+	}
+}
+
+func TestVerifier_ReadsOrganizationClaim(t *testing.T) {
+	keys := newTestKeys(t)
+	claims := validClaims()
+	claims["tenant"] = "acme"
+
+	v := NewVerifier(NewFileKeySet(writeJWKS(t, keys.jwks)), VerifierConfig{OrganizationClaim: "tenant"})
+	p, err := v.Verify(context.Background(), keys.sign(t, "RS256", "rsa-1", claims))
+	if err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if p.Organization != "acme" {
+		t.Fatalf("Organization = %q, want acme", p.Organization)
+	}
+}
+
+func TestVerifier_RejectsInvalidTokens(t *testing.T) {
+	keys := newTestKeys(t)
+	v := NewVerifier(NewFileKeySet(writeJWKS(t, keys.jwks)), VerifierConfig{Issuer: "https://idp.test", Audience: "control-plane-api"})
+
+	with := func(k string, val any) map[string]any {
+		c := validClaims()
+		if val == nil {
+			delete(c, k)
+		} else {
+			c[k] = val
+		}
+		return c
+	}
+
+	tampered := keys.sign(t, "RS256", "rsa-1", validClaims())
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+
+	cases := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"expired", keys.sign(t, "RS256", "rsa-1", with("exp", time.Now().Add(-time.Hour).Unix())), ErrTokenExpired},
+		{"not yet valid", keys.sign(t, "RS256", "rsa-1", with("nbf", time.Now().Add(time.Hour).Unix())), ErrTokenNotYetValid},
+		{"wrong issuer", keys.sign(t, "RS256", "rsa-1", with("iss", "https://evil.test")), ErrInvalidIssuer},
+		{"wrong audience", keys.sign(t, "RS256", "rsa-1", with("aud", "other")), ErrInvalidAudience},
+		{"no subject", keys.sign(t, "RS256", "rsa-1", with("sub", nil)), ErrMissingSubject},
+		{"tampered signature", tampered, ErrInvalidSignature},
+		{"alg none", keys.sign(t, "none", "rsa-1", validClaims()), ErrUnsupportedAlg},
+		{"garbage", "not-a-jwt", ErrMalformedToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := v.Verify(context.Background(), tc.token); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestURLKeySet_FetchesJWKS(t *testing.T) {
+	keys := newTestKeys(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(keys.jwks)
+	}))
+	t.Cleanup(srv.Close)
+
+	v := NewVerifier(NewURLKeySet(srv.URL, srv.Client()), VerifierConfig{})
+	if _, err := v.Verify(context.Background(), keys.sign(t, "ES256", "ec-1", validClaims())); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	keys := newTestKeys(t)
+	v := NewVerifier(NewFileKeySet(writeJWKS(t, keys.jwks)), VerifierConfig{})
+
+	var got Principal
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = PrincipalFromContext(r.Context())
+	})
+
+	serve := func(a *Authenticator, header, value string) int {
+		got = Principal{}
+		req := httptest.NewRequest(http.MethodGet, "/queries/applications", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		a.Middleware(next).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	enabled := NewAuthenticator(Options{Verifier: v, InternalToken: "s3cr3t", InternalSubject: "workflow-engine"})
+
+	if code := serve(enabled, "Authorization", "Bearer "+keys.sign(t, "RS256", "rsa-1", validClaims())); code != http.StatusOK || got.Subject != "alice" {
+		t.Fatalf("expected alice to be authenticated, got %d %+v", code, got)
+	}
+	if code := serve(enabled, InternalTokenHeader, "s3cr3t"); code != http.StatusOK || got.Kind != PrincipalService || got.Subject != "workflow-engine" {
+		t.Fatalf("expected internal service principal, got %d %+v", code, got)
+	}
+	if code := serve(enabled, "", ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", code)
+	}
+	if code := serve(enabled, "Authorization", "Bearer nope"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with invalid token, got %d", code)
+	}
+
+	dev := NewAuthenticator(Options{})
+	if code := serve(dev, "", ""); code != http.StatusOK || got.Subject != Anonymous.Subject || got.Kind != PrincipalAnonymous {
+		t.Fatalf("expected anonymous principal in dev mode, got %d %+v", code, got)
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	keysFor := func(p *Principal) []httpx.RateLimitKey {
+		req := httptest.NewRequest(http.MethodGet, "/queries/applications", nil)
+		if p != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), *p))
+		}
+		return RateLimitKeys(req)
+	}
+
+	user := keysFor(&Principal{Subject: "alice", Kind: PrincipalUser, Groups: []string{"team-a"}})
+	if len(user) != 2 || user[0] != (httpx.RateLimitKey{Scope: httpx.RateLimitScopePrincipal, ID: "alice"}) ||
+		user[1] != (httpx.RateLimitKey{Scope: httpx.RateLimitScopeTeam, ID: "team-a"}) {
+		t.Fatalf("unexpected user keys %+v", user)
+	}
+	scoped := keysFor(&Principal{Subject: "alice", Kind: PrincipalUser, Groups: []string{"team-a"}, Organization: "acme"})
+	if len(scoped) != 2 || scoped[1] != (httpx.RateLimitKey{Scope: httpx.RateLimitScopeTeam, ID: "acme/team-a"}) {
+		t.Fatalf("expected team bucket qualified by organization, got %+v", scoped)
+	}
+	if svc := keysFor(&Principal{Subject: "workflow-engine", Kind: PrincipalService}); len(svc) != 1 || svc[0].Scope != httpx.RateLimitScopeService {
+		t.Fatalf("unexpected service keys %+v", svc)
+	}
+	if anon := keysFor(nil); len(anon) != 1 || anon[0].Scope != httpx.RateLimitScopeClient {
+		t.Fatalf("unexpected anonymous keys %+v", anon)
+	}
+}
+
+func TestIdempotencyStore_ScopesKeysByPrincipal(t *testing.T) {
+	calls := 0
+	h := httpx.Idempotent(IdempotencyStore{Next: httpx.NewMemoryIdempotencyStore(time.Hour)}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		httpx.WriteJSON(w, http.StatusCreated, map[string]int{"call": calls})
+	}))
+	post := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/commands/things", strings.NewReader(`{"id":"a"}`))
+		req.Header.Set(httpx.IdempotencyKeyHeader, "k-1")
+		req = req.WithContext(WithPrincipal(req.Context(), Principal{Subject: subject, Kind: PrincipalUser}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	post("alice")
+	if rec := post("bob"); rec.Header().Get(httpx.IdempotentReplayedHeader) != "" || calls != 2 {
+		t.Fatalf("expected bob's request not to replay alice's response (calls=%d)", calls)
+	}
+	if rec := post("alice"); rec.Header().Get(httpx.IdempotentReplayedHeader) != "true" || calls != 2 {
+		t.Fatalf("expected alice's retry to be replayed (calls=%d)", calls)
+	}
+}
+
+func TestParseJWKS_SkipsUnsupportedKeys(t *testing.T) {
+	keys := newTestKeys(t)
+	var set map[string][]map[string]string
+	if err := json.Unmarshal(keys.jwks, &set); err != nil {
+		t.Fatalf("decode test jwks: %v", err)
+	}
+	set["keys"] = append(set["keys"],
+		map[string]string{"kty": "RSA", "kid": "enc-1", "alg": "RSA-OAEP", "n": set["keys"][0]["n"], "e": set["keys"][0]["e"]},
+		map[string]string{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": "AAAA"},
+		map[string]string{"kty": "EC", "kid": "p384-1", "crv": "P-384", "x": "AAAA", "y": "AAAA"},
+	)
+	raw, _ := json.Marshal(set)
+
+	parsed, err := parseJWKS(raw)
+	if err != nil {
+		t.Fatalf("expected unsupported keys to be skipped, got %v", err)
+	}
+	if len(parsed) != 2 || parsed["rsa-1"] == nil || parsed["ec-1"] == nil {
+		t.Fatalf("expected only the signing keys, got %v", parsed)
+	}
+
+	if _, err := parseJWKS([]byte(`{"keys":[{"kty":"OKP","kid":"ed-1","crv":"Ed25519","x":"AAAA"}]}`)); err == nil {
+		t.Fatalf("expected an error for a set without usable keys")
+	}
+}
+
+func TestKeySet_KeepsCachedKeysWhenRefreshFails(t *testing.T) {
+	keys := newTestKeys(t)
+	now := time.Now()
+	fail := false
+	ks := newKeySet(func(context.Context) ([]byte, error) {
+		if fail {
+			return nil, errors.New("idp unavailable")
+		}
+		return keys.jwks, nil
+	})
+	ks.now = func() time.Time { return now }
+
+	if _, err := ks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("expected initial load to succeed, got %v", err)
+	}
+	fail = true
+	now = now.Add(2 * defaultJWKSRefresh)
+	if _, err := ks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("expected the cached key after a failed refresh, got %v", err)
+	}
+	if _, err := ks.Key(context.Background(), "unknown"); err == nil {
+		t.Fatalf("expected an error for an unknown kid")
+	}
+}
+
+func TestKeySet_ReloadDoesNotBlockCachedKeys(t *testing.T) {
+	keys := newTestKeys(t)
+	now := time.Now()
+	started, release := make(chan struct{}), make(chan struct{})
+	loads := 0
+	ks := newKeySet(func(context.Context) ([]byte, error) {
+		loads++
+		if loads > 1 {
+			close(started)
+			<-release
+		}
+		return keys.jwks, nil
+	})
+	ks.now = func() time.Time { return now }
+	if _, err := ks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("expected initial load to succeed, got %v", err)
+	}
+
+	now = now.Add(2 * defaultJWKSRefresh)
+	done := make(chan error)
+	go func() {
+		_, err := ks.Key(context.Background(), "rsa-1")
+		done <- err
+	}()
+	<-started
+
+	// La recarga está colgada: una request con su kid en cache no espera.
+	if _, err := ks.Key(context.Background(), "ec-1"); err != nil {
+		t.Fatalf("expected the cached key while reloading, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected the reload to succeed, got %v", err)
+	}
+}
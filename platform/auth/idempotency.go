@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// IdempotencyStore decora un httpx.IdempotencyStore para que las keys sean
+// por principal: otro caller que reutilice la misma Idempotency-Key no
+// recibe la respuesta cacheada de la request original. Se monta detrás de
+// Authenticator.Middleware, que es quien deja el principal en el contexto.
+type IdempotencyStore struct {
+	Next httpx.IdempotencyStore
+}
+
+func (s IdempotencyStore) Begin(ctx context.Context, key, requestHash string) (*httpx.IdempotencyRecord, error) {
+	return s.Next.Begin(ctx, principalScopedKey(ctx, key), requestHash) //nolint:wrapcheck // decorador transparente del store
+}
+
+func (s IdempotencyStore) Complete(ctx context.Context, key string, rec httpx.IdempotencyRecord) error {
+	return s.Next.Complete(ctx, principalScopedKey(ctx, key), rec) //nolint:wrapcheck // decorador transparente del store
+}
+
+func (s IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.Next.Release(ctx, principalScopedKey(ctx, key)) //nolint:wrapcheck // decorador transparente del store
+}
+
+func principalScopedKey(ctx context.Context, key string) string {
+	return Actor(ctx, Anonymous.Subject) + " " + key
+}
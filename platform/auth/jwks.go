@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwk es el subset de RFC 7517 que soportamos (RSA y EC P-256).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// KeySet resuelve claves públicas por kid a partir de un JWKS. La fuente
+// puede ser un fichero local o una URL HTTP; el set se cachea durante
+// refresh y se recarga antes si aparece un kid desconocido (rotación de
+// claves en el IdP).
+//
+// La recarga no bloquea a las demás requests: una sola a la vez descarga el
+// set y, mientras tanto, las que encuentran su kid en cache la siguen
+// usando. Si la recarga falla se conservan las claves cacheadas.
+type KeySet struct {
+	load    func(ctx context.Context) ([]byte, error)
+	refresh time.Duration
+	now     func() time.Time
+
+	// reloadMu serializa las recargas; nunca se toma teniendo mu.
+	reloadMu sync.Mutex
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	// triedAt y attempts registran el último intento de recarga, exitoso o
+	// no.
+	triedAt  time.Time
+	attempts uint64
+}
+
+const (
+	defaultJWKSRefresh = 5 * time.Minute
+	minJWKSReload      = 30 * time.Second
+	maxJWKSBody        = 1 << 20
+)
+
+// NewFileKeySet lee el JWKS desde path.
+func NewFileKeySet(path string) *KeySet {
+	return newKeySet(func(context.Context) ([]byte, error) {
+		b, err := os.ReadFile(path) //nolint:gosec // el path viene de configuración del operador
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+		return b, nil
+	})
+}
+
+// NewURLKeySet descarga el JWKS desde url con el cliente dado (o uno con
+// timeout razonable si es nil).
+func NewURLKeySet(url string, client *http.Client) *KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return newKeySet(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create jwks request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch jwks: %w", err)
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+		}
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBody))
+		if err != nil {
+			return nil, fmt.Errorf("read jwks body: %w", err)
+		}
+		return b, nil
+	})
+}
+
+func newKeySet(load func(ctx context.Context) ([]byte, error)) *KeySet {
+	return &KeySet{load: load, refresh: defaultJWKSRefresh, now: time.Now}
+}
+
+// Key devuelve la clave pública con el kid dado.
+func (ks *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	now := ks.now()
+	ks.mu.Lock()
+	keys, loadedAt, triedAt, attempts := ks.keys, ks.loadedAt, ks.triedAt, ks.attempts
+	ks.mu.Unlock()
+
+	k, cached := keys[kid]
+	if cached && now.Sub(loadedAt) <= ks.refresh {
+		return k, nil
+	}
+	// Set vencido o kid desconocido (puede ser una rotación reciente).
+	// Recargamos, pero acotando la frecuencia para no martillar al IdP con
+	// tokens basura o mientras está caído.
+	if keys != nil && now.Sub(triedAt) <= minJWKSReload {
+		if cached {
+			return k, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if cached {
+		// Otra request ya está recargando: seguimos con la clave cacheada.
+		if !ks.reloadMu.TryLock() {
+			return k, nil
+		}
+	} else {
+		ks.reloadMu.Lock()
+	}
+	defer ks.reloadMu.Unlock()
+
+	keys, err := ks.reload(ctx, now, attempts)
+	if k, ok := keys[kid]; ok {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// reload descarga el set salvo que otra request lo haya intentado desde
+// seen (mientras esperábamos reloadMu). Devuelve las claves vigentes: las
+// nuevas o, si la recarga falla, las cacheadas junto con el error.
+func (ks *KeySet) reload(ctx context.Context, now time.Time, seen uint64) (map[string]crypto.PublicKey, error) {
+	ks.mu.Lock()
+	if ks.attempts != seen {
+		keys := ks.keys
+		ks.mu.Unlock()
+		return keys, nil
+	}
+	ks.mu.Unlock()
+
+	raw, err := ks.load(ctx)
+	var keys map[string]crypto.PublicKey
+	if err == nil {
+		keys, err = parseJWKS(raw)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.triedAt = now
+	ks.attempts++
+	if err != nil {
+		return ks.keys, err
+	}
+	ks.keys = keys
+	ks.loadedAt = now
+	return keys, nil
+}
+
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	// Los JWKS reales mezclan claves que no usamos (de cifrado, otros
+	// algoritmos o curvas): se saltean y sólo falla un set sin ninguna
+	// clave de firma utilizable.
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	var skipped []error
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if k.Alg != "" && k.Alg != "RS256" && k.Alg != "ES256" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("jwk %q: %w", k.Kid, err))
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		if len(skipped) > 0 {
+			return nil, fmt.Errorf("jwks has no usable signing keys: %w", errors.Join(skipped...))
+		}
+		return nil, errors.New("jwks has no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if _, err := pub.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64url: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
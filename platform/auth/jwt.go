@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Errores de verificación. Se exponen para que los tests y los callers
+// puedan distinguir un token mal formado de uno expirado.
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidAudience  = errors.New("invalid token audience")
+	ErrMissingSubject   = errors.New("token has no subject")
+)
+
+// VerifierConfig parametriza la validación de claims.
+type VerifierConfig struct {
+	Issuer      string // si no está vacío, iss debe coincidir
+	Audience    string // si no está vacío, aud debe contenerlo
+	GroupsClaim string // claim con los grupos; por defecto "groups"
+	RolesClaim  string // claim con los roles; por defecto "roles"
+	// OrganizationClaim es el claim con la organización a la que está
+	// ligado el principal; por defecto "org". Ver Principal.Organization.
+	OrganizationClaim string
+	Leeway            time.Duration // tolerancia de reloj para exp/nbf
+}
+
+// Verifier valida JWTs firmados (RS256/ES256) contra un KeySet.
+type Verifier struct {
+	keys *KeySet
+	cfg  VerifierConfig
+	now  func() time.Time
+}
+
+// NewVerifier crea un Verifier que resuelve claves en keys.
+func NewVerifier(keys *KeySet, cfg VerifierConfig) *Verifier {
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.OrganizationClaim == "" {
+		cfg.OrganizationClaim = "org"
+	}
+	return &Verifier{keys: keys, cfg: cfg, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify valida firma y claims de token y devuelve el Principal resultante.
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, ErrMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, ErrMalformedToken
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return Principal{}, fmt.Errorf("resolve signing key: %w", err)
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return Principal{}, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, err
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return Principal{}, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return Principal{}, ErrMissingSubject
+	}
+	org, _ := claims[v.cfg.OrganizationClaim].(string)
+	return Principal{
+		Subject:      sub,
+		Kind:         PrincipalUser,
+		Organization: org,
+		Groups:       stringList(claims[v.cfg.GroupsClaim]),
+		Roles:        stringList(claims[v.cfg.RolesClaim]),
+	}, nil
+}
+
+func decodeSegment(seg string, dst any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(dst); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrUnsupportedAlg
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		// Incluye "none" y HS*: nunca aceptamos algoritmos no asimétricos.
+		return ErrUnsupportedAlg
+	}
+}
+
+func (v *Verifier) validateClaims(claims map[string]any) error {
+	now := v.now()
+
+	exp, ok := numericDate(claims["exp"])
+	if !ok || now.After(exp.Add(v.cfg.Leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(v.cfg.Leeway).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+			return ErrInvalidIssuer
+		}
+	}
+	if v.cfg.Audience != "" {
+		found := false
+		for _, aud := range stringList(claims["aud"]) {
+			if aud == v.cfg.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidAudience
+		}
+	}
+	return nil
+}
+
+func numericDate(v any) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// stringList acepta tanto un string como un array de strings (aud, groups y
+// roles vienen en ambas formas según el IdP).
+func stringList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
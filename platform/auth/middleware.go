@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nuevo-idp/platform/config"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// InternalTokenHeader es el header con el token compartido servicio-a-servicio.
+const InternalTokenHeader = "X-Internal-Token"
+
+// ErrNotConfigured indica que no hay JWKS configurado (modo dev).
+var ErrNotConfigured = errors.New("jwt authentication not configured: set AUTH_JWKS_FILE or AUTH_JWKS_URL")
+
+// NewVerifierFromEnv construye un Verifier a partir de variables de entorno:
+//
+//   - AUTH_JWKS_FILE o AUTH_JWKS_URL: fuente del JWKS (una de las dos).
+//   - AUTH_ISSUER / AUTH_AUDIENCE: valores esperados de iss/aud (opcionales).
+//   - AUTH_GROUPS_CLAIM / AUTH_ROLES_CLAIM / AUTH_ORGANIZATION_CLAIM: nombres
+//     de los claims (opcionales).
+//
+// Devuelve ErrNotConfigured si no hay JWKS.
+func NewVerifierFromEnv() (*Verifier, error) {
+	var keys *KeySet
+	switch {
+	case config.Get("AUTH_JWKS_FILE", "") != "":
+		keys = NewFileKeySet(config.Get("AUTH_JWKS_FILE", ""))
+	case config.Get("AUTH_JWKS_URL", "") != "":
+		keys = NewURLKeySet(config.Get("AUTH_JWKS_URL", ""), nil)
+	default:
+		return nil, ErrNotConfigured
+	}
+
+	return NewVerifier(keys, VerifierConfig{
+		Issuer:            config.Get("AUTH_ISSUER", ""),
+		Audience:          config.Get("AUTH_AUDIENCE", ""),
+		GroupsClaim:       config.Get("AUTH_GROUPS_CLAIM", ""),
+		RolesClaim:        config.Get("AUTH_ROLES_CLAIM", ""),
+		OrganizationClaim: config.Get("AUTH_ORGANIZATION_CLAIM", ""),
+		Leeway:            time.Minute,
+	}), nil
+}
+
+// Options configura un Authenticator.
+type Options struct {
+	// Verifier valida bearer tokens. Si es nil, el Authenticator trabaja en
+	// modo dev: las requests sin credenciales pasan como Anonymous.
+	Verifier *Verifier
+	// InternalToken, si no está vacío, permite autenticar llamadas
+	// servicio-a-servicio con el header X-Internal-Token.
+	InternalToken string
+	// InternalSubject es el subject del principal de servicio resultante.
+	InternalSubject string
+}
+
+// Authenticator resuelve el Principal de cada request (bearer JWT o token
+// interno) y lo deja en el contexto.
+type Authenticator struct {
+	opts Options
+}
+
+// NewAuthenticator crea un Authenticator con las opciones dadas.
+func NewAuthenticator(opts Options) *Authenticator {
+	if opts.InternalSubject == "" {
+		opts.InternalSubject = "internal"
+	}
+	return &Authenticator{opts: opts}
+}
+
+// Enabled indica si se exige autenticación (hay Verifier configurado).
+func (a *Authenticator) Enabled() bool {
+	return a.opts.Verifier != nil
+}
+
+// Middleware autentica la request antes de delegar en next. Responde 401
+// problem+json si el bearer token es inválido o, con JWT habilitado, si la
+// request no trae credenciales.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r.Context(), r.Header.Get("Authorization"), r.Header.Get(InternalTokenHeader))
+		if err != nil {
+			challenge := "Bearer"
+			if perrors.Code(err) == "invalid_token" {
+				challenge = `Bearer error="invalid_token"`
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			httpx.WriteError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+// Authenticate resuelve el Principal a partir del valor del header
+// Authorization y del token interno. Lo usan el Middleware HTTP y los
+// transports que no son HTTP (p. ej. metadata de gRPC).
+func (a *Authenticator) Authenticate(ctx context.Context, authorization, internalToken string) (Principal, error) {
+	if token, ok := bearerToken(authorization); ok && a.opts.Verifier != nil {
+		p, err := a.opts.Verifier.Verify(ctx, token)
+		if err != nil {
+			return Principal{}, perrors.Unauthorized("invalid_token", "invalid bearer token", err)
+		}
+		return p, nil
+	}
+
+	if a.opts.InternalToken != "" {
+		if internalToken != "" && subtle.ConstantTimeCompare([]byte(internalToken), []byte(a.opts.InternalToken)) == 1 {
+			return Principal{Subject: a.opts.InternalSubject, Kind: PrincipalService}, nil
+		}
+	}
+
+	if a.opts.Verifier == nil {
+		return Anonymous, nil
+	}
+	return Principal{}, perrors.Unauthorized("missing_credentials", "bearer token required", nil)
+}
+
+func bearerToken(h string) (string, bool) {
+	const prefix = "bearer "
+	if len(h) <= len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(h[len(prefix):]), true
+}
@@ -0,0 +1,64 @@
+// Package auth autentica las requests HTTP de los servicios del IDP y
+// transporta la identidad resultante (Principal) en el contexto.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// PrincipalKind distingue personas de servicios internos.
+type PrincipalKind string
+
+const (
+	PrincipalUser      PrincipalKind = "user"
+	PrincipalService   PrincipalKind = "service"
+	PrincipalAnonymous PrincipalKind = "anonymous"
+)
+
+// Principal es la identidad autenticada de una request.
+type Principal struct {
+	Subject string        `json:"subject"`
+	Kind    PrincipalKind `json:"kind"`
+	// Organization liga al principal a una organización (tenant); vacío
+	// si el token no la restringe.
+	Organization string   `json:"organization,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+}
+
+// Anonymous es el principal que se usa en modo dev, cuando no hay JWKS
+// configurado y la request no trae credenciales.
+var Anonymous = Principal{Subject: "anonymous", Kind: PrincipalAnonymous}
+
+// HasRole indica si el principal tiene el rol dado.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// InGroup indica si el principal pertenece al grupo dado.
+func (p Principal) InGroup(group string) bool {
+	return slices.Contains(p.Groups, group)
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal devuelve una copia de ctx con el principal asociado.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromContext devuelve el principal de ctx, si lo hay.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
+
+// Actor devuelve el subject a registrar como autor de un cambio, o fallback
+// si el contexto no trae principal.
+func Actor(ctx context.Context, fallback string) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p.Subject != "" {
+		return p.Subject
+	}
+	return fallback
+}
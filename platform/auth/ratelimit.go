@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// RateLimitKeys es el httpx.RateLimitKeyFunc de los servicios autenticados
+// con Authenticator: los servicios internos consumen su bucket de servicio;
+// los usuarios, el de su subject y el de cada uno de sus grupos (teams). Los
+// IDs de team sólo son únicos dentro de una organización, así que el bucket
+// de un grupo lleva la organización del principal si la tiene. Las requests
+// sin principal o anónimas se limitan por IP.
+func RateLimitKeys(r *http.Request) []httpx.RateLimitKey {
+	p, ok := PrincipalFromContext(r.Context())
+	if !ok || p.Kind == PrincipalAnonymous {
+		return []httpx.RateLimitKey{httpx.ClientKey(r)}
+	}
+	if p.Kind == PrincipalService {
+		return []httpx.RateLimitKey{{Scope: httpx.RateLimitScopeService, ID: p.Subject}}
+	}
+
+	keys := make([]httpx.RateLimitKey, 0, 1+len(p.Groups))
+	keys = append(keys, httpx.RateLimitKey{Scope: httpx.RateLimitScopePrincipal, ID: p.Subject})
+	for _, g := range p.Groups {
+		if p.Organization != "" {
+			g = p.Organization + "/" + g
+		}
+		keys = append(keys, httpx.RateLimitKey{Scope: httpx.RateLimitScopeTeam, ID: g})
+	}
+	return keys
+}
@@ -11,14 +11,33 @@ const (
 	KindConflict   Kind = "conflict"
 	KindNotFound   Kind = "not_found"
 	KindInternal   Kind = "internal"
+
+	KindUnauthorized       Kind = "unauthorized"        // credenciales ausentes o inválidas
+	KindForbidden          Kind = "forbidden"           // autenticado, pero sin permiso para la operación
+	KindRateLimited        Kind = "rate_limited"        // el cliente superó su cuota de requests
+	KindPreconditionFailed Kind = "precondition_failed" // If-Match no coincide con la versión actual
+	KindUpstream           Kind = "upstream"            // falló un proveedor/servicio del que dependemos
 )
 
+// CodeConcurrentModification es el código del Conflict de una escritura que
+// perdió contra otra concurrente. A diferencia del resto de los conflictos,
+// reintentar el comando lo resuelve.
+const CodeConcurrentModification = "concurrent_modification"
+
 // Error es un wrapper enriquecido con Kind y Code.
 type Error struct {
 	Kind    Kind   // qué tipo de error es (domain, validation, ...)
 	Code    string // código estable, apto para logs/metrics (ej: "application_already_active")
 	Message string // mensaje pensado para humanos
 	Err     error  // causa original (opcional)
+	Fields  []FieldError
+}
+
+// FieldError describe un problema puntual de un campo de la request
+// (por ejemplo, un campo requerido ausente o con tipo incorrecto).
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e *Error) Error() string {
@@ -52,6 +71,33 @@ func Internal(code, msg string, cause error) *Error {
 	return &Error{Kind: KindInternal, Code: code, Message: msg, Err: cause}
 }
 
+func Unauthorized(code, msg string, cause error) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: msg, Err: cause}
+}
+
+func Forbidden(code, msg string, cause error) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: msg, Err: cause}
+}
+
+func RateLimited(code, msg string, cause error) *Error {
+	return &Error{Kind: KindRateLimited, Code: code, Message: msg, Err: cause}
+}
+
+func PreconditionFailed(code, msg string, cause error) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: msg, Err: cause}
+}
+
+func Upstream(code, msg string, cause error) *Error {
+	return &Error{Kind: KindUpstream, Code: code, Message: msg, Err: cause}
+}
+
+// WithFields devuelve una copia de e con los errores de campo indicados.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	cp := *e
+	cp.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &cp
+}
+
 // IsKind permite preguntar si, al desempaquetar err, aparece un Error de cierto Kind.
 func IsKind(err error, kind Kind) bool {
 	var e *Error
@@ -69,3 +115,21 @@ func Code(err error) string {
 	}
 	return ""
 }
+
+// KindOf devuelve el Kind si err es un Error de plataforma, o "" si no lo es.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return ""
+}
+
+// Fields devuelve los errores de campo si err es un Error de plataforma.
+func Fields(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package grpcx reúne helpers de gRPC comunes a los servicios del IDP:
+// mapeo entre errores de plataforma y status de gRPC, interceptores de
+// autenticación y errores, e instrumentación OpenTelemetry.
+package grpcx
+
+import (
+	"context"
+	"errors"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain es el Domain de los ErrorInfo que emite la plataforma. El
+// Reason es el Code estable del error y la metadata "kind" su Kind.
+const ErrorDomain = "nuevo-idp"
+
+const kindMetadataKey = "kind"
+
+// CodeFor mapea un Kind de plataforma al código gRPC equivalente. Es el
+// análogo de errors.StatusFor para HTTP.
+func CodeFor(kind perrors.Kind) codes.Code {
+	switch kind {
+	case perrors.KindValidation:
+		return codes.InvalidArgument
+	case perrors.KindNotFound:
+		return codes.NotFound
+	case perrors.KindConflict:
+		return codes.AlreadyExists
+	case perrors.KindDomain:
+		return codes.FailedPrecondition
+	case perrors.KindUnauthorized:
+		return codes.Unauthenticated
+	case perrors.KindForbidden:
+		return codes.PermissionDenied
+	case perrors.KindRateLimited:
+		return codes.ResourceExhausted
+	case perrors.KindPreconditionFailed:
+		return codes.Aborted
+	case perrors.KindUpstream:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// kindFor es el mapeo inverso, para status sin ErrorInfo (p. ej. generados
+// por el propio runtime de gRPC).
+func kindFor(code codes.Code) perrors.Kind {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return perrors.KindValidation
+	case codes.NotFound:
+		return perrors.KindNotFound
+	case codes.AlreadyExists:
+		return perrors.KindConflict
+	case codes.FailedPrecondition:
+		return perrors.KindDomain
+	case codes.Unauthenticated:
+		return perrors.KindUnauthorized
+	case codes.PermissionDenied:
+		return perrors.KindForbidden
+	case codes.ResourceExhausted:
+		return perrors.KindRateLimited
+	case codes.Aborted:
+		return perrors.KindPreconditionFailed
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return perrors.KindUpstream
+	default:
+		return perrors.KindInternal
+	}
+}
+
+// Status convierte err en un status gRPC. Los errores de plataforma llevan
+// un ErrorInfo con Code/Kind y, si tienen errores de campo, un BadRequest.
+// Los errores que ya son status se devuelven tal cual; cualquier otro error
+// se oculta detrás de un Internal genérico, igual que en HTTP.
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	var pe *perrors.Error
+	if !errors.As(err, &pe) {
+		pe = perrors.Internal("internal_error", "internal error", err)
+	}
+
+	st := status.New(CodeFor(pe.Kind), pe.Message)
+	withInfo, derr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   pe.Code,
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{kindMetadataKey: string(pe.Kind)},
+	})
+	if derr != nil {
+		return st
+	}
+	if len(pe.Fields) == 0 {
+		return withInfo
+	}
+
+	br := &errdetails.BadRequest{}
+	for _, f := range pe.Fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+	}
+	if withFields, derr := withInfo.WithDetails(br); derr == nil {
+		return withFields
+	}
+	return withInfo
+}
+
+// Error es Status(err).Err(); útil para devolver desde un handler gRPC.
+func Error(err error) error {
+	return Status(err).Err()
+}
+
+// FromError convierte un error recibido de un servidor gRPC en un
+// *errors.Error de plataforma, preservando Code, Kind y errores de campo.
+// Devuelve err sin cambios si no es un status gRPC.
+func FromError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	pe := &perrors.Error{Kind: kindFor(st.Code()), Message: st.Message(), Err: err}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() != ErrorDomain {
+				continue
+			}
+			pe.Code = d.GetReason()
+			if k := d.GetMetadata()[kindMetadataKey]; k != "" {
+				pe.Kind = perrors.Kind(k)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				pe.Fields = append(pe.Fields, perrors.FieldError{Field: v.GetField(), Message: v.GetDescription()})
+			}
+		}
+	}
+	return pe
+}
@@ -0,0 +1,69 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusRoundTripPreservesCodeKindAndFields(t *testing.T) {
+	orig := perrors.Validation("invalid_request_body", "invalid request body", nil).
+		WithFields(perrors.FieldError{Field: "name", Message: "is required"})
+
+	err := Error(fmt.Errorf("creating team: %w", orig))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+
+	back := FromError(err)
+	var pe *perrors.Error
+	if !errors.As(back, &pe) {
+		t.Fatalf("expected platform error, got %T", back)
+	}
+	if pe.Kind != perrors.KindValidation || pe.Code != "invalid_request_body" || pe.Message != "invalid request body" {
+		t.Fatalf("unexpected error %+v", pe)
+	}
+	if len(pe.Fields) != 1 || pe.Fields[0].Field != "name" {
+		t.Fatalf("expected field errors, got %+v", pe.Fields)
+	}
+}
+
+func TestStatusMapping(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+		kind perrors.Kind
+	}{
+		{perrors.NotFound("team_not_found", "team not found", nil), codes.NotFound, perrors.KindNotFound},
+		{perrors.Domain("invalid_state", "invalid state", nil), codes.FailedPrecondition, perrors.KindDomain},
+		{perrors.PreconditionFailed("version_mismatch", "version mismatch", nil), codes.Aborted, perrors.KindPreconditionFailed},
+		{perrors.Forbidden("forbidden", "forbidden", nil), codes.PermissionDenied, perrors.KindForbidden},
+		{errors.New("boom"), codes.Internal, perrors.KindInternal},
+	}
+	for _, tc := range cases {
+		st := Status(tc.err)
+		if st.Code() != tc.code {
+			t.Errorf("%v: expected %v, got %v", tc.err, tc.code, st.Code())
+		}
+		if got := perrors.KindOf(FromError(st.Err())); got != tc.kind {
+			t.Errorf("%v: expected kind %s, got %s", tc.err, tc.kind, got)
+		}
+	}
+
+	// Los errores sin clasificar no filtran detalles internos.
+	if msg := Status(errors.New("db password wrong")).Message(); msg != "internal error" {
+		t.Errorf("expected generic message, got %q", msg)
+	}
+	if Status(context.DeadlineExceeded).Code() != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded")
+	}
+	// Status sin ErrorInfo: el Kind se deduce del código.
+	if got := perrors.KindOf(FromError(status.Error(codes.Unavailable, "down"))); got != perrors.KindUpstream {
+		t.Errorf("expected upstream kind, got %s", got)
+	}
+}
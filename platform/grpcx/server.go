@@ -0,0 +1,119 @@
+package grpcx
+
+import (
+	"context"
+
+	"github.com/nuevo-idp/platform/auth"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Claves de metadata equivalentes a los headers HTTP de autenticación.
+const (
+	authorizationMetadataKey = "authorization"
+	internalTokenMetadataKey = "x-internal-token"
+)
+
+// NewServer crea un *grpc.Server instrumentado con OpenTelemetry, que
+// autentica cada llamada con authn (mismas credenciales que HTTP: bearer JWT
+// o token interno) y traduce los errores de plataforma a status con
+// ErrorInfo.
+func NewServer(authn *auth.Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	base := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(UnaryErrors(), UnaryAuth(authn)),
+		grpc.ChainStreamInterceptor(StreamErrors(), StreamAuth(authn)),
+	}
+	return grpc.NewServer(append(base, opts...)...)
+}
+
+// UnaryErrors convierte los errores devueltos por los handlers con Error.
+func UnaryErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, Error(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamErrors es UnaryErrors para RPCs de streaming.
+func StreamErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return Error(err)
+		}
+		return nil
+	}
+}
+
+// UnaryAuth resuelve el Principal desde la metadata y lo deja en el
+// contexto (ver auth.WithPrincipal).
+func UnaryAuth(authn *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authn)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth es UnaryAuth para RPCs de streaming.
+func StreamAuth(authn *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authn)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authn *auth.Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := authn.Authenticate(ctx, first(md, authorizationMetadataKey), first(md, internalTokenMetadataKey))
+	if err != nil {
+		return nil, err
+	}
+	return auth.WithPrincipal(ctx, p), nil
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+// ClientOptions devuelve las opciones de dial comunes: instrumentación
+// OpenTelemetry y conversión de los status recibidos a errores de
+// plataforma (ver FromError).
+func ClientOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return FromError(invoker(ctx, method, req, reply, cc, opts...))
+		}),
+	}
+}
+
+// WithBearerToken agrega un bearer token a la metadata saliente.
+func WithBearerToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, authorizationMetadataKey, "Bearer "+token)
+}
+
+// WithInternalToken agrega el token interno servicio-a-servicio a la
+// metadata saliente.
+func WithInternalToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, internalTokenMetadataKey, token)
+}
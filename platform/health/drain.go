@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nuevo-idp/platform/config"
+	"go.uber.org/zap"
+)
+
+// Valores por defecto del drenado. Entre los dos quedan por debajo de los
+// 30s de gracia que Kubernetes da por defecto antes del SIGKILL.
+const (
+	DefaultDrainDelay   = 5 * time.Second
+	DefaultDrainTimeout = 20 * time.Second
+)
+
+// DrainOptions configura el drenado.
+type DrainOptions struct {
+	// Delay es cuánto se sigue atendiendo tráfico después de marcar el
+	// servicio como no listo, para que el balanceador lo saque de rotación
+	// antes de cerrar los listeners.
+	Delay time.Duration
+	// Timeout acota el tiempo total de los pasos de drenado.
+	Timeout time.Duration
+}
+
+// DrainOptionsFromEnv lee SHUTDOWN_DELAY y SHUTDOWN_TIMEOUT (duraciones de
+// Go, p.ej. "5s"); los valores vacíos o inválidos usan los defaults.
+func DrainOptionsFromEnv() DrainOptions {
+	return DrainOptions{
+		Delay:   durationFromEnv("SHUTDOWN_DELAY", DefaultDrainDelay),
+		Timeout: durationFromEnv("SHUTDOWN_TIMEOUT", DefaultDrainTimeout),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(config.Get(key, ""))
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
+
+type drainStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Drainer coordina el apagado ordenado de un proceso: marca el Checker como
+// no listo, espera Delay y ejecuta los pasos en el orden en que se
+// registraron. El orden esperado es: dejar de aceptar tráfico (servidores),
+// terminar el trabajo en curso (workers, dispatchers), flush de tracing y
+// cierre de recursos (pools).
+type Drainer struct {
+	checker *Checker
+	logger  *zap.Logger
+	opts    DrainOptions
+	steps   []drainStep
+}
+
+// NewDrainer crea un Drainer para checker; checker puede ser nil si el
+// proceso no expone readiness.
+func NewDrainer(checker *Checker, logger *zap.Logger, opts DrainOptions) *Drainer {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDrainTimeout
+	}
+	return &Drainer{checker: checker, logger: logger, opts: opts}
+}
+
+// Add registra un paso de drenado.
+func (d *Drainer) Add(name string, fn func(ctx context.Context) error) {
+	d.steps = append(d.steps, drainStep{name: name, fn: fn})
+}
+
+// Drain ejecuta el drenado. Un paso que falla no interrumpe los siguientes:
+// los errores se registran y se devuelven juntos.
+func (d *Drainer) Drain(ctx context.Context) error {
+	if d.checker != nil {
+		d.checker.SetDraining()
+	}
+	d.logger.Info("draining", zap.Duration("delay", d.opts.Delay), zap.Duration("timeout", d.opts.Timeout))
+	if d.opts.Delay > 0 {
+		timer := time.NewTimer(d.opts.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.opts.Timeout)
+	defer cancel()
+
+	var errs []error
+	for _, step := range d.steps {
+		start := time.Now()
+		if err := step.fn(ctx); err != nil {
+			d.logger.Error("drain step failed", zap.String("step", step.name), zap.Duration("elapsed", time.Since(start)), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		d.logger.Info("drain step completed", zap.String("step", step.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
+
+// SignalContext devuelve un contexto que se cancela con SIGINT o SIGTERM.
+func SignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// ShutdownHTTP deja de aceptar conexiones y espera a que terminen las
+// requests en curso.
+func ShutdownHTTP(srv *http.Server) func(ctx context.Context) error {
+	return srv.Shutdown
+}
+
+// GracefulStopper es lo que necesita StopGracefully; lo cumple *grpc.Server.
+type GracefulStopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// StopGracefully espera a que terminen las llamadas en curso y, si se
+// vence el contexto, corta las que queden.
+func StopGracefully(s GracefulStopper) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := Blocking(s.GracefulStop)(ctx)
+		if err != nil {
+			s.Stop()
+		}
+		return err
+	}
+}
+
+// Blocking adapta un paso sin contexto (p.ej. worker.Stop de Temporal) para
+// que el drenado no espere más allá de su deadline.
+func Blocking(fn func()) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			fn()
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
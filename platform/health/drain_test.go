@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDrainer_MarksNotReadyAndRunsStepsInOrder(t *testing.T) {
+	c := NewChecker(time.Second)
+	d := NewDrainer(c, nil, DrainOptions{Timeout: time.Second})
+
+	var order []string
+	d.Add("http", func(context.Context) error {
+		if !c.Draining() {
+			t.Errorf("expected readiness to be off before stopping servers")
+		}
+		order = append(order, "http")
+		return nil
+	})
+	d.Add("worker", func(context.Context) error {
+		order = append(order, "worker")
+		return errors.New("stuck")
+	})
+	d.Add("tracing", func(context.Context) error {
+		order = append(order, "tracing")
+		return nil
+	})
+
+	err := d.Drain(context.Background())
+	if err == nil || err.Error() != "worker: stuck" {
+		t.Fatalf("expected worker error, got %v", err)
+	}
+	if len(order) != 3 || order[0] != "http" || order[1] != "worker" || order[2] != "tracing" {
+		t.Fatalf("unexpected order %v", order)
+	}
+	if code, report := readyz(t, c); code != http.StatusServiceUnavailable || report.Status != StatusDraining {
+		t.Fatalf("expected 503 draining, got %d %+v", code, report)
+	}
+}
+
+type fakeStopper struct {
+	release chan struct{}
+	stopped bool
+}
+
+func (f *fakeStopper) GracefulStop() { <-f.release }
+func (f *fakeStopper) Stop()         { f.stopped = true; close(f.release) }
+
+func TestStopGracefully_ForcesStopOnDeadline(t *testing.T) {
+	s := &fakeStopper{release: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := StopGracefully(s)(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !s.stopped {
+		t.Fatalf("expected forced stop")
+	}
+}
+
+func TestDrainOptionsFromEnv(t *testing.T) {
+	t.Setenv("SHUTDOWN_DELAY", "0s")
+	t.Setenv("SHUTDOWN_TIMEOUT", "bogus")
+	opts := DrainOptionsFromEnv()
+	if opts.Delay != 0 || opts.Timeout != DefaultDrainTimeout {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+}
@@ -0,0 +1,223 @@
+// Package health expone los probes de liveness y readiness de los servicios
+// y coordina el drenado ordenado al apagarlos.
+//
+// Liveness (/healthz) sólo indica que el proceso responde: no consulta
+// dependencias, para que el orquestador no reinicie pods sanos cuando falla
+// Postgres o Temporal. Readiness (/readyz) ejecuta los checks registrados y
+// deja de estar listo apenas empieza el drenado, para que el balanceador deje
+// de enviar tráfico antes de cerrar los listeners.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuevo-idp/platform/httpx"
+)
+
+// DefaultCheckTimeout acota cada check de readiness.
+const DefaultCheckTimeout = 2 * time.Second
+
+// Check verifica una dependencia y devuelve error si no está disponible.
+type Check func(ctx context.Context) error
+
+// Estados de un check y del probe completo.
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// ErrDraining lo informa /readyz mientras el proceso se está apagando.
+var ErrDraining = errors.New("shutting down")
+
+type namedCheck struct {
+	name     string
+	check    Check
+	required bool
+}
+
+// Checker agrupa los checks de readiness de un servicio.
+type Checker struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+// NewChecker crea un Checker sin checks; timeout <= 0 usa
+// DefaultCheckTimeout.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Require registra un check obligatorio: si falla, el servicio no está listo.
+func (c *Checker) Require(name string, check Check) {
+	c.add(namedCheck{name: name, check: check, required: true})
+}
+
+// Observe registra un check informativo: si falla, /readyz lo reporta como
+// degradado pero sigue respondiendo 200. Se usa para dependencias downstream,
+// cuya caída no debe sacar de servicio a quien las llama.
+func (c *Checker) Observe(name string, check Check) {
+	c.add(namedCheck{name: name, check: check})
+}
+
+func (c *Checker) add(nc namedCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, nc)
+}
+
+// SetDraining marca el inicio del drenado: desde ahí /readyz responde 503.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Draining indica si el drenado ya empezó.
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// CheckResult es el resultado de un check en /readyz.
+type CheckResult struct {
+	Status     string `json:"status"`
+	Required   bool   `json:"required"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report es el body de /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready indica si el reporte corresponde a un servicio listo.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+// Run ejecuta todos los checks en paralelo, cada uno con su timeout.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	if c.Draining() {
+		report.Status = StatusDraining
+		return report
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runOne(ctx, nc)
+		}()
+	}
+	wg.Wait()
+
+	for i, nc := range checks {
+		res := results[i]
+		report.Checks[nc.name] = res
+		switch {
+		case res.Status == StatusOK:
+		case nc.required:
+			report.Status = StatusUnavailable
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) runOne(ctx context.Context, nc namedCheck) (res CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		// Un check que paniquea no debe tirar el probe: se informa como caído.
+		if p := recover(); p != nil {
+			res = CheckResult{Status: StatusUnavailable, Error: fmt.Sprintf("panic: %v", p)}
+		}
+		res.Required = nc.required
+		res.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	if err := nc.check(ctx); err != nil {
+		return CheckResult{Status: StatusUnavailable, Error: err.Error()}
+	}
+	return CheckResult{Status: StatusOK}
+}
+
+// LivenessHandler responde "ok" mientras el proceso esté vivo.
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteText(w, http.StatusOK, "ok")
+	})
+}
+
+// ReadinessHandler responde el Report de los checks: 200 si el servicio está
+// listo (aunque esté degradado) y 503 si falla un check obligatorio o si
+// empezó el drenado.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		httpx.WriteJSON(w, status, report)
+	})
+}
+
+// Register monta /healthz (liveness) y /readyz (readiness) en mux.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.Handle("/healthz", c.LivenessHandler())
+	mux.Handle("/readyz", c.ReadinessHandler())
+}
+
+// PingCheck adapta una dependencia con Ping(ctx), como *pgxpool.Pool.
+func PingCheck(p interface{ Ping(context.Context) error }) Check {
+	return p.Ping
+}
+
+// HTTPCheck verifica que un servicio downstream responda en url. Cualquier
+// respuesta por debajo de 500 cuenta como alcanzable: un 401 o 404 también
+// prueba que el servicio está arriba. Si client es nil se usa uno sin
+// redirecciones.
+func HTTPCheck(client *http.Client, url string) Check {
+	if client == nil {
+		client = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func readyz(t *testing.T, c *Checker) (int, Report) {
+	t.Helper()
+	mux := http.NewServeMux()
+	c.Register(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid readiness body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, report
+}
+
+func TestReadiness_RequiredCheckFailureIsUnavailable(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.Require("postgres", func(context.Context) error { return errors.New("connection refused") })
+	c.Observe("downstream", func(context.Context) error { return nil })
+
+	code, report := readyz(t, c)
+	if code != http.StatusServiceUnavailable || report.Status != StatusUnavailable {
+		t.Fatalf("expected 503 unavailable, got %d %+v", code, report)
+	}
+	if got := report.Checks["postgres"]; got.Status != StatusUnavailable || got.Error != "connection refused" || !got.Required {
+		t.Fatalf("unexpected postgres result %+v", got)
+	}
+	if got := report.Checks["downstream"]; got.Status != StatusOK {
+		t.Fatalf("unexpected downstream result %+v", got)
+	}
+}
+
+func TestReadiness_ObservedCheckFailureIsDegraded(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.Require("postgres", func(context.Context) error { return nil })
+	c.Observe("downstream", func(context.Context) error { panic("boom") })
+
+	code, report := readyz(t, c)
+	if code != http.StatusOK || report.Status != StatusDegraded {
+		t.Fatalf("expected 200 degraded, got %d %+v", code, report)
+	}
+	if got := report.Checks["downstream"]; got.Status != StatusUnavailable || got.Error != "panic: boom" {
+		t.Fatalf("unexpected downstream result %+v", got)
+	}
+}
+
+func TestReadiness_CheckTimeout(t *testing.T) {
+	c := NewChecker(20 * time.Millisecond)
+	c.Require("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if code, _ := readyz(t, c); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 on timeout, got %d", code)
+	}
+}
+
+func TestLivenessIgnoresChecksAndDraining(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.Require("postgres", func(context.Context) error { return errors.New("down") })
+	c.SetDraining()
+
+	mux := http.NewServeMux()
+	c.Register(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("expected 200 ok, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	status := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	check := HTTPCheck(nil, srv.URL)
+	if err := check(context.Background()); err != nil {
+		t.Fatalf("expected 401 to count as reachable, got %v", err)
+	}
+	status = http.StatusBadGateway
+	if err := check(context.Background()); err == nil {
+		t.Fatalf("expected error on 502")
+	}
+}
@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// VersionETag devuelve el ETag (fuerte) de la versión v de un recurso.
+func VersionETag(v int64) string {
+	return `"` + strconv.FormatInt(v, 10) + `"`
+}
+
+// ParseVersionETag extrae la versión de un ETag generado por VersionETag.
+// Acepta también la forma débil (W/"3").
+func ParseVersionETag(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// NotModified fija el header ETag y, si la request trae un If-None-Match
+// que lo incluye (o "*"), responde 304 sin body y devuelve true.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	inm := r.Header.Get("If-None-Match")
+	if inm == "" {
+		return false
+	}
+	want, _ := ParseVersionETag(etag)
+	for _, candidate := range strings.Split(inm, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+		// If-None-Match usa comparación débil (RFC 9110 §13.1.2).
+		if v, ok := ParseVersionETag(candidate); ok && v == want {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatchVersion devuelve la versión pedida en el header If-Match. ok es
+// false si no hay header o si es "*" (cualquier versión). Un valor que no es
+// un ETag de versión devuelve un error de validación.
+func IfMatchVersion(r *http.Request) (version int64, ok bool, err error) {
+	im := strings.TrimSpace(r.Header.Get("If-Match"))
+	if im == "" || im == "*" {
+		return 0, false, nil
+	}
+	// If-Match usa comparación fuerte: los ETags débiles nunca coinciden.
+	if strings.HasPrefix(im, "W/") || strings.Contains(im, ",") {
+		return 0, false, perrors.Validation("invalid_if_match", "If-Match must be a single strong ETag", nil)
+	}
+	v, parsed := ParseVersionETag(im)
+	if !parsed {
+		return 0, false, perrors.Validation("invalid_if_match", "If-Match must be a resource ETag", nil)
+	}
+	return v, true, nil
+}
@@ -0,0 +1,65 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNotModified(t *testing.T) {
+	cases := map[string]bool{
+		"":              false,
+		`"3"`:           true,
+		`W/"3"`:         true,
+		`"2", "3"`:      true,
+		"*":             true,
+		`"2"`:           false,
+		"not-an-etag":   false,
+		`"1", W/"2"`:    false,
+		`W/"4", "five"`: false,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/queries/applications?id=a", nil)
+		if header != "" {
+			req.Header.Set("If-None-Match", header)
+		}
+		rec := httptest.NewRecorder()
+
+		got := NotModified(rec, req, VersionETag(3))
+		if got != want {
+			t.Errorf("If-None-Match %q: expected %v, got %v", header, want, got)
+		}
+		if rec.Header().Get("ETag") != `"3"` {
+			t.Errorf("If-None-Match %q: expected ETag header, got %q", header, rec.Header().Get("ETag"))
+		}
+		if got && rec.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %q: expected 304, got %d", header, rec.Code)
+		}
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	cases := []struct {
+		header  string
+		version int64
+		ok      bool
+		wantErr bool
+	}{
+		{"", 0, false, false},
+		{"*", 0, false, false},
+		{`"7"`, 7, true, false},
+		{`W/"7"`, 0, false, true},
+		{`"7", "8"`, 0, false, true},
+		{"seven", 0, false, true},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/commands/applications/approve", nil)
+		if tc.header != "" {
+			req.Header.Set("If-Match", tc.header)
+		}
+		v, ok, err := IfMatchVersion(req)
+		if v != tc.version || ok != tc.ok || (err != nil) != tc.wantErr {
+			t.Errorf("If-Match %q: got (%d, %v, %v)", tc.header, v, ok, err)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package httpx
 import (
 	"encoding/json"
 	"net/http"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/openapi"
 )
 
 // WriteJSON escribe una respuesta JSON con el status dado.
@@ -14,31 +17,95 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 }
 
 // WriteText escribe una respuesta de texto plano con el status dado.
+// Reservado para respuestas no-error (por ejemplo /healthz); los errores
+// deben pasar por WriteError/WriteProblem.
 func WriteText(w http.ResponseWriter, status int, msg string) {
 	w.WriteHeader(status)
 	_, _ = w.Write([]byte(msg))
 }
 
 // RequireMethod valida que la request use el método esperado.
-// Si no coincide, escribe 405 y devuelve false.
+// Si no coincide, escribe un 405 problem+json y devuelve false.
 func RequireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
 	if r.Method != method {
-		WriteText(w, http.StatusMethodNotAllowed, "method not allowed")
+		w.Header().Set("Allow", method)
+		p := NewProblem(r, http.StatusMethodNotAllowed,
+			perrors.Validation("method_not_allowed", "method "+r.Method+" not allowed", nil))
+		WriteProblem(w, p)
 		return false
 	}
 	return true
 }
 
 // DecodeJSON decodifica el body como JSON en dst.
-// Si falla, escribe un 400 con el mensaje dado (o "invalid json" si está vacío)
-// y devuelve false.
+// Si falla, escribe un 400 problem+json con el mensaje dado (o "invalid json"
+// si está vacío) y devuelve false.
 func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any, msg string) bool {
 	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
 		if msg == "" {
 			msg = "invalid json"
 		}
-		WriteText(w, http.StatusBadRequest, msg)
+		WriteError(w, r, perrors.Validation("invalid_json", msg, nil))
 		return false
 	}
 	return true
 }
+
+// StatusFor mapea el Kind de un error de plataforma a un status HTTP. Un
+// error sin clasificar (sin Kind) es un 500: no se sabe si es culpa del
+// cliente, y tratarlo como definitivo haría que Idempotent lo cachee.
+func StatusFor(err error) int {
+	switch {
+	case perrors.IsKind(err, perrors.KindDomain), perrors.IsKind(err, perrors.KindValidation):
+		return http.StatusBadRequest
+	case perrors.IsKind(err, perrors.KindNotFound):
+		return http.StatusNotFound
+	case perrors.IsKind(err, perrors.KindConflict):
+		return http.StatusConflict
+	case perrors.IsKind(err, perrors.KindUnauthorized):
+		return http.StatusUnauthorized
+	case perrors.IsKind(err, perrors.KindForbidden):
+		return http.StatusForbidden
+	case perrors.IsKind(err, perrors.KindRateLimited):
+		return http.StatusTooManyRequests
+	case perrors.IsKind(err, perrors.KindPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case perrors.IsKind(err, perrors.KindUpstream):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteError escribe err como problem+json con el status derivado de su Kind.
+// Un conflicto por escritura concurrente lleva Retry-After, porque
+// reintentar la request lo resuelve.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if perrors.Code(err) == perrors.CodeConcurrentModification {
+		w.Header().Set("Retry-After", "1")
+	}
+	WriteProblem(w, NewProblem(r, StatusFor(err), err))
+}
+
+// DecodeAndValidate valida el body contra el schema OpenAPI derivado del
+// tipo de dst y lo decodifica. Si falla, escribe un 400 estructurado con el
+// detalle por campo y devuelve false.
+func DecodeAndValidate(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := openapi.DecodeJSON(r, dst); err != nil {
+		WriteError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// RequireQuery devuelve el valor del parámetro de query name. Si falta,
+// escribe un 400 estructurado con el detalle del campo y devuelve false.
+func RequireQuery(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		WriteError(w, r, perrors.Validation("invalid_query", name+" is required", nil).
+			WithFields(perrors.FieldError{Field: name, Message: "is required"}))
+		return "", false
+	}
+	return v, true
+}
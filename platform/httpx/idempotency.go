@@ -0,0 +1,281 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// IdempotencyKeyHeader es el header con el que los clientes identifican una
+// request para que sus reintentos no repitan el side-effect.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marca las respuestas servidas desde el store.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const (
+	maxIdempotencyKeyLen  = 255
+	maxIdempotentBodySize = 1 << 20
+)
+
+// IdempotencyRecord es lo que se guarda por cada key: el hash de la request
+// original y, una vez completada, su respuesta.
+type IdempotencyRecord struct {
+	RequestHash string
+	Done        bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore persiste las keys de idempotencia.
+//
+// Begin reserva key para una request nueva (devuelve nil) o, si la key ya
+// existe, devuelve el record actual (en curso o completado). Complete guarda
+// la respuesta final y Release libera una reserva sin respuesta cacheable.
+type IdempotencyStore interface {
+	Begin(ctx context.Context, key, requestHash string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, rec IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryIdempotencyStore es un IdempotencyStore en memoria con expiración.
+// Suficiente para una única réplica; en multi-réplica debe reemplazarse por
+// un store compartido.
+//
+// Las keys vencidas se descartan en un barrido amortizado dentro de Begin,
+// como máximo una vez por sweepEvery.
+type MemoryIdempotencyStore struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	sweepEvery time.Duration
+	now        func() time.Time
+	entries    map[string]memoryIdempotencyEntry
+	lastSweep  time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	rec       IdempotencyRecord
+	expiresAt time.Time
+}
+
+const defaultIdempotencySweepInterval = 10 * time.Minute
+
+// NewMemoryIdempotencyStore crea un store en memoria cuyas keys expiran
+// tras ttl.
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		ttl:        ttl,
+		sweepEvery: min(ttl, defaultIdempotencySweepInterval),
+		now:        time.Now,
+		entries:    make(map[string]memoryIdempotencyEntry),
+	}
+}
+
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key, requestHash string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		rec := e.rec
+		return &rec, nil
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{
+		rec:       IdempotencyRecord{RequestHash: requestHash},
+		expiresAt: now.Add(s.ttl),
+	}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, rec IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec.Done = true
+	s.entries[key] = memoryIdempotencyEntry{rec: rec, expiresAt: s.now().Add(s.ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepEvery {
+		return
+	}
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Idempotent envuelve next para que las requests con Idempotency-Key se
+// ejecuten una sola vez: los reintentos con el mismo payload reciben la
+// respuesta original, la reutilización de la key con otro payload devuelve
+// 422 y un reintento concurrente con la original devuelve 409.
+//
+// Ante un 5xx, un rechazo transitorio (401/403/429), cualquier respuesta
+// con Retry-After (por ejemplo, un 409 concurrent_modification) o un panic
+// del handler la key se libera para que el cliente pueda reintentar. Un body
+// de más de 1MB se rechaza con 413 sin reservar la key. Las requests sin
+// header pasan sin cambios.
+func Idempotent(store IdempotencyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			WriteError(w, r, perrors.Validation("invalid_idempotency_key", "Idempotency-Key is too long", nil).
+				WithFields(perrors.FieldError{Field: IdempotencyKeyHeader, Message: "must be at most 255 characters"}))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			WriteError(w, r, perrors.Validation("invalid_request_body", "could not read request body", err))
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			WriteProblem(w, NewProblem(r, http.StatusRequestEntityTooLarge,
+				perrors.Validation("request_body_too_large", "request body must be at most 1MB", nil)))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		scoped := r.Method + " " + r.URL.Path + " " + key
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])
+
+		prev, err := store.Begin(ctx, scoped, hash)
+		if err != nil {
+			WriteError(w, r, perrors.Internal("idempotency_store_error", "could not reserve idempotency key", err))
+			return
+		}
+		if prev != nil {
+			switch {
+			case prev.RequestHash != hash:
+				WriteProblem(w, NewProblem(r, http.StatusUnprocessableEntity,
+					perrors.Validation("idempotency_key_reused", "Idempotency-Key was already used with a different payload", nil)))
+			case !prev.Done:
+				w.Header().Set("Retry-After", "1")
+				WriteError(w, r, perrors.Conflict("idempotency_key_in_flight", "a request with this Idempotency-Key is still in progress", nil))
+			default:
+				replayIdempotent(w, prev)
+			}
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			// Si el handler entra en pánico, la key no puede quedar en curso
+			// hasta que venza: se libera y el pánico sigue su camino.
+			if p := recover(); p != nil {
+				_ = store.Release(ctx, scoped)
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+
+		if !cacheableStatus(rec.status) || w.Header().Get("Retry-After") != "" {
+			_ = store.Release(ctx, scoped)
+			return
+		}
+		_ = store.Complete(ctx, scoped, IdempotencyRecord{
+			RequestHash: hash,
+			Status:      rec.status,
+			Header:      w.Header().Clone(),
+			Body:        rec.body.Bytes(),
+		})
+	})
+}
+
+// cacheableStatus indica si una respuesta es el resultado definitivo de la
+// request y puede servirse en los reintentos.
+func cacheableStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func replayIdempotent(w http.ResponseWriter, rec *IdempotencyRecord) {
+	for k, vs := range rec.Header {
+		w.Header()[k] = append([]string(nil), vs...)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	_, _ = w.Write(rec.Body)
+}
+
+// idempotencyRecorder captura status y body mientras los escribe al cliente.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b) //nolint:wrapcheck // passthrough del ResponseWriter original
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey asocia a ctx la key base que los clientes HTTP usarán
+// en las requests salientes (ver SetIdempotencyKey).
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// IdempotencyKeyFromContext devuelve la key base asociada a ctx, si la hay.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key, ok && key != ""
+}
+
+// SetIdempotencyKey pone el header Idempotency-Key en req a partir de la key
+// base del contexto de la request. parts distingue varias requests emitidas
+// dentro de la misma operación (por ejemplo, una por environment). Si el
+// contexto no trae key, no hace nada.
+func SetIdempotencyKey(req *http.Request, parts ...string) {
+	key, ok := IdempotencyKeyFromContext(req.Context())
+	if !ok {
+		return
+	}
+	if len(parts) > 0 {
+		key += ":" + strings.Join(parts, ":")
+	}
+	req.Header.Set(IdempotencyKeyHeader, key)
+}
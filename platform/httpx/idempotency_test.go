@@ -0,0 +1,159 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func newIdempotentTestHandler(calls *int, status int) http.Handler {
+	store := NewMemoryIdempotencyStore(time.Hour)
+	return Idempotent(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		WriteJSON(w, status, map[string]int{"call": *calls})
+	}))
+}
+
+func doIdempotent(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/commands/things", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotent_ReplaysOriginalResponse(t *testing.T) {
+	calls := 0
+	h := newIdempotentTestHandler(&calls, http.StatusCreated)
+
+	first := doIdempotent(h, "k-1", `{"id":"a"}`)
+	second := doIdempotent(h, "k-1", `{"id":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replay of %d %q, got %d %q", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected %s header on replay", IdempotentReplayedHeader)
+	}
+}
+
+func TestIdempotent_RejectsKeyReuseWithDifferentPayload(t *testing.T) {
+	calls := 0
+	h := newIdempotentTestHandler(&calls, http.StatusCreated)
+
+	doIdempotent(h, "k-1", `{"id":"a"}`)
+	rec := doIdempotent(h, "k-1", `{"id":"b"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotent_DoesNotCacheServerErrors(t *testing.T) {
+	calls := 0
+	h := newIdempotentTestHandler(&calls, http.StatusInternalServerError)
+
+	doIdempotent(h, "k-1", `{"id":"a"}`)
+	doIdempotent(h, "k-1", `{"id":"a"}`)
+
+	if calls != 2 {
+		t.Fatalf("expected 5xx not to be cached, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotent_DoesNotCacheConcurrentModifications(t *testing.T) {
+	calls := 0
+	h := Idempotent(NewMemoryIdempotencyStore(time.Hour), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		WriteError(w, r, perrors.Conflict(perrors.CodeConcurrentModification, "modified concurrently", nil))
+	}))
+
+	first := doIdempotent(h, "k-1", `{"id":"a"}`)
+	doIdempotent(h, "k-1", `{"id":"a"}`)
+
+	if first.Code != http.StatusConflict || first.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected retryable 409, got %d %v", first.Code, first.Header())
+	}
+	if calls != 2 {
+		t.Fatalf("expected concurrent_modification not to be cached, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotent_ReleasesKeyWhenHandlerPanics(t *testing.T) {
+	calls := 0
+	h := Idempotent(NewMemoryIdempotencyStore(time.Hour), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		WriteJSON(w, http.StatusCreated, map[string]int{"call": calls})
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected the panic to propagate")
+			}
+		}()
+		doIdempotent(h, "k-1", `{"id":"a"}`)
+	}()
+	if rec := doIdempotent(h, "k-1", `{"id":"a"}`); rec.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("expected retry to run the handler again, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotent_RejectsBodiesOverLimit(t *testing.T) {
+	calls := 0
+	h := newIdempotentTestHandler(&calls, http.StatusCreated)
+
+	rec := doIdempotent(h, "k-1", strings.Repeat("x", maxIdempotentBodySize+1))
+	if rec.Code != http.StatusRequestEntityTooLarge || calls != 0 {
+		t.Fatalf("expected 413 without running the handler, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotent_WithoutKeyPassesThrough(t *testing.T) {
+	calls := 0
+	h := newIdempotentTestHandler(&calls, http.StatusCreated)
+
+	doIdempotent(h, "", `{"id":"a"}`)
+	doIdempotent(h, "", `{"id":"a"}`)
+
+	if calls != 2 {
+		t.Fatalf("expected handler to run for every request without key, ran %d times", calls)
+	}
+}
+
+func TestMemoryIdempotencyStore_SweepsExpiredKeys(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore(time.Hour)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, key := range []string{"k-1", "k-2", "k-3"} {
+		if _, err := store.Begin(ctx, key, "h"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := store.Begin(ctx, "k-4", "h"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(store.entries) != 1 {
+		t.Fatalf("expected expired keys to be swept, %d remain", len(store.entries))
+	}
+}
@@ -0,0 +1,22 @@
+package httpx
+
+import "context"
+
+// OrganizationHeader indica la organización en la que opera una request.
+const OrganizationHeader = "X-Organization-ID"
+
+type organizationCtxKey struct{}
+
+// WithOrganization asocia a ctx la organización que los clientes HTTP
+// envían en OrganizationHeader. Sirve para procesos que atienden varias
+// organizaciones con un mismo cliente, como las actividades de
+// workflow-engine.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationCtxKey{}, organizationID)
+}
+
+// OrganizationFromContext devuelve la organización asociada a ctx, si la hay.
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	org, ok := ctx.Value(organizationCtxKey{}).(string)
+	return org, ok && org != ""
+}
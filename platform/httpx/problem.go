@@ -0,0 +1,107 @@
+package httpx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType es el media type de los errores HTTP (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// maxProblemBody acota cuánto leemos del body de un error remoto.
+const maxProblemBody = 64 << 10
+
+// Problem es el cuerpo estándar de error de todos los servicios
+// (application/problem+json). Además de los miembros de RFC 7807 lleva el
+// Code/Kind estables de perrors, el trace ID de la request y, para errores de
+// validación, el detalle por campo.
+type Problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Code     string               `json:"code"`
+	Kind     perrors.Kind         `json:"kind,omitempty"`
+	TraceID  string               `json:"traceId,omitempty"`
+	Errors   []perrors.FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem construye el Problem para err con el status dado. El trace ID se
+// toma del span activo en la request, si lo hay.
+func NewProblem(r *http.Request, status int, err error) Problem {
+	code := perrors.Code(err)
+	if code == "" {
+		code = "unknown_error"
+	}
+
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Kind:   perrors.KindOf(err),
+		Errors: perrors.Fields(err),
+	}
+	if err != nil {
+		p.Detail = err.Error()
+	}
+	if r != nil {
+		p.Instance = r.URL.Path
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			p.TraceID = sc.TraceID().String()
+		}
+	}
+	return p
+}
+
+// WriteProblem escribe p como application/problem+json.
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// ParseProblem interpreta el body de una respuesta de error. Acepta
+// problem+json, el shape legacy {code,message} y texto plano (que queda como
+// Detail), de modo que los clientes funcionen también contra versiones
+// anteriores de los servicios.
+func ParseProblem(status int, body []byte) Problem {
+	var payload struct {
+		Problem
+		Message string `json:"message"` // shape legacy {code,message}
+	}
+
+	var p Problem
+	if err := json.Unmarshal(body, &payload); err != nil {
+		p.Detail = strings.TrimSpace(string(body))
+	} else {
+		p = payload.Problem
+		if p.Detail == "" {
+			p.Detail = payload.Message
+		}
+	}
+	if p.Status == 0 {
+		p.Status = status
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(status)
+	}
+	return p
+}
+
+// ReadProblem lee (acotado) y cierra el body de resp y lo interpreta con
+// ParseProblem.
+func ReadProblem(resp *http.Response) Problem {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProblemBody))
+	return ParseProblem(resp.StatusCode, body)
+}
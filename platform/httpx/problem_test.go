@@ -0,0 +1,69 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func TestWriteError_WritesProblemJSON(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/commands/teams", nil)
+	rec := httptest.NewRecorder()
+
+	err := perrors.Validation("invalid_request_body", "invalid request body", nil).
+		WithFields(perrors.FieldError{Field: "name", Message: "is required"})
+	WriteError(rec, r, err)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("expected content type %q, got %q", ProblemContentType, ct)
+	}
+
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("expected problem JSON, got %v", err)
+	}
+	if p.Status != http.StatusBadRequest || p.Code != "invalid_request_body" || p.Kind != perrors.KindValidation {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+	if p.Instance != "/commands/teams" || len(p.Errors) != 1 {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	cases := map[perrors.Kind]int{
+		perrors.KindDomain:             http.StatusBadRequest,
+		perrors.KindValidation:         http.StatusBadRequest,
+		perrors.KindNotFound:           http.StatusNotFound,
+		perrors.KindConflict:           http.StatusConflict,
+		perrors.KindUnauthorized:       http.StatusUnauthorized,
+		perrors.KindForbidden:          http.StatusForbidden,
+		perrors.KindRateLimited:        http.StatusTooManyRequests,
+		perrors.KindPreconditionFailed: http.StatusPreconditionFailed,
+		perrors.KindUpstream:           http.StatusBadGateway,
+		perrors.KindInternal:           http.StatusInternalServerError,
+	}
+	for kind, want := range cases {
+		err := &perrors.Error{Kind: kind, Code: "x", Message: "x"}
+		if got := StatusFor(err); got != want {
+			t.Errorf("kind %s: expected %d, got %d", kind, want, got)
+		}
+	}
+	if got := StatusFor(errors.New("boom")); got != http.StatusInternalServerError {
+		t.Errorf("unclassified error: expected %d, got %d", http.StatusInternalServerError, got)
+	}
+}
+
+func TestParseProblem_FallsBackToPlainText(t *testing.T) {
+	p := ParseProblem(http.StatusBadGateway, []byte("bad gateway\n"))
+	if p.Status != http.StatusBadGateway || p.Detail != "bad gateway" || p.Code != "" {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+}
@@ -0,0 +1,233 @@
+package httpx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuevo-idp/platform/config"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/observability"
+)
+
+// Scopes de rate limiting. Cada request consume un token de cada bucket que
+// le corresponde (por ejemplo, el del principal y el de cada uno de sus
+// teams), así un team no puede saturar el servicio repartiendo la carga
+// entre varios usuarios.
+const (
+	RateLimitScopePrincipal = "principal"
+	RateLimitScopeTeam      = "team"
+	RateLimitScopeService   = "service"
+	RateLimitScopeClient    = "client"
+)
+
+// RateLimit es la configuración de un token bucket: Rate tokens por segundo
+// y una ráfaga máxima de Burst. Un Rate <= 0 deshabilita el límite.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseRateLimit interpreta "<rate>/<burst>" (por ejemplo "10/20"). "off" o
+// "0" deshabilitan el límite.
+func ParseRateLimit(s string) (RateLimit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return RateLimit{}, nil
+	}
+	rate, burst, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected <rate>/<burst>", s)
+	}
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+	b, err := strconv.Atoi(burst)
+	if err != nil || b < 1 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", s)
+	}
+	return RateLimit{Rate: r, Burst: b}, nil
+}
+
+// RateLimitKey identifica un bucket dentro de un grupo de rutas.
+type RateLimitKey struct {
+	Scope string
+	ID    string
+}
+
+// RateLimitKeyFunc devuelve los buckets que consume una request. Se evalúa
+// después de la autenticación, así que puede leer el principal del contexto.
+type RateLimitKeyFunc func(r *http.Request) []RateLimitKey
+
+// RateLimitPolicy configura los límites de un grupo de rutas (por ejemplo,
+// "commands" o "queries"). Los scopes sin límite no se restringen.
+type RateLimitPolicy struct {
+	Group  string
+	Limits map[string]RateLimit
+}
+
+// RateLimitPolicyFromEnv parte de defaults y los sobrescribe con las
+// variables RATE_LIMIT_<GROUP>_<SCOPE> (por ejemplo
+// RATE_LIMIT_COMMANDS_PRINCIPAL=5/20). Un valor inválido conserva el
+// default y se informa en el error, que nombra cada variable inválida; la
+// política devuelta sirve igual.
+func RateLimitPolicyFromEnv(group string, defaults map[string]RateLimit) (RateLimitPolicy, error) {
+	limits := make(map[string]RateLimit, len(defaults))
+	for scope, limit := range defaults {
+		limits[scope] = limit
+	}
+	var errs []error
+	for _, scope := range []string{RateLimitScopePrincipal, RateLimitScopeTeam, RateLimitScopeService, RateLimitScopeClient} {
+		key := "RATE_LIMIT_" + strings.ToUpper(group) + "_" + strings.ToUpper(scope)
+		raw := config.Get(key, "")
+		if raw == "" {
+			continue
+		}
+		limit, err := ParseRateLimit(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		limits[scope] = limit
+	}
+	return RateLimitPolicy{Group: group, Limits: limits}, errors.Join(errs...)
+}
+
+// RateLimiter aplica token buckets en memoria. Los buckets inactivos se
+// descartan pasado idleTTL. Igual que MemoryIdempotencyStore, el estado es
+// por réplica.
+type RateLimiter struct {
+	keys    RateLimitKeyFunc
+	now     func() time.Time
+	idleTTL time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+const defaultRateLimitIdleTTL = 10 * time.Minute
+
+// NewRateLimiter crea un RateLimiter que resuelve los buckets con keys.
+func NewRateLimiter(keys RateLimitKeyFunc) *RateLimiter {
+	return &RateLimiter{
+		keys:    keys,
+		now:     time.Now,
+		idleTTL: defaultRateLimitIdleTTL,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Middleware limita next según policy. Si algún bucket está vacío responde
+// 429 problem+json con código "rate_limited" y Retry-After, sin consumir
+// tokens de los demás buckets.
+func (l *RateLimiter) Middleware(policy RateLimitPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wait, scope := l.take(policy, l.keys(r))
+		if wait > 0 {
+			observability.ObserveRateLimited(policy.Group, scope)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			WriteError(w, r, perrors.RateLimited("rate_limited", "rate limit exceeded for "+scope, nil))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take consume un token de cada bucket de keys. Si alguno no tiene tokens,
+// no consume ninguno y devuelve la espera hasta el próximo token junto con
+// el scope que limitó.
+func (l *RateLimiter) take(policy RateLimitPolicy, keys []RateLimitKey) (time.Duration, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	type candidate struct {
+		bucket *tokenBucket
+		tokens float64
+	}
+	candidates := make([]candidate, 0, len(keys))
+	var (
+		maxWait time.Duration
+		limited string
+	)
+	for _, k := range keys {
+		limit, ok := policy.Limits[k.Scope]
+		if !ok || limit.Rate <= 0 {
+			continue
+		}
+		id := policy.Group + "|" + k.Scope + "|" + k.ID
+		b, ok := l.buckets[id]
+		if !ok {
+			b = &tokenBucket{tokens: float64(limit.Burst), last: now}
+			l.buckets[id] = b
+		}
+		tokens := math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+		if tokens < 1 {
+			wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+			if wait > maxWait {
+				maxWait, limited = wait, k.Scope
+			}
+		}
+		candidates = append(candidates, candidate{bucket: b, tokens: tokens})
+	}
+	if maxWait > 0 {
+		return maxWait, limited
+	}
+	for _, c := range candidates {
+		c.bucket.tokens = c.tokens - 1
+		c.bucket.last = now
+	}
+	return 0, ""
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTTL {
+		return
+	}
+	for id, b := range l.buckets {
+		if now.Sub(b.last) > l.idleTTL {
+			delete(l.buckets, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+// ClientKey identifica al cliente por IP remota. Es el fallback para
+// requests sin identidad.
+func ClientKey(r *http.Request) RateLimitKey {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return RateLimitKey{Scope: RateLimitScopeClient, ID: host}
+}
+
+// ServiceTokenKeys devuelve un RateLimitKeyFunc que identifica a los
+// servicios internos por el token del header dado (hasheado, para no
+// retener el secreto) y al resto de clientes por IP.
+func ServiceTokenKeys(header string) RateLimitKeyFunc {
+	return func(r *http.Request) []RateLimitKey {
+		if token := r.Header.Get(header); token != "" {
+			sum := sha256.Sum256([]byte(token))
+			return []RateLimitKey{{Scope: RateLimitScopeService, ID: hex.EncodeToString(sum[:8])}}
+		}
+		return []RateLimitKey{ClientKey(r)}
+	}
+}
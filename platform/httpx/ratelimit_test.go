@@ -0,0 +1,125 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRateLimitTestHandler(keys RateLimitKeyFunc, limits map[string]RateLimit) (http.Handler, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	l := NewRateLimiter(keys)
+	l.now = func() time.Time { return now }
+	h := l.Middleware(RateLimitPolicy{Group: "commands", Limits: limits}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return h, &now
+}
+
+func doRateLimited(h http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/commands/things", nil)
+	if token != "" {
+		req.Header.Set("X-Internal-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimiter_ThrottlesAfterBurstAndRefills(t *testing.T) {
+	h, now := newRateLimitTestHandler(ServiceTokenKeys("X-Internal-Token"), map[string]RateLimit{
+		RateLimitScopeService: {Rate: 0.5, Burst: 2},
+	})
+
+	for i := 0; i < 2; i++ {
+		if rec := doRateLimited(h, "engine"); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: expected %d, got %d", i, http.StatusNoContent, rec.Code)
+		}
+	}
+
+	rec := doRateLimited(h, "engine")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d after burst, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("expected problem+json, got %q", ct)
+	}
+
+	// Otro token tiene su propio bucket.
+	if rec := doRateLimited(h, "other"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected independent bucket per token, got %d", rec.Code)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if rec := doRateLimited(h, "engine"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected bucket to refill, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiter_DoesNotConsumeWhenAnyBucketIsEmpty(t *testing.T) {
+	team := RateLimitKey{Scope: RateLimitScopeTeam, ID: "team-a"}
+	keysFor := func(user string) RateLimitKeyFunc {
+		return func(*http.Request) []RateLimitKey {
+			return []RateLimitKey{{Scope: RateLimitScopePrincipal, ID: user}, team}
+		}
+	}
+	limits := map[string]RateLimit{
+		RateLimitScopePrincipal: {Rate: 1, Burst: 1},
+		RateLimitScopeTeam:      {Rate: 1, Burst: 2},
+	}
+
+	l := NewRateLimiter(nil)
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+	policy := RateLimitPolicy{Group: "commands", Limits: limits}
+
+	if wait, _ := l.take(policy, keysFor("alice")(nil)); wait != 0 {
+		t.Fatalf("alice: expected token, waited %s", wait)
+	}
+	if wait, scope := l.take(policy, keysFor("alice")(nil)); wait == 0 || scope != RateLimitScopePrincipal {
+		t.Fatalf("alice: expected principal limit, got wait=%s scope=%q", wait, scope)
+	}
+	// El rechazo de alice no consumió el bucket del team: bob todavía entra.
+	if wait, _ := l.take(policy, keysFor("bob")(nil)); wait != 0 {
+		t.Fatalf("bob: expected token, waited %s", wait)
+	}
+	if wait, scope := l.take(policy, keysFor("carol")(nil)); wait == 0 || scope != RateLimitScopeTeam {
+		t.Fatalf("carol: expected team limit, got wait=%s scope=%q", wait, scope)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	if got, err := ParseRateLimit("10/20"); err != nil || got != (RateLimit{Rate: 10, Burst: 20}) {
+		t.Fatalf("unexpected %+v %v", got, err)
+	}
+	if got, err := ParseRateLimit("off"); err != nil || got.Rate != 0 {
+		t.Fatalf("expected disabled limit, got %+v %v", got, err)
+	}
+	for _, bad := range []string{"10", "x/2", "1/0"} {
+		if _, err := ParseRateLimit(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+
+	t.Setenv("RATE_LIMIT_QUERIES_TEAM", "3/4")
+	p, err := RateLimitPolicyFromEnv("queries", map[string]RateLimit{RateLimitScopePrincipal: {Rate: 1, Burst: 1}})
+	if err != nil || p.Limits[RateLimitScopeTeam] != (RateLimit{Rate: 3, Burst: 4}) || p.Limits[RateLimitScopePrincipal].Burst != 1 {
+		t.Fatalf("unexpected policy %+v %v", p, err)
+	}
+
+	// Un valor inválido conserva el default y el error nombra la variable.
+	t.Setenv("RATE_LIMIT_QUERIES_PRINCIPAL", "10")
+	t.Setenv("RATE_LIMIT_QUERIES_CLIENT", "x/2")
+	p, err = RateLimitPolicyFromEnv("queries", map[string]RateLimit{RateLimitScopePrincipal: {Rate: 1, Burst: 1}})
+	if err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_QUERIES_PRINCIPAL") || !strings.Contains(err.Error(), "RATE_LIMIT_QUERIES_CLIENT") {
+		t.Fatalf("expected an error naming both variables, got %v", err)
+	}
+	if p.Limits[RateLimitScopePrincipal] != (RateLimit{Rate: 1, Burst: 1}) || p.Limits[RateLimitScopeTeam] != (RateLimit{Rate: 3, Burst: 4}) {
+		t.Fatalf("expected defaults and valid overrides to be kept, got %+v", p)
+	}
+}
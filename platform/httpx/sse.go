@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EventStreamContentType es el media type de Server-Sent Events.
+const EventStreamContentType = "text/event-stream"
+
+// EventStream escribe Server-Sent Events sobre una respuesta HTTP.
+type EventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewEventStream prepara w para SSE: fija los headers, quita el write
+// deadline del servidor (el stream dura lo que dure la conexión) y envía
+// los headers. Devuelve error si w no soporta flush.
+func NewEventStream(w http.ResponseWriter) (*EventStream, error) {
+	rc := http.NewResponseController(w)
+
+	h := w.Header()
+	h.Set("Content-Type", EventStreamContentType)
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+
+	// Algunos writers (p.ej. httptest.ResponseRecorder) no soportan
+	// deadlines; no es fatal.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return nil, fmt.Errorf("event stream requires a flushable response writer: %w", err)
+	}
+	return &EventStream{w: w, rc: rc}, nil
+}
+
+// Send escribe un evento con el id y nombre dados (ambos opcionales) y data
+// serializada como JSON en una sola línea.
+func (s *EventStream) Send(id, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal event data: %w", err)
+	}
+
+	var b strings.Builder
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", payload)
+	return s.write(b.String())
+}
+
+// Comment escribe un comentario SSE; sirve de heartbeat para que proxies y
+// clientes no den la conexión por muerta.
+func (s *EventStream) Comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+func (s *EventStream) write(chunk string) error {
+	if _, err := s.w.Write([]byte(chunk)); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("flush event: %w", err)
+	}
+	return nil
+}
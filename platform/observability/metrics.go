@@ -53,6 +53,14 @@ var (
 		[]string{"workflow", "result"},
 	)
 
+	httpRateLimitedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_rate_limited_total",
+			Help: "Total de requests HTTP rechazadas por rate limiting por grupo de rutas y scope.",
+		},
+		[]string{"group", "scope"},
+	)
+
 	workflowRetriesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "workflow_retries_total",
@@ -64,7 +72,7 @@ var (
 
 // InitMetrics registra los collectors HTTP globales. Debe llamarse una vez en main.
 func InitMetrics() {
-	prometheus.MustRegister(httpRequestsTotal, httpRequestDurationSeconds, domainEventsTotal, downstreamErrorsTotal, workflowRunDurationSeconds, workflowRetriesTotal)
+	prometheus.MustRegister(httpRequestsTotal, httpRequestDurationSeconds, domainEventsTotal, downstreamErrorsTotal, workflowRunDurationSeconds, workflowRetriesTotal, httpRateLimitedTotal)
 }
 
 // ObserveDomainEvent incrementa un contador para eventos de dominio de alto nivel.
@@ -86,6 +94,13 @@ func ObserveDownstreamError(target, code string, status int) {
 	downstreamErrorsTotal.WithLabelValues(target, code, statusStr).Inc()
 }
 
+// ObserveRateLimited incrementa el contador de requests rechazadas por rate
+// limiting. "group" es el grupo de rutas (commands, queries, ...) y "scope"
+// el bucket que se agotó (principal, team, service, client).
+func ObserveRateLimited(group, scope string) {
+	httpRateLimitedTotal.WithLabelValues(group, scope).Inc()
+}
+
 // ObserveWorkflowDuration registra la duración de una ejecución de workflow
 // en segundos, etiquetada por nombre lógico y resultado (success/error).
 func ObserveWorkflowDuration(workflowName, result string, seconds float64) {
@@ -147,6 +162,12 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap permite que http.ResponseController llegue al writer original
+// (Flush, deadlines), necesario para respuestas en streaming.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // normalizeRoute intenta reducir la cardinalidad de las rutas HTTP
 // reemplazando IDs numéricos o UUIDs comunes por comodines.
 func normalizeRoute(path string) string {
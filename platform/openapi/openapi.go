@@ -0,0 +1,222 @@
+// Package openapi genera documentos OpenAPI 3 a partir de la tabla de rutas
+// de cada servicio y de los tipos Go de request/response, y valida los
+// bodies entrantes contra los schemas derivados de esos mismos tipos.
+//
+// La idea es que el contrato HTTP tenga una única fuente de verdad: los
+// structs de request que ya usan los handlers.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version es la versión de la especificación OpenAPI que emitimos.
+const Version = "3.0.3"
+
+// Info describe metadatos generales del documento.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Param describe un parámetro de query o header de una operación.
+type Param struct {
+	Name        string
+	In          string // "query" o "header"
+	Description string
+	Required    bool
+}
+
+// Operation es una entrada de la tabla de rutas de un servicio. Request y
+// Response son valores cero de los tipos Go correspondientes (o nil).
+type Operation struct {
+	Method      string
+	Path        string
+	ID          string
+	Summary     string
+	Tags        []string
+	Params      []Param
+	Request     any
+	Response    any
+	Status      int // status de éxito; por defecto 200
+	Description string
+	// ResponseContentType es el media type de la respuesta de éxito; por
+	// defecto application/json (p.ej. text/event-stream para streams SSE).
+	ResponseContentType string
+}
+
+// Spec agrupa la información necesaria para construir un documento.
+// Error es el tipo usado para documentar las respuestas de error y
+// ErrorContentType su media type (por defecto application/json).
+type Spec struct {
+	Info             Info
+	Operations       []Operation
+	Error            any
+	ErrorContentType string
+}
+
+// Document es la representación JSON (subset) de un documento OpenAPI 3.
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*opObject `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+type components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type opObject struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []paramObject        `json:"parameters,omitempty"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type paramObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Build genera el documento OpenAPI para la especificación dada.
+func Build(spec Spec) *Document {
+	g := &generator{components: map[string]*Schema{}, visiting: map[reflect.Type]bool{}}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    spec.Info,
+		Paths:   map[string]map[string]*opObject{},
+	}
+
+	var errSchema *Schema
+	errContentType := spec.ErrorContentType
+	if errContentType == "" {
+		errContentType = "application/json"
+	}
+	if spec.Error != nil {
+		errSchema = g.schema(reflect.TypeOf(spec.Error))
+	}
+
+	for _, op := range spec.Operations {
+		item, ok := doc.Paths[op.Path]
+		if !ok {
+			item = map[string]*opObject{}
+			doc.Paths[op.Path] = item
+		}
+
+		obj := &opObject{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Description: op.Description,
+			Tags:        op.Tags,
+			Responses:   map[string]*response{},
+		}
+
+		for _, p := range op.Params {
+			in := p.In
+			if in == "" {
+				in = "query"
+			}
+			obj.Parameters = append(obj.Parameters, paramObject{
+				Name:        p.Name,
+				In:          in,
+				Description: p.Description,
+				Required:    p.Required,
+				Schema:      &Schema{Type: "string"},
+			})
+		}
+
+		if op.Request != nil {
+			obj.RequestBody = &requestBody{
+				Required: true,
+				Content: map[string]mediaType{
+					"application/json": {Schema: g.schema(reflect.TypeOf(op.Request))},
+				},
+			}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		ok2 := &response{Description: http.StatusText(status)}
+		if op.Response != nil {
+			contentType := op.ResponseContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			ok2.Content = map[string]mediaType{
+				contentType: {Schema: g.schema(reflect.TypeOf(op.Response))},
+			}
+		}
+		obj.Responses[strconv.Itoa(status)] = ok2
+
+		if errSchema != nil {
+			obj.Responses["default"] = &response{
+				Description: "Error",
+				Content:     map[string]mediaType{errContentType: {Schema: errSchema}},
+			}
+		}
+
+		item[strings.ToLower(op.Method)] = obj
+	}
+
+	doc.Components.Schemas = g.components
+	return doc
+}
+
+// Handler devuelve un http.Handler que sirve el documento como JSON.
+func (d *Document) Handler() http.Handler {
+	body, err := json.MarshalIndent(d, "", "  ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
+
+// SortedPaths devuelve los paths documentados, ordenados. Útil para tests y
+// fitness functions que verifican que la tabla de rutas esté completa.
+func (d *Document) SortedPaths() []string {
+	out := make([]string, 0, len(d.Paths))
+	for p := range d.Paths {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+type nested struct {
+	Key string `json:"key" validate:"required"`
+}
+
+type sampleRequest struct {
+	ID      string            `json:"id" validate:"required"`
+	Count   int               `json:"count"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Nested  *nested           `json:"nested"`
+}
+
+func TestDecodeJSON_Valid(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"id":"a","count":2,"tags":["x"],"labels":{"k":"v"},"nested":{"key":"k"}}`))
+
+	var dst sampleRequest
+	if err := DecodeJSON(r, &dst); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dst.ID != "a" || dst.Count != 2 || dst.Nested == nil || dst.Nested.Key != "k" {
+		t.Fatalf("unexpected decoded value: %+v", dst)
+	}
+}
+
+func TestDecodeJSON_FieldErrors(t *testing.T) {
+	body := `{"count":1.5,"enabled":"yes","tags":[1],"labels":{"k":2},"nested":{},"extra":true}`
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+
+	var dst sampleRequest
+	err := DecodeJSON(r, &dst)
+	if !perrors.IsKind(err, perrors.KindValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	want := map[string]bool{
+		"count": true, "enabled": true, "extra": true, "id": true,
+		"labels.k": true, "nested.key": true, "tags[0]": true,
+	}
+	got := perrors.Fields(err)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d field errors, got %+v", len(want), got)
+	}
+	for _, f := range got {
+		if !want[f.Field] {
+			t.Errorf("unexpected field error %+v", f)
+		}
+	}
+}
+
+func TestDecodeJSON_InvalidJSON(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`not-json`))
+
+	var dst sampleRequest
+	if err := DecodeJSON(r, &dst); perrors.Code(err) != "invalid_json" {
+		t.Fatalf("expected invalid_json, got %v", err)
+	}
+}
+
+func TestDecodeJSONBytes_RawMessageIsDecodedLater(t *testing.T) {
+	var envelope struct {
+		Kind string          `json:"kind" validate:"required"`
+		Body json.RawMessage `json:"body" validate:"required"`
+	}
+	if err := DecodeJSONBytes([]byte(`{"kind":"sample","body":{"count":1}}`), &envelope); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// El documento embebido se valida con su propio schema.
+	var dst sampleRequest
+	err := DecodeJSONBytes(envelope.Body, &dst)
+	if fields := perrors.Fields(err); len(fields) != 1 || fields[0].Field != "id" {
+		t.Fatalf("expected id field error, got %v", err)
+	}
+}
+
+func TestBuild_RegistersComponents(t *testing.T) {
+	doc := Build(Spec{
+		Info:       Info{Title: "test", Version: "v1"},
+		Operations: []Operation{{Method: "POST", Path: "/things", ID: "createThing", Request: sampleRequest{}, Status: 201}},
+	})
+
+	op := doc.Paths["/things"]["post"]
+	if op == nil || op.RequestBody == nil {
+		t.Fatalf("expected POST /things with request body")
+	}
+	if _, ok := doc.Components.Schemas["sampleRequest"]; !ok {
+		t.Fatalf("expected sampleRequest component, got %v", doc.Components.Schemas)
+	}
+	if _, ok := op.Responses["201"]; !ok {
+		t.Fatalf("expected 201 response")
+	}
+}
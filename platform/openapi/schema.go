@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema es el subset de JSON Schema/OpenAPI que usamos tanto para
+// documentar como para validar bodies.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// generator construye schemas por reflexión. Si components es nil, los
+// structs se expanden inline (modo validación); si no, los structs con
+// nombre se registran como componentes y se referencian con $ref.
+type generator struct {
+	components map[string]*Schema
+	visiting   map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t == rawMessageType {
+		// JSON sin interpretar: lo valida quien lo decodifica.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// interface{} y otros: cualquier valor JSON.
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	name := t.Name()
+	if g.components != nil && name != "" {
+		ref := &Schema{Ref: "#/components/schemas/" + name}
+		if _, ok := g.components[name]; ok || g.visiting[t] {
+			return ref
+		}
+		g.visiting[t] = true
+		g.components[name] = g.objectSchema(t)
+		delete(g.visiting, t)
+		return ref
+	}
+
+	if g.visiting[t] {
+		// Tipo recursivo en modo inline: no podemos expandirlo más.
+		return &Schema{Type: "object"}
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+	return g.objectSchema(t)
+}
+
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			g.addFields(s, f.Type)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+		}
+
+		prop := g.schema(f.Type)
+		if hasTagOption(f.Tag.Get("validate"), "required") {
+			s.Required = append(s.Required, name)
+			if prop.Type == "string" {
+				one := 1
+				cp := *prop
+				cp.MinLength = &one
+				prop = &cp
+			}
+		}
+		s.Properties[name] = prop
+	}
+}
+
+func hasTagOption(tag, opt string) bool {
+	for _, p := range strings.Split(tag, ",") {
+		if strings.TrimSpace(p) == opt {
+			return true
+		}
+	}
+	return false
+}
+
+var inlineCache sync.Map // reflect.Type -> *Schema
+
+// SchemaOf devuelve el schema inline (sin $ref) para el tipo de v. Los
+// resultados se cachean por tipo.
+func SchemaOf(v any) *Schema {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return &Schema{}
+	}
+	if cached, ok := inlineCache.Load(t); ok {
+		if s, ok := cached.(*Schema); ok {
+			return s
+		}
+	}
+	g := &generator{visiting: map[reflect.Type]bool{}}
+	s := g.schema(t)
+	inlineCache.Store(t, s)
+	return s
+}
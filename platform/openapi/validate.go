@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// MaxBodyBytes limita el tamaño de los bodies que aceptamos validar.
+const MaxBodyBytes = 1 << 20
+
+// Validate valida un valor JSON ya decodificado (con UseNumber) contra el
+// schema y devuelve los errores de campo encontrados, ordenados por campo.
+func (s *Schema) Validate(v any) []perrors.FieldError {
+	var out []perrors.FieldError
+	s.validate("", v, &out)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+func (s *Schema) validate(path string, v any, out *[]perrors.FieldError) {
+	if s == nil || s.Type == "" {
+		return
+	}
+
+	field := path
+	if field == "" {
+		field = "body"
+	}
+	add := func(msg string) {
+		*out = append(*out, perrors.FieldError{Field: field, Message: msg})
+	}
+
+	if v == nil {
+		// null se trata como ausente; la obligatoriedad se valida en el objeto padre.
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			add("must be a string")
+			return
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			add("must not be empty")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			add("must be a boolean")
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			add("must be an integer")
+			return
+		}
+		if _, err := n.Int64(); err != nil {
+			add("must be an integer")
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			add("must be a number")
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			add("must be an array")
+			return
+		}
+		for i, item := range items {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, out)
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			add("must be an object")
+			return
+		}
+		s.validateObject(path, obj, out)
+	}
+}
+
+func (s *Schema) validateObject(path string, obj map[string]any, out *[]perrors.FieldError) {
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	for _, name := range s.Required {
+		if val, ok := obj[name]; !ok || val == nil {
+			*out = append(*out, perrors.FieldError{Field: join(name), Message: "is required"})
+		}
+	}
+
+	for name, val := range obj {
+		if prop, ok := s.Properties[name]; ok {
+			prop.validate(join(name), val, out)
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				*out = append(*out, perrors.FieldError{Field: join(name), Message: "unknown field"})
+			}
+		case *Schema:
+			extra.validate(join(name), val, out)
+		}
+	}
+}
+
+// DecodeJSON lee el body de r, lo valida contra el schema derivado del tipo
+// de dst y, si es válido, lo decodifica en dst. Los fallos se devuelven como
+// *perrors.Error de tipo validation con el detalle por campo.
+func DecodeJSON(r *http.Request, dst any) error {
+	raw, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes+1))
+	if err != nil {
+		return perrors.Validation("invalid_request_body", "could not read request body", err)
+	}
+	if len(raw) > MaxBodyBytes {
+		return perrors.Validation("request_body_too_large", "request body too large", nil)
+	}
+	return DecodeJSONBytes(raw, dst)
+}
+
+// DecodeJSONBytes es DecodeJSON sobre un body ya leído; sirve para validar
+// documentos embebidos en otro (p.ej. cada comando de un batch).
+func DecodeJSONBytes(raw []byte, dst any) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return perrors.Validation("invalid_json", "request body is required", nil)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return perrors.Validation("invalid_json", "invalid json", err)
+	}
+	if dec.More() {
+		return perrors.Validation("invalid_json", "invalid json", errors.New("unexpected data after JSON value"))
+	}
+
+	if fields := SchemaOf(dst).Validate(generic); len(fields) > 0 {
+		msgs := make([]string, 0, len(fields))
+		for _, f := range fields {
+			msgs = append(msgs, f.Field+" "+f.Message)
+		}
+		return perrors.Validation("invalid_request_body", "invalid request body: "+strings.Join(msgs, "; "), nil).WithFields(fields...)
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return perrors.Validation("invalid_json", "invalid json", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Package validation define las reglas de identificadores y nombres de los
+// recursos del IDP.
+//
+// Los IDs siguen el formato de label DNS-1123 (minúsculas, dígitos y '-',
+// empiezan y terminan con alfanumérico, hasta 63 caracteres) porque terminan
+// en nombres de repositorios, namespaces y recursos de Kubernetes. Los IDs
+// derivados de otros (p.ej. "code-<appID>") se arman con JoinID para que el
+// resultado cumpla las mismas reglas en lugar de concatenarse a ciegas.
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+const (
+	// MaxIDLength es el largo máximo de un label DNS-1123.
+	MaxIDLength = 63
+	// MaxNameLength acota los nombres visibles (en caracteres, no bytes).
+	MaxNameLength = 100
+	// MaxRepositoryNameLength es el largo máximo de un nombre de repositorio
+	// en GitHub.
+	MaxRepositoryNameLength = 100
+)
+
+// reservedIDs no se aceptan como ID porque colisionan con rutas, palabras
+// clave de clientes o recursos del sistema.
+var reservedIDs = map[string]bool{
+	"admin":     true,
+	"all":       true,
+	"api":       true,
+	"internal":  true,
+	"new":       true,
+	"none":      true,
+	"null":      true,
+	"self":      true,
+	"system":    true,
+	"undefined": true,
+}
+
+// IsReservedID indica si id es una palabra reservada.
+func IsReservedID(id string) bool {
+	return reservedIDs[id]
+}
+
+// ID valida un identificador y devuelve el error de campo, o nil si es
+// válido.
+func ID(field, id string) *perrors.FieldError {
+	if msg := checkLabel(id, MaxIDLength); msg != "" {
+		return &perrors.FieldError{Field: field, Message: msg}
+	}
+	if IsReservedID(id) {
+		return &perrors.FieldError{Field: field, Message: fmt.Sprintf("%q is a reserved word", id)}
+	}
+	return nil
+}
+
+// Name valida un nombre visible: no vacío, sin espacios al principio ni al
+// final, sin caracteres de control ni '/', y hasta MaxNameLength caracteres.
+func Name(field, name string) *perrors.FieldError {
+	fail := func(msg string) *perrors.FieldError { return &perrors.FieldError{Field: field, Message: msg} }
+	switch {
+	case name == "":
+		return fail("is required")
+	case !utf8.ValidString(name):
+		return fail("must be valid UTF-8")
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return fail(fmt.Sprintf("must be at most %d characters", MaxNameLength))
+	case strings.TrimSpace(name) != name:
+		return fail("must not start or end with whitespace")
+	case strings.ContainsFunc(name, func(r rune) bool { return unicode.IsControl(r) || r == '/' }):
+		return fail("must not contain control characters or '/'")
+	}
+	return nil
+}
+
+// JoinID arma un ID derivado uniendo parts con '-' y valida el resultado,
+// de modo que un prefijo más un ID largo no produzca un ID inválido.
+func JoinID(parts ...string) (string, error) {
+	id := strings.Join(parts, "-")
+	if fe := ID("id", id); fe != nil {
+		return "", perrors.Validation("invalid_derived_id", fmt.Sprintf("derived id %q %s", id, fe.Message), nil).WithFields(*fe)
+	}
+	return id, nil
+}
+
+// RepositoryName arma el nombre de un repositorio Git uniendo parts con '-'.
+// Usa las reglas de los IDs con el largo máximo de GitHub.
+func RepositoryName(parts ...string) (string, error) {
+	name := strings.Join(parts, "-")
+	if msg := checkLabel(name, MaxRepositoryNameLength); msg != "" {
+		return "", perrors.Validation("invalid_repository_name", fmt.Sprintf("repository name %q %s", name, msg), nil).
+			WithFields(perrors.FieldError{Field: "repository", Message: msg})
+	}
+	return name, nil
+}
+
+// checkLabel valida el formato DNS-1123 con el largo máximo dado y devuelve
+// el mensaje de error, o "" si es válido.
+func checkLabel(s string, maxLen int) string {
+	if s == "" {
+		return "is required"
+	}
+	if len(s) > maxLen {
+		return fmt.Sprintf("must be at most %d characters", maxLen)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return "must contain only lowercase letters, digits and '-'"
+		}
+	}
+	if s[0] == '-' || s[len(s)-1] == '-' {
+		return "must start and end with a lowercase letter or digit"
+	}
+	return ""
+}
+
+// Validator acumula errores de campo para devolverlos juntos.
+type Validator struct {
+	fields []perrors.FieldError
+}
+
+// New crea un Validator vacío.
+func New() *Validator {
+	return &Validator{}
+}
+
+// ID valida un identificador (ver ID).
+func (v *Validator) ID(field, id string) *Validator {
+	return v.add(ID(field, id))
+}
+
+// OptionalID valida id sólo si no está vacío.
+func (v *Validator) OptionalID(field, id string) *Validator {
+	if id == "" {
+		return v
+	}
+	return v.ID(field, id)
+}
+
+// Name valida un nombre visible (ver Name).
+func (v *Validator) Name(field, name string) *Validator {
+	return v.add(Name(field, name))
+}
+
+// MaxLength valida que value no supere max caracteres; para campos libres
+// sin formato (p.ej. purpose).
+func (v *Validator) MaxLength(field, value string, maxLen int) *Validator {
+	if utf8.RuneCountInString(value) > maxLen {
+		v.fields = append(v.fields, perrors.FieldError{Field: field, Message: fmt.Sprintf("must be at most %d characters", maxLen)})
+	}
+	return v
+}
+
+// NonNegative valida que value no sea negativo; para límites y contadores.
+func (v *Validator) NonNegative(field string, value int) *Validator {
+	if value < 0 {
+		v.fields = append(v.fields, perrors.FieldError{Field: field, Message: "must not be negative"})
+	}
+	return v
+}
+
+func (v *Validator) add(fe *perrors.FieldError) *Validator {
+	if fe != nil {
+		v.fields = append(v.fields, *fe)
+	}
+	return v
+}
+
+// Err devuelve un error de validación con todos los errores de campo
+// acumulados, o nil si no hubo ninguno.
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(v.fields))
+	for _, f := range v.fields {
+		msgs = append(msgs, f.Field+" "+f.Message)
+	}
+	return perrors.Validation("invalid_arguments", "invalid arguments: "+strings.Join(msgs, "; "), nil).WithFields(v.fields...)
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func TestID(t *testing.T) {
+	valid := []string{"a", "team-1", "app-1-env-dev", "0abc", strings.Repeat("a", MaxIDLength)}
+	for _, id := range valid {
+		if fe := ID("id", id); fe != nil {
+			t.Errorf("%q: expected valid, got %s", id, fe.Message)
+		}
+	}
+
+	invalid := []string{"", "Team-1", "team 1", "team/1", "-team", "team-", "team_1", "équipo", strings.Repeat("a", MaxIDLength+1), "new", "all"}
+	for _, id := range invalid {
+		if fe := ID("id", id); fe == nil || fe.Field != "id" {
+			t.Errorf("%q: expected field error, got %+v", id, fe)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	for _, name := range []string{"Platform", "App A", "Equipo Pagos ñ"} {
+		if fe := Name("name", name); fe != nil {
+			t.Errorf("%q: expected valid, got %s", name, fe.Message)
+		}
+	}
+	for _, name := range []string{"", " Platform", "a/b", "line\nbreak", strings.Repeat("x", MaxNameLength+1)} {
+		if fe := Name("name", name); fe == nil {
+			t.Errorf("%q: expected field error", name)
+		}
+	}
+	// El largo se mide en caracteres: 100 'ñ' son 200 bytes pero son válidos.
+	if fe := Name("name", strings.Repeat("ñ", MaxNameLength)); fe != nil {
+		t.Errorf("expected multibyte name to be valid, got %s", fe.Message)
+	}
+}
+
+func TestJoinIDAndRepositoryName(t *testing.T) {
+	if id, err := JoinID("code", "app-1"); err != nil || id != "code-app-1" {
+		t.Fatalf("unexpected JoinID result %q, %v", id, err)
+	}
+	_, err := JoinID("code", strings.Repeat("a", MaxIDLength))
+	if perrors.Code(err) != "invalid_derived_id" || !perrors.IsKind(err, perrors.KindValidation) {
+		t.Fatalf("expected invalid_derived_id, got %v", err)
+	}
+
+	long := strings.Repeat("a", MaxIDLength)
+	if name, err := RepositoryName("appenv", long); err != nil || name != "appenv-"+long {
+		t.Fatalf("expected repository name to allow up to %d characters, got %q, %v", MaxRepositoryNameLength, name, err)
+	}
+	if _, err := RepositoryName("appenv", "App_1"); perrors.Code(err) != "invalid_repository_name" {
+		t.Fatalf("expected invalid_repository_name, got %v", err)
+	}
+}
+
+func TestValidatorAccumulatesFieldErrors(t *testing.T) {
+	err := New().ID("id", "Bad ID").Name("name", "").OptionalID("teamId", "").MaxLength("purpose", "abc", 2).NonNegative("limit", -1).NonNegative("count", 0).Err()
+	if perrors.Code(err) != "invalid_arguments" {
+		t.Fatalf("expected invalid_arguments, got %v", err)
+	}
+	fields := perrors.Fields(err)
+	if len(fields) != 4 || fields[0].Field != "id" || fields[1].Field != "name" || fields[2].Field != "purpose" || fields[3].Field != "limit" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+
+	if err := New().ID("id", "team-1").Name("name", "Platform").Err(); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
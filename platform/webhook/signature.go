@@ -0,0 +1,72 @@
+// Package webhook firma y verifica payloads de webhooks con HMAC-SHA256.
+//
+// La firma cubre "<timestamp>.<body>", con el timestamp en segundos Unix
+// enviado en su propio header, de modo que un receptor puede rechazar
+// replays fuera de una ventana de tolerancia. El formato del header de firma
+// es "sha256=<hex>".
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// Headers de un webhook firmado.
+const (
+	SignatureHeader = "X-IDP-Signature"
+	TimestampHeader = "X-IDP-Timestamp"
+	EventHeader     = "X-IDP-Event"
+	DeliveryHeader  = "X-IDP-Delivery"
+)
+
+const signaturePrefix = "sha256="
+
+// DefaultTolerance es la diferencia máxima aceptada entre el timestamp
+// firmado y el reloj del receptor.
+const DefaultTolerance = 5 * time.Minute
+
+// Sign devuelve el valor del header de firma para body enviado en ts.
+func Sign(secret []byte, ts time.Time, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, strconv.FormatInt(ts.Unix(), 10), body))
+}
+
+// Verify comprueba la firma y el timestamp de un webhook recibido. now es
+// el reloj del receptor; tolerance <= 0 usa DefaultTolerance. Devuelve un
+// error perrors de tipo Unauthorized si la firma no es válida.
+func Verify(secret []byte, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return perrors.Unauthorized("invalid_webhook_timestamp", "missing or malformed "+TimestampHeader, err)
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > tolerance || skew < -tolerance {
+		return perrors.Unauthorized("webhook_timestamp_out_of_range", "webhook timestamp outside tolerance", nil)
+	}
+
+	hexSig, ok := strings.CutPrefix(signature, signaturePrefix)
+	if !ok {
+		return perrors.Unauthorized("invalid_webhook_signature", "missing or malformed "+SignatureHeader, nil)
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil || !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return perrors.Unauthorized("invalid_webhook_signature", "webhook signature mismatch", err)
+	}
+	return nil
+}
+
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("s3cr3t")
+	body := []byte(`{"id":1}`)
+	sentAt := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(sentAt.Unix(), 10)
+	sig := Sign(secret, sentAt, body)
+
+	if err := Verify(secret, sig, ts, body, sentAt.Add(time.Minute), 0); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+
+	cases := map[string]struct {
+		secret []byte
+		sig    string
+		ts     string
+		body   []byte
+		now    time.Time
+		code   string
+	}{
+		"tampered body":  {secret, sig, ts, []byte(`{"id":2}`), sentAt, "invalid_webhook_signature"},
+		"wrong secret":   {[]byte("other"), sig, ts, body, sentAt, "invalid_webhook_signature"},
+		"missing prefix": {secret, sig[len("sha256="):], ts, body, sentAt, "invalid_webhook_signature"},
+		"bad timestamp":  {secret, sig, "yesterday", body, sentAt, "invalid_webhook_timestamp"},
+		"replayed":       {secret, sig, ts, body, sentAt.Add(time.Hour), "webhook_timestamp_out_of_range"},
+	}
+	for name, tc := range cases {
+		err := Verify(tc.secret, tc.sig, tc.ts, tc.body, tc.now, 0)
+		if !perrors.IsKind(err, perrors.KindUnauthorized) || perrors.Code(err) != tc.code {
+			t.Errorf("%s: expected unauthorized %s, got %v", name, tc.code, err)
+		}
+	}
+}
@@ -2,27 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 	"go.uber.org/zap"
 
 	"github.com/nuevo-idp/platform/config"
-	"github.com/nuevo-idp/platform/httpx"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/health"
 	"github.com/nuevo-idp/platform/observability"
 	"github.com/nuevo-idp/platform/tracing"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/appenvprovhttp"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/gitproviderhttp"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/hooksinks"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/secretbindingshttp"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/webhookreceiver"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/workflowapi"
 	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
 )
 
@@ -37,40 +43,56 @@ func main() {
 
 	observability.InitMetrics()
 
-	// Inicializar tracing global para el servicio.
+	// Inicializar tracing global para el servicio. El flush de spans es un
+	// paso del drenado.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	shutdownTracing, err := tracing.InitTracing(ctx, "workflow-engine")
 	if err != nil {
 		logger.Warn("failed to initialize tracing", zap.Error(err))
-	} else {
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = shutdownTracing(shutdownCtx)
-		}()
 	}
 
-	// HTTP health endpoint
+	cpBaseURL := config.Get("CONTROL_PLANE_API_URL", "http://control-plane-api:8080")
+	ewBaseURL := config.Get("EXECUTION_WORKERS_URL", "http://execution-workers:8082")
+	drainOpts := health.DrainOptionsFromEnv()
+
+	// Readiness: sin Temporal el engine no procesa nada; los servicios
+	// downstream sólo se observan, para no sacar de rotación al engine
+	// cuando cae uno de ellos.
+	temporal := &temporalRuntime{}
+	checker := health.NewChecker(0)
+	checker.Require("temporal", temporal.check)
+	checker.Observe("control-plane-api", health.HTTPCheck(nil, cpBaseURL+"/healthz"))
+	checker.Observe("execution-workers", health.HTTPCheck(nil, ewBaseURL+"/healthz"))
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		httpx.WriteText(w, http.StatusOK, "ok")
-	})
+	checker.Register(mux)
 	mux.Handle("/metrics", promhttp.Handler())
 
+	// Webhooks de sistemas externos que señalizan workflows en espera.
+	inboundSecret := config.Get("INBOUND_WEBHOOK_SECRET", "")
+	if inboundSecret == "" {
+		logger.Warn("INBOUND_WEBHOOK_SECRET not set; /webhooks/inbound rejects every delivery")
+	}
+	mux.Handle("/webhooks/inbound", webhookreceiver.NewReceiver(temporal, logger, webhookreceiver.Options{Secret: []byte(inboundSecret)}))
+	// Decisiones de aprobación que reenvía el control plane.
+	mux.Handle("/internal/signals", webhookreceiver.NewSignalHandler(temporal, logger, config.Get("INTERNAL_AUTH_TOKEN", "")))
+	// Gestión de workflows (iniciar, describir, listar, cancelar, señalizar).
+	workflowapi.NewHandler(temporal, logger, config.Get("INTERNAL_AUTH_TOKEN", "")).Register(mux)
+
 	// Start Temporal worker in background
 	go func() {
-		if err := runTemporalWorker(logger); err != nil {
+		if err := startTemporalWorker(logger, temporal, cpBaseURL, ewBaseURL, drainOpts); err != nil {
 			// En entornos donde Temporal aún no está listo (p.ej., smoke-tests
 			// levantando toda la stack), no derribamos el proceso HTTP completo;
-			// registramos el error y dejamos vivo el health endpoint.
+			// registramos el error y dejamos vivo el health endpoint. /readyz
+			// informa que el worker no está corriendo.
 			logger.Error("temporal worker failed", zap.Error(err))
 		}
 	}()
 
-	logger.Info("workflow-engine listening and connected to Temporal", zap.String("addr", ":8081"))
+	logger.Info("workflow-engine listening", zap.String("addr", ":8081"))
 
-	// Handle graceful shutdown for HTTP server
 	server := &http.Server{
 		Addr:              ":8081",
 		Handler:           otelhttp.NewHandler(observability.InstrumentHTTP(mux), "workflow-engine"),
@@ -86,38 +108,176 @@ func main() {
 		}
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+	sigCtx, stop := health.SignalContext(context.Background())
+	defer stop()
+	<-sigCtx.Done()
 	logger.Info("shutting down workflow-engine")
+
+	// Drenado: el worker deja de tomar tareas y espera las actividades en
+	// curso; el servidor HTTP sólo sirve probes y métricas, así que se cierra
+	// después para que /readyz siga informando el drenado.
+	drainer := health.NewDrainer(checker, logger, drainOpts)
+	drainer.Add("temporal-worker", health.Blocking(temporal.stop))
+	drainer.Add("http", health.ShutdownHTTP(server))
+	drainer.Add("tracing", shutdownTracing)
+	if err := drainer.Drain(context.Background()); err != nil {
+		logger.Error("workflow-engine shutdown incomplete", zap.Error(err))
+	}
+}
+
+// temporalRuntime guarda el cliente y el worker de Temporal una vez
+// conectados, para el check de readiness y el drenado.
+type temporalRuntime struct {
+	mu      sync.Mutex
+	client  client.Client
+	worker  worker.Worker
+	stopped bool
+}
+
+// set registra el cliente y el worker; devuelve false si el drenado ya
+// empezó y quien llama debe cerrarlos.
+func (t *temporalRuntime) set(c client.Client, w worker.Worker) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.client, t.worker = c, w
+	return true
 }
 
-func runTemporalWorker(logger *zap.Logger) error {
+func (t *temporalRuntime) current() client.Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.client
+}
+
+func (t *temporalRuntime) check(ctx context.Context) error {
+	c := t.current()
+	if c == nil {
+		return errors.New("temporal worker not started")
+	}
+	_, err := c.CheckHealth(ctx, &client.CheckHealthRequest{})
+	return err
+}
+
+// errTemporalNotConnected lo devuelven los métodos de webhookreceiver.Signaler
+// y workflowapi.Temporal mientras el cliente no esté conectado.
+var errTemporalNotConnected = perrors.Upstream("temporal_unavailable", "temporal client not connected", nil)
+
+func (t *temporalRuntime) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	c := t.current()
+	if c == nil {
+		return nil, errTemporalNotConnected
+	}
+	return c.DescribeWorkflowExecution(ctx, workflowID, runID)
+}
+
+func (t *temporalRuntime) SignalWorkflow(ctx context.Context, workflowID, runID, signalName string, arg interface{}) error {
+	c := t.current()
+	if c == nil {
+		return errTemporalNotConnected
+	}
+	return c.SignalWorkflow(ctx, workflowID, runID, signalName, arg)
+}
+
+func (t *temporalRuntime) ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	c := t.current()
+	if c == nil {
+		return nil, errTemporalNotConnected
+	}
+	return c.ExecuteWorkflow(ctx, options, workflow, args...)
+}
+
+func (t *temporalRuntime) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	c := t.current()
+	if c == nil {
+		return nil, errTemporalNotConnected
+	}
+	return c.ListWorkflow(ctx, request)
+}
+
+func (t *temporalRuntime) CancelWorkflow(ctx context.Context, workflowID, runID string) error {
+	c := t.current()
+	if c == nil {
+		return errTemporalNotConnected
+	}
+	return c.CancelWorkflow(ctx, workflowID, runID)
+}
+
+func (t *temporalRuntime) TerminateWorkflow(ctx context.Context, workflowID, runID, reason string, details ...interface{}) error {
+	c := t.current()
+	if c == nil {
+		return errTemporalNotConnected
+	}
+	return c.TerminateWorkflow(ctx, workflowID, runID, reason, details...)
+}
+
+// stop frena el worker (espera las actividades en curso hasta
+// WorkerStopTimeout) y cierra el cliente.
+func (t *temporalRuntime) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.worker != nil {
+		t.worker.Stop()
+	}
+	if t.client != nil {
+		t.client.Close()
+	}
+}
+
+func startTemporalWorker(logger *zap.Logger, rt *temporalRuntime, cpBaseURL, ewBaseURL string, drainOpts health.DrainOptions) error {
 	host := config.Get("TEMPORAL_HOST", "temporal:7233")
 
-	c, err := client.Dial(client.Options{HostPort: host})
+	// El propagador lleva la organización de cada workflow a sus
+	// actividades, que la envían a control-plane-api en X-Organization-ID.
+	c, err := client.Dial(client.Options{
+		HostPort:           host,
+		ContextPropagators: []workflow.ContextPropagator{internalworkflow.NewOrganizationPropagator()},
+	})
 	if err != nil {
 		return fmt.Errorf("dial temporal client: %w", err)
 	}
 
 	// Configure control-plane-api client for activities
-	cpBaseURL := config.Get("CONTROL_PLANE_API_URL", "http://control-plane-api:8080")
 	cpClient := controlplanehttp.NewClient(cpBaseURL)
 	internalworkflow.SetControlPlaneClient(cpClient)
 	internalworkflow.SetApplicationOnboardingPort(cpClient)
 	internalworkflow.SetSecretRotationPort(cpClient)
+	internalworkflow.SetApplicationDecommissioningPort(cpClient)
+
+	// Retry policies por paso y timeouts de las esperas (WORKFLOW_STEP_*,
+	// WORKFLOW_WAIT_*), con los defaults del estado deseado. Un valor
+	// inválido conserva el default y queda en el log.
+	policies, err := internalworkflow.PoliciesFromEnv()
+	if err != nil {
+		logger.Warn("ignoring invalid workflow policies", zap.Error(err))
+	}
+	internalworkflow.SetPolicies(policies)
+
+	// Sinks de los hooks del ciclo de vida (HOOK_NOTIFY_*, HOOK_AUDIT); sin
+	// configurar, van al log.
+	notifySinks, auditSink := hooksinks.FromEnv(logger)
+	internalworkflow.SetNotificationSinks(notifySinks)
+	internalworkflow.SetAuditSink(auditSink)
 
 	// Configure Git provider client (execution-workers)
-	ewBaseURL := config.Get("EXECUTION_WORKERS_URL", "http://execution-workers:8082")
 	internalworkflow.SetGitProvider(gitproviderhttp.NewClient(ewBaseURL))
 	internalworkflow.SetAppEnvProvisioningProvider(appenvprovhttp.NewClient(ewBaseURL))
 	internalworkflow.SetSecretBindingsRotationPort(secretbindingshttp.NewClient(ewBaseURL))
 
-	w := worker.New(c, internalworkflow.ApplicationEnvironmentProvisioningTaskQueue, worker.Options{})
-	w.RegisterWorkflow(internalworkflow.ApplicationEnvironmentProvisioning)
-	w.RegisterWorkflow(internalworkflow.ApplicationOnboarding)
-	w.RegisterWorkflow(internalworkflow.ApplicationActivation)
-	w.RegisterWorkflow(internalworkflow.SecretRotation)
+	w := worker.New(c, internalworkflow.ApplicationEnvironmentProvisioningTaskQueue, worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{internalworkflow.NewIdempotencyInterceptor()},
+		// Las actividades en curso tienen hasta 3/4 del drenado para terminar;
+		// el resto queda para cerrar HTTP y hacer flush de tracing.
+		WorkerStopTimeout: drainOpts.Timeout * 3 / 4,
+	})
+	// Los workflows se registran con su nombre de Definition, el mismo con
+	// el que los inicia la API de gestión.
+	for _, def := range internalworkflow.Definitions() {
+		w.RegisterWorkflowWithOptions(def.Workflow, workflow.RegisterOptions{Name: def.Name})
+	}
 	w.RegisterActivity(internalworkflow.MaterializeRepositories)
 	w.RegisterActivity(internalworkflow.ApplyBranchProtection)
 	w.RegisterActivity(internalworkflow.ProvisionSecrets)
@@ -133,11 +293,22 @@ func runTemporalWorker(logger *zap.Logger) error {
 	w.RegisterActivity(internalworkflow.PerformSecretRotation)
 	w.RegisterActivity(internalworkflow.CompleteSecretRotationActivity)
 	w.RegisterActivity(internalworkflow.UpdateSecretBindingsForSecret)
+	w.RegisterActivity(internalworkflow.RequestDecommissioningApproval)
+	w.RegisterActivity(internalworkflow.TransitionApplicationToDecommissioning)
+	w.RegisterActivity(internalworkflow.ListApplicationEnvironmentsActivity)
+	w.RegisterActivity(internalworkflow.DecommissionApplicationEnvironmentActivity)
+	w.RegisterActivity(internalworkflow.RevokeApplicationEnvironmentSecretBindings)
+	w.RegisterActivity(internalworkflow.ArchiveApplicationActivity)
+	w.RegisterActivity(internalworkflow.RunHook)
 
 	logger.Info("starting Temporal worker", zap.String("taskQueue", internalworkflow.ApplicationEnvironmentProvisioningTaskQueue))
-	if err := w.Run(worker.InterruptCh()); err != nil {
-		return fmt.Errorf("run temporal worker: %w", err)
+	if err := w.Start(); err != nil {
+		c.Close()
+		return fmt.Errorf("start temporal worker: %w", err)
+	}
+	if !rt.set(c, w) {
+		w.Stop()
+		c.Close()
 	}
-
 	return nil
 }
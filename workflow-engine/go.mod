@@ -3,15 +3,20 @@ module github.com/nuevo-idp/workflow-engine
 go 1.24.0
 
 require (
+	github.com/nuevo-idp/control-plane-api v0.0.0
 	github.com/nuevo-idp/platform v0.0.0
 	github.com/prometheus/client_golang v1.19.0
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.65.0
 	go.opentelemetry.io/otel v1.40.0
+	go.temporal.io/api v1.36.0
 	go.temporal.io/sdk v1.28.0
 	go.uber.org/zap v1.27.1
 )
 
-replace github.com/nuevo-idp/platform => ../platform
+replace (
+	github.com/nuevo-idp/control-plane-api => ../control-plane-api
+	github.com/nuevo-idp/platform => ../platform
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -43,7 +48,6 @@ require (
 	go.opentelemetry.io/otel/sdk v1.40.0 // indirect
 	go.opentelemetry.io/otel/trace v1.40.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.9.0 // indirect
-	go.temporal.io/api v1.36.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/exp v0.0.0-20231127185646-65229373498e // indirect
 	golang.org/x/net v0.49.0 // indirect
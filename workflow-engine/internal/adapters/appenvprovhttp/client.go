@@ -5,12 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/tracing"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -66,6 +66,7 @@ func (c *Client) post(ctx context.Context, path string, appEnvID string) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	setInternalAuthHeader(req)
+	httpx.SetIdempotencyKey(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -99,7 +100,9 @@ func setInternalAuthHeader(req *http.Request) {
 type Error struct {
 	Status  int
 	Path    string
+	Code    string
 	Message string
+	TraceID string
 }
 
 func (e *Error) Error() string {
@@ -112,7 +115,15 @@ func (e *Error) Error() string {
 		msg = fmt.Sprintf("execution-workers returned status %d for %s", e.Status, e.Path)
 	}
 
-	return fmt.Sprintf("execution-workers appenv provider error: status=%d path=%s message=%s", e.Status, e.Path, msg)
+	code := e.Code
+	if code == "" {
+		code = "unknown_error"
+	}
+
+	if e.TraceID != "" {
+		return fmt.Sprintf("execution-workers appenv provider error: status=%d path=%s code=%s message=%s trace_id=%s", e.Status, e.Path, code, msg, e.TraceID)
+	}
+	return fmt.Sprintf("execution-workers appenv provider error: status=%d path=%s code=%s message=%s", e.Status, e.Path, code, msg)
 }
 
 func newErrorFromResponse(resp *http.Response, path string) error {
@@ -120,10 +131,12 @@ func newErrorFromResponse(resp *http.Response, path string) error {
 		return &Error{Status: 0, Path: path, Message: "nil response from execution-workers"}
 	}
 
-	body, _ := io.ReadAll(resp.Body)
+	p := httpx.ReadProblem(resp)
 	return &Error{
 		Status:  resp.StatusCode,
 		Path:    path,
-		Message: string(body),
+		Code:    p.Code,
+		Message: p.Detail,
+		TraceID: p.TraceID,
 	}
 }
@@ -1,124 +1,54 @@
+// Package controlplanehttp implementa los puertos del workflow-engine sobre
+// el SDK público de control-plane-api (controlplane/client). Acá sólo vive
+// lo propio del engine: las convenciones de IDs derivados y la key de
+// idempotencia por environment.
 package controlplanehttp
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
 	"time"
 
+	"github.com/nuevo-idp/control-plane-api/controlplane/client"
 	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/validation"
 	"go.opentelemetry.io/otel/attribute"
 )
 
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	api *client.Client
 }
 
-type completeAppEnvProvisioningRequest struct {
-	ID string `json:"id"`
-}
-
-type declareCodeRepositoryRequest struct {
-	ID            string `json:"id"`
-	ApplicationID string `json:"applicationId"`
-}
-
-type declareDeploymentRepositoryRequest struct {
-	ID              string `json:"id"`
-	ApplicationID   string `json:"applicationId"`
-	DeploymentModel string `json:"deploymentModel"`
-}
-
-type declareGitOpsIntegrationRequest struct {
-	ID               string `json:"id"`
-	ApplicationID    string `json:"applicationId"`
-	DeploymentRepoID string `json:"deploymentRepositoryId"`
-}
-
-type declareApplicationEnvironmentRequest struct {
-	ID            string `json:"id"`
-	ApplicationID string `json:"applicationId"`
-	EnvironmentID string `json:"environmentId"`
-}
-
-type startApplicationOnboardingRequest struct {
-	ID string `json:"id"`
-}
-
-type activateApplicationRequest struct {
-	ID string `json:"id"`
-}
-
-type completeSecretRotationRequest struct {
-	ID string `json:"id"`
-}
-
-// Error representa un error devuelto por control-plane-api. Captura el
-// status HTTP junto con el código y mensaje estructurados del cuerpo JSON.
-type Error struct {
-	Status  int
-	Code    string
-	Message string
-}
-
-func (e *Error) Error() string {
-	if e == nil {
-		return ""
-	}
-
-	code := e.Code
-	if code == "" {
-		code = "unknown_error"
-	}
-
-	msg := e.Message
-	if strings.TrimSpace(msg) == "" {
-		msg = fmt.Sprintf("control-plane-api returned status %d", e.Status)
-	}
-
-	return fmt.Sprintf("control-plane-api error: status=%d code=%s message=%s", e.Status, code, msg)
-}
-
-func newErrorFromResponse(resp *http.Response) error {
-	if resp == nil {
-		return &Error{Status: 0, Code: "unknown_error", Message: "nil response from control-plane-api"}
-	}
-
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	var payload struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
-	}
-	_ = json.Unmarshal(body, &payload) // si falla, usamos el raw body abajo
+// Error es el error del SDK: conserva el status HTTP y el problem+json
+// (código, kind, mensaje, trace ID y detalle por campo).
+type Error = client.Error
 
-	msg := payload.Message
-	if strings.TrimSpace(msg) == "" {
-		msg = strings.TrimSpace(string(body))
-	}
+// ApplicationEnvironment es el recurso tal como lo devuelve el SDK.
+type ApplicationEnvironment = client.ApplicationEnvironment
 
-	return &Error{
-		Status:  resp.StatusCode,
-		Code:    payload.Code,
-		Message: msg,
-	}
-}
+const (
+	// decommissioningApprovalSignal es la señal que espera
+	// ApplicationDecommissioning (workflow.DecommissioningApprovalSignalName).
+	decommissioningApprovalSignal = "DecommissioningApproval"
+	// applicationEnvironmentTarget es el targetType de los SecretBindings
+	// de un ApplicationEnvironment.
+	applicationEnvironmentTarget = "ApplicationEnvironment"
+)
 
+// NewClient crea el adapter. Se autentica como servicio interno con
+// INTERNAL_AUTH_TOKEN y no reintenta: de eso se ocupa la retry policy de las
+// activities de Temporal, que además conserva la Idempotency-Key. El mismo
+// cliente atiende a todas las organizaciones: cada llamada envía la del
+// contexto de la actividad (httpx.WithOrganization) en X-Organization-ID.
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-	}
+	return &Client{api: client.New(client.Options{
+		BaseURL:       baseURL,
+		InternalToken: config.Get("INTERNAL_AUTH_TOKEN", ""),
+		Retry:         client.RetryPolicy{MaxAttempts: 1},
+	})}
 }
 
 func (c *Client) CompleteApplicationEnvironmentProvisioning(ctx context.Context, appEnvID string) error {
@@ -126,27 +56,7 @@ func (c *Client) CompleteApplicationEnvironmentProvisioning(ctx context.Context,
 	span.SetAttributes(attribute.String("appenv.id", appEnvID))
 	defer span.End()
 
-	body, err := json.Marshal(completeAppEnvProvisioningRequest{ID: appEnvID})
-	if err != nil {
-		return fmt.Errorf("marshal complete appenv provisioning request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/application-environments/complete-provisioning", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create complete appenv provisioning request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("call complete appenv provisioning endpoint: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.CompleteApplicationEnvironmentProvisioning(ctx, client.CompleteApplicationEnvironmentProvisioningRequest{ID: appEnvID})
 }
 
 // DeclareCodeRepository implementa el puerto de onboarding para crear un CodeRepository
@@ -157,31 +67,14 @@ func (c *Client) DeclareCodeRepository(ctx context.Context, applicationID string
 	span.SetAttributes(attribute.String("application.id", applicationID))
 	defer span.End()
 
-	reqBody := declareCodeRepositoryRequest{
-		ID:            "code-" + applicationID,
-		ApplicationID: applicationID,
-	}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("marshal declare code repository request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/code-repositories", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create declare code repository request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
+	id, err := validation.JoinID("code", applicationID)
 	if err != nil {
-		return fmt.Errorf("call declare code repository endpoint: %w", err)
+		return fmt.Errorf("derive code repository id: %w", err)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.DeclareCodeRepository(ctx, client.DeclareCodeRepositoryRequest{
+		ID:            id,
+		ApplicationID: applicationID,
+	})
 }
 
 // DeclareDeploymentRepository crea un DeploymentRepository asociado a la Application.
@@ -190,32 +83,15 @@ func (c *Client) DeclareDeploymentRepository(ctx context.Context, applicationID
 	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.DeclareDeploymentRepository")
 	defer span.End()
 
-	reqBody := declareDeploymentRepositoryRequest{
-		ID:              "dep-" + applicationID,
-		ApplicationID:   applicationID,
-		DeploymentModel: "GitOpsPerApplication",
-	}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("marshal declare deployment repository request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/deployment-repositories", bytes.NewReader(body))
+	id, err := validation.JoinID("dep", applicationID)
 	if err != nil {
-		return fmt.Errorf("create declare deployment repository request: %w", err)
+		return fmt.Errorf("derive deployment repository id: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("call declare deployment repository endpoint: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.DeclareDeploymentRepository(ctx, client.DeclareDeploymentRepositoryRequest{
+		ID:              id,
+		ApplicationID:   applicationID,
+		DeploymentModel: "GitOpsPerApplication",
+	})
 }
 
 // DeclareGitOpsIntegration crea la integración GitOps usando el deployment repo
@@ -224,33 +100,20 @@ func (c *Client) DeclareGitOpsIntegration(ctx context.Context, applicationID str
 	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.DeclareGitOpsIntegration")
 	defer span.End()
 
-	depID := "dep-" + applicationID
-	reqBody := declareGitOpsIntegrationRequest{
-		ID:               "gi-" + applicationID,
-		ApplicationID:    applicationID,
-		DeploymentRepoID: depID,
-	}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("marshal declare gitops integration request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/gitops-integrations", bytes.NewReader(body))
+	id, err := validation.JoinID("gi", applicationID)
 	if err != nil {
-		return fmt.Errorf("create declare gitops integration request: %w", err)
+		return fmt.Errorf("derive gitops integration id: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
+	// Mismo ID que arma DeclareDeploymentRepository.
+	depID, err := validation.JoinID("dep", applicationID)
 	if err != nil {
-		return fmt.Errorf("call declare gitops integration endpoint: %w", err)
+		return fmt.Errorf("derive deployment repository id: %w", err)
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.DeclareGitOpsIntegration(ctx, client.DeclareGitOpsIntegrationRequest{
+		ID:               id,
+		ApplicationID:    applicationID,
+		DeploymentRepoID: depID,
+	})
 }
 
 // DeclareApplicationEnvironments declara los ApplicationEnvironment para un conjunto
@@ -261,34 +124,29 @@ func (c *Client) DeclareApplicationEnvironments(ctx context.Context, application
 
 	envs := []string{"env-dev", "env-prod"}
 	for _, envID := range envs {
-		reqBody := declareApplicationEnvironmentRequest{
-			ID:            applicationID + "-" + envID,
-			ApplicationID: applicationID,
-			EnvironmentID: envID,
-		}
-		body, err := json.Marshal(reqBody)
+		id, err := validation.JoinID(applicationID, envID)
 		if err != nil {
-			return fmt.Errorf("marshal declare application environment request: %w", err)
+			return fmt.Errorf("derive application environment id: %w", err)
 		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/application-environments", bytes.NewReader(body))
-		if err != nil {
-			return fmt.Errorf("create declare application environment request: %w", err)
+		// Una key por environment: con la misma key la API respondería el
+		// resultado del primer comando a todos.
+		var opts []client.CallOption
+		if key, ok := httpx.IdempotencyKeyFromContext(ctx); ok {
+			opts = append(opts, client.WithIdempotencyKey(key+":"+envID))
 		}
-		req.Header.Set("Content-Type", "application/json")
-		setInternalAuthHeader(req)
-
-		resp, err := c.httpClient.Do(req)
+		err = c.api.DeclareApplicationEnvironment(ctx, client.DeclareApplicationEnvironmentRequest{
+			ID:            id,
+			ApplicationID: applicationID,
+			EnvironmentID: envID,
+		}, opts...)
 		if err != nil {
-			return fmt.Errorf("call declare application environment endpoint: %w", err)
-		}
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			// Mantener el contexto del envID en el mensaje de error.
-			if apiErr, ok := newErrorFromResponse(resp).(*Error); ok {
+			var apiErr *Error
+			if errors.As(err, &apiErr) {
 				apiErr.Message = fmt.Sprintf("%s (environment=%s)", apiErr.Message, envID)
 				return apiErr
 			}
-			return fmt.Errorf("control-plane-api returned status %d for environment %s", resp.StatusCode, envID)
+			return fmt.Errorf("declare application environment %s: %w", envID, err)
 		}
 	}
 
@@ -302,27 +160,7 @@ func (c *Client) MarkApplicationOnboarding(ctx context.Context, applicationID st
 	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.MarkApplicationOnboarding")
 	defer span.End()
 
-	body, err := json.Marshal(startApplicationOnboardingRequest{ID: applicationID})
-	if err != nil {
-		return fmt.Errorf("marshal startApplicationOnboardingRequest: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/applications/start-onboarding", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create start-onboarding HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("do start-onboarding HTTP request: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.StartApplicationOnboarding(ctx, client.StartApplicationOnboardingRequest{ID: applicationID})
 }
 
 //nolint:misspell
@@ -332,27 +170,7 @@ func (c *Client) ActivateApplication(ctx context.Context, applicationID string)
 	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.ActivateApplication")
 	defer span.End()
 
-	body, err := json.Marshal(activateApplicationRequest{ID: applicationID})
-	if err != nil {
-		return fmt.Errorf("marshal activateApplicationRequest: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/applications/activate", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create activate HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("do activate HTTP request: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
-	}
-
-	return nil
+	return c.api.ActivateApplication(ctx, client.ActivateApplicationRequest{ID: applicationID})
 }
 
 //nolint:misspell
@@ -362,36 +180,140 @@ func (c *Client) CompleteSecretRotation(ctx context.Context, secretID string) er
 	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.CompleteSecretRotation")
 	defer span.End()
 
-	body, err := json.Marshal(completeSecretRotationRequest{ID: secretID})
+	return c.api.CompleteSecretRotation(ctx, client.CompleteSecretRotationRequest{ID: secretID})
+}
+
+// RequestDecommissioningApproval crea el pedido de aprobación del
+// decommissioning de applicationID. La decisión la toma un platformAdmin y
+// llega a workflowID como la señal DecommissioningApproval. El ID del pedido
+// incluye el run: un re-run después de un rechazo pide una aprobación nueva.
+// Si el pedido ya existe y sigue pendiente para workflowID (un reintento de
+// la actividad), no es un error.
+func (c *Client) RequestDecommissioningApproval(ctx context.Context, applicationID, workflowID, runID string, timeout time.Duration) error {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.RequestDecommissioningApproval")
+	span.SetAttributes(attribute.String("application.id", applicationID))
+	defer span.End()
+
+	id, err := validation.JoinID("decommissioning", applicationID, runSuffix(runID))
 	if err != nil {
-		return fmt.Errorf("marshal completeSecretRotationRequest: %w", err)
+		return fmt.Errorf("derive approval id: %w", err)
+	}
+	err = c.api.CreateApprovalRequest(ctx, client.CreateApprovalRequestRequest{
+		ID:             id,
+		Type:           "ApplicationDecommissioning",
+		ResourceType:   "Application",
+		ResourceID:     applicationID,
+		RolesAllowed:   []string{"platformAdmin"},
+		Reason:         "decommissioning of application " + applicationID,
+		WorkflowID:     workflowID,
+		SignalName:     decommissioningApprovalSignal,
+		TimeoutSeconds: int64(timeout / time.Second),
+	})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Code == "approval_already_exists" {
+		existing, getErr := c.api.GetApproval(ctx, id)
+		if getErr == nil && existing.WorkflowID == workflowID && existing.State == "Pending" {
+			return nil
+		}
 	}
+	return err
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/commands/secrets/complete-rotation", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create complete-rotation HTTP request: %w", err)
+// runSuffix acorta runID a sus primeros caracteres alfanuméricos, para que
+// el ID del pedido de aprobación siga entrando en un ID válido.
+func runSuffix(runID string) string {
+	const n = 8
+	out := make([]byte, 0, n)
+	for i := 0; i < len(runID) && len(out) < n; i++ {
+		switch ch := runID[i]; {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9':
+			out = append(out, ch)
+		case ch >= 'A' && ch <= 'Z':
+			out = append(out, ch+'a'-'A')
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
-	setInternalAuthHeader(req)
+	return string(out)
+}
 
-	resp, err := c.httpClient.Do(req)
+// GetApplicationState devuelve el estado actual de la Application.
+func (c *Client) GetApplicationState(ctx context.Context, applicationID string) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.GetApplicationState")
+	span.SetAttributes(attribute.String("application.id", applicationID))
+	defer span.End()
+
+	app, err := c.api.GetApplication(ctx, applicationID)
 	if err != nil {
-		return fmt.Errorf("do complete-rotation HTTP request: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return newErrorFromResponse(resp)
+		return "", err
 	}
+	return app.State, nil
+}
 
-	return nil
+// StartApplicationDecommissioning llama al comando HTTP que mueve la
+// Application de Deprecated a Decommissioning.
+func (c *Client) StartApplicationDecommissioning(ctx context.Context, applicationID string) error {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.StartApplicationDecommissioning")
+	span.SetAttributes(attribute.String("application.id", applicationID))
+	defer span.End()
+
+	return c.api.StartApplicationDecommissioning(ctx, client.StartApplicationDecommissioningRequest{ID: applicationID})
 }
 
-const internalAuthHeader = "X-Internal-Token"
+// ListApplicationEnvironments devuelve los ApplicationEnvironments de la
+// Application.
+func (c *Client) ListApplicationEnvironments(ctx context.Context, applicationID string) ([]ApplicationEnvironment, error) {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.ListApplicationEnvironments")
+	span.SetAttributes(attribute.String("application.id", applicationID))
+	defer span.End()
 
-func setInternalAuthHeader(req *http.Request) {
-	if req == nil {
-		return
+	return c.api.ListApplicationEnvironments(ctx, applicationID)
+}
+
+func (c *Client) DecommissionApplicationEnvironment(ctx context.Context, appEnvID string) error {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.DecommissionApplicationEnvironment")
+	span.SetAttributes(attribute.String("appenv.id", appEnvID))
+	defer span.End()
+
+	return c.api.DecommissionApplicationEnvironment(ctx, client.DecommissionApplicationEnvironmentRequest{ID: appEnvID})
+}
+
+// RevokeApplicationEnvironmentSecretBindings revoca los SecretBindings de
+// appEnvID que todavía no están revocados, uno por request.
+func (c *Client) RevokeApplicationEnvironmentSecretBindings(ctx context.Context, appEnvID string) error {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.RevokeApplicationEnvironmentSecretBindings")
+	span.SetAttributes(attribute.String("appenv.id", appEnvID))
+	defer span.End()
+
+	bindings, err := c.api.ListSecretBindings(ctx, applicationEnvironmentTarget, appEnvID)
+	if err != nil {
+		return err
 	}
-	if token := config.Get("INTERNAL_AUTH_TOKEN", ""); token != "" {
-		req.Header.Set(internalAuthHeader, token)
+	for _, b := range bindings {
+		if b.State == "Revoked" {
+			continue
+		}
+		// Una key por binding, igual que en DeclareApplicationEnvironments.
+		var opts []client.CallOption
+		if key, ok := httpx.IdempotencyKeyFromContext(ctx); ok {
+			opts = append(opts, client.WithIdempotencyKey(key+":"+b.ID))
+		}
+		if err := c.api.RevokeSecretBinding(ctx, client.RevokeSecretBindingRequest{ID: b.ID}, opts...); err != nil {
+			var apiErr *Error
+			if errors.As(err, &apiErr) {
+				apiErr.Message = fmt.Sprintf("%s (secretBinding=%s)", apiErr.Message, b.ID)
+				return apiErr
+			}
+			return fmt.Errorf("revoke secret binding %s: %w", b.ID, err)
+		}
 	}
+	return nil
+}
+
+// ArchiveApplication llama al comando HTTP que mueve la Application de
+// Decommissioning a Archived.
+func (c *Client) ArchiveApplication(ctx context.Context, applicationID string) error {
+	ctx, span := tracing.StartSpan(ctx, "controlplanehttp.ArchiveApplication")
+	span.SetAttributes(attribute.String("application.id", applicationID))
+	defer span.End()
+
+	return c.api.ArchiveApplication(ctx, client.ArchiveApplicationRequest{ID: applicationID})
 }
@@ -2,10 +2,17 @@ package controlplanehttp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
 )
 
 func TestCompleteApplicationEnvironmentProvisioning_SendsInternalAuthHeader(t *testing.T) {
@@ -27,3 +34,184 @@ func TestCompleteApplicationEnvironmentProvisioning_SendsInternalAuthHeader(t *t
 		t.Fatalf("expected X-Internal-Token header to be 'test-token', got %q", gotHeader)
 	}
 }
+
+func TestCompleteApplicationEnvironmentProvisioning_ParsesProblemJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"type":"about:blank","title":"Conflict","status":409,"detail":"appenv is not provisioning","code":"appenv_invalid_state","kind":"conflict","traceId":"abc123","errors":[{"field":"id","message":"is required"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL)
+	err := c.CompleteApplicationEnvironmentProvisioning(context.Background(), "ae-1")
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %T (%v)", err, err)
+	}
+	if apiErr.Status != http.StatusConflict || apiErr.Code != "appenv_invalid_state" || apiErr.Kind != "conflict" {
+		t.Fatalf("unexpected error fields: %+v", apiErr)
+	}
+	if apiErr.Message != "appenv is not provisioning" || apiErr.TraceID != "abc123" {
+		t.Fatalf("unexpected message/trace: %+v", apiErr)
+	}
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "id" {
+		t.Fatalf("expected field errors to be parsed, got %+v", apiErr.Fields)
+	}
+}
+
+func TestCompleteApplicationEnvironmentProvisioning_ParsesLegacyErrorShape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"appenv_not_found","message":"appenv not found"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL)
+	err := c.CompleteApplicationEnvironmentProvisioning(context.Background(), "ae-1")
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %T (%v)", err, err)
+	}
+	if apiErr.Code != "appenv_not_found" || apiErr.Message != "appenv not found" {
+		t.Fatalf("unexpected error fields: %+v", apiErr)
+	}
+}
+
+func TestDeclareApplicationEnvironments_SendsPerEnvironmentIdempotencyKeys(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(httpx.IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := httpx.WithIdempotencyKey(context.Background(), "wf-1/5")
+	c := NewClient(server.URL)
+	if err := c.DeclareApplicationEnvironments(ctx, "app-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "wf-1/5:env-dev" || keys[1] != "wf-1/5:env-prod" {
+		t.Fatalf("expected per-environment idempotency keys, got %v", keys)
+	}
+}
+
+func TestDerivedIDs_AreValidatedBeforeCallingTheAPI(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL)
+	longApp := strings.Repeat("a", 60) // "code-" + 60 supera los 63 caracteres
+	if err := c.DeclareCodeRepository(context.Background(), longApp); perrors.Code(err) != "invalid_derived_id" {
+		t.Fatalf("expected invalid_derived_id, got %v", err)
+	}
+	if err := c.DeclareApplicationEnvironments(context.Background(), longApp); perrors.Code(err) != "invalid_derived_id" {
+		t.Fatalf("expected invalid_derived_id, got %v", err)
+	}
+	if err := c.DeclareDeploymentRepository(context.Background(), longApp); perrors.Code(err) != "invalid_derived_id" {
+		t.Fatalf("expected invalid_derived_id, got %v", err)
+	}
+	// "gi-" + 60 entra, pero el deployment repo que referencia no.
+	if err := c.DeclareGitOpsIntegration(context.Background(), longApp); perrors.Code(err) != "invalid_derived_id" {
+		t.Fatalf("expected invalid_derived_id, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no calls to the API, got %d", calls)
+	}
+}
+
+func TestRevokeApplicationEnvironmentSecretBindings_RevokesPendingBindings(t *testing.T) {
+	var revoked, keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("targetType") != "ApplicationEnvironment" || r.URL.Query().Get("targetId") != "app-1-env-dev" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id":"b-1","state":"Active"},{"id":"b-2","state":"Revoked"},{"id":"b-3","state":"Declared"}]`))
+			return
+		}
+		var body struct {
+			ID string `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		revoked = append(revoked, body.ID)
+		keys = append(keys, r.Header.Get(httpx.IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := httpx.WithIdempotencyKey(context.Background(), "wf-1/9")
+	c := NewClient(server.URL)
+	if err := c.RevokeApplicationEnvironmentSecretBindings(ctx, "app-1-env-dev"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(revoked) != 2 || revoked[0] != "b-1" || revoked[1] != "b-3" {
+		t.Fatalf("expected b-1 and b-3 to be revoked, got %v", revoked)
+	}
+	if keys[0] != "wf-1/9:b-1" || keys[1] != "wf-1/9:b-3" {
+		t.Fatalf("expected per-binding idempotency keys, got %v", keys)
+	}
+}
+
+func TestRequestDecommissioningApproval_UsesOneApprovalPerRun(t *testing.T) {
+	existing := map[string]string{} // id -> state
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			id := r.URL.Query().Get("id")
+			_, _ = w.Write([]byte(`{"id":"` + id + `","workflowId":"default:application-decommissioning-app-1","state":"` + existing[id] + `"}`))
+			return
+		}
+		var body struct {
+			ID string `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := existing[body.ID]; ok {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"status":409,"code":"approval_already_exists","kind":"conflict","detail":"approval already exists"}`))
+			return
+		}
+		existing[body.ID] = "Pending"
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL)
+	ctx := context.Background()
+	wfID := "default:application-decommissioning-app-1"
+	if err := c.RequestDecommissioningApproval(ctx, "app-1", wfID, "0F8FAD5B-D9CB-469F-A165-70867728950E", time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if existing["decommissioning-app-1-0f8fad5b"] != "Pending" {
+		t.Fatalf("expected a run-scoped approval id, got %v", existing)
+	}
+
+	// Un reintento de la actividad encuentra su pedido pendiente.
+	if err := c.RequestDecommissioningApproval(ctx, "app-1", wfID, "0F8FAD5B-D9CB-469F-A165-70867728950E", time.Hour); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+
+	// Un re-run después de un rechazo pide una aprobación nueva.
+	existing["decommissioning-app-1-0f8fad5b"] = "Rejected"
+	if err := c.RequestDecommissioningApproval(ctx, "app-1", wfID, "7c9e6679-7425-40de-944b-e07fc1f90ae7", time.Hour); err != nil {
+		t.Fatalf("expected the re-run to succeed, got %v", err)
+	}
+	if existing["decommissioning-app-1-7c9e6679"] != "Pending" {
+		t.Fatalf("expected a new approval for the re-run, got %v", existing)
+	}
+
+	// Un pedido ya decidido con el mismo ID sigue siendo un conflicto.
+	if err := c.RequestDecommissioningApproval(ctx, "app-1", wfID, "0f8fad5b", time.Hour); perrors.Code(err) != "approval_already_exists" {
+		t.Fatalf("expected approval_already_exists, got %v", err)
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/tracing"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -57,6 +57,7 @@ func (c *Client) CreateRepository(ctx context.Context, owner, name string, priva
 	}
 	req.Header.Set("Content-Type", "application/json")
 	setInternalAuthHeader(req)
+	httpx.SetIdempotencyKey(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -88,7 +89,9 @@ func setInternalAuthHeader(req *http.Request) {
 // del proveedor Git. Captura el status HTTP y el cuerpo en texto plano.
 type Error struct {
 	Status  int
+	Code    string
 	Message string
+	TraceID string
 }
 
 func (e *Error) Error() string {
@@ -101,7 +104,15 @@ func (e *Error) Error() string {
 		msg = fmt.Sprintf("execution-workers returned status %d", e.Status)
 	}
 
-	return fmt.Sprintf("execution-workers git provider error: status=%d message=%s", e.Status, msg)
+	code := e.Code
+	if code == "" {
+		code = "unknown_error"
+	}
+
+	if e.TraceID != "" {
+		return fmt.Sprintf("execution-workers git provider error: status=%d code=%s message=%s trace_id=%s", e.Status, code, msg, e.TraceID)
+	}
+	return fmt.Sprintf("execution-workers git provider error: status=%d code=%s message=%s", e.Status, code, msg)
 }
 
 func newErrorFromResponse(resp *http.Response) error {
@@ -109,9 +120,11 @@ func newErrorFromResponse(resp *http.Response) error {
 		return &Error{Status: 0, Message: "nil response from execution-workers"}
 	}
 
-	body, _ := io.ReadAll(resp.Body)
+	p := httpx.ReadProblem(resp)
 	return &Error{
 		Status:  resp.StatusCode,
-		Message: string(body),
+		Code:    p.Code,
+		Message: p.Detail,
+		TraceID: p.TraceID,
 	}
 }
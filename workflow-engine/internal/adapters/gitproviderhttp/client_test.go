@@ -63,3 +63,23 @@ func TestCreateRepository_SendsInternalAuthHeader(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestCreateRepository_ParsesProblemJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"type":"about:blank","title":"Bad Gateway","status":502,"detail":"failed to create repository in GitHub","code":"github_create_repository_failed","kind":"upstream","traceId":"abc123"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	err := c.CreateRepository(context.Background(), "owner", "name", true)
+
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if e.Code != "github_create_repository_failed" || e.Message != "failed to create repository in GitHub" || e.TraceID != "abc123" {
+		t.Fatalf("unexpected error fields: %+v", e)
+	}
+}
@@ -0,0 +1,246 @@
+// Package hooksinks implementa los HookSink en los que los workflows
+// entregan sus hooks: log estructurado, webhook firmado y email por SMTP.
+//
+// Cada sink se configura con una especificación:
+//
+//	log                      log del engine (auditLog lleva audit=true)
+//	webhook:<url>            POST JSON firmado como los webhooks salientes
+//	email:<to>[,<to>...]     email a través del servidor SMTP configurado
+package hooksinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/webhook"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+var (
+	_ internalworkflow.HookSink = (*LogSink)(nil)
+	_ internalworkflow.HookSink = (*WebhookSink)(nil)
+	_ internalworkflow.HookSink = (*EmailSink)(nil)
+)
+
+// LogSink escribe los hooks en el log del engine.
+type LogSink struct {
+	logger *zap.Logger
+}
+
+func NewLogSink(logger *zap.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Deliver(_ context.Context, ev internalworkflow.HookEvent) error {
+	s.logger.Info("workflow hook",
+		zap.Bool("audit", ev.Type == internalworkflow.HookAuditLog),
+		zap.String("type", ev.Type),
+		zap.String("point", ev.Point),
+		zap.String("workflow", ev.Workflow),
+		zap.String("workflow_id", ev.WorkflowID),
+		zap.String("run_id", ev.RunID),
+		zap.String("resource_id", ev.ResourceID),
+		zap.String("step", ev.Step),
+		zap.String("channel", ev.Channel),
+		zap.String("message", ev.Message),
+		zap.String("error", ev.Error),
+	)
+	return nil
+}
+
+// WebhookSink hace un POST del HookEvent a url. Con secreto, la request va
+// firmada con platform/webhook.
+type WebhookSink struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewWebhookSink(url string, secret []byte) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, ev internalworkflow.HookEvent) error {
+	ctx, span := tracing.StartSpan(ctx, "hooksinks.Webhook")
+	span.SetAttributes(
+		attribute.String("workflow.id", ev.WorkflowID),
+		attribute.String("hook.point", ev.Point),
+	)
+	defer span.End()
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal hook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create hook request: %w", err)
+	}
+	sentAt := s.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, "workflow."+ev.Point)
+	// Un reintento de la actividad repite el ID, así el receptor deduplica.
+	req.Header.Set(webhook.DeliveryHeader, deliveryID(ev))
+	if len(s.secret) > 0 {
+		req.Header.Set(webhook.TimestampHeader, fmt.Sprintf("%d", sentAt.Unix()))
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(s.secret, sentAt, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call hook webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hook webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deliveryID identifica la entrega de un hook: el run, el punto, el paso y
+// la posición y el tipo del hook, para que dos hooks del mismo punto no se
+// dedupliquen entre sí.
+func deliveryID(ev internalworkflow.HookEvent) string {
+	id := ev.WorkflowID + "/" + ev.RunID + "/" + ev.Point
+	if ev.Step != "" {
+		id += "/" + ev.Step
+	}
+	return id + "/" + strconv.Itoa(ev.Index) + "/" + ev.Type
+}
+
+// EmailSink envía los hooks por email a través de un servidor SMTP sin
+// autenticación; en local es el stand-in de docker-compose.
+type EmailSink struct {
+	addr string
+	from string
+	to   []string
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmailSink(addr, from string, to []string) *EmailSink {
+	return &EmailSink{addr: addr, from: from, to: to, send: smtp.SendMail}
+}
+
+func (s *EmailSink) Deliver(_ context.Context, ev internalworkflow.HookEvent) error {
+	if err := s.send(s.addr, nil, s.from, s.to, s.message(ev)); err != nil {
+		return fmt.Errorf("send hook email: %w", err)
+	}
+	return nil
+}
+
+func (s *EmailSink) message(ev internalworkflow.HookEvent) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: [%s] %s\r\n", ev.Workflow, ev.Message)
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "%s\r\n\r\n", ev.Message)
+	fmt.Fprintf(&b, "Workflow: %s (%s, run %s)\r\n", ev.Workflow, ev.WorkflowID, ev.RunID)
+	fmt.Fprintf(&b, "Recurso: %s\r\n", ev.ResourceID)
+	fmt.Fprintf(&b, "Punto: %s\r\n", ev.Point)
+	if ev.Step != "" {
+		fmt.Fprintf(&b, "Paso: %s\r\n", ev.Step)
+	}
+	if ev.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\r\n", ev.Error)
+	}
+	fmt.Fprintf(&b, "Hora: %s\r\n", ev.At.UTC().Format(time.RFC3339))
+	return []byte(b.String())
+}
+
+// Options configura los sinks que arma ParseSink.
+type Options struct {
+	Logger        *zap.Logger
+	WebhookSecret []byte
+	SMTPAddr      string
+	SMTPFrom      string
+}
+
+// OptionsFromEnv lee HOOK_WEBHOOK_SECRET, HOOK_SMTP_ADDR y HOOK_SMTP_FROM.
+func OptionsFromEnv(logger *zap.Logger) Options {
+	return Options{
+		Logger:        logger,
+		WebhookSecret: []byte(config.Get("HOOK_WEBHOOK_SECRET", "")),
+		SMTPAddr:      config.Get("HOOK_SMTP_ADDR", "localhost:1025"),
+		SMTPFrom:      config.Get("HOOK_SMTP_FROM", "workflow-engine@idp.local"),
+	}
+}
+
+// ParseSink arma el sink de spec (ver la documentación del paquete).
+func ParseSink(spec string, opts Options) (internalworkflow.HookSink, error) {
+	kind, target, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	switch kind {
+	case "log":
+		return NewLogSink(opts.Logger), nil
+	case "webhook":
+		if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+			return nil, fmt.Errorf("invalid hook sink %q: webhook needs an http(s) URL", spec)
+		}
+		return NewWebhookSink(target, opts.WebhookSecret), nil
+	case "email":
+		var to []string
+		for _, addr := range strings.Split(target, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		if len(to) == 0 {
+			return nil, fmt.Errorf("invalid hook sink %q: email needs at least one recipient", spec)
+		}
+		return NewEmailSink(opts.SMTPAddr, opts.SMTPFrom, to), nil
+	default:
+		return nil, fmt.Errorf("invalid hook sink %q: expected log, webhook:<url> or email:<to>", spec)
+	}
+}
+
+// FromEnv arma los sinks de notificación y el de auditoría a partir de
+// HOOK_NOTIFY_DEFAULT, HOOK_NOTIFY_<CANAL> (uno por canal usado en los hooks)
+// y HOOK_AUDIT. Sin configurar, todo va al log; un canal sin variable usa
+// el sink por defecto. Una especificación inválida se registra y se ignora.
+func FromEnv(logger *zap.Logger) (map[string]internalworkflow.HookSink, internalworkflow.HookSink) {
+	opts := OptionsFromEnv(logger)
+	parse := func(key string, fallback internalworkflow.HookSink) internalworkflow.HookSink {
+		spec := config.Get(key, "")
+		if spec == "" {
+			return fallback
+		}
+		sink, err := ParseSink(spec, opts)
+		if err != nil {
+			logger.Warn("ignoring invalid hook sink", zap.String("key", key), zap.Error(err))
+			return fallback
+		}
+		return sink
+	}
+
+	logSink := NewLogSink(logger)
+	notify := map[string]internalworkflow.HookSink{"": parse("HOOK_NOTIFY_DEFAULT", logSink)}
+	for _, channel := range internalworkflow.HookChannels() {
+		if sink := parse("HOOK_NOTIFY_"+strings.ToUpper(channel), nil); sink != nil {
+			notify[channel] = sink
+		}
+	}
+	return notify, parse("HOOK_AUDIT", logSink)
+}
@@ -0,0 +1,142 @@
+package hooksinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/webhook"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	"go.uber.org/zap"
+)
+
+func testEvent() internalworkflow.HookEvent {
+	return internalworkflow.HookEvent{
+		Type:       internalworkflow.HookNotify,
+		Point:      internalworkflow.HookAfterStep,
+		Index:      1,
+		Workflow:   "SecretRotation",
+		WorkflowID: "secret-rotation-sec-1",
+		RunID:      "run-1",
+		ResourceID: "sec-1",
+		Step:       internalworkflow.StepRotateSecret,
+		Message:    "Secreto rotado exitosamente.",
+		At:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWebhookSink_PostsSignedEvent(t *testing.T) {
+	secret := []byte("hook-secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, time.Now(), time.Minute); err != nil {
+			t.Fatalf("expected a valid signature, got %v", err)
+		}
+		if got := r.Header.Get(webhook.EventHeader); got != "workflow.afterStep" {
+			t.Fatalf("unexpected event header %q", got)
+		}
+		if got := r.Header.Get(webhook.DeliveryHeader); got != "secret-rotation-sec-1/run-1/afterStep/RotateSecret/1/notify" {
+			t.Fatalf("unexpected delivery header %q", got)
+		}
+		var ev internalworkflow.HookEvent
+		if err := json.Unmarshal(body, &ev); err != nil || ev.ResourceID != "sec-1" || ev.Step != internalworkflow.StepRotateSecret {
+			t.Fatalf("unexpected payload %s (err=%v)", body, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookSink(server.URL, secret).Deliver(context.Background(), testEvent()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestWebhookSink_Non2xxReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookSink(server.URL, nil).Deliver(context.Background(), testEvent()); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestEmailSink_SendsMessage(t *testing.T) {
+	sink := NewEmailSink("localhost:1025", "engine@idp.local", []string{"ops@idp.local", "sec@idp.local"})
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	sink.send = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	if err := sink.Deliver(context.Background(), testEvent()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotAddr != "localhost:1025" || len(gotTo) != 2 {
+		t.Fatalf("unexpected envelope %s %v", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [SecretRotation] Secreto rotado exitosamente.") || !strings.Contains(gotMsg, "Paso: RotateSecret") {
+		t.Fatalf("unexpected message:\n%s", gotMsg)
+	}
+}
+
+func TestEmailSink_SendErrorIsReturned(t *testing.T) {
+	sink := NewEmailSink("localhost:1025", "engine@idp.local", []string{"ops@idp.local"})
+	sink.send = func(string, smtp.Auth, string, []string, []byte) error {
+		return errors.New("connection refused")
+	}
+	if err := sink.Deliver(context.Background(), testEvent()); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestParseSink(t *testing.T) {
+	opts := Options{Logger: zap.NewNop(), SMTPAddr: "localhost:1025", SMTPFrom: "engine@idp.local"}
+	if sink, err := ParseSink("log", opts); err != nil {
+		t.Fatalf("log: unexpected error %v", err)
+	} else if _, ok := sink.(*LogSink); !ok {
+		t.Fatalf("log: expected *LogSink, got %T", sink)
+	}
+	if sink, err := ParseSink("webhook:https://hooks.example/idp", opts); err != nil {
+		t.Fatalf("webhook: unexpected error %v", err)
+	} else if s, ok := sink.(*WebhookSink); !ok || s.url != "https://hooks.example/idp" {
+		t.Fatalf("webhook: unexpected sink %#v", sink)
+	}
+	if sink, err := ParseSink("email:ops@idp.local, sec@idp.local", opts); err != nil {
+		t.Fatalf("email: unexpected error %v", err)
+	} else if s, ok := sink.(*EmailSink); !ok || len(s.to) != 2 || s.addr != "localhost:1025" {
+		t.Fatalf("email: unexpected sink %#v", sink)
+	}
+	for _, spec := range []string{"", "slack:#ops", "webhook:", "webhook:ftp://x", "email:", "email: , "} {
+		if _, err := ParseSink(spec, opts); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestFromEnv_ConfiguresChannelsAndKeepsDefaultsOnInvalidSpecs(t *testing.T) {
+	t.Setenv("HOOK_NOTIFY_DEFAULT", "webhook:https://hooks.example/default")
+	t.Setenv("HOOK_NOTIFY_ALERTS", "email:oncall@idp.local")
+	t.Setenv("HOOK_AUDIT", "carrier-pigeon")
+
+	notify, audit := FromEnv(zap.NewNop())
+	if _, ok := notify[""].(*WebhookSink); !ok {
+		t.Fatalf("expected the default channel to be a webhook, got %T", notify[""])
+	}
+	if _, ok := notify["alerts"].(*EmailSink); !ok {
+		t.Fatalf("expected the alerts channel to be an email, got %T", notify["alerts"])
+	}
+	if _, ok := audit.(*LogSink); !ok {
+		t.Fatalf("expected an invalid audit spec to keep the log sink, got %T", audit)
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/nuevo-idp/platform/config"
+	"github.com/nuevo-idp/platform/httpx"
 	"github.com/nuevo-idp/platform/tracing"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -52,6 +52,7 @@ func (c *Client) UpdateSecretBindingsForSecret(ctx context.Context, secretID str
 	}
 	req.Header.Set("Content-Type", "application/json")
 	setInternalAuthHeader(req)
+	httpx.SetIdempotencyKey(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -72,7 +73,9 @@ func (c *Client) UpdateSecretBindingsForSecret(ctx context.Context, secretID str
 // actualizar SecretBindings para un Secret concreto.
 type Error struct {
 	Status  int
+	Code    string
 	Message string
+	TraceID string
 }
 
 func (e *Error) Error() string {
@@ -85,7 +88,15 @@ func (e *Error) Error() string {
 		msg = fmt.Sprintf("execution-workers returned status %d", e.Status)
 	}
 
-	return fmt.Sprintf("execution-workers secret bindings provider error: status=%d message=%s", e.Status, msg)
+	code := e.Code
+	if code == "" {
+		code = "unknown_error"
+	}
+
+	if e.TraceID != "" {
+		return fmt.Sprintf("execution-workers secret bindings provider error: status=%d code=%s message=%s trace_id=%s", e.Status, code, msg, e.TraceID)
+	}
+	return fmt.Sprintf("execution-workers secret bindings provider error: status=%d code=%s message=%s", e.Status, code, msg)
 }
 
 func newErrorFromResponse(resp *http.Response) error {
@@ -93,10 +104,12 @@ func newErrorFromResponse(resp *http.Response) error {
 		return &Error{Status: 0, Message: "nil response from execution-workers"}
 	}
 
-	body, _ := io.ReadAll(resp.Body)
+	p := httpx.ReadProblem(resp)
 	return &Error{
 		Status:  resp.StatusCode,
-		Message: string(body),
+		Code:    p.Code,
+		Message: p.Detail,
+		TraceID: p.TraceID,
 	}
 }
 
@@ -0,0 +1,277 @@
+// Package webhookreceiver recibe webhooks de sistemas externos (scanners de
+// seguridad, validadores de rotación) y los entrega como señales de Temporal
+// a los workflows que los esperan.
+//
+// El evento viaja en X-IDP-Event y su ID de entrega en X-IDP-Delivery, con
+// la misma firma HMAC que los webhooks salientes del control plane (ver
+// platform/webhook). El body es {"organizationId": "...", "resourceId": "...",
+// "payload": {...}}; el workflow destino se deriva del evento, de la
+// organización (default si no se indica) y del resourceId.
+//
+// SignalHandler entrega además las señales de aprobación que reenvía el
+// control plane, con el workflow destino explícito.
+package webhookreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/openapi"
+	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/validation"
+	"github.com/nuevo-idp/platform/webhook"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	"go.opentelemetry.io/otel/attribute"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.uber.org/zap"
+)
+
+// maxBodyBytes acota el body de un webhook entrante.
+const maxBodyBytes = 1 << 20
+
+// Signaler es lo que el receptor necesita de Temporal; lo cumple
+// client.Client.
+type Signaler interface {
+	DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error)
+	SignalWorkflow(ctx context.Context, workflowID, runID, signalName string, arg interface{}) error
+}
+
+type inboundEventRequest struct {
+	// OrganizationID es la organización del recurso; vacío es la default.
+	// Viaja en el body, no en un header, para que la cubra la firma.
+	OrganizationID string `json:"organizationId"`
+	// ResourceID es el ID del recurso que orquesta el workflow (Application
+	// para SecurityScanPassed, Secret para RotationValidatedExternally).
+	ResourceID string         `json:"resourceId" validate:"required"`
+	Payload    map[string]any `json:"payload"`
+}
+
+type inboundEventResponse struct {
+	ReceivedEvent
+	// Duplicate indica que la entrega ya se había recibido y no se volvió a
+	// señalizar.
+	Duplicate bool `json:"duplicate"`
+}
+
+// Options configura el Receiver.
+type Options struct {
+	// Secret verifica la firma HMAC. Sin secreto no hay forma de autenticar
+	// al emisor, así que el Receiver rechaza todas las entregas.
+	Secret []byte
+	// Tolerance es la ventana aceptada para X-IDP-Timestamp; <= 0 usa
+	// webhook.DefaultTolerance.
+	Tolerance time.Duration
+	// Store registra los eventos recibidos; nil usa uno en memoria de 24h.
+	Store *EventStore
+	// Now permite fijar el reloj en tests.
+	Now func() time.Time
+}
+
+// Receiver es el handler HTTP de los webhooks entrantes.
+type Receiver struct {
+	signaler Signaler
+	logger   *zap.Logger
+	opts     Options
+}
+
+// NewReceiver crea un Receiver que señaliza a través de signaler.
+func NewReceiver(signaler Signaler, logger *zap.Logger, opts Options) *Receiver {
+	if opts.Store == nil {
+		opts.Store = NewEventStore(24 * time.Hour)
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Receiver{signaler: signaler, logger: logger, opts: opts}
+}
+
+// verify comprueba la firma de la entrega.
+func (rc *Receiver) verify(r *http.Request, body []byte) error {
+	if len(rc.opts.Secret) == 0 {
+		return perrors.Unauthorized("webhook_secret_not_configured", "inbound webhooks are disabled until INBOUND_WEBHOOK_SECRET is set", nil)
+	}
+	return webhook.Verify(rc.opts.Secret, r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, rc.opts.Now(), rc.opts.Tolerance) //nolint:wrapcheck // ya es un perrors
+}
+
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	ctx, span := tracing.StartSpan(r.Context(), "webhookreceiver.Receive")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, rc.logger)
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil || len(body) > maxBodyBytes {
+		httpx.WriteError(w, r, perrors.Validation("invalid_request_body", "webhook body is unreadable or too large", err))
+		return
+	}
+	if err := rc.verify(r, body); err != nil {
+		logger.Warn("inbound webhook rejected", zap.String("code", perrors.Code(err)))
+		observability.ObserveDomainEvent("inbound_webhook_received", "unauthorized")
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	deliveryID := r.Header.Get(webhook.DeliveryHeader)
+	eventName := r.Header.Get(webhook.EventHeader)
+	span.SetAttributes(
+		attribute.String("webhook.delivery_id", deliveryID),
+		attribute.String("webhook.event", eventName),
+	)
+
+	ev, duplicate, err := rc.receive(ctx, deliveryID, eventName, body)
+	if err != nil {
+		logger.Warn("inbound webhook rejected",
+			zap.String("delivery_id", deliveryID),
+			zap.String("event", eventName),
+			zap.String("code", perrors.Code(err)),
+			zap.Error(err),
+		)
+		observability.ObserveDomainEvent("inbound_webhook_received", "rejected")
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	span.SetAttributes(attribute.String("workflow.id", ev.WorkflowID))
+	if duplicate {
+		observability.ObserveDomainEvent("inbound_webhook_received", "duplicate")
+		httpx.WriteJSON(w, http.StatusOK, inboundEventResponse{ReceivedEvent: *ev, Duplicate: true})
+		return
+	}
+	logger.Info("inbound webhook delivered",
+		zap.Bool("audit", true),
+		zap.String("delivery_id", ev.DeliveryID),
+		zap.String("event", ev.Event),
+		zap.String("workflow_id", ev.WorkflowID),
+		zap.String("run_id", ev.RunID),
+	)
+	observability.ObserveDomainEvent("inbound_webhook_received", "success")
+	httpx.WriteJSON(w, http.StatusAccepted, inboundEventResponse{ReceivedEvent: *ev})
+}
+
+// receive valida el evento, lo entrega como señal y lo registra. Devuelve
+// duplicate=true si la entrega ya se había registrado.
+func (rc *Receiver) receive(ctx context.Context, deliveryID, eventName string, body []byte) (*ReceivedEvent, bool, error) {
+	if deliveryID == "" {
+		return nil, false, perrors.Validation("missing_delivery_id", "missing "+webhook.DeliveryHeader+" header", nil)
+	}
+	route, ok := internalworkflow.ExternalEventFor(eventName)
+	if !ok {
+		return nil, false, perrors.Validation("unknown_event", "unknown event "+eventName, nil)
+	}
+	var req inboundEventRequest
+	if err := openapi.DecodeJSONBytes(body, &req); err != nil {
+		return nil, false, err //nolint:wrapcheck // ya es un perrors de validación
+	}
+	if fe := validation.ID("resourceId", req.ResourceID); fe != nil {
+		return nil, false, perrors.Validation("invalid_arguments", "invalid arguments: resourceId "+fe.Message, nil).WithFields(*fe)
+	}
+	organizationID := req.OrganizationID
+	if organizationID == "" {
+		organizationID = internalworkflow.DefaultOrganizationID
+	}
+	if fe := validation.ID("organizationId", organizationID); fe != nil {
+		return nil, false, perrors.Validation("invalid_arguments", "invalid arguments: organizationId "+fe.Message, nil).WithFields(*fe)
+	}
+
+	now := rc.opts.Now()
+	existing, err := rc.opts.Store.reserve(deliveryID, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	ev := &ReceivedEvent{
+		DeliveryID:     deliveryID,
+		Event:          eventName,
+		OrganizationID: organizationID,
+		ResourceID:     req.ResourceID,
+		WorkflowID:     route.WorkflowID(organizationID, req.ResourceID),
+		Signal:         route.Signal,
+		Payload:        req.Payload,
+		ReceivedAt:     now,
+	}
+	if err := rc.signal(ctx, route, ev); err != nil {
+		// Sin registrar: el emisor puede reintentar cuando el workflow
+		// exista.
+		rc.opts.Store.release(deliveryID)
+		return nil, false, err
+	}
+	rc.opts.Store.complete(ev)
+	return ev, false, nil
+}
+
+// signal comprueba que el workflow destino esté corriendo y le envía la
+// señal con el payload del evento.
+func (rc *Receiver) signal(ctx context.Context, route internalworkflow.ExternalEvent, ev *ReceivedEvent) error {
+	runID, err := runningWorkflow(ctx, rc.signaler, ev.WorkflowID, route.Workflow)
+	if err != nil {
+		return err
+	}
+	ev.RunID = runID
+
+	return signalRun(ctx, rc.signaler, ev.WorkflowID, ev.RunID, route.Signal, internalworkflow.ExternalSignal{
+		DeliveryID: ev.DeliveryID,
+		Event:      ev.Event,
+		ReceivedAt: ev.ReceivedAt,
+		Payload:    ev.Payload,
+	})
+}
+
+// runningWorkflow devuelve el run en curso de workflowID. Si workflowType no
+// está vacío, el workflow además tiene que ser de ese tipo.
+func runningWorkflow(ctx context.Context, signaler Signaler, workflowID, workflowType string) (string, error) {
+	desc, err := signaler.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return "", mapTemporalError(err, workflowType, workflowID)
+	}
+	info := desc.GetWorkflowExecutionInfo()
+	if workflowType != "" && info.GetType().GetName() != workflowType {
+		return "", perrors.NotFound("workflow_not_found", workflowType+" workflow "+workflowID+" not found", nil)
+	}
+	if status := info.GetStatus(); status != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return "", perrors.Conflict("workflow_not_running", "workflow "+workflowID+" is "+status.String(), nil)
+	}
+	return info.GetExecution().GetRunId(), nil
+}
+
+// signalRun envía la señal al run que describimos: si terminó entre medio,
+// Temporal responde NotFound y lo informamos como no corriendo.
+func signalRun(ctx context.Context, signaler Signaler, workflowID, runID, signalName string, arg any) error {
+	err := signaler.SignalWorkflow(ctx, workflowID, runID, signalName, arg)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return perrors.Conflict("workflow_not_running", "workflow "+workflowID+" is no longer running", err)
+		}
+		return mapTemporalError(err, "", workflowID)
+	}
+	return nil
+}
+
+func mapTemporalError(err error, workflowType, workflowID string) error {
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		name := "workflow " + workflowID
+		if workflowType != "" {
+			name = workflowType + " " + name
+		}
+		return perrors.NotFound("workflow_not_found", name+" not found", err)
+	}
+	var pe *perrors.Error
+	if errors.As(err, &pe) {
+		return err
+	}
+	return perrors.Upstream("temporal_unavailable", "failed to reach Temporal", err)
+}
@@ -0,0 +1,234 @@
+package webhookreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/webhook"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.uber.org/zap"
+)
+
+type sentSignal struct {
+	workflowID, runID, name string
+	arg                     internalworkflow.ExternalSignal
+	approval                internalworkflow.ApprovalSignal
+}
+
+type fakeSignaler struct {
+	workflows map[string]*workflowpb.WorkflowExecutionInfo
+	signals   []sentSignal
+}
+
+func newFakeSignaler() *fakeSignaler {
+	return &fakeSignaler{workflows: make(map[string]*workflowpb.WorkflowExecutionInfo)}
+}
+
+func (f *fakeSignaler) add(workflowID, workflowType string, status enumspb.WorkflowExecutionStatus) {
+	f.workflows[workflowID] = &workflowpb.WorkflowExecutionInfo{
+		Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: "run-" + workflowID},
+		Type:      &commonpb.WorkflowType{Name: workflowType},
+		Status:    status,
+	}
+}
+
+func (f *fakeSignaler) DescribeWorkflowExecution(_ context.Context, workflowID, _ string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	info, ok := f.workflows[workflowID]
+	if !ok {
+		return nil, serviceerror.NewNotFound("workflow not found")
+	}
+	return &workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: info}, nil
+}
+
+func (f *fakeSignaler) SignalWorkflow(_ context.Context, workflowID, runID, signalName string, arg interface{}) error {
+	sent := sentSignal{workflowID: workflowID, runID: runID, name: signalName}
+	switch a := arg.(type) {
+	case internalworkflow.ExternalSignal:
+		sent.arg = a
+	case internalworkflow.ApprovalSignal:
+		sent.approval = a
+	}
+	f.signals = append(f.signals, sent)
+	return nil
+}
+
+var (
+	testSecret = []byte("s3cret")
+	testNow    = time.Unix(1_700_000_000, 0)
+)
+
+func newTestReceiver() (*Receiver, *fakeSignaler) {
+	signaler := newFakeSignaler()
+	rc := NewReceiver(signaler, zap.NewNop(), Options{Secret: testSecret, Now: func() time.Time { return testNow }})
+	return rc, signaler
+}
+
+func deliver(rc *Receiver, event, deliveryID string, body any, secret []byte) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/inbound", bytes.NewReader(raw))
+	req.Header.Set(webhook.EventHeader, event)
+	req.Header.Set(webhook.DeliveryHeader, deliveryID)
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(testNow.Unix(), 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, testNow, raw))
+	rec := httptest.NewRecorder()
+	rc.ServeHTTP(rec, req)
+	return rec
+}
+
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &p)
+	return p.Code
+}
+
+func TestReceiver_SignalsRunningWorkflowAndRecordsEvent(t *testing.T) {
+	rc, signaler := newTestReceiver()
+	signaler.add("default:application-onboarding-app-1", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	body := map[string]any{"resourceId": "app-1", "payload": map[string]any{"scanner": "trivy", "critical": 0}}
+	rec := deliver(rc, "SecurityScanPassed", "d-1", body, testSecret)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(signaler.signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d", len(signaler.signals))
+	}
+	got := signaler.signals[0]
+	if got.workflowID != "default:application-onboarding-app-1" || got.runID != "run-default:application-onboarding-app-1" || got.name != "SecurityScanPassed" {
+		t.Fatalf("unexpected signal %+v", got)
+	}
+	if got.arg.DeliveryID != "d-1" || got.arg.Payload["scanner"] != "trivy" || !got.arg.ReceivedAt.Equal(testNow) {
+		t.Fatalf("unexpected signal payload %+v", got.arg)
+	}
+	if ev := rc.opts.Store.Get("d-1"); ev == nil || ev.WorkflowID != "default:application-onboarding-app-1" {
+		t.Fatalf("expected event to be recorded, got %+v", ev)
+	}
+
+	// Un reintento del emisor no vuelve a señalizar.
+	rec = deliver(rc, "SecurityScanPassed", "d-1", body, testSecret)
+	var resp inboundEventResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusOK || !resp.Duplicate || len(signaler.signals) != 1 {
+		t.Fatalf("expected duplicate without new signal, got %d %+v (%d signals)", rec.Code, resp, len(signaler.signals))
+	}
+}
+
+func TestReceiver_RoutesRotationValidatedToSecretRotation(t *testing.T) {
+	rc, signaler := newTestReceiver()
+	signaler.add("default:secret-rotation-db-password", "SecretRotation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	rec := deliver(rc, "RotationValidatedExternally", "d-2", map[string]any{"resourceId": "db-password"}, testSecret)
+	if rec.Code != http.StatusAccepted || len(signaler.signals) != 1 || signaler.signals[0].name != "RotationValidatedExternally" {
+		t.Fatalf("unexpected result %d %s %+v", rec.Code, rec.Body.String(), signaler.signals)
+	}
+}
+
+func TestReceiver_RoutesToTheWorkflowOfTheOrganization(t *testing.T) {
+	rc, signaler := newTestReceiver()
+	signaler.add("default:secret-rotation-db-password", "SecretRotation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	signaler.add("acme:secret-rotation-db-password", "SecretRotation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	rec := deliver(rc, "RotationValidatedExternally", "d-3", map[string]any{"organizationId": "acme", "resourceId": "db-password"}, testSecret)
+	if rec.Code != http.StatusAccepted || len(signaler.signals) != 1 || signaler.signals[0].workflowID != "acme:secret-rotation-db-password" {
+		t.Fatalf("expected the acme workflow to be signalled, got %d %s %+v", rec.Code, rec.Body.String(), signaler.signals)
+	}
+	if ev := rc.opts.Store.Get("d-3"); ev == nil || ev.OrganizationID != "acme" {
+		t.Fatalf("expected the organization to be recorded, got %+v", ev)
+	}
+
+	rec = deliver(rc, "RotationValidatedExternally", "d-4", map[string]any{"organizationId": "Acme Corp", "resourceId": "db-password"}, testSecret)
+	if rec.Code != http.StatusBadRequest || problemCode(t, rec) != "invalid_arguments" {
+		t.Fatalf("expected 400 invalid_arguments, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReceiver_RejectsUnknownAndCompletedWorkflows(t *testing.T) {
+	rc, signaler := newTestReceiver()
+	signaler.add("default:application-onboarding-app-done", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED)
+	signaler.add("default:application-onboarding-app-other", "ApplicationActivation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	cases := []struct {
+		resourceID string
+		status     int
+		code       string
+	}{
+		{"app-missing", http.StatusNotFound, "workflow_not_found"},
+		{"app-done", http.StatusConflict, "workflow_not_running"},
+		{"app-other", http.StatusNotFound, "workflow_not_found"},
+	}
+	for i, tc := range cases {
+		deliveryID := "d-" + strconv.Itoa(i)
+		rec := deliver(rc, "SecurityScanPassed", deliveryID, map[string]any{"resourceId": tc.resourceID}, testSecret)
+		if rec.Code != tc.status || problemCode(t, rec) != tc.code {
+			t.Errorf("%s: expected %d %s, got %d %s", tc.resourceID, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+		// Los rechazos no se registran: el emisor puede reintentar.
+		if rc.opts.Store.Get(deliveryID) != nil {
+			t.Errorf("%s: rejected event must not be recorded", tc.resourceID)
+		}
+	}
+	if len(signaler.signals) != 0 {
+		t.Fatalf("expected no signals, got %+v", signaler.signals)
+	}
+}
+
+func TestReceiver_RejectsEverythingWithoutSecret(t *testing.T) {
+	signaler := newFakeSignaler()
+	signaler.add("default:application-onboarding-app-1", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	rc := NewReceiver(signaler, zap.NewNop(), Options{Now: func() time.Time { return testNow }})
+
+	rec := deliver(rc, "SecurityScanPassed", "d-1", map[string]any{"resourceId": "app-1"}, nil)
+	if rec.Code != http.StatusUnauthorized || problemCode(t, rec) != "webhook_secret_not_configured" {
+		t.Fatalf("expected 401 webhook_secret_not_configured, got %d %s", rec.Code, rec.Body.String())
+	}
+	if len(signaler.signals) != 0 {
+		t.Fatalf("expected no signals, got %+v", signaler.signals)
+	}
+}
+
+func TestReceiver_RejectsInvalidRequests(t *testing.T) {
+	rc, signaler := newTestReceiver()
+	signaler.add("default:application-onboarding-app-1", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	valid := map[string]any{"resourceId": "app-1"}
+
+	cases := []struct {
+		name       string
+		event      string
+		deliveryID string
+		body       any
+		secret     []byte
+		status     int
+		code       string
+	}{
+		{"bad signature", "SecurityScanPassed", "d-1", valid, []byte("other"), http.StatusUnauthorized, "invalid_webhook_signature"},
+		{"unknown event", "SomethingElse", "d-1", valid, testSecret, http.StatusBadRequest, "unknown_event"},
+		{"missing delivery", "SecurityScanPassed", "", valid, testSecret, http.StatusBadRequest, "missing_delivery_id"},
+		{"missing resource", "SecurityScanPassed", "d-1", map[string]any{}, testSecret, http.StatusBadRequest, "invalid_request_body"},
+		{"invalid resource", "SecurityScanPassed", "d-1", map[string]any{"resourceId": "App 1"}, testSecret, http.StatusBadRequest, "invalid_arguments"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := deliver(rc, tc.event, tc.deliveryID, tc.body, tc.secret)
+			if rec.Code != tc.status || problemCode(t, rec) != tc.code {
+				t.Fatalf("expected %d %s, got %d %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+	if len(signaler.signals) != 0 {
+		t.Fatalf("expected no signals, got %+v", signaler.signals)
+	}
+}
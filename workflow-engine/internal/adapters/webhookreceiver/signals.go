@@ -0,0 +1,97 @@
+package webhookreceiver
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/tracing"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+type internalSignalRequest struct {
+	WorkflowID string                          `json:"workflowId" validate:"required"`
+	Signal     string                          `json:"signal" validate:"required"`
+	Payload    internalworkflow.ApprovalSignal `json:"payload"`
+}
+
+type internalSignalResponse struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Signal     string `json:"signal"`
+}
+
+// SignalHandler recibe las señales que reenvía el control plane (POST
+// /internal/signals), hoy sólo las decisiones de aprobación manual. A
+// diferencia de los webhooks externos, el workflow destino viene explícito
+// y la llamada se autentica con el token interno.
+type SignalHandler struct {
+	signaler Signaler
+	logger   *zap.Logger
+	// token es INTERNAL_AUTH_TOKEN; vacío no exige autenticación (modo dev).
+	token string
+}
+
+// NewSignalHandler crea un SignalHandler que señaliza a través de signaler.
+func NewSignalHandler(signaler Signaler, logger *zap.Logger, internalToken string) *SignalHandler {
+	return &SignalHandler{signaler: signaler, logger: logger, token: internalToken}
+}
+
+func (h *SignalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !httpx.RequireMethod(w, r, http.MethodPost) {
+		return
+	}
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(auth.InternalTokenHeader)), []byte(h.token)) != 1 {
+		httpx.WriteError(w, r, perrors.Unauthorized("invalid_internal_token", "missing or invalid internal auth token", nil))
+		return
+	}
+	ctx, span := tracing.StartSpan(r.Context(), "webhookreceiver.Signal")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, h.logger)
+
+	var req internalSignalRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("workflow.id", req.WorkflowID),
+		attribute.String("workflow.signal", req.Signal),
+	)
+	if !internalworkflow.IsApprovalSignal(req.Signal) {
+		httpx.WriteError(w, r, perrors.Validation("unknown_signal", "unknown signal "+req.Signal, nil))
+		return
+	}
+
+	runID, err := runningWorkflow(ctx, h.signaler, req.WorkflowID, "")
+	if err == nil {
+		err = signalRun(ctx, h.signaler, req.WorkflowID, runID, req.Signal, req.Payload)
+	}
+	if err != nil {
+		logger.Warn("internal signal rejected",
+			zap.String("workflow_id", req.WorkflowID),
+			zap.String("signal", req.Signal),
+			zap.String("code", perrors.Code(err)),
+			zap.Error(err),
+		)
+		observability.ObserveDomainEvent("internal_signal_sent", "rejected")
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	logger.Info("internal signal delivered",
+		zap.Bool("audit", true),
+		zap.String("workflow_id", req.WorkflowID),
+		zap.String("run_id", runID),
+		zap.String("signal", req.Signal),
+		zap.String("approval_id", req.Payload.ApprovalID),
+		zap.Bool("approved", req.Payload.Approved),
+		zap.String("by", req.Payload.By),
+	)
+	observability.ObserveDomainEvent("internal_signal_sent", "success")
+	httpx.WriteJSON(w, http.StatusAccepted, internalSignalResponse{WorkflowID: req.WorkflowID, RunID: runID, Signal: req.Signal})
+}
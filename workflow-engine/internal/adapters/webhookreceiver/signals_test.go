@@ -0,0 +1,76 @@
+package webhookreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuevo-idp/platform/auth"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.uber.org/zap"
+)
+
+func postSignal(h *SignalHandler, token string, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/internal/signals", bytes.NewReader(raw))
+	if token != "" {
+		req.Header.Set(auth.InternalTokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSignalHandler_ForwardsApprovalDecision(t *testing.T) {
+	signaler := newFakeSignaler()
+	signaler.add("application-decommissioning-app-1", "ApplicationDecommissioning", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	h := NewSignalHandler(signaler, zap.NewNop(), "internal")
+
+	body := map[string]any{
+		"workflowId": "application-decommissioning-app-1",
+		"signal":     "DecommissioningApproval",
+		"payload":    map[string]any{"approvalId": "decommission-app-1", "approved": true, "by": "pat", "role": "platformAdmin"},
+	}
+	if rec := postSignal(h, "", body); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	rec := postSignal(h, "internal", body)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(signaler.signals) != 1 {
+		t.Fatalf("expected 1 signal, got %+v", signaler.signals)
+	}
+	got := signaler.signals[0]
+	if got.name != "DecommissioningApproval" || got.runID != "run-application-decommissioning-app-1" || !got.approval.Approved || got.approval.By != "pat" {
+		t.Fatalf("unexpected signal %+v", got)
+	}
+}
+
+func TestSignalHandler_RejectsUnknownSignalsAndStoppedWorkflows(t *testing.T) {
+	signaler := newFakeSignaler()
+	signaler.add("application-decommissioning-app-done", "ApplicationDecommissioning", enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED)
+	h := NewSignalHandler(signaler, zap.NewNop(), "")
+
+	cases := []struct {
+		workflowID, signal string
+		status             int
+		code               string
+	}{
+		{"application-decommissioning-app-done", "SecurityScanPassed", http.StatusBadRequest, "unknown_signal"},
+		{"application-decommissioning-app-done", "DecommissioningApproval", http.StatusConflict, "workflow_not_running"},
+		{"application-decommissioning-app-404", "DecommissioningApproval", http.StatusNotFound, "workflow_not_found"},
+	}
+	for _, tc := range cases {
+		rec := postSignal(h, "", map[string]any{"workflowId": tc.workflowID, "signal": tc.signal})
+		if rec.Code != tc.status || problemCode(t, rec) != tc.code {
+			t.Errorf("%s/%s: expected %d %s, got %d %s", tc.workflowID, tc.signal, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+	if len(signaler.signals) != 0 {
+		t.Fatalf("expected no signals, got %+v", signaler.signals)
+	}
+}
@@ -0,0 +1,87 @@
+package webhookreceiver
+
+import (
+	"sync"
+	"time"
+
+	perrors "github.com/nuevo-idp/platform/errors"
+)
+
+// ReceivedEvent es un evento externo que se entregó como señal.
+type ReceivedEvent struct {
+	DeliveryID     string         `json:"deliveryId"`
+	Event          string         `json:"event"`
+	OrganizationID string         `json:"organizationId"`
+	ResourceID     string         `json:"resourceId"`
+	WorkflowID     string         `json:"workflowId"`
+	RunID          string         `json:"runId"`
+	Signal         string         `json:"signal"`
+	Payload        map[string]any `json:"payload,omitempty"`
+	ReceivedAt     time.Time      `json:"receivedAt"`
+}
+
+// EventStore registra los eventos recibidos por DeliveryID, para responder
+// los reintentos del emisor sin señalizar dos veces.
+type EventStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	events   map[string]*ReceivedEvent
+	inFlight map[string]bool
+}
+
+// NewEventStore crea un EventStore en memoria que recuerda cada evento
+// durante ttl.
+func NewEventStore(ttl time.Duration) *EventStore {
+	return &EventStore{ttl: ttl, events: make(map[string]*ReceivedEvent), inFlight: make(map[string]bool)}
+}
+
+// Get devuelve el evento registrado con deliveryID, o nil.
+func (s *EventStore) Get(deliveryID string) *ReceivedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ev := s.events[deliveryID]; ev != nil {
+		cp := *ev
+		return &cp
+	}
+	return nil
+}
+
+// reserve marca deliveryID como en curso. Devuelve el evento si ya se
+// registró, o un Conflict si otra request lo está procesando.
+func (s *EventStore) reserve(deliveryID string, now time.Time) (*ReceivedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+	if ev := s.events[deliveryID]; ev != nil {
+		cp := *ev
+		return &cp, nil
+	}
+	if s.inFlight[deliveryID] {
+		return nil, perrors.Conflict("webhook_delivery_in_flight", "delivery "+deliveryID+" is being processed", nil)
+	}
+	s.inFlight[deliveryID] = true
+	return nil, nil
+}
+
+// complete registra ev y libera su reserva.
+func (s *EventStore) complete(ev *ReceivedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, ev.DeliveryID)
+	s.events[ev.DeliveryID] = ev
+}
+
+// release libera la reserva sin registrar nada: el emisor puede reintentar.
+func (s *EventStore) release(deliveryID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, deliveryID)
+}
+
+func (s *EventStore) prune(now time.Time) {
+	for id, ev := range s.events {
+		if now.Sub(ev.ReceivedAt) > s.ttl {
+			delete(s.events, id)
+		}
+	}
+}
@@ -0,0 +1,560 @@
+// Package workflowapi expone la gestión de los workflows del engine por HTTP:
+// iniciar un workflow registrado para un recurso, describir y listar sus
+// runs, cancelarlos o terminarlos y enviarles las señales que esperan. Es la
+// alternativa al Temporal CLI para operar el engine; todas las rutas se
+// autentican con el token interno.
+package workflowapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	perrors "github.com/nuevo-idp/platform/errors"
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/tracing"
+	"github.com/nuevo-idp/platform/validation"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	"go.opentelemetry.io/otel/attribute"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+)
+
+// listPageSize acota las ejecuciones por página de GET /workflows/runs.
+const listPageSize = 50
+
+// Temporal es lo que la API necesita de Temporal; lo cumple client.Client.
+type Temporal interface {
+	ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error)
+	DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error)
+	ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error)
+	CancelWorkflow(ctx context.Context, workflowID, runID string) error
+	TerminateWorkflow(ctx context.Context, workflowID, runID, reason string, details ...interface{}) error
+	SignalWorkflow(ctx context.Context, workflowID, runID, signalName string, arg interface{}) error
+}
+
+// Handler sirve las rutas /workflows.
+type Handler struct {
+	temporal Temporal
+	logger   *zap.Logger
+	// token es INTERNAL_AUTH_TOKEN; vacío no exige autenticación (modo dev).
+	token string
+	// now permite fijar el reloj en tests.
+	now func() time.Time
+}
+
+// NewHandler crea un Handler que opera los workflows a través de temporal.
+func NewHandler(temporal Temporal, logger *zap.Logger, internalToken string) *Handler {
+	return &Handler{temporal: temporal, logger: logger, token: internalToken, now: time.Now}
+}
+
+// Register monta las rutas en mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.Handle("/workflows", h.route(http.MethodGet, h.definitions))
+	mux.Handle("/workflows/start", h.route(http.MethodPost, h.start))
+	mux.Handle("/workflows/runs", h.route(http.MethodGet, h.list))
+	mux.Handle("/workflows/runs/describe", h.route(http.MethodGet, h.describe))
+	mux.Handle("/workflows/runs/cancel", h.route(http.MethodPost, h.cancel))
+	mux.Handle("/workflows/runs/terminate", h.route(http.MethodPost, h.terminate))
+	mux.Handle("/workflows/runs/signal", h.route(http.MethodPost, h.signal))
+}
+
+// route exige el método y el token interno antes de llamar a next.
+func (h *Handler) route(method string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !httpx.RequireMethod(w, r, method) {
+			return
+		}
+		if h.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(auth.InternalTokenHeader)), []byte(h.token)) != 1 {
+			httpx.WriteError(w, r, perrors.Unauthorized("invalid_internal_token", "missing or invalid internal auth token", nil))
+			return
+		}
+		next(w, r)
+	})
+}
+
+type definitionResponse struct {
+	Name         string   `json:"name"`
+	ResourceType string   `json:"resourceType"`
+	Signals      []string `json:"signals"`
+}
+
+func (h *Handler) definitions(w http.ResponseWriter, _ *http.Request) {
+	defs := internalworkflow.Definitions()
+	out := make([]definitionResponse, 0, len(defs))
+	for _, d := range defs {
+		signals := d.Signals
+		if signals == nil {
+			signals = []string{}
+		}
+		out = append(out, definitionResponse{Name: d.Name, ResourceType: d.ResourceType, Signals: signals})
+	}
+	httpx.WriteJSON(w, http.StatusOK, out)
+}
+
+type startRequest struct {
+	Workflow string `json:"workflow" validate:"required"`
+	// OrganizationID es la organización del recurso; vacío es la default.
+	OrganizationID string `json:"organizationId"`
+	ResourceID     string `json:"resourceId" validate:"required"`
+}
+
+type runResponse struct {
+	Workflow   string `json:"workflow,omitempty"`
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+}
+
+func (h *Handler) start(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.Start")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, h.logger)
+
+	var req startRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+	def, ok := internalworkflow.DefinitionFor(req.Workflow)
+	if !ok {
+		httpx.WriteError(w, r, perrors.Validation("unknown_workflow", "unknown workflow "+req.Workflow, nil))
+		return
+	}
+	organizationID, err := organization(req.OrganizationID)
+	if err != nil {
+		httpx.WriteError(w, r, err)
+		return
+	}
+	if fe := validation.ID("resourceId", req.ResourceID); fe != nil {
+		httpx.WriteError(w, r, perrors.Validation("invalid_arguments", "invalid arguments: resourceId "+fe.Message, nil).WithFields(*fe))
+		return
+	}
+
+	workflowID := def.WorkflowID(organizationID, req.ResourceID)
+	span.SetAttributes(attribute.String("workflow.type", def.Name), attribute.String("workflow.id", workflowID))
+	run, err := h.temporal.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: internalworkflow.ApplicationEnvironmentProvisioningTaskQueue,
+		// Un run en curso para el recurso es un conflicto, no un run a
+		// reutilizar: quien inicia espera un run nuevo.
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}, def.Name, def.Input(organizationID, req.ResourceID))
+	if err != nil {
+		err = mapTemporalError(err, workflowID)
+		h.reject(logger, "workflow_started", workflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	logger.Info("workflow started",
+		zap.Bool("audit", true),
+		zap.String("workflow", def.Name),
+		zap.String("workflow_id", run.GetID()),
+		zap.String("run_id", run.GetRunID()),
+		zap.String("organization_id", organizationID),
+		zap.String("resource_id", req.ResourceID),
+	)
+	observability.ObserveDomainEvent("workflow_started", "success")
+	httpx.WriteJSON(w, http.StatusCreated, runResponse{Workflow: def.Name, WorkflowID: run.GetID(), RunID: run.GetRunID()})
+}
+
+// runSummary resume una ejecución en los listados.
+type runSummary struct {
+	Workflow   string     `json:"workflow"`
+	WorkflowID string     `json:"workflowId"`
+	RunID      string     `json:"runId"`
+	Status     string     `json:"status"`
+	StartTime  *time.Time `json:"startTime,omitempty"`
+	CloseTime  *time.Time `json:"closeTime,omitempty"`
+}
+
+type pendingActivity struct {
+	Activity        string `json:"activity"`
+	State           string `json:"state"`
+	Attempt         int32  `json:"attempt"`
+	MaximumAttempts int32  `json:"maximumAttempts"`
+	LastFailure     string `json:"lastFailure,omitempty"`
+}
+
+type describeResponse struct {
+	runSummary
+	// CurrentStep es la actividad en curso o, si no hay ninguna y el
+	// workflow espera señales, "waitForSignal".
+	CurrentStep       string            `json:"currentStep,omitempty"`
+	PendingActivities []pendingActivity `json:"pendingActivities"`
+	// PendingSignals son las señales que el run puede estar esperando y que
+	// se pueden enviar por /workflows/runs/signal: las del workflow mientras
+	// corre sin actividades pendientes, salvo las de aprobación, que se
+	// deciden en el control plane.
+	PendingSignals []string `json:"pendingSignals"`
+}
+
+func (h *Handler) describe(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.Describe")
+	defer span.End()
+
+	workflowID, ok := httpx.RequireQuery(w, r, "workflowId")
+	if !ok {
+		return
+	}
+	span.SetAttributes(attribute.String("workflow.id", workflowID))
+	desc, err := h.temporal.DescribeWorkflowExecution(ctx, workflowID, r.URL.Query().Get("runId"))
+	if err != nil {
+		httpx.WriteError(w, r, mapTemporalError(err, workflowID))
+		return
+	}
+
+	out := describeResponse{
+		runSummary:        summarize(desc.GetWorkflowExecutionInfo()),
+		PendingActivities: []pendingActivity{},
+		PendingSignals:    []string{},
+	}
+	for _, pa := range desc.GetPendingActivities() {
+		out.PendingActivities = append(out.PendingActivities, pendingActivity{
+			Activity:        pa.GetActivityType().GetName(),
+			State:           pa.GetState().String(),
+			Attempt:         pa.GetAttempt(),
+			MaximumAttempts: pa.GetMaximumAttempts(),
+			LastFailure:     pa.GetLastFailure().GetMessage(),
+		})
+	}
+	if len(out.PendingActivities) > 0 {
+		out.CurrentStep = out.PendingActivities[0].Activity
+	} else if desc.GetWorkflowExecutionInfo().GetStatus() == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		if def, ok := internalworkflow.DefinitionFor(out.Workflow); ok && len(def.Signals) > 0 {
+			out.CurrentStep = "waitForSignal"
+			for _, signal := range def.Signals {
+				if !internalworkflow.IsApprovalSignal(signal) {
+					out.PendingSignals = append(out.PendingSignals, signal)
+				}
+			}
+		}
+	}
+	httpx.WriteJSON(w, http.StatusOK, out)
+}
+
+type listResponse struct {
+	Runs []runSummary `json:"runs"`
+	// NextPageToken se pasa como pageToken para la página siguiente; vacío
+	// si no hay más.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// resourceQueryParams mapea cada parámetro de GET /workflows/runs al tipo de
+// recurso que filtra.
+var resourceQueryParams = []struct{ param, resourceType string }{
+	{"applicationId", internalworkflow.ResourceApplication},
+	{"applicationEnvironmentId", internalworkflow.ResourceApplicationEnvironment},
+	{"secretId", internalworkflow.ResourceSecret},
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.List")
+	defer span.End()
+
+	query, err := listQuery(r)
+	if err != nil {
+		httpx.WriteError(w, r, err)
+		return
+	}
+	pageToken, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("pageToken"))
+	if err != nil {
+		httpx.WriteError(w, r, perrors.Validation("invalid_page_token", "pageToken is not valid", err))
+		return
+	}
+
+	resp, err := h.temporal.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		PageSize:      listPageSize,
+		NextPageToken: pageToken,
+		Query:         query,
+	})
+	if err != nil {
+		httpx.WriteError(w, r, mapTemporalError(err, ""))
+		return
+	}
+	out := listResponse{
+		Runs:          make([]runSummary, 0, len(resp.GetExecutions())),
+		NextPageToken: base64.RawURLEncoding.EncodeToString(resp.GetNextPageToken()),
+	}
+	for _, info := range resp.GetExecutions() {
+		out.Runs = append(out.Runs, summarize(info))
+	}
+	httpx.WriteJSON(w, http.StatusOK, out)
+}
+
+// listQuery arma la consulta de visibilidad con los workflow IDs que los
+// workflows registrados derivan del recurso pedido, en la organización de
+// organizationId (la default si falta). Exige exactamente un parámetro de
+// recurso.
+func listQuery(r *http.Request) (string, error) {
+	var param, resourceType, resourceID string
+	for _, p := range resourceQueryParams {
+		if v := r.URL.Query().Get(p.param); v != "" {
+			if param != "" {
+				return "", perrors.Validation("invalid_query", "only one of applicationId, applicationEnvironmentId or secretId is allowed", nil)
+			}
+			param, resourceType, resourceID = p.param, p.resourceType, v
+		}
+	}
+	if param == "" {
+		return "", perrors.Validation("invalid_query", "one of applicationId, applicationEnvironmentId or secretId is required", nil)
+	}
+	// El ID validado no admite comillas, así que se puede interpolar.
+	if fe := validation.ID(param, resourceID); fe != nil {
+		return "", perrors.Validation("invalid_query", param+" "+fe.Message, nil).WithFields(*fe)
+	}
+
+	organizationID, err := organization(r.URL.Query().Get("organizationId"))
+	if err != nil {
+		return "", err
+	}
+
+	var terms []string
+	for _, d := range internalworkflow.Definitions() {
+		if d.ResourceType == resourceType {
+			terms = append(terms, "WorkflowId = '"+d.WorkflowID(organizationID, resourceID)+"'")
+		}
+	}
+	return strings.Join(terms, " OR "), nil
+}
+
+// organization valida el organizationId de una request; vacío es la
+// organización default.
+func organization(organizationID string) (string, error) {
+	if organizationID == "" {
+		return internalworkflow.DefaultOrganizationID, nil
+	}
+	if fe := validation.ID("organizationId", organizationID); fe != nil {
+		return "", perrors.Validation("invalid_arguments", "invalid arguments: organizationId "+fe.Message, nil).WithFields(*fe)
+	}
+	return organizationID, nil
+}
+
+type runRequest struct {
+	WorkflowID string `json:"workflowId" validate:"required"`
+	// RunID vacío apunta al último run del workflow.
+	RunID string `json:"runId"`
+}
+
+func (h *Handler) cancel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.Cancel")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, h.logger)
+
+	var req runRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+	span.SetAttributes(attribute.String("workflow.id", req.WorkflowID))
+	if err := h.checkRegistered(ctx, req.WorkflowID, req.RunID); err != nil {
+		h.reject(logger, "workflow_cancelled", req.WorkflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+	if err := h.temporal.CancelWorkflow(ctx, req.WorkflowID, req.RunID); err != nil {
+		err = mapTemporalError(err, req.WorkflowID)
+		h.reject(logger, "workflow_cancelled", req.WorkflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	logger.Info("workflow cancellation requested",
+		zap.Bool("audit", true),
+		zap.String("workflow_id", req.WorkflowID),
+		zap.String("run_id", req.RunID),
+	)
+	observability.ObserveDomainEvent("workflow_cancelled", "success")
+	httpx.WriteJSON(w, http.StatusAccepted, runResponse{WorkflowID: req.WorkflowID, RunID: req.RunID})
+}
+
+type terminateRequest struct {
+	WorkflowID string `json:"workflowId" validate:"required"`
+	RunID      string `json:"runId"`
+	// Reason queda en el historial del workflow; es obligatoria porque
+	// terminar no le da al workflow la oportunidad de compensar.
+	Reason string `json:"reason" validate:"required"`
+}
+
+func (h *Handler) terminate(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.Terminate")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, h.logger)
+
+	var req terminateRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+	span.SetAttributes(attribute.String("workflow.id", req.WorkflowID))
+	if err := h.checkRegistered(ctx, req.WorkflowID, req.RunID); err != nil {
+		h.reject(logger, "workflow_terminated", req.WorkflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+	if err := h.temporal.TerminateWorkflow(ctx, req.WorkflowID, req.RunID, req.Reason); err != nil {
+		err = mapTemporalError(err, req.WorkflowID)
+		h.reject(logger, "workflow_terminated", req.WorkflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	logger.Info("workflow terminated",
+		zap.Bool("audit", true),
+		zap.String("workflow_id", req.WorkflowID),
+		zap.String("run_id", req.RunID),
+		zap.String("reason", req.Reason),
+	)
+	observability.ObserveDomainEvent("workflow_terminated", "success")
+	httpx.WriteJSON(w, http.StatusAccepted, runResponse{WorkflowID: req.WorkflowID, RunID: req.RunID})
+}
+
+// checkRegistered comprueba que el run de workflowID sea de un workflow
+// registrado, igual que sendSignal: cancel y terminate no operan sobre otros
+// workflows del namespace.
+func (h *Handler) checkRegistered(ctx context.Context, workflowID, runID string) error {
+	desc, err := h.temporal.DescribeWorkflowExecution(ctx, workflowID, runID)
+	if err != nil {
+		return mapTemporalError(err, workflowID)
+	}
+	name := desc.GetWorkflowExecutionInfo().GetType().GetName()
+	if _, ok := internalworkflow.DefinitionFor(name); !ok {
+		return perrors.Validation("unknown_workflow", "unknown workflow "+name, nil)
+	}
+	return nil
+}
+
+type signalRequest struct {
+	WorkflowID string         `json:"workflowId" validate:"required"`
+	Signal     string         `json:"signal" validate:"required"`
+	Payload    map[string]any `json:"payload"`
+}
+
+type signalResponse struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Signal     string `json:"signal"`
+}
+
+func (h *Handler) signal(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.StartSpan(r.Context(), "workflowapi.Signal")
+	defer span.End()
+	logger := observability.LoggerWithTrace(ctx, h.logger)
+
+	var req signalRequest
+	if !httpx.DecodeAndValidate(w, r, &req) {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("workflow.id", req.WorkflowID),
+		attribute.String("workflow.signal", req.Signal),
+	)
+
+	runID, err := h.sendSignal(ctx, req)
+	if err != nil {
+		h.reject(logger, "workflow_signal_sent", req.WorkflowID, err)
+		httpx.WriteError(w, r, err)
+		return
+	}
+
+	logger.Info("workflow signal sent",
+		zap.Bool("audit", true),
+		zap.String("workflow_id", req.WorkflowID),
+		zap.String("run_id", runID),
+		zap.String("signal", req.Signal),
+	)
+	observability.ObserveDomainEvent("workflow_signal_sent", "success")
+	httpx.WriteJSON(w, http.StatusAccepted, signalResponse{WorkflowID: req.WorkflowID, RunID: runID, Signal: req.Signal})
+}
+
+// sendSignal comprueba que el run en curso de req.WorkflowID sea de un
+// workflow que espera req.Signal y se la envía como ExternalSignal. Las
+// señales de aprobación no se aceptan: la decisión se toma en el control
+// plane, que registra al aprobador y la reenvía por /internal/signals.
+func (h *Handler) sendSignal(ctx context.Context, req signalRequest) (string, error) {
+	if internalworkflow.IsApprovalSignal(req.Signal) {
+		return "", perrors.Validation("approval_signal_not_allowed",
+			req.Signal+" is an approval decision: use POST /commands/approvals/approve or /commands/approvals/reject in control-plane-api", nil)
+	}
+
+	desc, err := h.temporal.DescribeWorkflowExecution(ctx, req.WorkflowID, "")
+	if err != nil {
+		return "", mapTemporalError(err, req.WorkflowID)
+	}
+	info := desc.GetWorkflowExecutionInfo()
+	def, ok := internalworkflow.DefinitionFor(info.GetType().GetName())
+	if !ok || !def.AcceptsSignal(req.Signal) {
+		return "", perrors.Validation("unknown_signal", info.GetType().GetName()+" does not accept signal "+req.Signal, nil)
+	}
+	if status := info.GetStatus(); status != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return "", perrors.Conflict("workflow_not_running", "workflow "+req.WorkflowID+" is "+status.String(), nil)
+	}
+	runID := info.GetExecution().GetRunId()
+
+	arg := internalworkflow.ExternalSignal{Event: req.Signal, ReceivedAt: h.now(), Payload: req.Payload}
+	if err := h.temporal.SignalWorkflow(ctx, req.WorkflowID, runID, req.Signal, arg); err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return "", perrors.Conflict("workflow_not_running", "workflow "+req.WorkflowID+" is no longer running", err)
+		}
+		return "", mapTemporalError(err, req.WorkflowID)
+	}
+	return runID, nil
+}
+
+func (h *Handler) reject(logger *zap.Logger, event, workflowID string, err error) {
+	logger.Warn("workflow operation rejected",
+		zap.String("event", event),
+		zap.String("workflow_id", workflowID),
+		zap.String("code", perrors.Code(err)),
+		zap.Error(err),
+	)
+	observability.ObserveDomainEvent(event, "rejected")
+}
+
+func summarize(info *workflowpb.WorkflowExecutionInfo) runSummary {
+	out := runSummary{
+		Workflow:   info.GetType().GetName(),
+		WorkflowID: info.GetExecution().GetWorkflowId(),
+		RunID:      info.GetExecution().GetRunId(),
+		Status:     info.GetStatus().String(),
+	}
+	if t := info.GetStartTime(); t != nil {
+		start := t.AsTime()
+		out.StartTime = &start
+	}
+	if t := info.GetCloseTime(); t != nil {
+		closed := t.AsTime()
+		out.CloseTime = &closed
+	}
+	return out
+}
+
+// mapTemporalError traduce los errores de Temporal a perrors. workflowID
+// vacío es una operación que no apunta a un workflow (el listado).
+func mapTemporalError(err error, workflowID string) error {
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) && workflowID != "" {
+		return perrors.NotFound("workflow_not_found", "workflow "+workflowID+" not found", err)
+	}
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return perrors.Conflict("workflow_already_running", "workflow "+workflowID+" is already running", err)
+	}
+	var invalid *serviceerror.InvalidArgument
+	if errors.As(err, &invalid) {
+		return perrors.Validation("invalid_arguments", invalid.Error(), err)
+	}
+	var pe *perrors.Error
+	if errors.As(err, &pe) {
+		return err
+	}
+	return perrors.Upstream("temporal_unavailable", "failed to reach Temporal", err)
+}
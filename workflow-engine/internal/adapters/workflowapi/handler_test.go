@@ -0,0 +1,359 @@
+package workflowapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/platform/auth"
+	internalworkflow "github.com/nuevo-idp/workflow-engine/internal/workflow"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+)
+
+type fakeRun struct {
+	client.WorkflowRun
+	id, runID string
+}
+
+func (r fakeRun) GetID() string    { return r.id }
+func (r fakeRun) GetRunID() string { return r.runID }
+
+type sentSignal struct {
+	workflowID, runID, name string
+	arg                     any
+}
+
+type fakeTemporal struct {
+	workflows  map[string]*workflowpb.WorkflowExecutionInfo
+	pending    map[string][]*workflowpb.PendingActivityInfo
+	started    []client.StartWorkflowOptions
+	inputs     []any
+	queries    []string
+	signals    []sentSignal
+	cancelled  []string
+	terminated []string
+}
+
+func newFakeTemporal() *fakeTemporal {
+	return &fakeTemporal{
+		workflows: make(map[string]*workflowpb.WorkflowExecutionInfo),
+		pending:   make(map[string][]*workflowpb.PendingActivityInfo),
+	}
+}
+
+func (f *fakeTemporal) add(workflowID, workflowType string, status enumspb.WorkflowExecutionStatus) {
+	f.workflows[workflowID] = &workflowpb.WorkflowExecutionInfo{
+		Execution: &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: "run-" + workflowID},
+		Type:      &commonpb.WorkflowType{Name: workflowType},
+		Status:    status,
+	}
+}
+
+func (f *fakeTemporal) ExecuteWorkflow(_ context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	if info, ok := f.workflows[options.ID]; ok && info.GetStatus() == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return nil, serviceerror.NewWorkflowExecutionAlreadyStarted("already started", "", info.GetExecution().GetRunId())
+	}
+	f.add(options.ID, workflow.(string), enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	f.started = append(f.started, options)
+	f.inputs = append(f.inputs, args...)
+	return fakeRun{id: options.ID, runID: "run-" + options.ID}, nil
+}
+
+func (f *fakeTemporal) DescribeWorkflowExecution(_ context.Context, workflowID, _ string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	info, ok := f.workflows[workflowID]
+	if !ok {
+		return nil, serviceerror.NewNotFound("workflow not found")
+	}
+	return &workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: info, PendingActivities: f.pending[workflowID]}, nil
+}
+
+// ListWorkflow sólo registra la consulta y devuelve todos los workflows: la
+// consulta la evalúa Temporal.
+func (f *fakeTemporal) ListWorkflow(_ context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	f.queries = append(f.queries, request.GetQuery())
+	resp := &workflowservice.ListWorkflowExecutionsResponse{}
+	for _, info := range f.workflows {
+		resp.Executions = append(resp.Executions, info)
+	}
+	return resp, nil
+}
+
+func (f *fakeTemporal) CancelWorkflow(_ context.Context, workflowID, _ string) error {
+	if _, ok := f.workflows[workflowID]; !ok {
+		return serviceerror.NewNotFound("workflow not found")
+	}
+	f.cancelled = append(f.cancelled, workflowID)
+	return nil
+}
+
+func (f *fakeTemporal) TerminateWorkflow(_ context.Context, workflowID, _, reason string, _ ...interface{}) error {
+	if _, ok := f.workflows[workflowID]; !ok {
+		return serviceerror.NewNotFound("workflow not found")
+	}
+	f.terminated = append(f.terminated, workflowID+": "+reason)
+	return nil
+}
+
+func (f *fakeTemporal) SignalWorkflow(_ context.Context, workflowID, runID, signalName string, arg interface{}) error {
+	f.signals = append(f.signals, sentSignal{workflowID: workflowID, runID: runID, name: signalName, arg: arg})
+	return nil
+}
+
+var testNow = time.Unix(1_700_000_000, 0)
+
+func newTestMux(token string) (*http.ServeMux, *fakeTemporal) {
+	temporal := newFakeTemporal()
+	h := NewHandler(temporal, zap.NewNop(), token)
+	h.now = func() time.Time { return testNow }
+	mux := http.NewServeMux()
+	h.Register(mux)
+	return mux, temporal
+}
+
+func do(mux *http.ServeMux, method, target string, body any) *httptest.ResponseRecorder {
+	var raw []byte
+	if body != nil {
+		raw, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(raw))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &p)
+	return p.Code
+}
+
+func TestHandler_StartsRegisteredWorkflowForResource(t *testing.T) {
+	mux, temporal := newTestMux("")
+
+	rec := do(mux, http.MethodPost, "/workflows/start", map[string]string{"workflow": "SecretRotation", "resourceId": "sec-1"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp runResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.WorkflowID != "default:secret-rotation-sec-1" || resp.RunID != "run-default:secret-rotation-sec-1" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	opts := temporal.started[0]
+	if opts.TaskQueue != internalworkflow.ApplicationEnvironmentProvisioningTaskQueue || !opts.WorkflowExecutionErrorWhenAlreadyStarted {
+		t.Fatalf("unexpected start options %+v", opts)
+	}
+	if in, ok := temporal.inputs[0].(internalworkflow.SecretRotationInput); !ok || in.SecretID != "sec-1" || in.OrganizationID != internalworkflow.DefaultOrganizationID {
+		t.Fatalf("unexpected input %#v", temporal.inputs[0])
+	}
+
+	rec = do(mux, http.MethodPost, "/workflows/start", map[string]string{"workflow": "SecretRotation", "resourceId": "sec-1"})
+	if rec.Code != http.StatusConflict || problemCode(t, rec) != "workflow_already_running" {
+		t.Fatalf("expected 409 workflow_already_running, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// El mismo recurso en otra organización es otro workflow.
+	rec = do(mux, http.MethodPost, "/workflows/start", map[string]string{"workflow": "SecretRotation", "organizationId": "acme", "resourceId": "sec-1"})
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusCreated || resp.WorkflowID != "acme:secret-rotation-sec-1" {
+		t.Fatalf("expected a run in acme, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if in := temporal.inputs[1].(internalworkflow.SecretRotationInput); in.OrganizationID != "acme" {
+		t.Fatalf("unexpected input %#v", temporal.inputs[1])
+	}
+}
+
+func TestHandler_RejectsInvalidStarts(t *testing.T) {
+	mux, temporal := newTestMux("")
+
+	cases := []struct {
+		name string
+		body map[string]string
+		code string
+	}{
+		{"unknown workflow", map[string]string{"workflow": "Nope", "resourceId": "app-1"}, "unknown_workflow"},
+		{"invalid resource", map[string]string{"workflow": "ApplicationOnboarding", "resourceId": "App 1"}, "invalid_arguments"},
+		{"invalid organization", map[string]string{"workflow": "ApplicationOnboarding", "organizationId": "Acme:1", "resourceId": "app-1"}, "invalid_arguments"},
+	}
+	for _, tc := range cases {
+		rec := do(mux, http.MethodPost, "/workflows/start", tc.body)
+		if rec.Code != http.StatusBadRequest || problemCode(t, rec) != tc.code {
+			t.Fatalf("%s: expected 400 %s, got %d: %s", tc.name, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+	if len(temporal.started) != 0 {
+		t.Fatalf("expected no workflow started, got %v", temporal.started)
+	}
+}
+
+func TestHandler_DescribesCurrentStepAndPendingSignals(t *testing.T) {
+	mux, temporal := newTestMux("")
+	temporal.add("application-onboarding-app-1", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	temporal.add("appenv-provisioning-ae-1", "ApplicationEnvironmentProvisioning", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	temporal.pending["appenv-provisioning-ae-1"] = []*workflowpb.PendingActivityInfo{{
+		ActivityType:    &commonpb.ActivityType{Name: "ProvisionSecrets"},
+		State:           enumspb.PENDING_ACTIVITY_STATE_STARTED,
+		Attempt:         2,
+		MaximumAttempts: 5,
+	}}
+
+	rec := do(mux, http.MethodGet, "/workflows/runs/describe?workflowId=application-onboarding-app-1", nil)
+	var waiting describeResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &waiting)
+	if rec.Code != http.StatusOK || waiting.CurrentStep != "waitForSignal" || len(waiting.PendingSignals) != 1 || waiting.PendingSignals[0] != "SecurityScanPassed" {
+		t.Fatalf("unexpected describe %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(mux, http.MethodGet, "/workflows/runs/describe?workflowId=appenv-provisioning-ae-1", nil)
+	var busy describeResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &busy)
+	if busy.CurrentStep != "ProvisionSecrets" || len(busy.PendingActivities) != 1 || busy.PendingActivities[0].Attempt != 2 || len(busy.PendingSignals) != 0 {
+		t.Fatalf("unexpected describe: %s", rec.Body.String())
+	}
+
+	// DecommissioningApproval se decide en el control plane: no figura.
+	temporal.add("application-decommissioning-app-1", "ApplicationDecommissioning", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	rec = do(mux, http.MethodGet, "/workflows/runs/describe?workflowId=application-decommissioning-app-1", nil)
+	var approval describeResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &approval)
+	if approval.CurrentStep != "waitForSignal" || len(approval.PendingSignals) != 0 {
+		t.Fatalf("expected no signals to send, got %s", rec.Body.String())
+	}
+
+	rec = do(mux, http.MethodGet, "/workflows/runs/describe?workflowId=missing", nil)
+	if rec.Code != http.StatusNotFound || problemCode(t, rec) != "workflow_not_found" {
+		t.Fatalf("expected 404 workflow_not_found, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_ListsRunsOfResource(t *testing.T) {
+	mux, temporal := newTestMux("")
+	temporal.add("default:application-onboarding-app-1", "ApplicationOnboarding", enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED)
+
+	rec := do(mux, http.MethodGet, "/workflows/runs?applicationId=app-1", nil)
+	var resp listResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusOK || len(resp.Runs) != 1 || resp.Runs[0].Workflow != "ApplicationOnboarding" {
+		t.Fatalf("unexpected list %d: %s", rec.Code, rec.Body.String())
+	}
+	want := "WorkflowId = 'default:application-activation-app-1' OR WorkflowId = 'default:application-decommissioning-app-1' OR WorkflowId = 'default:application-onboarding-app-1'"
+	if temporal.queries[0] != want {
+		t.Fatalf("unexpected query %q", temporal.queries[0])
+	}
+	_ = do(mux, http.MethodGet, "/workflows/runs?secretId=sec-1&organizationId=acme", nil)
+	if temporal.queries[1] != "WorkflowId = 'acme:secret-rotation-sec-1'" {
+		t.Fatalf("unexpected query %q", temporal.queries[1])
+	}
+
+	for _, target := range []string{"/workflows/runs", "/workflows/runs?applicationId=app-1&secretId=sec-1", "/workflows/runs?secretId=x'%20OR%20'1", "/workflows/runs?secretId=sec-1&organizationId=x'%20OR%20'1"} {
+		if rec := do(mux, http.MethodGet, target, nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestHandler_CancelsAndTerminatesRuns(t *testing.T) {
+	mux, temporal := newTestMux("")
+	temporal.add("secret-rotation-sec-1", "SecretRotation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	if rec := do(mux, http.MethodPost, "/workflows/runs/cancel", map[string]string{"workflowId": "secret-rotation-sec-1"}); rec.Code != http.StatusAccepted {
+		t.Fatalf("cancel: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(mux, http.MethodPost, "/workflows/runs/terminate", map[string]string{"workflowId": "secret-rotation-sec-1"}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("terminate without reason: expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(mux, http.MethodPost, "/workflows/runs/terminate", map[string]string{"workflowId": "secret-rotation-sec-1", "reason": "stuck"}); rec.Code != http.StatusAccepted {
+		t.Fatalf("terminate: expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(temporal.cancelled) != 1 || len(temporal.terminated) != 1 || temporal.terminated[0] != "secret-rotation-sec-1: stuck" {
+		t.Fatalf("unexpected calls: cancelled=%v terminated=%v", temporal.cancelled, temporal.terminated)
+	}
+
+	if rec := do(mux, http.MethodPost, "/workflows/runs/cancel", map[string]string{"workflowId": "missing"}); rec.Code != http.StatusNotFound {
+		t.Fatalf("cancel missing: expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_CancelsAndTerminatesOnlyRegisteredWorkflows(t *testing.T) {
+	mux, temporal := newTestMux("")
+	temporal.add("temporal-sys-cleanup", "SystemCleanup", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	if rec := do(mux, http.MethodPost, "/workflows/runs/cancel", map[string]string{"workflowId": "temporal-sys-cleanup"}); rec.Code != http.StatusBadRequest || problemCode(t, rec) != "unknown_workflow" {
+		t.Fatalf("cancel: expected 400 unknown_workflow, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(mux, http.MethodPost, "/workflows/runs/terminate", map[string]string{"workflowId": "temporal-sys-cleanup", "reason": "stuck"}); rec.Code != http.StatusBadRequest || problemCode(t, rec) != "unknown_workflow" {
+		t.Fatalf("terminate: expected 400 unknown_workflow, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(temporal.cancelled) != 0 || len(temporal.terminated) != 0 {
+		t.Fatalf("unexpected calls: cancelled=%v terminated=%v", temporal.cancelled, temporal.terminated)
+	}
+}
+
+func TestHandler_SendsKnownSignals(t *testing.T) {
+	mux, temporal := newTestMux("")
+	temporal.add("secret-rotation-sec-1", "SecretRotation", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+	temporal.add("application-decommissioning-app-1", "ApplicationDecommissioning", enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING)
+
+	rec := do(mux, http.MethodPost, "/workflows/runs/signal", map[string]any{
+		"workflowId": "secret-rotation-sec-1",
+		"signal":     "RotationValidatedExternally",
+		"payload":    map[string]any{"validator": "ops"},
+	})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	ext, ok := temporal.signals[0].arg.(internalworkflow.ExternalSignal)
+	if !ok || ext.Event != "RotationValidatedExternally" || !ext.ReceivedAt.Equal(testNow) || ext.Payload["validator"] != "ops" {
+		t.Fatalf("unexpected signal %+v", temporal.signals[0])
+	}
+
+	// Las aprobaciones se deciden en el control plane, no acá.
+	rec = do(mux, http.MethodPost, "/workflows/runs/signal", map[string]any{
+		"workflowId": "application-decommissioning-app-1",
+		"signal":     "DecommissioningApproval",
+		"payload":    map[string]any{"approvalId": "decommissioning-app-1", "approved": true, "by": "alice"},
+	})
+	if rec.Code != http.StatusBadRequest || problemCode(t, rec) != "approval_signal_not_allowed" || !strings.Contains(rec.Body.String(), "/commands/approvals/approve") {
+		t.Fatalf("expected 400 approval_signal_not_allowed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(mux, http.MethodPost, "/workflows/runs/signal", map[string]any{"workflowId": "secret-rotation-sec-1", "signal": "SecurityScanPassed"})
+	if rec.Code != http.StatusBadRequest || problemCode(t, rec) != "unknown_signal" {
+		t.Fatalf("expected 400 unknown_signal, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(temporal.signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d", len(temporal.signals))
+	}
+}
+
+func TestHandler_RequiresInternalToken(t *testing.T) {
+	mux, _ := newTestMux("internal")
+
+	if rec := do(mux, http.MethodGet, "/workflows", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows", nil)
+	req.Header.Set(auth.InternalTokenHeader, "internal")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var defs []definitionResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &defs)
+	if rec.Code != http.StatusOK || len(defs) != len(internalworkflow.Definitions()) {
+		t.Fatalf("unexpected definitions %d: %s", rec.Code, rec.Body.String())
+	}
+}
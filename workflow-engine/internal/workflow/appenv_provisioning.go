@@ -2,12 +2,13 @@ package workflow
 
 import (
 	"context"
-	"time"
 
 	"fmt"
 	"errors"
 
+	perrors "github.com/nuevo-idp/platform/errors"
 	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/platform/validation"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/appenvprovhttp"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/gitproviderhttp"
@@ -22,6 +23,9 @@ const ApplicationEnvironmentProvisioningTaskQueue = "appenv-provisioning-task-qu
 // ApplicationEnvironmentProvisioningInput is a minimal representation of the intent
 // "ApplicationEnvironmentProvisioning" from ejemplo_estado_Deseado.json.
 type ApplicationEnvironmentProvisioningInput struct {
+	// OrganizationID es la organización del recurso; vacío es
+	// DefaultOrganizationID.
+	OrganizationID           string
 	ApplicationEnvironmentID string
 }
 
@@ -74,6 +78,8 @@ func SetAppEnvProvisioningProvider(p AppEnvProvisioningProvider) {
 }
 
 func ApplicationEnvironmentProvisioning(ctx workflow.Context, input ApplicationEnvironmentProvisioningInput) (err error) {
+	ctx = withOrganization(ctx, input.OrganizationID)
+	ctx = withPolicies(ctx, "ApplicationEnvironmentProvisioning")
 	start := workflow.Now(ctx)
 	result := "success"
 
@@ -82,9 +88,12 @@ func ApplicationEnvironmentProvisioning(ctx workflow.Context, input ApplicationE
 		observability.ObserveWorkflowRetries("ApplicationEnvironmentProvisioning", 1)
 	}
 
+	hooks := newLifecycle(ctx, "ApplicationEnvironmentProvisioning", input.ApplicationEnvironmentID)
+
 	defer func() {
 		if err != nil {
 			result = "error"
+			hooks.onFailure(ctx, err)
 		}
 		duration := workflow.Now(ctx).Sub(start).Seconds()
 		observability.ObserveWorkflowDuration("ApplicationEnvironmentProvisioning", result, duration)
@@ -95,37 +104,32 @@ func ApplicationEnvironmentProvisioning(ctx workflow.Context, input ApplicationE
 		return temporal.NewNonRetryableApplicationError("ApplicationEnvironmentID is required", "bad_input", nil)
 	}
 
+	hooks.beforeStart(ctx)
+
 	// Each step is an activity so that later we can map them to
 	// concrete calls to execution-workers (GitHub, secrets, etc.).
-	opts := workflow.ActivityOptions{
-		StartToCloseTimeout: 5 * time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    5 * time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    1 * time.Minute,
-			MaximumAttempts:    5,
-		},
-	}
-	ctx = workflow.WithActivityOptions(ctx, opts)
-
-	steps := []interface{}{
-		MaterializeRepositories,
-		ApplyBranchProtection,
-		ProvisionSecrets,
-		CreateSecretBindings,
-		VerifyGitOpsReconciliation,
-		FinalizeApplicationEnvironmentProvisioning,
+	// Cada paso corre con su StepPolicy y sus hooks afterStep.
+	steps := []struct {
+		name     string
+		activity interface{}
+	}{
+		{StepRepositories, MaterializeRepositories},
+		{StepBranchProtection, ApplyBranchProtection},
+		{StepSecrets, ProvisionSecrets},
+		{StepSecretBindings, CreateSecretBindings},
+		{StepGitOpsReconciliation, VerifyGitOpsReconciliation},
+		{StepTransitionToActive, FinalizeApplicationEnvironmentProvisioning},
 	}
 
 	for _, step := range steps {
-		future := workflow.ExecuteActivity(ctx, step, input.ApplicationEnvironmentID)
-		if err := future.Get(ctx, nil); err != nil {
+		if err := hooks.step(ctx, step.name, step.activity, input.ApplicationEnvironmentID); err != nil {
 			observability.ObserveDomainEvent("workflow_appenv_provisioning_failed", "error")
 			//nolint:wrapcheck // propagamos el error tal cual para preservar el tipo de ApplicationError
 			return err
 		}
 	}
 
+	hooks.afterSuccess(ctx)
 	observability.ObserveDomainEvent("workflow_appenv_provisioning_completed", "success")
 	return nil
 }
@@ -141,11 +145,15 @@ func MaterializeRepositories(ctx context.Context, appEnvID string) error {
 
 	// Por ahora usamos una convención simple de nombre; más adelante
 	// podremos derivarlo de más contexto del ApplicationEnvironment.
-	repoName := "appenv-" + appEnvID
+	repoName, err := validation.RepositoryName("appenv", appEnvID)
+	if err != nil {
+		//nolint:wrapcheck // devolvemos directamente ApplicationError de Temporal para que el workflow pueda inspeccionar Type
+		return temporal.NewNonRetryableApplicationError(err.Error(), perrors.Code(err), err)
+	}
 	owner := "platform" // TODO: parametrizar por equipo/organización
 
 	logger.Info("Creating Git repository via GitProvider", "owner", owner, "name", repoName, "appEnvID", appEnvID)
-	err := gitProvider.CreateRepository(ctx, owner, repoName, true)
+	err = gitProvider.CreateRepository(ctx, owner, repoName, true)
 	logExecutionWorkersErrorIfAny(logger, err, "CreateRepository", appEnvID)
 	return mapExecutionWorkersError(err)
 }
@@ -225,12 +233,13 @@ func FinalizeApplicationEnvironmentProvisioning(ctx context.Context, appEnvID st
 			"control_plane_status", apiErr.Status,
 			"control_plane_code", apiErr.Code,
 			"control_plane_message", apiErr.Message,
+			"control_plane_trace_id", apiErr.TraceID,
 		)
 	}
 
 	// Si el control-plane-api devolvió un 4xx, consideramos el error como
 	// no-retriable a nivel de Temporal para evitar reintentos inútiles.
-	if apiErr != nil && apiErr.Status >= 400 && apiErr.Status < 500 {
+	if apiErr != nil && apiErr.Status >= 400 && apiErr.Status < 500 && !isTransientClientError(apiErr.Status, apiErr.Code) {
 		code := apiErr.Code
 		if code == "" {
 			code = "control_plane_client_error"
@@ -256,7 +265,7 @@ func mapExecutionWorkersError(err error) error {
 	}
 
 	var gitErr *gitproviderhttp.Error
-	if errors.As(err, &gitErr) && gitErr.Status >= 400 && gitErr.Status < 500 {
+	if errors.As(err, &gitErr) && gitErr.Status >= 400 && gitErr.Status < 500 && !isTransientClientError(gitErr.Status, gitErr.Code) {
 		msg := gitErr.Message
 		if msg == "" {
 			msg = err.Error()
@@ -267,7 +276,7 @@ func mapExecutionWorkersError(err error) error {
 	}
 
 	var appEnvErr *appenvprovhttp.Error
-	if errors.As(err, &appEnvErr) && appEnvErr.Status >= 400 && appEnvErr.Status < 500 {
+	if errors.As(err, &appEnvErr) && appEnvErr.Status >= 400 && appEnvErr.Status < 500 && !isTransientClientError(appEnvErr.Status, appEnvErr.Code) {
 		msg := appEnvErr.Message
 		if msg == "" {
 			msg = err.Error()
@@ -293,7 +302,9 @@ func logExecutionWorkersErrorIfAny(logger log.Logger, err error, op string, appE
 			"operation", op,
 			"appEnvID", appEnvID,
 			"execution_workers_status", gitErr.Status,
+			"execution_workers_code", gitErr.Code,
 			"execution_workers_message", gitErr.Message,
+			"execution_workers_trace_id", gitErr.TraceID,
 		)
 		return
 	}
@@ -305,7 +316,9 @@ func logExecutionWorkersErrorIfAny(logger log.Logger, err error, op string, appE
 			"appEnvID", appEnvID,
 			"execution_workers_status", appEnvErr.Status,
 			"execution_workers_path", appEnvErr.Path,
+			"execution_workers_code", appEnvErr.Code,
 			"execution_workers_message", appEnvErr.Message,
+			"execution_workers_trace_id", appEnvErr.TraceID,
 		)
 		return
 	}
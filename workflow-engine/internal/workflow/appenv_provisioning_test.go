@@ -316,3 +316,17 @@ func TestMapExecutionWorkersError_AppEnvProvider4xx(t *testing.T) {
 		t.Fatalf("expected error to be non-retriable")
 	}
 }
+
+func TestMapErrors_RateLimitedIsRetryable(t *testing.T) {
+	errs := []error{
+		mapExecutionWorkersError(&gitproviderhttp.Error{Status: 429, Code: "rate_limited"}),
+		mapExecutionWorkersError(&appenvprovhttp.Error{Status: 429, Path: "/appenv/secrets", Code: "rate_limited"}),
+		mapControlPlaneError(&controlplanehttp.Error{Status: 429, Code: "rate_limited"}),
+	}
+	for _, err := range errs {
+		var appErr *temporal.ApplicationError
+		if errors.As(err, &appErr) && appErr.NonRetryable() {
+			t.Fatalf("expected 429 to stay retryable, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuevo-idp/platform/observability"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// StepWaitForApproval es la espera de DecommissioningApproval. No ejecuta
+// actividades, así que no tiene StepPolicy, pero admite hooks afterStep.
+const StepWaitForApproval = "WaitForApproval"
+
+// ApplicationDecommissioningInput modela la intención
+// "ApplicationDecommissioning" del estado deseado. La precondición es que la
+// Application ya esté en Deprecated.
+type ApplicationDecommissioningInput struct {
+	// OrganizationID es la organización del recurso; vacío es
+	// DefaultOrganizationID.
+	OrganizationID string
+	ApplicationID  string
+}
+
+// ApplicationDecommissioningPort es un puerto estrecho hacia
+// control-plane-api con los comandos y queries del decommissioning.
+type ApplicationDecommissioningPort interface {
+	// RequestDecommissioningApproval crea el pedido de aprobación del run
+	// runID, cuya decisión llega a workflowID como DecommissioningApproval.
+	RequestDecommissioningApproval(ctx context.Context, applicationID, workflowID, runID string, timeout time.Duration) error
+	// GetApplicationState devuelve el estado actual de la Application.
+	GetApplicationState(ctx context.Context, applicationID string) (string, error)
+	StartApplicationDecommissioning(ctx context.Context, applicationID string) error
+	ListApplicationEnvironments(ctx context.Context, applicationID string) ([]controlplanehttp.ApplicationEnvironment, error)
+	DecommissionApplicationEnvironment(ctx context.Context, appEnvID string) error
+	// RevokeApplicationEnvironmentSecretBindings revoca los SecretBindings
+	// de appEnvID que no estén revocados.
+	RevokeApplicationEnvironmentSecretBindings(ctx context.Context, appEnvID string) error
+	ArchiveApplication(ctx context.Context, applicationID string) error
+}
+
+var applicationDecommissioningPort ApplicationDecommissioningPort
+
+// SetApplicationDecommissioningPort permite a main y a los tests inyectar
+// la implementación (adapter HTTP en producción, fakes en tests).
+func SetApplicationDecommissioningPort(p ApplicationDecommissioningPort) {
+	applicationDecommissioningPort = p
+}
+
+// ApplicationDecommissioning da de baja una Application deprecada. Sigue los
+// steps del estado deseado:
+// - waitForApproval DecommissioningApproval (platformAdmin, 48h por defecto, falla al vencer)
+// - transition Application to Decommissioning
+// - transition ApplicationEnvironments to Decommissioning (fan-out)
+// - revoke SecretBindings (fan-out por ApplicationEnvironment)
+// - transition Application to Archived
+//
+// Un run nuevo después de un rechazo o un fallo pide otra aprobación y
+// saltea las transiciones de la Application que ya se aplicaron.
+func ApplicationDecommissioning(ctx workflow.Context, input ApplicationDecommissioningInput) (err error) {
+	ctx = withOrganization(ctx, input.OrganizationID)
+	ctx = withPolicies(ctx, "ApplicationDecommissioning")
+	start := workflow.Now(ctx)
+	result := "success"
+
+	info := workflow.GetInfo(ctx)
+	if info.Attempt > 1 {
+		observability.ObserveWorkflowRetries("ApplicationDecommissioning", 1)
+	}
+
+	hooks := newLifecycle(ctx, "ApplicationDecommissioning", input.ApplicationID)
+
+	defer func() {
+		if err != nil {
+			result = "error"
+			hooks.onFailure(ctx, err)
+			observability.ObserveDomainEvent("workflow_application_decommissioning_failed", "error")
+		}
+		duration := workflow.Now(ctx).Sub(start).Seconds()
+		observability.ObserveWorkflowDuration("ApplicationDecommissioning", result, duration)
+	}()
+
+	if input.ApplicationID == "" {
+		return temporal.NewNonRetryableApplicationError("ApplicationID is required", "bad_input", nil) //nolint:wrapcheck
+	}
+
+	hooks.beforeStart(ctx)
+
+	// 1. Pedir la aprobación y esperar la decisión. Los dos pasos admiten
+	// hooks afterStep.
+	if err := hooks.step(ctx, StepRequestApproval, RequestDecommissioningApproval, input.ApplicationID, info.WorkflowExecution.ID); err != nil {
+		return err //nolint:wrapcheck
+	}
+	if err := waitForDecommissioningApproval(ctx); err != nil {
+		return err
+	}
+	hooks.afterStep(ctx, StepWaitForApproval)
+
+	// 2. Transicionar la Application a Decommissioning.
+	if err := hooks.step(ctx, StepTransitionToDecommissioning, TransitionApplicationToDecommissioning, input.ApplicationID); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// 3. Decomisionar sus ApplicationEnvironments en paralelo. Los que ya
+	// están en Decommissioning o Retired se saltean, para que un workflow
+	// reiniciado no falle con los que ya decomisionó.
+	var appEnvs []controlplanehttp.ApplicationEnvironment
+	if err := workflow.ExecuteActivity(withStep(ctx, "ApplicationDecommissioning", StepListApplicationEnvironments), ListApplicationEnvironmentsActivity, input.ApplicationID).Get(ctx, &appEnvs); err != nil {
+		return err //nolint:wrapcheck
+	}
+	hooks.afterStep(ctx, StepListApplicationEnvironments)
+	var pending, all []string
+	for _, ae := range appEnvs {
+		all = append(all, ae.ID)
+		if ae.State != "Decommissioning" && ae.State != "Retired" {
+			pending = append(pending, ae.ID)
+		}
+	}
+	if err := fanOut(withStep(ctx, "ApplicationDecommissioning", StepDecommissionApplicationEnvironment), DecommissionApplicationEnvironmentActivity, pending); err != nil {
+		return err
+	}
+	hooks.afterStep(ctx, StepDecommissionApplicationEnvironment)
+
+	// 4. Revocar los SecretBindings de cada ApplicationEnvironment, también
+	// de los que ya estaban decomisionados.
+	if err := fanOut(withStep(ctx, "ApplicationDecommissioning", StepRevokeSecretBindings), RevokeApplicationEnvironmentSecretBindings, all); err != nil {
+		return err
+	}
+	hooks.afterStep(ctx, StepRevokeSecretBindings)
+
+	// 5. Archivar la Application.
+	if err := hooks.step(ctx, StepTransitionToArchived, ArchiveApplicationActivity, input.ApplicationID); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	hooks.afterSuccess(ctx)
+	observability.ObserveDomainEvent("workflow_application_decommissioning_completed", "success")
+	return nil
+}
+
+// fanOut ejecuta activity una vez por ID en paralelo y espera a todas. Devuelve
+// el primer error, después de que hayan terminado las demás: así un fallo no
+// deja actividades huérfanas mientras el workflow ya terminó.
+func fanOut(ctx workflow.Context, activityFn any, ids []string) error {
+	futures := make([]workflow.Future, 0, len(ids))
+	for _, id := range ids {
+		futures = append(futures, workflow.ExecuteActivity(ctx, activityFn, id))
+	}
+	var first error
+	for _, f := range futures {
+		if err := f.Get(ctx, nil); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func waitForDecommissioningApproval(ctx workflow.Context) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Waiting for DecommissioningApproval signal")
+
+	signalCh := workflow.GetSignalChannel(ctx, DecommissioningApprovalSignalName)
+	timer := workflow.NewTimer(ctx, waitTimeout(ctx, "ApplicationDecommissioning", DecommissioningApprovalSignalName))
+
+	selector := workflow.NewSelector(ctx)
+	var received bool
+	var signal ApprovalSignal
+
+	selector.AddReceive(signalCh, func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, &signal)
+		received = true
+	})
+	selector.AddFuture(timer, func(f workflow.Future) {})
+
+	selector.Select(ctx)
+
+	if !received {
+		logger.Info("Decommissioning approval timeout reached")
+		return temporal.NewNonRetryableApplicationError("decommissioning approval timeout", "decommissioning_approval_timeout", nil) //nolint:wrapcheck
+	}
+	if !signal.Approved {
+		logger.Info("Decommissioning rejected", "approvalId", signal.ApprovalID, "by", signal.By)
+		return temporal.NewNonRetryableApplicationError("decommissioning rejected by "+signal.By, "decommissioning_rejected", nil) //nolint:wrapcheck
+	}
+
+	logger.Info("Decommissioning approved", "approvalId", signal.ApprovalID, "by", signal.By)
+	return nil
+}
+
+// RequestDecommissioningApproval crea en el control plane el pedido de
+// aprobación que señaliza a workflowID con la decisión. El pedido es del run
+// actual, así que un re-run no choca con el de un run anterior.
+//
+// Antes comprueba la precondición: la Application tiene que estar en
+// Deprecated, o en Decommissioning o Archived si un run anterior ya avanzó.
+// Si no, falla sin reintentos y no se pide la aprobación.
+func RequestDecommissioningApproval(ctx context.Context, applicationID, workflowID string) error {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; skipping RequestDecommissioningApproval", "applicationId", applicationID)
+		return nil
+	}
+
+	state, err := applicationDecommissioningPort.GetApplicationState(ctx, applicationID)
+	if err != nil {
+		logControlPlaneErrorIfAny(logger, err, "GetApplication")
+		return mapControlPlaneError(err)
+	}
+	if state != "Deprecated" && state != "Decommissioning" && state != "Archived" {
+		return temporal.NewNonRetryableApplicationError("application "+applicationID+" is "+state+"; only Deprecated applications can be decommissioned", "application_not_deprecated", nil) //nolint:wrapcheck
+	}
+
+	runID := activity.GetInfo(ctx).WorkflowExecution.RunID
+	logger.Info("Requesting decommissioning approval via control-plane-api", "applicationId", applicationID, "workflowId", workflowID, "runId", runID)
+	err = applicationDecommissioningPort.RequestDecommissioningApproval(ctx, applicationID, workflowID, runID, policies.Wait("ApplicationDecommissioning", DecommissioningApprovalSignalName))
+	logControlPlaneErrorIfAny(logger, err, "RequestDecommissioningApproval")
+	return mapControlPlaneError(err)
+}
+
+// TransitionApplicationToDecommissioning mueve la Application de Deprecated
+// a Decommissioning. Si ya está en Decommissioning o Archived (un run
+// anterior llegó hasta ahí) no hace nada.
+func TransitionApplicationToDecommissioning(ctx context.Context, applicationID string) error {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; skipping StartApplicationDecommissioning", "applicationId", applicationID)
+		return nil
+	}
+
+	state, err := applicationDecommissioningPort.GetApplicationState(ctx, applicationID)
+	if err != nil {
+		logControlPlaneErrorIfAny(logger, err, "GetApplication")
+		return mapControlPlaneError(err)
+	}
+	if state == "Decommissioning" || state == "Archived" {
+		logger.Info("Application already decommissioning; skipping StartApplicationDecommissioning", "applicationId", applicationID, "state", state)
+		return nil
+	}
+
+	logger.Info("Starting application decommissioning via control-plane-api", "applicationId", applicationID)
+	err = applicationDecommissioningPort.StartApplicationDecommissioning(ctx, applicationID)
+	logControlPlaneErrorIfAny(logger, err, "StartApplicationDecommissioning")
+	return mapControlPlaneError(err)
+}
+
+// ListApplicationEnvironmentsActivity devuelve los ApplicationEnvironments
+// de la Application con su estado.
+func ListApplicationEnvironmentsActivity(ctx context.Context, applicationID string) ([]controlplanehttp.ApplicationEnvironment, error) {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; no application environments to list", "applicationId", applicationID)
+		return nil, nil
+	}
+
+	appEnvs, err := applicationDecommissioningPort.ListApplicationEnvironments(ctx, applicationID)
+	logControlPlaneErrorIfAny(logger, err, "ListApplicationEnvironments")
+	return appEnvs, mapControlPlaneError(err)
+}
+
+// DecommissionApplicationEnvironmentActivity mueve un ApplicationEnvironment
+// a Decommissioning.
+func DecommissionApplicationEnvironmentActivity(ctx context.Context, appEnvID string) error {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; skipping DecommissionApplicationEnvironment", "appEnvId", appEnvID)
+		return nil
+	}
+
+	logger.Info("Decommissioning application environment via control-plane-api", "appEnvId", appEnvID)
+	err := applicationDecommissioningPort.DecommissionApplicationEnvironment(ctx, appEnvID)
+	logControlPlaneErrorIfAny(logger, err, "DecommissionApplicationEnvironment")
+	return mapControlPlaneError(err)
+}
+
+// RevokeApplicationEnvironmentSecretBindings revoca los SecretBindings que
+// apuntan a un ApplicationEnvironment.
+func RevokeApplicationEnvironmentSecretBindings(ctx context.Context, appEnvID string) error {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; skipping SecretBindings revocation", "appEnvId", appEnvID)
+		return nil
+	}
+
+	logger.Info("Revoking SecretBindings of application environment via control-plane-api", "appEnvId", appEnvID)
+	err := applicationDecommissioningPort.RevokeApplicationEnvironmentSecretBindings(ctx, appEnvID)
+	logControlPlaneErrorIfAny(logger, err, "RevokeSecretBinding")
+	return mapControlPlaneError(err)
+}
+
+// ArchiveApplicationActivity mueve la Application de Decommissioning a
+// Archived. Si ya está archivada no hace nada.
+func ArchiveApplicationActivity(ctx context.Context, applicationID string) error {
+	logger := activity.GetLogger(ctx)
+	if applicationDecommissioningPort == nil {
+		logger.Info("No ApplicationDecommissioningPort configured; skipping ArchiveApplication", "applicationId", applicationID)
+		return nil
+	}
+
+	state, err := applicationDecommissioningPort.GetApplicationState(ctx, applicationID)
+	if err != nil {
+		logControlPlaneErrorIfAny(logger, err, "GetApplication")
+		return mapControlPlaneError(err)
+	}
+	if state == "Archived" {
+		logger.Info("Application already archived; skipping ArchiveApplication", "applicationId", applicationID)
+		return nil
+	}
+
+	logger.Info("Archiving application via control-plane-api", "applicationId", applicationID)
+	err = applicationDecommissioningPort.ArchiveApplication(ctx, applicationID)
+	logControlPlaneErrorIfAny(logger, err, "ArchiveApplication")
+	return mapControlPlaneError(err)
+}
@@ -0,0 +1,271 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+type fakeDecommissioningPort struct {
+	mu             sync.Mutex
+	approvals      []string
+	runIDs         []string
+	state          string
+	started        int
+	decommissioned []string
+	revoked        []string
+	archived       int
+	startErr       error
+	decommissionFn func(appEnvID string) error
+}
+
+func (f *fakeDecommissioningPort) RequestDecommissioningApproval(_ context.Context, _, workflowID, runID string, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.approvals = append(f.approvals, workflowID)
+	f.runIDs = append(f.runIDs, runID)
+	return nil
+}
+
+func (f *fakeDecommissioningPort) GetApplicationState(_ context.Context, _ string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.state == "" {
+		return "Deprecated", nil
+	}
+	return f.state, nil
+}
+
+func (f *fakeDecommissioningPort) StartApplicationDecommissioning(_ context.Context, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started++
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.state = "Decommissioning"
+	return nil
+}
+
+func (f *fakeDecommissioningPort) ListApplicationEnvironments(_ context.Context, applicationID string) ([]controlplanehttp.ApplicationEnvironment, error) {
+	return []controlplanehttp.ApplicationEnvironment{
+		{ID: applicationID + "-env-dev", State: "Active"},
+		{ID: applicationID + "-env-prod", State: "Frozen"},
+		{ID: applicationID + "-env-old", State: "Retired"},
+	}, nil
+}
+
+func (f *fakeDecommissioningPort) DecommissionApplicationEnvironment(_ context.Context, appEnvID string) error {
+	if f.decommissionFn != nil {
+		if err := f.decommissionFn(appEnvID); err != nil {
+			return err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.decommissioned = append(f.decommissioned, appEnvID)
+	return nil
+}
+
+func (f *fakeDecommissioningPort) RevokeApplicationEnvironmentSecretBindings(_ context.Context, appEnvID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoked = append(f.revoked, appEnvID)
+	return nil
+}
+
+func (f *fakeDecommissioningPort) ArchiveApplication(_ context.Context, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.archived++
+	f.state = "Archived"
+	return nil
+}
+
+func newDecommissioningEnv(t *testing.T, port ApplicationDecommissioningPort) *testsuite.TestWorkflowEnvironment {
+	t.Helper()
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+
+	SetApplicationDecommissioningPort(port)
+	t.Cleanup(func() { SetApplicationDecommissioningPort(nil) })
+
+	env.RegisterWorkflow(ApplicationDecommissioning)
+	env.RegisterActivity(RequestDecommissioningApproval)
+	env.RegisterActivity(TransitionApplicationToDecommissioning)
+	env.RegisterActivity(ListApplicationEnvironmentsActivity)
+	env.RegisterActivity(DecommissionApplicationEnvironmentActivity)
+	env.RegisterActivity(RevokeApplicationEnvironmentSecretBindings)
+	env.RegisterActivity(ArchiveApplicationActivity)
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: ApplicationDecommissioningWorkflowID("", "app-1")})
+	return env
+}
+
+func signalDecommissioningApproval(env *testsuite.TestWorkflowEnvironment, approved bool, after time.Duration) {
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(DecommissioningApprovalSignalName, ApprovalSignal{ApprovalID: "decommissioning-app-1", Approved: approved, By: "alice", Role: "platformAdmin"})
+	}, after)
+}
+
+func TestApplicationDecommissioning_HappyPath(t *testing.T) {
+	fake := &fakeDecommissioningPort{}
+	env := newDecommissioningEnv(t, fake)
+	signalDecommissioningApproval(env, true, 2*time.Hour)
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatalf("workflow not completed")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(fake.approvals) != 1 || fake.approvals[0] != "default:application-decommissioning-app-1" || fake.runIDs[0] == "" {
+		t.Fatalf("expected an approval request for the workflow run, got %v %v", fake.approvals, fake.runIDs)
+	}
+	sort.Strings(fake.decommissioned)
+	if fake.started != 1 || len(fake.decommissioned) != 2 || fake.decommissioned[0] != "app-1-env-dev" || fake.decommissioned[1] != "app-1-env-prod" {
+		t.Fatalf("expected the non-retired environments to be decommissioned, got started=%d %v", fake.started, fake.decommissioned)
+	}
+	if len(fake.revoked) != 3 {
+		t.Fatalf("expected bindings of every environment to be revoked, got %v", fake.revoked)
+	}
+	if fake.archived != 1 {
+		t.Fatalf("expected 1 ArchiveApplication call, got %d", fake.archived)
+	}
+}
+
+func TestApplicationDecommissioning_RequiresID(t *testing.T) {
+	env := newDecommissioningEnv(t, &fakeDecommissioningPort{})
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{})
+
+	if err := env.GetWorkflowError(); err == nil {
+		t.Fatalf("expected error for missing ApplicationID, got nil")
+	}
+}
+
+func TestApplicationDecommissioning_FailsWithoutApproval(t *testing.T) {
+	cases := map[string]struct {
+		signal   bool
+		approved bool
+		errType  string
+	}{
+		"timeout":  {errType: "decommissioning_approval_timeout"},
+		"rejected": {signal: true, approved: false, errType: "decommissioning_rejected"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeDecommissioningPort{}
+			env := newDecommissioningEnv(t, fake)
+			if tc.signal {
+				signalDecommissioningApproval(env, tc.approved, time.Hour)
+			}
+
+			env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+
+			var appErr *temporal.ApplicationError
+			if err := env.GetWorkflowError(); !errors.As(err, &appErr) || appErr.Type() != tc.errType {
+				t.Fatalf("expected %s, got %v", tc.errType, err)
+			}
+			if fake.started != 0 || fake.archived != 0 {
+				t.Fatalf("expected no transitions, got started=%d archived=%d", fake.started, fake.archived)
+			}
+		})
+	}
+}
+
+func TestApplicationDecommissioning_RequiresADeprecatedApplication(t *testing.T) {
+	fake := &fakeDecommissioningPort{state: "Active"}
+	env := newDecommissioningEnv(t, fake)
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+
+	var appErr *temporal.ApplicationError
+	if err := env.GetWorkflowError(); !errors.As(err, &appErr) || appErr.Type() != "application_not_deprecated" {
+		t.Fatalf("expected application_not_deprecated, got %v", err)
+	}
+	if len(fake.approvals) != 0 || fake.started != 0 {
+		t.Fatalf("expected no approval request nor transitions, got approvals=%v started=%d", fake.approvals, fake.started)
+	}
+}
+
+func TestApplicationDecommissioning_CanBeRerunAfterRejection(t *testing.T) {
+	fake := &fakeDecommissioningPort{}
+
+	rejected := newDecommissioningEnv(t, fake)
+	signalDecommissioningApproval(rejected, false, time.Hour)
+	rejected.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+	var appErr *temporal.ApplicationError
+	if err := rejected.GetWorkflowError(); !errors.As(err, &appErr) || appErr.Type() != "decommissioning_rejected" {
+		t.Fatalf("expected decommissioning_rejected, got %v", err)
+	}
+
+	// El re-run con el mismo workflow ID pide una aprobación nueva.
+	rerun := newDecommissioningEnv(t, fake)
+	signalDecommissioningApproval(rerun, true, time.Hour)
+	rerun.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+	if err := rerun.GetWorkflowError(); err != nil {
+		t.Fatalf("expected the re-run to complete, got %v", err)
+	}
+	if len(fake.approvals) != 2 || fake.started != 1 || fake.archived != 1 || fake.state != "Archived" {
+		t.Fatalf("expected a second approval and a full decommissioning, got approvals=%v started=%d archived=%d state=%s", fake.approvals, fake.started, fake.archived, fake.state)
+	}
+}
+
+func TestApplicationDecommissioning_RerunSkipsTransitionsAlreadyApplied(t *testing.T) {
+	cases := map[string]struct {
+		state    string
+		started  int
+		archived int
+	}{
+		"decommissioning": {state: "Decommissioning", archived: 1},
+		"archived":        {state: "Archived"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeDecommissioningPort{state: tc.state}
+			env := newDecommissioningEnv(t, fake)
+			signalDecommissioningApproval(env, true, time.Hour)
+
+			env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if fake.started != tc.started || fake.archived != tc.archived {
+				t.Fatalf("expected started=%d archived=%d, got started=%d archived=%d", tc.started, tc.archived, fake.started, fake.archived)
+			}
+		})
+	}
+}
+
+func TestApplicationDecommissioning_DoesNotArchiveWhenAnEnvironmentFails(t *testing.T) {
+	fake := &fakeDecommissioningPort{decommissionFn: func(appEnvID string) error {
+		if appEnvID == "app-1-env-prod" {
+			return &controlplanehttp.Error{Status: 400, Code: "application_environment_invalid_state_for_decommissioning", Message: "application environment is already Retired"}
+		}
+		return nil
+	}}
+	env := newDecommissioningEnv(t, fake)
+	signalDecommissioningApproval(env, true, time.Minute)
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+
+	var appErr *temporal.ApplicationError
+	if err := env.GetWorkflowError(); !errors.As(err, &appErr) || appErr.Type() != "application_environment_invalid_state_for_decommissioning" || !appErr.NonRetryable() {
+		t.Fatalf("expected a non-retryable domain error, got %v", err)
+	}
+	if len(fake.decommissioned) != 1 || len(fake.revoked) != 0 || fake.archived != 0 {
+		t.Fatalf("expected the workflow to stop before revoking, got decommissioned=%v revoked=%v archived=%d", fake.decommissioned, fake.revoked, fake.archived)
+	}
+}
@@ -3,8 +3,9 @@ package workflow
 import (
 	"context"
 	"errors"
-	"time"
+	"net/http"
 
+	perrors "github.com/nuevo-idp/platform/errors"
 	"github.com/nuevo-idp/platform/observability"
 	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
 	"go.temporal.io/sdk/activity"
@@ -18,7 +19,10 @@ const ApplicationOnboardingTaskQueue = "application-onboarding-task-queue"
 // ejemplo de estado deseado. Es deliberadamente mínima: el resto del contexto
 // vive en el control-plane-api.
 type ApplicationOnboardingInput struct {
-	ApplicationID string
+	// OrganizationID es la organización del recurso; vacío es
+	// DefaultOrganizationID.
+	OrganizationID string
+	ApplicationID  string
 }
 
 // ApplicationActivationInput modela la intención "ApplicationActivation" del
@@ -27,7 +31,10 @@ type ApplicationOnboardingInput struct {
 // También es mínima: el workflow asume que las
 // precondiciones (todos los ApplicationEnvironment activos) ya se cumplieron.
 type ApplicationActivationInput struct {
-	ApplicationID string
+	// OrganizationID es la organización del recurso; vacío es
+	// DefaultOrganizationID.
+	OrganizationID string
+	ApplicationID  string
 }
 
 // ApplicationOnboardingPort es un puerto estrecho hacia control-plane-api para
@@ -60,6 +67,8 @@ const securityScanPassedSignalName = "SecurityScanPassed"
 // - esperar evento SecurityScanPassed con timeout
 // - transicionar Application a Onboarding
 func ApplicationOnboarding(ctx workflow.Context, input ApplicationOnboardingInput) (err error) {
+	ctx = withOrganization(ctx, input.OrganizationID)
+	ctx = withPolicies(ctx, "ApplicationOnboarding")
 	start := workflow.Now(ctx)
 	result := "success"
 
@@ -68,9 +77,12 @@ func ApplicationOnboarding(ctx workflow.Context, input ApplicationOnboardingInpu
 		observability.ObserveWorkflowRetries("ApplicationOnboarding", 1)
 	}
 
+	hooks := newLifecycle(ctx, "ApplicationOnboarding", input.ApplicationID)
+
 	defer func() {
 		if err != nil {
 			result = "error"
+			hooks.onFailure(ctx, err)
 		}
 		duration := workflow.Now(ctx).Sub(start).Seconds()
 		observability.ObserveWorkflowDuration("ApplicationOnboarding", result, duration)
@@ -80,38 +92,29 @@ func ApplicationOnboarding(ctx workflow.Context, input ApplicationOnboardingInpu
 		return temporal.NewNonRetryableApplicationError("ApplicationID is required", "bad_input", nil) //nolint:wrapcheck
 	}
 
-	opts := workflow.ActivityOptions{
-		StartToCloseTimeout: 5 * time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    5 * time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    1 * time.Minute,
-			MaximumAttempts:    5,
-		},
-	}
-	ctx = workflow.WithActivityOptions(ctx, opts)
+	hooks.beforeStart(ctx)
 
 	// 1. Crear CodeRepository para la aplicación.
-	if err := workflow.ExecuteActivity(ctx, CreateCodeRepositoryForApplication, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepCodeRepository, CreateCodeRepositoryForApplication, input.ApplicationID); err != nil {
 		observability.ObserveDomainEvent("workflow_application_onboarding_failed", "error")
 		return err //nolint:wrapcheck
 	}
 
 	// 2. Crear DeploymentRepository si aplica. El adapter decidirá si realmente
 	// crea algo o si es un no-op según el modelo de despliegue.
-	if err := workflow.ExecuteActivity(ctx, CreateDeploymentRepositoryForApplication, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepDeploymentRepository, CreateDeploymentRepositoryForApplication, input.ApplicationID); err != nil {
 		observability.ObserveDomainEvent("workflow_application_onboarding_failed", "error")
 		return err //nolint:wrapcheck
 	}
 
 	// 3. Crear GitOpsIntegration para la aplicación.
-	if err := workflow.ExecuteActivity(ctx, CreateGitOpsIntegrationForApplication, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepGitOpsIntegration, CreateGitOpsIntegrationForApplication, input.ApplicationID); err != nil {
 		observability.ObserveDomainEvent("workflow_application_onboarding_failed", "error")
 		return err //nolint:wrapcheck
 	}
 
 	// 4. Declarar los ApplicationEnvironments necesarios para la aplicación.
-	if err := workflow.ExecuteActivity(ctx, DeclareApplicationEnvironmentsForApplication, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepApplicationEnvironments, DeclareApplicationEnvironmentsForApplication, input.ApplicationID); err != nil {
 		observability.ObserveDomainEvent("workflow_application_onboarding_failed", "error")
 		return err //nolint:wrapcheck
 	}
@@ -123,11 +126,12 @@ func ApplicationOnboarding(ctx workflow.Context, input ApplicationOnboardingInpu
 	}
 
 	// 6. Transicionar la Application a estado Onboarding.
-	if err := workflow.ExecuteActivity(ctx, TransitionApplicationToOnboarding, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepTransitionToOnboarding, TransitionApplicationToOnboarding, input.ApplicationID); err != nil {
 		observability.ObserveDomainEvent("workflow_application_onboarding_failed", "error")
 		return err //nolint:wrapcheck
 	}
 
+	hooks.afterSuccess(ctx)
 	observability.ObserveDomainEvent("workflow_application_onboarding_completed", "success")
 	return nil
 }
@@ -137,14 +141,14 @@ func waitForSecurityScanPassed(ctx workflow.Context) error {
 	logger.Info("Waiting for SecurityScanPassed event")
 
 	signalCh := workflow.GetSignalChannel(ctx, securityScanPassedSignalName)
-	timer := workflow.NewTimer(ctx, 15*time.Minute) // 900s como en el JSON de estado deseado
+	timer := workflow.NewTimer(ctx, waitTimeout(ctx, "ApplicationOnboarding", securityScanPassedSignalName))
 
 	selector := workflow.NewSelector(ctx)
 	var received bool
+	var signal ExternalSignal
 
 	selector.AddReceive(signalCh, func(c workflow.ReceiveChannel, more bool) {
-		var payload interface{}
-		c.Receive(ctx, &payload)
+		c.Receive(ctx, &signal)
 		received = true
 	})
 	selector.AddFuture(timer, func(f workflow.Future) {})
@@ -152,7 +156,7 @@ func waitForSecurityScanPassed(ctx workflow.Context) error {
 	selector.Select(ctx)
 
 	if received {
-		logger.Info("Received SecurityScanPassed signal")
+		logger.Info("Received SecurityScanPassed signal", "deliveryId", signal.DeliveryID)
 		return nil
 	}
 
@@ -236,6 +240,8 @@ func TransitionApplicationToOnboarding(ctx context.Context, applicationID string
 // validadas antes de dispararlo (por ejemplo, todos los ApplicationEnvironment
 // están en estado Active).
 func ApplicationActivation(ctx workflow.Context, input ApplicationActivationInput) (err error) {
+	ctx = withOrganization(ctx, input.OrganizationID)
+	ctx = withPolicies(ctx, "ApplicationActivation")
 	start := workflow.Now(ctx)
 	result := "success"
 
@@ -244,9 +250,12 @@ func ApplicationActivation(ctx workflow.Context, input ApplicationActivationInpu
 		observability.ObserveWorkflowRetries("ApplicationActivation", 1)
 	}
 
+	hooks := newLifecycle(ctx, "ApplicationActivation", input.ApplicationID)
+
 	defer func() {
 		if err != nil {
 			result = "error"
+			hooks.onFailure(ctx, err)
 		}
 		duration := workflow.Now(ctx).Sub(start).Seconds()
 		observability.ObserveWorkflowDuration("ApplicationActivation", result, duration)
@@ -256,21 +265,13 @@ func ApplicationActivation(ctx workflow.Context, input ApplicationActivationInpu
 		return temporal.NewNonRetryableApplicationError("ApplicationID is required", "bad_input", nil) //nolint:wrapcheck
 	}
 
-	opts := workflow.ActivityOptions{
-		StartToCloseTimeout: 5 * time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    5 * time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    1 * time.Minute,
-			MaximumAttempts:    5,
-		},
-	}
-	ctx = workflow.WithActivityOptions(ctx, opts)
+	hooks.beforeStart(ctx)
 
-	if err := workflow.ExecuteActivity(ctx, TransitionApplicationToActive, input.ApplicationID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepTransitionToActive, TransitionApplicationToActive, input.ApplicationID); err != nil {
 		return err //nolint:wrapcheck
 	}
 
+	hooks.afterSuccess(ctx)
 	return nil
 }
 
@@ -308,6 +309,7 @@ func logControlPlaneErrorIfAny(logger interface {
 			"control_plane_status", apiErr.Status,
 			"control_plane_code", apiErr.Code,
 			"control_plane_message", apiErr.Message,
+			"control_plane_trace_id", apiErr.TraceID,
 		)
 		return
 	}
@@ -328,7 +330,7 @@ func mapControlPlaneError(err error) error {
 	}
 
 	var apiErr *controlplanehttp.Error
-	if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 {
+	if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 && !isTransientClientError(apiErr.Status, apiErr.Code) {
 		code := apiErr.Code
 		if code == "" {
 			code = "control_plane_client_error"
@@ -342,5 +344,21 @@ func mapControlPlaneError(err error) error {
 		return temporal.NewNonRetryableApplicationError(msg, code, err) //nolint:wrapcheck
 	}
 
+	// Un ID derivado inválido (validation.JoinID) falla antes de llamar a la
+	// API y tampoco se arregla reintentando.
+	if perrors.IsKind(err, perrors.KindValidation) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), perrors.Code(err), err) //nolint:wrapcheck
+	}
+
 	return err
 }
+
+// isTransientClientError identifica los 4xx (de control-plane-api o
+// execution-workers) que son transitorios y deben reintentarse: el servidor
+// aplicó rate limiting (429), la request original con la misma
+// Idempotency-Key sigue en curso, así que el reintento de Temporal obtendrá
+// su respuesta, u otro escritor guardó el recurso en el medio
+// (concurrent_modification) y el comando no se aplicó.
+func isTransientClientError(status int, code string) bool {
+	return status == http.StatusTooManyRequests || code == "idempotency_key_in_flight" || code == "concurrent_modification"
+}
@@ -0,0 +1,241 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nuevo-idp/platform/observability"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Puntos del ciclo de vida en los que corren los hooks del bloque "hook" del
+// estado deseado.
+const (
+	HookBeforeStart  = "beforeStart"
+	HookAfterStep    = "afterStep"
+	HookAfterSuccess = "afterSuccess"
+	HookOnFailure    = "onFailure"
+)
+
+// Tipos de hook.
+const (
+	HookNotify   = "notify"
+	HookAuditLog = "auditLog"
+)
+
+// Hook es una entrada del bloque "hook" de un workflow.
+type Hook struct {
+	Type    string
+	Message string
+	// Channel elige el sink de una notificación; vacío es el canal por
+	// defecto.
+	Channel string
+	// IfStep limita un afterStep a ese paso.
+	IfStep string
+}
+
+// HookEvent es lo que recibe un HookSink: el hook y el run que lo disparó.
+type HookEvent struct {
+	Type  string `json:"type"`
+	Point string `json:"point"`
+	// Index es la posición del hook entre los de su punto; distingue dos
+	// hooks del mismo punto y paso.
+	Index      int       `json:"index"`
+	Workflow   string    `json:"workflow"`
+	WorkflowID string    `json:"workflowId"`
+	RunID      string    `json:"runId"`
+	ResourceID string    `json:"resourceId"`
+	Step       string    `json:"step,omitempty"`
+	Message    string    `json:"message"`
+	Channel    string    `json:"channel,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// workflowHooks son los bloques "hook" del estado deseado, por workflow y
+// punto del ciclo de vida.
+var workflowHooks = map[string]map[string][]Hook{
+	"ApplicationOnboarding": {
+		HookBeforeStart:  {{Type: HookNotify, Message: "Iniciando onboarding de aplicación..."}},
+		HookAfterSuccess: {{Type: HookAuditLog, Message: "Onboarding completado"}},
+		HookOnFailure:    {{Type: HookNotify, Message: "Onboarding fallido", Channel: "alerts"}},
+	},
+	"ApplicationEnvironmentProvisioning": {
+		HookAfterStep: {{Type: HookNotify, Message: "Provisioning terminado para ApplicationEnvironment.", IfStep: StepTransitionToActive}},
+	},
+	"ApplicationActivation": {
+		HookBeforeStart:  {{Type: HookNotify, Message: "Activando aplicación…"}},
+		HookAfterSuccess: {{Type: HookAuditLog, Message: "Aplicación activada correctamente."}},
+	},
+	"ApplicationDecommissioning": {
+		HookAfterSuccess: {{Type: HookAuditLog, Message: "Aplicación archivada tras decommissioning"}},
+	},
+	"SecretRotation": {
+		HookAfterStep: {{Type: HookNotify, Message: "Secreto rotado exitosamente.", IfStep: StepRotateSecret}},
+	},
+}
+
+// HookChannels devuelve los canales de notificación que usan los hooks, sin
+// el canal por defecto.
+func HookChannels() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, points := range workflowHooks {
+		for _, hooks := range points {
+			for _, h := range hooks {
+				if h.Type == HookNotify && h.Channel != "" && !seen[h.Channel] {
+					seen[h.Channel] = true
+					out = append(out, h.Channel)
+				}
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// HookSink entrega hooks: las notificaciones de un canal o el audit log.
+type HookSink interface {
+	Deliver(ctx context.Context, ev HookEvent) error
+}
+
+var (
+	notificationSinks map[string]HookSink
+	auditSink         HookSink
+)
+
+// SetNotificationSinks configura los sinks de las notificaciones por canal.
+// El canal "" es el default y recibe también las de los canales sin sink
+// propio.
+func SetNotificationSinks(sinks map[string]HookSink) {
+	notificationSinks = sinks
+}
+
+// SetAuditSink configura el sink de los hooks auditLog.
+func SetAuditSink(s HookSink) {
+	auditSink = s
+}
+
+func hookSink(ev HookEvent) HookSink {
+	if ev.Type == HookAuditLog {
+		return auditSink
+	}
+	if s, ok := notificationSinks[ev.Channel]; ok {
+		return s
+	}
+	return notificationSinks[""]
+}
+
+// hookActivityOptions: un hook es best-effort, reintenta poco y no hereda la
+// StepPolicy del paso.
+var hookActivityOptions = workflow.ActivityOptions{
+	StartToCloseTimeout: 30 * time.Second,
+	RetryPolicy: &temporal.RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2.0,
+		MaximumAttempts:    3,
+	},
+}
+
+// hooksChangeID marca en el historial los runs que ejecutan hooks; los que
+// empezaron antes de que existieran se reproducen sin ellos.
+const hooksChangeID = "lifecycle-hooks"
+
+// lifecycle ejecuta los pasos de un run y sus hooks.
+type lifecycle struct {
+	workflow   string
+	resourceID string
+	hooks      map[string][]Hook
+	enabled    bool
+}
+
+func newLifecycle(ctx workflow.Context, workflowName, resourceID string) *lifecycle {
+	version := workflow.GetVersion(ctx, hooksChangeID, workflow.DefaultVersion, 1)
+	return &lifecycle{workflow: workflowName, resourceID: resourceID, hooks: workflowHooks[workflowName], enabled: version == 1}
+}
+
+// step ejecuta la actividad del paso con su StepPolicy y, si termina bien,
+// los hooks afterStep.
+func (l *lifecycle) step(ctx workflow.Context, step string, activityFn any, args ...any) error {
+	if err := workflow.ExecuteActivity(withStep(ctx, l.workflow, step), activityFn, args...).Get(ctx, nil); err != nil {
+		return err //nolint:wrapcheck
+	}
+	l.afterStep(ctx, step)
+	return nil
+}
+
+func (l *lifecycle) beforeStart(ctx workflow.Context) {
+	l.run(ctx, HookBeforeStart, "", nil)
+}
+
+func (l *lifecycle) afterStep(ctx workflow.Context, step string) {
+	l.run(ctx, HookAfterStep, step, nil)
+}
+
+func (l *lifecycle) afterSuccess(ctx workflow.Context) {
+	l.run(ctx, HookAfterSuccess, "", nil)
+}
+
+// onFailure corre en un contexto desconectado para notificar también los
+// runs cancelados.
+func (l *lifecycle) onFailure(ctx workflow.Context, cause error) {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	l.run(ctx, HookOnFailure, "", cause)
+}
+
+// run ejecuta los hooks de point. Un hook que falla se registra y el
+// workflow sigue.
+func (l *lifecycle) run(ctx workflow.Context, point, step string, cause error) {
+	if !l.enabled {
+		return
+	}
+	info := workflow.GetInfo(ctx)
+	ctx = workflow.WithActivityOptions(ctx, hookActivityOptions)
+	for i, h := range l.hooks[point] {
+		if h.IfStep != "" && h.IfStep != step {
+			continue
+		}
+		ev := HookEvent{
+			Type:       h.Type,
+			Point:      point,
+			Index:      i,
+			Workflow:   l.workflow,
+			WorkflowID: info.WorkflowExecution.ID,
+			RunID:      info.WorkflowExecution.RunID,
+			ResourceID: l.resourceID,
+			Step:       step,
+			Message:    h.Message,
+			Channel:    h.Channel,
+			At:         workflow.Now(ctx),
+		}
+		if cause != nil {
+			ev.Error = cause.Error()
+		}
+		if err := workflow.ExecuteActivity(ctx, RunHook, ev).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Warn("Workflow hook failed; continuing", "point", point, "type", h.Type, "step", step, "error", err)
+		}
+	}
+}
+
+// RunHook entrega un hook a su sink. Sin sink configurado sólo queda en el
+// log de la actividad.
+func RunHook(ctx context.Context, ev HookEvent) error {
+	logger := activity.GetLogger(ctx)
+	sink := hookSink(ev)
+	if sink == nil {
+		logger.Info("No hook sink configured; skipping delivery", "type", ev.Type, "point", ev.Point, "workflow", ev.Workflow, "message", ev.Message)
+		return nil
+	}
+
+	if err := sink.Deliver(ctx, ev); err != nil {
+		logger.Warn("Workflow hook delivery failed", "type", ev.Type, "point", ev.Point, "channel", ev.Channel, "error", err)
+		observability.ObserveDomainEvent("workflow_hook_delivered", "error")
+		return fmt.Errorf("deliver %s hook: %w", ev.Type, err)
+	}
+	observability.ObserveDomainEvent("workflow_hook_delivered", "success")
+	return nil
+}
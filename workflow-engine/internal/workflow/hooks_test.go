@@ -0,0 +1,182 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.temporal.io/sdk/testsuite"
+)
+
+type recordingHookSink struct {
+	mu     sync.Mutex
+	events []HookEvent
+	err    error
+}
+
+func (s *recordingHookSink) Deliver(_ context.Context, ev HookEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	return s.err
+}
+
+func (s *recordingHookSink) points() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.events))
+	for _, ev := range s.events {
+		out = append(out, ev.Point)
+	}
+	return out
+}
+
+// useHookSinks configura los sinks del test y los quita al terminar.
+func useHookSinks(t *testing.T, notify map[string]HookSink, audit HookSink) {
+	t.Helper()
+	SetNotificationSinks(notify)
+	SetAuditSink(audit)
+	t.Cleanup(func() {
+		SetNotificationSinks(nil)
+		SetAuditSink(nil)
+	})
+}
+
+func newOnboardingEnvWithHooks(port ApplicationOnboardingPort) *testsuite.TestWorkflowEnvironment {
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	SetApplicationOnboardingPort(port)
+	env.RegisterWorkflow(ApplicationOnboarding)
+	env.RegisterActivity(CreateCodeRepositoryForApplication)
+	env.RegisterActivity(CreateDeploymentRepositoryForApplication)
+	env.RegisterActivity(CreateGitOpsIntegrationForApplication)
+	env.RegisterActivity(DeclareApplicationEnvironmentsForApplication)
+	env.RegisterActivity(TransitionApplicationToOnboarding)
+	env.RegisterActivity(RunHook)
+	return env
+}
+
+func TestApplicationOnboarding_RunsLifecycleHooks(t *testing.T) {
+	notify, audit := &recordingHookSink{}, &recordingHookSink{}
+	useHookSinks(t, map[string]HookSink{"": notify}, audit)
+
+	env := newOnboardingEnvWithHooks(&fakeApplicationOnboardingPort{})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(securityScanPassedSignalName, nil)
+	}, time.Minute)
+	env.ExecuteWorkflow(ApplicationOnboarding, ApplicationOnboardingInput{ApplicationID: "app-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := notify.points(); len(got) != 1 || got[0] != HookBeforeStart {
+		t.Fatalf("expected a single beforeStart notification, got %v", got)
+	}
+	if notify.events[0].Message != "Iniciando onboarding de aplicación..." || notify.events[0].ResourceID != "app-1" {
+		t.Fatalf("unexpected notification %+v", notify.events[0])
+	}
+	if len(audit.events) != 1 || audit.events[0].Point != HookAfterSuccess || audit.events[0].Type != HookAuditLog {
+		t.Fatalf("expected a single afterSuccess audit log, got %+v", audit.events)
+	}
+}
+
+func TestApplicationOnboarding_NotifiesAlertsChannelOnFailure(t *testing.T) {
+	notify, alerts, audit := &recordingHookSink{}, &recordingHookSink{}, &recordingHookSink{}
+	useHookSinks(t, map[string]HookSink{"": notify, "alerts": alerts}, audit)
+
+	env := newOnboardingEnvWithHooks(&failingOnboardingPort{})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(securityScanPassedSignalName, nil)
+	}, time.Minute)
+	env.ExecuteWorkflow(ApplicationOnboarding, ApplicationOnboardingInput{ApplicationID: "app-invalid"})
+	if env.GetWorkflowError() == nil {
+		t.Fatalf("expected the workflow to fail")
+	}
+
+	if len(alerts.events) != 1 || alerts.events[0].Point != HookOnFailure || alerts.events[0].Error == "" {
+		t.Fatalf("expected a single onFailure alert with the cause, got %+v", alerts.events)
+	}
+	if got := notify.points(); len(got) != 1 || got[0] != HookBeforeStart {
+		t.Fatalf("expected only the beforeStart notification on the default channel, got %v", got)
+	}
+	if len(audit.events) != 0 {
+		t.Fatalf("expected no audit log for a failed run, got %+v", audit.events)
+	}
+}
+
+func TestApplicationOnboarding_FailingHookSinkDoesNotFailWorkflow(t *testing.T) {
+	failing := &recordingHookSink{err: errors.New("smtp unavailable")}
+	useHookSinks(t, map[string]HookSink{"": failing}, failing)
+
+	fake := &fakeApplicationOnboardingPort{}
+	env := newOnboardingEnvWithHooks(fake)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(securityScanPassedSignalName, nil)
+	}, time.Minute)
+	env.ExecuteWorkflow(ApplicationOnboarding, ApplicationOnboardingInput{ApplicationID: "app-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected a failing hook not to fail the workflow, got %v", err)
+	}
+	if fake.applicationOnboardingCalls != 1 {
+		t.Fatalf("expected the workflow to reach TransitionToOnboarding, got %d calls", fake.applicationOnboardingCalls)
+	}
+	// beforeStart y afterSuccess, con los reintentos de hookActivityOptions.
+	if got := len(failing.events); got != 2*int(hookActivityOptions.RetryPolicy.MaximumAttempts) {
+		t.Fatalf("expected every hook to be retried, got %d deliveries", got)
+	}
+}
+
+func TestSecretRotation_AfterStepHookOnlyForRotateSecret(t *testing.T) {
+	notify := &recordingHookSink{}
+	useHookSinks(t, map[string]HookSink{"": notify}, &recordingHookSink{})
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	SetSecretRotationPort(&fakeSecretRotationPort{})
+	env.RegisterWorkflow(SecretRotation)
+	env.RegisterActivity(PerformSecretRotation)
+	env.RegisterActivity(UpdateSecretBindingsForSecret)
+	env.RegisterActivity(CompleteSecretRotationActivity)
+	env.RegisterActivity(RunHook)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(rotationValidatedSignalName, nil)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(SecretRotation, SecretRotationInput{SecretID: "sec-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(notify.events) != 1 || notify.events[0].Point != HookAfterStep || notify.events[0].Step != StepRotateSecret {
+		t.Fatalf("expected a single afterStep notification for RotateSecret, got %+v", notify.events)
+	}
+}
+
+func TestApplicationDecommissioning_AfterStepHooksForApproval(t *testing.T) {
+	original := workflowHooks["ApplicationDecommissioning"]
+	workflowHooks["ApplicationDecommissioning"] = map[string][]Hook{
+		HookAfterStep: {
+			{Type: HookNotify, Message: "Aprobación pedida", IfStep: StepRequestApproval},
+			{Type: HookNotify, Message: "Aprobación recibida", IfStep: StepWaitForApproval},
+		},
+	}
+	t.Cleanup(func() { workflowHooks["ApplicationDecommissioning"] = original })
+	notify := &recordingHookSink{}
+	useHookSinks(t, map[string]HookSink{"": notify}, &recordingHookSink{})
+
+	env := newDecommissioningEnv(t, &fakeDecommissioningPort{})
+	env.RegisterActivity(RunHook)
+	signalDecommissioningApproval(env, true, time.Hour)
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(notify.events) != 2 || notify.events[0].Step != StepRequestApproval || notify.events[1].Step != StepWaitForApproval {
+		t.Fatalf("expected afterStep hooks for the approval request and wait, got %+v", notify.events)
+	}
+	if notify.events[0].Index != 0 || notify.events[1].Index != 1 {
+		t.Fatalf("expected each hook to carry its index, got %+v", notify.events)
+	}
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/nuevo-idp/platform/httpx"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+)
+
+// IdempotencyKeyForActivity deriva la Idempotency-Key base de una actividad.
+// WorkflowID + RunID + ActivityID es estable entre reintentos de la misma
+// actividad y distinto entre actividades, que es justo lo que necesitan los
+// comandos de control-plane-api y execution-workers para deduplicar. El
+// RunID evita que un run nuevo con el mismo workflow ID (un re-run tras un
+// fallo) reciba las respuestas cacheadas del run anterior.
+func IdempotencyKeyForActivity(info activity.Info) string {
+	return info.WorkflowExecution.ID + "/" + info.WorkflowExecution.RunID + "/" + info.ActivityID
+}
+
+// NewIdempotencyInterceptor devuelve un interceptor de worker que agrega a
+// cada actividad la key de idempotencia en el contexto; los adapters HTTP la
+// envían como header Idempotency-Key.
+func NewIdempotencyInterceptor() interceptor.WorkerInterceptor {
+	return &idempotencyInterceptor{}
+}
+
+type idempotencyInterceptor struct {
+	interceptor.WorkerInterceptorBase
+}
+
+func (i *idempotencyInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &idempotencyActivityInbound{}
+	a.Next = next
+	return a
+}
+
+type idempotencyActivityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (a *idempotencyActivityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	ctx = httpx.WithIdempotencyKey(ctx, IdempotencyKeyForActivity(activity.GetInfo(ctx)))
+	return a.Next.ExecuteActivity(ctx, in) //nolint:wrapcheck // el interceptor no debe alterar el error de la actividad
+}
@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+type keyCapturingOnboardingPort struct {
+	fakeApplicationOnboardingPort
+	keys []string
+}
+
+func (p *keyCapturingOnboardingPort) DeclareCodeRepository(ctx context.Context, _ string) error {
+	key, _ := httpx.IdempotencyKeyFromContext(ctx)
+	p.keys = append(p.keys, key)
+	return nil
+}
+
+func TestIdempotencyInterceptor_AddsKeyDerivedFromWorkflowAndActivity(t *testing.T) {
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewIdempotencyInterceptor()},
+	})
+
+	port := &keyCapturingOnboardingPort{}
+	SetApplicationOnboardingPort(port)
+	t.Cleanup(func() { SetApplicationOnboardingPort(nil) })
+
+	env.RegisterActivity(CreateCodeRepositoryForApplication)
+	if _, err := env.ExecuteActivity(CreateCodeRepositoryForApplication, "app-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(port.keys) != 1 || port.keys[0] == "" {
+		t.Fatalf("expected one non-empty idempotency key, got %v", port.keys)
+	}
+	if strings.Count(port.keys[0], "/") < 2 {
+		t.Fatalf("expected key of the form <workflowID>/<runID>/<activityID>, got %q", port.keys[0])
+	}
+}
+
+func TestIdempotencyKeyForActivity_DiffersBetweenRuns(t *testing.T) {
+	info := activity.Info{ActivityID: "5"}
+	info.WorkflowExecution.ID = "application-decommissioning-app-1"
+	info.WorkflowExecution.RunID = "run-1"
+	first := IdempotencyKeyForActivity(info)
+	info.WorkflowExecution.RunID = "run-2"
+	if second := IdempotencyKeyForActivity(info); first == second {
+		t.Fatalf("expected a new run to get a new key, both are %q", first)
+	}
+}
+
+func TestMapControlPlaneError_InFlightIdempotencyKeyIsRetryable(t *testing.T) {
+	for _, code := range []string{"idempotency_key_in_flight", "concurrent_modification"} {
+		err := mapControlPlaneError(&controlplanehttp.Error{Status: 409, Code: code})
+
+		var appErr *temporal.ApplicationError
+		if errors.As(err, &appErr) && appErr.NonRetryable() {
+			t.Fatalf("expected %s conflict to stay retryable, got %v", code, err)
+		}
+	}
+}
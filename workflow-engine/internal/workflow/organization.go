@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nuevo-idp/platform/httpx"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// DefaultOrganizationID es la organización de los inputs que no indican
+// una; coincide con la organización implícita de control-plane-api.
+const DefaultOrganizationID = "default"
+
+// organizationOrDefault devuelve organizationID o DefaultOrganizationID si
+// está vacío.
+func organizationOrDefault(organizationID string) string {
+	if organizationID == "" {
+		return DefaultOrganizationID
+	}
+	return organizationID
+}
+
+// organizationWorkflowID antepone la organización al ID del workflow: los
+// IDs de los recursos son únicos dentro de su organización, no en toda la
+// instalación. Los IDs validados no contienen ':', así que dos
+// organizaciones nunca comparten workflow ID.
+func organizationWorkflowID(organizationID, id string) string {
+	return organizationOrDefault(organizationID) + ":" + id
+}
+
+// organizationHeader es el header de Temporal con la organización del run.
+const organizationHeader = "organization-id"
+
+type organizationCtxKey struct{}
+
+// withOrganization deja organizationID en ctx para que las actividades que
+// ejecuta el workflow operen en esa organización (ver
+// NewOrganizationPropagator).
+func withOrganization(ctx workflow.Context, organizationID string) workflow.Context {
+	return workflow.WithValue(ctx, organizationCtxKey{}, organizationOrDefault(organizationID))
+}
+
+// NewOrganizationPropagator devuelve el propagador que lleva la
+// organización del workflow a sus actividades, donde queda como
+// httpx.WithOrganization: los adapters la envían en X-Organization-ID. Se
+// registra en las client.Options del cliente de Temporal.
+func NewOrganizationPropagator() workflow.ContextPropagator {
+	return organizationPropagator{}
+}
+
+type organizationPropagator struct{}
+
+func (organizationPropagator) Inject(ctx context.Context, w workflow.HeaderWriter) error {
+	if org, ok := httpx.OrganizationFromContext(ctx); ok {
+		return setOrganizationHeader(w, org)
+	}
+	return nil
+}
+
+func (organizationPropagator) InjectFromWorkflow(ctx workflow.Context, w workflow.HeaderWriter) error {
+	if org, ok := ctx.Value(organizationCtxKey{}).(string); ok && org != "" {
+		return setOrganizationHeader(w, org)
+	}
+	return nil
+}
+
+func (organizationPropagator) Extract(ctx context.Context, r workflow.HeaderReader) (context.Context, error) {
+	org, err := organizationFromHeader(r)
+	if err != nil || org == "" {
+		return ctx, err
+	}
+	return httpx.WithOrganization(ctx, org), nil
+}
+
+func (organizationPropagator) ExtractToWorkflow(ctx workflow.Context, r workflow.HeaderReader) (workflow.Context, error) {
+	org, err := organizationFromHeader(r)
+	if err != nil || org == "" {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, organizationCtxKey{}, org), nil
+}
+
+func setOrganizationHeader(w workflow.HeaderWriter, org string) error {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(org)
+	if err != nil {
+		return fmt.Errorf("encode organization header: %w", err)
+	}
+	w.Set(organizationHeader, payload)
+	return nil
+}
+
+func organizationFromHeader(r workflow.HeaderReader) (string, error) {
+	payload, ok := r.Get(organizationHeader)
+	if !ok {
+		return "", nil
+	}
+	var org string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &org); err != nil {
+		return "", fmt.Errorf("decode organization header: %w", err)
+	}
+	return org, nil
+}
@@ -0,0 +1,53 @@
+package workflow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/nuevo-idp/platform/httpx"
+	"github.com/nuevo-idp/workflow-engine/internal/adapters/controlplanehttp"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestOrganizationWorkflowID_PrefixesTheOrganization(t *testing.T) {
+	if got := ApplicationOnboardingWorkflowID("acme", "app-1"); got != "acme:application-onboarding-app-1" {
+		t.Fatalf("unexpected workflow id %q", got)
+	}
+	if got := SecretRotationWorkflowID("", "sec-1"); got != "default:secret-rotation-sec-1" {
+		t.Fatalf("expected the default organization, got %q", got)
+	}
+}
+
+func TestApplicationActivation_SendsTheOrganizationToControlPlane(t *testing.T) {
+	var mu sync.Mutex
+	var orgs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		orgs = append(orgs, r.Header.Get(httpx.OrganizationHeader))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	var ts testsuite.WorkflowTestSuite
+	ts.SetContextPropagators([]workflow.ContextPropagator{NewOrganizationPropagator()})
+	env := ts.NewTestWorkflowEnvironment()
+	SetApplicationOnboardingPort(controlplanehttp.NewClient(server.URL))
+	t.Cleanup(func() { SetApplicationOnboardingPort(nil) })
+	env.RegisterWorkflow(ApplicationActivation)
+	env.RegisterActivity(TransitionApplicationToActive)
+	env.RegisterActivity(RunHook)
+
+	env.ExecuteWorkflow(ApplicationActivation, ApplicationActivationInput{OrganizationID: "acme", ApplicationID: "app-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(orgs) != 1 || orgs[0] != "acme" {
+		t.Fatalf("expected the call to carry X-Organization-ID acme, got %v", orgs)
+	}
+}
@@ -0,0 +1,276 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nuevo-idp/platform/config"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Pasos de los workflows que ejecutan actividades. Cada uno tiene su
+// StepPolicy; los nombres siguen los resources del estado deseado.
+const (
+	StepCodeRepository                     = "CodeRepository"
+	StepDeploymentRepository               = "DeploymentRepository"
+	StepGitOpsIntegration                  = "GitOpsIntegration"
+	StepApplicationEnvironments            = "ApplicationEnvironments"
+	StepTransitionToOnboarding             = "TransitionToOnboarding"
+	StepTransitionToActive                 = "TransitionToActive"
+	StepRepositories                       = "Repositories"
+	StepBranchProtection                   = "BranchProtection"
+	StepSecrets                            = "Secrets"
+	StepSecretBindings                     = "SecretBindings"
+	StepGitOpsReconciliation               = "GitOpsReconciliation"
+	StepRotateSecret                       = "RotateSecret"
+	StepCompleteRotation                   = "CompleteRotation"
+	StepRequestApproval                    = "RequestApproval"
+	StepTransitionToDecommissioning        = "TransitionToDecommissioning"
+	StepListApplicationEnvironments        = "ListApplicationEnvironments"
+	StepDecommissionApplicationEnvironment = "DecommissionApplicationEnvironment"
+	StepRevokeSecretBindings               = "RevokeSecretBindings"
+	StepTransitionToArchived               = "TransitionToArchived"
+)
+
+// StepPolicy es la retry policy y el timeout de las actividades de un paso.
+// Backoff es el primer intervalo entre intentos; los siguientes se duplican
+// hasta un minuto (o hasta Backoff, si es mayor).
+type StepPolicy struct {
+	MaxAttempts int32
+	Backoff     time.Duration
+	Timeout     time.Duration
+}
+
+// DefaultStepPolicy aplica a los pasos sin retryPolicy en el estado deseado.
+var DefaultStepPolicy = StepPolicy{MaxAttempts: 5, Backoff: 5 * time.Second, Timeout: 5 * time.Minute}
+
+func (p StepPolicy) activityOptions() workflow.ActivityOptions {
+	maxInterval := time.Minute
+	if p.Backoff > maxInterval {
+		maxInterval = p.Backoff
+	}
+	return workflow.ActivityOptions{
+		StartToCloseTimeout: p.Timeout,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    p.Backoff,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    maxInterval,
+			MaximumAttempts:    p.MaxAttempts,
+		},
+	}
+}
+
+// ParseStepPolicy interpreta "<attempts>/<backoff>[/<timeout>]" (por ejemplo
+// "3/30s" o "3/30s/10m"). Sin timeout se conserva el de base.
+func ParseStepPolicy(s string, base StepPolicy) (StepPolicy, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return StepPolicy{}, fmt.Errorf("invalid step policy %q: expected <attempts>/<backoff>[/<timeout>]", s)
+	}
+	attempts, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil || attempts < 1 {
+		return StepPolicy{}, fmt.Errorf("invalid step policy %q: attempts must be a positive integer", s)
+	}
+	backoff, err := time.ParseDuration(parts[1])
+	if err != nil || backoff <= 0 {
+		return StepPolicy{}, fmt.Errorf("invalid step policy %q: backoff must be a positive duration", s)
+	}
+	p := StepPolicy{MaxAttempts: int32(attempts), Backoff: backoff, Timeout: base.Timeout}
+	if len(parts) == 3 {
+		if p.Timeout, err = time.ParseDuration(parts[2]); err != nil || p.Timeout <= 0 {
+			return StepPolicy{}, fmt.Errorf("invalid step policy %q: timeout must be a positive duration", s)
+		}
+	}
+	return p, nil
+}
+
+type stepKey struct{ workflow, name string }
+
+// Policies guarda la StepPolicy de cada paso y el timeout de cada espera de
+// señal, por workflow.
+type Policies struct {
+	steps map[stepKey]StepPolicy
+	waits map[stepKey]time.Duration
+}
+
+// DefaultPolicies devuelve las retry policies y los timeoutSeconds del estado
+// deseado.
+func DefaultPolicies() Policies {
+	return Policies{
+		steps: map[stepKey]StepPolicy{
+			{"ApplicationOnboarding", StepCodeRepository}:                    {MaxAttempts: 3, Backoff: 30 * time.Second, Timeout: DefaultStepPolicy.Timeout},
+			{"ApplicationOnboarding", StepDeploymentRepository}:              {MaxAttempts: 2, Backoff: 15 * time.Second, Timeout: DefaultStepPolicy.Timeout},
+			{"ApplicationEnvironmentProvisioning", StepGitOpsReconciliation}: {MaxAttempts: 5, Backoff: 10 * time.Second, Timeout: DefaultStepPolicy.Timeout},
+		},
+		waits: map[stepKey]time.Duration{
+			{"ApplicationOnboarding", securityScanPassedSignalName}:           900 * time.Second,
+			{"SecretRotation", rotationValidatedSignalName}:                   3600 * time.Second,
+			{"ApplicationDecommissioning", DecommissioningApprovalSignalName}: 172800 * time.Second,
+		},
+	}
+}
+
+// Step devuelve la StepPolicy del paso step de workflowName.
+func (p Policies) Step(workflowName, step string) StepPolicy {
+	if sp, ok := p.steps[stepKey{workflowName, step}]; ok {
+		return sp
+	}
+	return DefaultStepPolicy
+}
+
+// Wait devuelve cuánto espera workflowName la señal signal antes de fallar.
+func (p Policies) Wait(workflowName, signal string) time.Duration {
+	return p.waits[stepKey{workflowName, signal}]
+}
+
+// SetStep fija la StepPolicy de un paso.
+func (p *Policies) SetStep(workflowName, step string, sp StepPolicy) {
+	p.steps[stepKey{workflowName, step}] = sp
+}
+
+// SetWait fija el timeout de la espera de una señal.
+func (p *Policies) SetWait(workflowName, signal string, timeout time.Duration) {
+	p.waits[stepKey{workflowName, signal}] = timeout
+}
+
+// PoliciesFromEnv parte de DefaultPolicies y las sobrescribe, para cada paso
+// y señal de los workflows registrados, con las variables
+// WORKFLOW_STEP_<WORKFLOW>_<STEP> ("<attempts>/<backoff>[/<timeout>]", p.ej.
+// WORKFLOW_STEP_APPLICATION_ONBOARDING_CODE_REPOSITORY=3/30s) y
+// WORKFLOW_WAIT_<WORKFLOW>_<SIGNAL> (duración de Go, p.ej. 900s). Un valor
+// inválido conserva el default y se informa en el error, que nombra cada
+// variable inválida; las policies devueltas sirven igual.
+func PoliciesFromEnv() (Policies, error) {
+	p := DefaultPolicies()
+	var errs []error
+	for _, def := range Definitions() {
+		for _, step := range def.Steps {
+			key := "WORKFLOW_STEP_" + envName(def.Name) + "_" + envName(step)
+			raw := config.Get(key, "")
+			if raw == "" {
+				continue
+			}
+			sp, err := ParseStepPolicy(raw, p.Step(def.Name, step))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				continue
+			}
+			p.SetStep(def.Name, step, sp)
+		}
+		for _, signal := range def.Signals {
+			key := "WORKFLOW_WAIT_" + envName(def.Name) + "_" + envName(signal)
+			raw := config.Get(key, "")
+			if raw == "" {
+				continue
+			}
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				errs = append(errs, fmt.Errorf("%s: invalid wait %q: must be a positive duration", key, raw))
+				continue
+			}
+			p.SetWait(def.Name, signal, d)
+		}
+	}
+	return p, errors.Join(errs...)
+}
+
+// envName pasa un nombre en CamelCase a SCREAMING_SNAKE_CASE.
+func envName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+var policies = DefaultPolicies()
+
+// SetPolicies permite a main cargar las policies de la configuración y a los
+// tests acortarlas. Cada run fija las vigentes al empezar (ver
+// withPolicies): un cambio afecta a los runs que empiezan después.
+func SetPolicies(p Policies) {
+	policies = p
+}
+
+// runPolicies son las policies de un workflow fijadas para un run. Se
+// serializan en el historial, así que sus campos son exportados.
+type runPolicies struct {
+	Default StepPolicy
+	Steps   map[string]StepPolicy
+	Waits   map[string]time.Duration
+}
+
+func (p Policies) forWorkflow(workflowName string) runPolicies {
+	rp := runPolicies{Default: DefaultStepPolicy, Steps: map[string]StepPolicy{}, Waits: map[string]time.Duration{}}
+	for k, sp := range p.steps {
+		if k.workflow == workflowName {
+			rp.Steps[k.name] = sp
+		}
+	}
+	for k, d := range p.waits {
+		if k.workflow == workflowName {
+			rp.Waits[k.name] = d
+		}
+	}
+	return rp
+}
+
+// policiesChangeID marca en el historial los runs que fijan sus policies al
+// empezar; los anteriores se reproducen leyendo las del proceso.
+const policiesChangeID = "run-policies"
+
+type policiesCtxKey struct{}
+
+// withPolicies registra en el historial, con un SideEffect, las policies de
+// workflowName vigentes al empezar el run y las deja en el contexto. Leer la
+// variable global desde el workflow no es determinista: un replay en un
+// worker con otra configuración armaría otros timers y retry policies.
+func withPolicies(ctx workflow.Context, workflowName string) workflow.Context {
+	if workflow.GetVersion(ctx, policiesChangeID, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return ctx
+	}
+	var rp runPolicies
+	if err := workflow.SideEffect(ctx, func(workflow.Context) any {
+		return policies.forWorkflow(workflowName)
+	}).Get(&rp); err != nil {
+		workflow.GetLogger(ctx).Warn("Could not decode run policies; using the worker's", "error", err)
+		return ctx
+	}
+	return workflow.WithValue(ctx, policiesCtxKey{}, rp)
+}
+
+// stepPolicy devuelve la StepPolicy del paso: la fijada por el run o, en
+// runs anteriores a withPolicies, la del proceso.
+func stepPolicy(ctx workflow.Context, workflowName, step string) StepPolicy {
+	rp, ok := ctx.Value(policiesCtxKey{}).(runPolicies)
+	if !ok {
+		return policies.Step(workflowName, step)
+	}
+	if sp, ok := rp.Steps[step]; ok {
+		return sp
+	}
+	return rp.Default
+}
+
+// waitTimeout devuelve cuánto espera el run la señal signal, con el mismo
+// criterio que stepPolicy.
+func waitTimeout(ctx workflow.Context, workflowName, signal string) time.Duration {
+	rp, ok := ctx.Value(policiesCtxKey{}).(runPolicies)
+	if !ok {
+		return policies.Wait(workflowName, signal)
+	}
+	return rp.Waits[signal]
+}
+
+// withStep aplica al contexto las ActivityOptions del paso step.
+func withStep(ctx workflow.Context, workflowName, step string) workflow.Context {
+	return workflow.WithActivityOptions(ctx, stepPolicy(ctx, workflowName, step).activityOptions())
+}
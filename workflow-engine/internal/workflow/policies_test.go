@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestParseStepPolicy(t *testing.T) {
+	p, err := ParseStepPolicy("3/30s", DefaultStepPolicy)
+	if err != nil || p != (StepPolicy{MaxAttempts: 3, Backoff: 30 * time.Second, Timeout: DefaultStepPolicy.Timeout}) {
+		t.Fatalf("unexpected policy %+v (err=%v)", p, err)
+	}
+	p, err = ParseStepPolicy("2/15s/10m", DefaultStepPolicy)
+	if err != nil || p.Timeout != 10*time.Minute {
+		t.Fatalf("unexpected policy %+v (err=%v)", p, err)
+	}
+	for _, raw := range []string{"", "3", "0/30s", "3/soon", "3/30s/0s", "3/30s/1m/x"} {
+		if _, err := ParseStepPolicy(raw, DefaultStepPolicy); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestPoliciesFromEnv_OverridesDefaults(t *testing.T) {
+	t.Setenv("WORKFLOW_STEP_APPLICATION_ONBOARDING_CODE_REPOSITORY", "4/1s/2m")
+	t.Setenv("WORKFLOW_STEP_SECRET_ROTATION_SECRET_BINDINGS", "not-a-policy")
+	t.Setenv("WORKFLOW_WAIT_SECRET_ROTATION_ROTATION_VALIDATED_EXTERNALLY", "10m")
+	t.Setenv("WORKFLOW_WAIT_APPLICATION_ONBOARDING_SECURITY_SCAN_PASSED", "-5m")
+
+	p, err := PoliciesFromEnv()
+	if err == nil || !strings.Contains(err.Error(), "WORKFLOW_STEP_SECRET_ROTATION_SECRET_BINDINGS") || !strings.Contains(err.Error(), "WORKFLOW_WAIT_APPLICATION_ONBOARDING_SECURITY_SCAN_PASSED") {
+		t.Fatalf("expected an error naming both invalid variables, got %v", err)
+	}
+	if got := p.Step("ApplicationOnboarding", StepCodeRepository); got != (StepPolicy{MaxAttempts: 4, Backoff: time.Second, Timeout: 2 * time.Minute}) {
+		t.Fatalf("unexpected CodeRepository policy %+v", got)
+	}
+	if got := p.Step("ApplicationOnboarding", StepDeploymentRepository); got.MaxAttempts != 2 || got.Backoff != 15*time.Second {
+		t.Fatalf("expected the DeploymentRepository default, got %+v", got)
+	}
+	if got := p.Step("SecretRotation", StepSecretBindings); got != DefaultStepPolicy {
+		t.Fatalf("expected an invalid value to keep the default, got %+v", got)
+	}
+	if got := p.Wait("SecretRotation", rotationValidatedSignalName); got != 10*time.Minute {
+		t.Fatalf("unexpected RotationValidatedExternally wait %v", got)
+	}
+	if got := p.Wait("ApplicationOnboarding", securityScanPassedSignalName); got != 900*time.Second {
+		t.Fatalf("unexpected SecurityScanPassed wait %v", got)
+	}
+}
+
+func TestDefaultPolicies_CoverEveryWait(t *testing.T) {
+	p := DefaultPolicies()
+	for _, def := range Definitions() {
+		for _, signal := range def.Signals {
+			if p.Wait(def.Name, signal) <= 0 {
+				t.Errorf("%s has no timeout for %s", def.Name, signal)
+			}
+		}
+	}
+}
+
+type unavailableCodeRepositoryPort struct {
+	fakeApplicationOnboardingPort
+}
+
+func (f *unavailableCodeRepositoryPort) DeclareCodeRepository(_ context.Context, _ string) error {
+	f.codeRepoCalls++
+	return errors.New("control-plane-api unavailable")
+}
+
+func TestApplicationOnboarding_RetriesCodeRepositoryPerStepPolicy(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(DefaultPolicies()) })
+
+	for _, tc := range []struct {
+		name     string
+		policies func() Policies
+		attempts int
+	}{
+		{"default", DefaultPolicies, 3},
+		{"configured", func() Policies {
+			p := DefaultPolicies()
+			p.SetStep("ApplicationOnboarding", StepCodeRepository, StepPolicy{MaxAttempts: 1, Backoff: time.Second, Timeout: time.Minute})
+			return p
+		}, 1},
+	} {
+		SetPolicies(tc.policies())
+		var ts testsuite.WorkflowTestSuite
+		env := ts.NewTestWorkflowEnvironment()
+		fake := &unavailableCodeRepositoryPort{}
+		SetApplicationOnboardingPort(fake)
+		env.RegisterWorkflow(ApplicationOnboarding)
+		env.RegisterActivity(CreateCodeRepositoryForApplication)
+
+		env.ExecuteWorkflow(ApplicationOnboarding, ApplicationOnboardingInput{ApplicationID: "app-1"})
+		if env.GetWorkflowError() == nil {
+			t.Fatalf("%s: expected the workflow to fail", tc.name)
+		}
+		if fake.codeRepoCalls != tc.attempts {
+			t.Fatalf("%s: expected %d CodeRepository attempts, got %d", tc.name, tc.attempts, fake.codeRepoCalls)
+		}
+	}
+}
+
+func TestApplicationOnboarding_UsesConfiguredSecurityScanWait(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(DefaultPolicies()) })
+	p := DefaultPolicies()
+	p.SetWait("ApplicationOnboarding", securityScanPassedSignalName, 2*time.Hour)
+	SetPolicies(p)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	SetApplicationOnboardingPort(&fakeApplicationOnboardingPort{})
+	env.RegisterWorkflow(ApplicationOnboarding)
+	env.RegisterActivity(CreateCodeRepositoryForApplication)
+	env.RegisterActivity(CreateDeploymentRepositoryForApplication)
+	env.RegisterActivity(CreateGitOpsIntegrationForApplication)
+	env.RegisterActivity(DeclareApplicationEnvironmentsForApplication)
+	env.RegisterActivity(TransitionApplicationToOnboarding)
+
+	// Con el default de 900s la señal llegaría tarde.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(securityScanPassedSignalName, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(ApplicationOnboarding, ApplicationOnboardingInput{ApplicationID: "app-1"})
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestApplicationDecommissioning_KeepsThePoliciesOfTheRun(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(DefaultPolicies()) })
+	p := DefaultPolicies()
+	p.SetStep("ApplicationDecommissioning", StepTransitionToDecommissioning, StepPolicy{MaxAttempts: 1, Backoff: time.Second, Timeout: time.Minute})
+	SetPolicies(p)
+
+	fake := &fakeDecommissioningPort{startErr: errors.New("control-plane-api unavailable")}
+	env := newDecommissioningEnv(t, fake)
+	// Cambiar la configuración con el run en curso no cambia sus pasos.
+	env.RegisterDelayedCallback(func() { SetPolicies(DefaultPolicies()) }, 30*time.Minute)
+	signalDecommissioningApproval(env, true, time.Hour)
+
+	env.ExecuteWorkflow(ApplicationDecommissioning, ApplicationDecommissioningInput{ApplicationID: "app-1"})
+	if env.GetWorkflowError() == nil {
+		t.Fatal("expected the workflow to fail")
+	}
+	if fake.started != 1 {
+		t.Fatalf("expected the run's single attempt, got %d", fake.started)
+	}
+}
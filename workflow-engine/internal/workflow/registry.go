@@ -0,0 +1,117 @@
+package workflow
+
+import "sort"
+
+// Recursos que orquestan los workflows; la API de gestión los usa para
+// listar las ejecuciones de un recurso.
+const (
+	ResourceApplication            = "Application"
+	ResourceApplicationEnvironment = "ApplicationEnvironment"
+	ResourceSecret                 = "Secret"
+)
+
+// Definition describe un workflow registrado en el worker: cómo se inicia
+// para un recurso y qué señales acepta.
+type Definition struct {
+	Name     string
+	Workflow any
+	// ResourceType es el recurso cuyo ID recibe el workflow como input.
+	ResourceType string
+	// WorkflowID deriva el ID de la ejecución a partir de la organización y
+	// el recurso.
+	WorkflowID func(organizationID, resourceID string) string
+	// Input arma el input del workflow para el recurso de la organización.
+	Input func(organizationID, resourceID string) any
+	// Steps son los pasos con actividades, cada uno con su StepPolicy.
+	Steps []string
+	// Signals son las señales que el workflow puede esperar; cada espera
+	// tiene su timeout en Policies.
+	Signals []string
+}
+
+// AcceptsSignal indica si signal es una de las señales del workflow.
+func (d Definition) AcceptsSignal(signal string) bool {
+	for _, s := range d.Signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+var definitions = []Definition{
+	{
+		Name:         "ApplicationOnboarding",
+		Workflow:     ApplicationOnboarding,
+		ResourceType: ResourceApplication,
+		WorkflowID:   ApplicationOnboardingWorkflowID,
+		Input:        func(org, id string) any { return ApplicationOnboardingInput{OrganizationID: org, ApplicationID: id} },
+		Steps: []string{
+			StepCodeRepository, StepDeploymentRepository, StepGitOpsIntegration,
+			StepApplicationEnvironments, StepTransitionToOnboarding,
+		},
+		Signals: []string{securityScanPassedSignalName},
+	},
+	{
+		Name:         "ApplicationActivation",
+		Workflow:     ApplicationActivation,
+		ResourceType: ResourceApplication,
+		WorkflowID:   ApplicationActivationWorkflowID,
+		Input:        func(org, id string) any { return ApplicationActivationInput{OrganizationID: org, ApplicationID: id} },
+		Steps:        []string{StepTransitionToActive},
+	},
+	{
+		Name:         "ApplicationDecommissioning",
+		Workflow:     ApplicationDecommissioning,
+		ResourceType: ResourceApplication,
+		WorkflowID:   ApplicationDecommissioningWorkflowID,
+		Input: func(org, id string) any {
+			return ApplicationDecommissioningInput{OrganizationID: org, ApplicationID: id}
+		},
+		Steps: []string{
+			StepRequestApproval, StepTransitionToDecommissioning, StepListApplicationEnvironments,
+			StepDecommissionApplicationEnvironment, StepRevokeSecretBindings, StepTransitionToArchived,
+		},
+		Signals: []string{DecommissioningApprovalSignalName},
+	},
+	{
+		Name:         "ApplicationEnvironmentProvisioning",
+		Workflow:     ApplicationEnvironmentProvisioning,
+		ResourceType: ResourceApplicationEnvironment,
+		WorkflowID:   ApplicationEnvironmentProvisioningWorkflowID,
+		Input: func(org, id string) any {
+			return ApplicationEnvironmentProvisioningInput{OrganizationID: org, ApplicationEnvironmentID: id}
+		},
+		Steps: []string{
+			StepRepositories, StepBranchProtection, StepSecrets,
+			StepSecretBindings, StepGitOpsReconciliation, StepTransitionToActive,
+		},
+	},
+	{
+		Name:         "SecretRotation",
+		Workflow:     SecretRotation,
+		ResourceType: ResourceSecret,
+		WorkflowID:   SecretRotationWorkflowID,
+		Input:        func(org, id string) any { return SecretRotationInput{OrganizationID: org, SecretID: id} },
+		Steps:        []string{StepRotateSecret, StepSecretBindings, StepCompleteRotation},
+		Signals:      []string{rotationValidatedSignalName},
+	},
+}
+
+// Definitions devuelve los workflows que registra el worker, por nombre.
+func Definitions() []Definition {
+	out := append([]Definition(nil), definitions...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// DefinitionFor devuelve la definición del workflow name, o false si no es
+// un workflow registrado.
+func DefinitionFor(name string) (Definition, bool) {
+	for _, d := range definitions {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"context"
-	"time"
 
 	"github.com/nuevo-idp/platform/observability"
 	"go.temporal.io/sdk/activity"
@@ -15,7 +14,10 @@ const SecretRotationTaskQueue = "secret-rotation-task-queue"
 // SecretRotationInput modela la intención "SecretRotation" del estado deseado.
 // La precondición es que el Secret ya esté en estado Rotating.
 type SecretRotationInput struct {
-	SecretID string
+	// OrganizationID es la organización del recurso; vacío es
+	// DefaultOrganizationID.
+	OrganizationID string
+	SecretID       string
 }
 
 // SecretRotationPort es un puerto estrecho hacia control-plane-api para
@@ -58,6 +60,8 @@ const rotationValidatedSignalName = "RotationValidatedExternally"
 // - update SecretBindings (por ahora asumimos que ocurre fuera del dominio)
 // - transition Secret to Active
 func SecretRotation(ctx workflow.Context, input SecretRotationInput) (err error) {
+	ctx = withOrganization(ctx, input.OrganizationID)
+	ctx = withPolicies(ctx, "SecretRotation")
 	start := workflow.Now(ctx)
 	result := "success"
 
@@ -66,9 +70,12 @@ func SecretRotation(ctx workflow.Context, input SecretRotationInput) (err error)
 		observability.ObserveWorkflowRetries("SecretRotation", 1)
 	}
 
+	hooks := newLifecycle(ctx, "SecretRotation", input.SecretID)
+
 	defer func() {
 		if err != nil {
 			result = "error"
+			hooks.onFailure(ctx, err)
 		}
 		duration := workflow.Now(ctx).Sub(start).Seconds()
 		observability.ObserveWorkflowDuration("SecretRotation", result, duration)
@@ -78,19 +85,10 @@ func SecretRotation(ctx workflow.Context, input SecretRotationInput) (err error)
 		return temporal.NewNonRetryableApplicationError("SecretID is required", "bad_input", nil) //nolint:wrapcheck
 	}
 
-	opts := workflow.ActivityOptions{
-		StartToCloseTimeout: 5 * time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    5 * time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    1 * time.Minute,
-			MaximumAttempts:    5,
-		},
-	}
-	ctx = workflow.WithActivityOptions(ctx, opts)
+	hooks.beforeStart(ctx)
 
 	// 1. Rotar el secreto (aún sin efectos en execution-workers).
-	if err := workflow.ExecuteActivity(ctx, PerformSecretRotation, input.SecretID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepRotateSecret, PerformSecretRotation, input.SecretID); err != nil {
 		observability.ObserveDomainEvent("workflow_secret_rotation_failed", "error")
 		//nolint:wrapcheck // propagamos el error tal cual para preservar el tipo de ApplicationError
 		return err
@@ -103,19 +101,20 @@ func SecretRotation(ctx workflow.Context, input SecretRotationInput) (err error)
 	}
 
 	// 3. Actualizar los SecretBindings asociados al Secret en sistemas externos.
-	if err := workflow.ExecuteActivity(ctx, UpdateSecretBindingsForSecret, input.SecretID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepSecretBindings, UpdateSecretBindingsForSecret, input.SecretID); err != nil {
 		observability.ObserveDomainEvent("workflow_secret_rotation_failed", "error")
 		//nolint:wrapcheck // propagamos el error tal cual para preservar el tipo de ApplicationError
 		return err
 	}
 
 	// 4. Completar la rotación en el control-plane.
-	if err := workflow.ExecuteActivity(ctx, CompleteSecretRotationActivity, input.SecretID).Get(ctx, nil); err != nil {
+	if err := hooks.step(ctx, StepCompleteRotation, CompleteSecretRotationActivity, input.SecretID); err != nil {
 		observability.ObserveDomainEvent("workflow_secret_rotation_failed", "error")
 		//nolint:wrapcheck // propagamos el error tal cual para preservar el tipo de ApplicationError
 		return err
 	}
 
+	hooks.afterSuccess(ctx)
 	observability.ObserveDomainEvent("workflow_secret_rotation_completed", "success")
 	return nil
 }
@@ -125,14 +124,14 @@ func waitForRotationValidatedExternally(ctx workflow.Context) error {
 	logger.Info("Waiting for RotationValidatedExternally event")
 
 	signalCh := workflow.GetSignalChannel(ctx, rotationValidatedSignalName)
-	timer := workflow.NewTimer(ctx, 60*time.Minute)
+	timer := workflow.NewTimer(ctx, waitTimeout(ctx, "SecretRotation", rotationValidatedSignalName))
 
 	selector := workflow.NewSelector(ctx)
 	var received bool
+	var signal ExternalSignal
 
 	selector.AddReceive(signalCh, func(c workflow.ReceiveChannel, more bool) {
-		var payload interface{}
-		c.Receive(ctx, &payload)
+		c.Receive(ctx, &signal)
 		received = true
 	})
 	selector.AddFuture(timer, func(f workflow.Future) {})
@@ -140,7 +139,7 @@ func waitForRotationValidatedExternally(ctx workflow.Context) error {
 	selector.Select(ctx)
 
 	if received {
-		logger.Info("Received RotationValidatedExternally signal")
+		logger.Info("Received RotationValidatedExternally signal", "deliveryId", signal.DeliveryID)
 		return nil
 	}
 
@@ -0,0 +1,102 @@
+package workflow
+
+import "time"
+
+// Los workflows que esperan eventos externos se inician con un ID derivado
+// de la organización y del recurso que orquestan, para que un sistema
+// externo pueda señalizarlos conociendo sólo ese recurso.
+
+// ApplicationOnboardingWorkflowID es el ID con el que se inicia
+// ApplicationOnboarding para applicationID en organizationID.
+func ApplicationOnboardingWorkflowID(organizationID, applicationID string) string {
+	return organizationWorkflowID(organizationID, "application-onboarding-"+applicationID)
+}
+
+// ApplicationActivationWorkflowID es el ID con el que se inicia
+// ApplicationActivation para applicationID en organizationID.
+func ApplicationActivationWorkflowID(organizationID, applicationID string) string {
+	return organizationWorkflowID(organizationID, "application-activation-"+applicationID)
+}
+
+// ApplicationEnvironmentProvisioningWorkflowID es el ID con el que se inicia
+// ApplicationEnvironmentProvisioning para appEnvID en organizationID.
+func ApplicationEnvironmentProvisioningWorkflowID(organizationID, appEnvID string) string {
+	return organizationWorkflowID(organizationID, "appenv-provisioning-"+appEnvID)
+}
+
+// SecretRotationWorkflowID es el ID con el que se inicia SecretRotation para
+// secretID en organizationID.
+func SecretRotationWorkflowID(organizationID, secretID string) string {
+	return organizationWorkflowID(organizationID, "secret-rotation-"+secretID)
+}
+
+// ApplicationDecommissioningWorkflowID es el ID con el que se inicia
+// ApplicationDecommissioning para applicationID en organizationID; es el
+// workflowId al que el control plane reenvía la decisión de la aprobación.
+func ApplicationDecommissioningWorkflowID(organizationID, applicationID string) string {
+	return organizationWorkflowID(organizationID, "application-decommissioning-"+applicationID)
+}
+
+// ExternalSignal es el payload de las señales que llegan desde sistemas
+// externos (ver el receptor de webhooks).
+type ExternalSignal struct {
+	DeliveryID string
+	Event      string
+	ReceivedAt time.Time
+	Payload    map[string]any
+}
+
+// ExternalEvent describe cómo se entrega un evento externo: qué señal se
+// envía y a qué workflow, a partir de la organización y el ID del recurso.
+type ExternalEvent struct {
+	Signal     string
+	Workflow   string
+	WorkflowID func(organizationID, resourceID string) string
+}
+
+// externalEvents son los eventos que los sistemas externos pueden enviar,
+// por nombre. El nombre coincide con el de la señal.
+var externalEvents = map[string]ExternalEvent{
+	securityScanPassedSignalName: {
+		Signal:     securityScanPassedSignalName,
+		Workflow:   "ApplicationOnboarding",
+		WorkflowID: ApplicationOnboardingWorkflowID,
+	},
+	rotationValidatedSignalName: {
+		Signal:     rotationValidatedSignalName,
+		Workflow:   "SecretRotation",
+		WorkflowID: SecretRotationWorkflowID,
+	},
+}
+
+// ExternalEventFor devuelve el ExternalEvent de event, o false si no es un
+// evento conocido.
+func ExternalEventFor(event string) (ExternalEvent, bool) {
+	ev, ok := externalEvents[event]
+	return ev, ok
+}
+
+// DecommissioningApprovalSignalName es la señal con la que el control plane
+// entrega la decisión del waitForApproval de ApplicationDecommissioning.
+const DecommissioningApprovalSignalName = "DecommissioningApproval"
+
+// ApprovalSignal es el payload de las señales de aprobación manual: la
+// decisión de un pedido de aprobación del control plane.
+type ApprovalSignal struct {
+	ApprovalID string    `json:"approvalId"`
+	Approved   bool      `json:"approved"`
+	By         string    `json:"by"`
+	Role       string    `json:"role,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// approvalSignals son las señales que el control plane puede reenviar.
+var approvalSignals = map[string]bool{
+	DecommissioningApprovalSignalName: true,
+}
+
+// IsApprovalSignal indica si signal es una señal de aprobación manual.
+func IsApprovalSignal(signal string) bool {
+	return approvalSignals[signal]
+}